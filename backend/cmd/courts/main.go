@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/user/tennis-connect/config"
+	"github.com/user/tennis-connect/courtdata"
+	"github.com/user/tennis-connect/database"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/repository"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		showHelp()
+		os.Exit(2)
+	}
+
+	// Load environment variables and configuration
+	loadEnvForEnvironment()
+	cfg := config.LoadConfig()
+
+	switch os.Args[1] {
+	case "import":
+		importCourts(cfg, os.Args[2:])
+	case "export":
+		exportCourts(cfg, os.Args[2:])
+	default:
+		showHelp()
+		os.Exit(2)
+	}
+}
+
+// importCourts reads a file of courts, prints what importing it would change
+// and, unless it's a dry run, saves the changes
+func importCourts(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	formatName := flags.String("format", "", "geojson, csv, osm or osm.pbf (default: from the file extension)")
+	dryRun := flags.Bool("dry-run", false, "show what would change without saving anything")
+	userFlag := flags.String("user", "", "ID of the moderator credited with the import in court histories")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		log.Fatalf("Usage: courts import [-format FORMAT] [-dry-run] [-user ID] FILE")
+	}
+	path := flags.Arg(0)
+
+	var userID uuid.UUID
+	if !*dryRun {
+		var err error
+		if userID, err = uuid.Parse(*userFlag); err != nil {
+			log.Fatalf("Please provide the importing moderator's ID with -user, or use -dry-run")
+		}
+	}
+
+	format, err := courtdata.ParseFormat(*formatName, path)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Error opening %s: %v", path, err)
+	}
+	defer file.Close()
+	records, err := courtdata.Read(file, format)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", path, err)
+	}
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+	defer db.Close()
+	courtRepo := repository.NewCourtRepository(db)
+
+	ctx := context.Background()
+	if !*dryRun {
+		isModerator, err := courtRepo.IsModerator(ctx, userID)
+		if err != nil {
+			log.Fatalf("Error checking moderator: %v", err)
+		}
+		if !isModerator {
+			log.Fatalf("User %s is not a moderator", userID)
+		}
+	}
+
+	plan, err := courtdata.NewPlan(ctx, courtRepo, records)
+	if err != nil {
+		log.Fatalf("Error planning import: %v", err)
+	}
+	if err := plan.WriteText(os.Stdout); err != nil {
+		log.Fatalf("Error writing report: %v", err)
+	}
+	if *dryRun {
+		log.Println("Dry run, nothing saved")
+		return
+	}
+
+	saved, err := plan.Apply(ctx, courtRepo, userID, filepath.Base(path))
+	if err != nil {
+		log.Fatalf("Error after saving %d court(s): %v", saved, err)
+	}
+	log.Printf("Saved %d court(s)", saved)
+}
+
+// exportCourts writes the catalogue as GeoJSON
+func exportCourts(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	status := flags.String("status", models.CourtStatusApproved, "export courts with this status")
+	output := flags.String("o", "", "file to write (default: standard output)")
+	flags.Parse(args)
+
+	db, err := database.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %v", err)
+	}
+	defer db.Close()
+
+	courts, err := repository.NewCourtRepository(db).GetCatalogue(context.Background(), *status)
+	if err != nil {
+		log.Fatalf("Error fetching courts: %v", err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			log.Fatalf("Error creating %s: %v", *output, err)
+		}
+	}
+	if err := courtdata.WriteGeoJSON(out, courts); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if err := out.Close(); err != nil {
+		log.Fatalf("Error writing %s: %v", *output, err)
+	}
+	if *output != "" {
+		log.Printf("Exported %d court(s) to %s", len(courts), *output)
+	}
+}
+
+// loadEnvForEnvironment loads environment variables based on APP_ENV
+func loadEnvForEnvironment() {
+	// Try to load .env file
+	envFiles := []string{
+		".env",
+		"../.env",
+	}
+
+	for _, file := range envFiles {
+		if _, err := os.Stat(file); err == nil {
+			if err := godotenv.Load(file); err != nil {
+				log.Printf("Warning: Error loading %s file: %v", file, err)
+			} else {
+				break
+			}
+		}
+	}
+
+	// Check if APP_ENV is set
+	appEnv := os.Getenv("APP_ENV")
+	if appEnv == "" {
+		appEnv = "development"
+		os.Setenv("APP_ENV", appEnv)
+	}
+
+	// Try to load environment-specific .env file
+	envFileName := fmt.Sprintf(".env.%s", appEnv)
+	envFiles = []string{
+		envFileName,
+		"../" + envFileName,
+	}
+
+	for _, file := range envFiles {
+		if _, err := os.Stat(file); err == nil {
+			if err := godotenv.Load(file); err != nil {
+				log.Printf("Warning: Error loading %s file: %v", file, err)
+			} else {
+				break
+			}
+		}
+	}
+}
+
+// showHelp displays usage information
+func showHelp() {
+	fmt.Println("Court Catalogue Utility")
+	fmt.Println("\nUsage:")
+	fmt.Println("  courts [command] [flags]")
+	fmt.Println("\nCommands:")
+	fmt.Println("  import FILE  Import courts from GeoJSON, CSV or an OpenStreetMap extract")
+	fmt.Println("               (leisure=pitch, sport=tennis), skipping courts already listed")
+	fmt.Println("  export       Export the catalogue as GeoJSON")
+	fmt.Println("\nExamples:")
+	fmt.Println("  courts import -dry-run taiwan-latest.osm.pbf    # Show what would change")
+	fmt.Println("  courts import -user USER_ID courts.csv          # Import, credited to a moderator")
+	fmt.Println("  courts export -o courts.geojson                 # Export approved courts")
+	fmt.Println("  courts export -status pending                   # Export courts awaiting review")
+}
@@ -12,6 +12,8 @@ type Config struct {
 	Server      ServerConfig
 	Database    DatabaseConfig
 	JWT         JWTConfig
+	Payments    PaymentsConfig
+	Geocoding   GeocodingConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -36,6 +38,17 @@ type JWTConfig struct {
 	Expiration int // in minutes
 }
 
+// PaymentsConfig holds payment provider configuration
+type PaymentsConfig struct {
+	Provider string // Only "fake" is built in
+}
+
+// GeocodingConfig holds geocoding provider configuration
+type GeocodingConfig struct {
+	Provider string // "offline" (the embedded gazetteer only) or "bigdatacloud"
+	Timeout  int    // in seconds, for online providers
+}
+
 // GetConnectionString returns a formatted database connection string
 func (c *DatabaseConfig) GetConnectionString() string {
 	return fmt.Sprintf(
@@ -65,6 +78,13 @@ func LoadConfig() *Config {
 			Secret:     getEnvOrDefault("JWT_SECRET", "tennis-connect-secret-key-change-in-production"),
 			Expiration: getEnvAsIntOrDefault("JWT_EXPIRATION", 60), // minutes
 		},
+		Payments: PaymentsConfig{
+			Provider: getEnvOrDefault("PAYMENT_PROVIDER", "fake"),
+		},
+		Geocoding: GeocodingConfig{
+			Provider: getEnvOrDefault("GEOCODER_PROVIDER", "offline"),
+			Timeout:  getEnvAsIntOrDefault("GEOCODER_TIMEOUT", 5),
+		},
 	}
 
 	return config
@@ -0,0 +1,182 @@
+// Package courtdata moves courts in and out of the catalogue in bulk. It
+// reads GeoJSON, CSV and OpenStreetMap extracts, plans an import against the
+// existing courts so duplicates are caught and a dry run can show what would
+// change, and writes the catalogue back out as GeoJSON.
+package courtdata
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/tennis-connect/models"
+)
+
+// Format is a court file format
+type Format string
+
+const (
+	FormatGeoJSON Format = "geojson"
+	FormatCSV     Format = "csv"
+	FormatOSM     Format = "osm"     // OpenStreetMap XML
+	FormatOSMPBF  Format = "osm.pbf" // OpenStreetMap protocol buffers
+)
+
+// Defaults for the details import files often leave out
+const (
+	DefaultName      = "Tennis Courts"
+	DefaultCourtType = "Hard"
+)
+
+// MaxImportBytes is the largest file the import endpoint accepts. Larger
+// extracts can be imported with the courts command.
+const MaxImportBytes = 256 << 20
+
+// Record is a court read from a file
+type Record struct {
+	Source string       `json:"source"` // Where in the file it came from, e.g. "line 4" or "way/123"
+	Court  models.Court `json:"court"`
+}
+
+// ParseFormat parses a format name. If the name is empty the format is
+// guessed from the file's extension.
+func ParseFormat(name, filename string) (Format, error) {
+	if name == "" {
+		lower := strings.ToLower(filename)
+		switch {
+		case strings.HasSuffix(lower, ".pbf"):
+			return FormatOSMPBF, nil
+		case strings.HasSuffix(lower, ".osm"), strings.HasSuffix(lower, ".xml"):
+			return FormatOSM, nil
+		case strings.HasSuffix(lower, ".csv"):
+			return FormatCSV, nil
+		case strings.HasSuffix(lower, ".geojson"), strings.HasSuffix(lower, ".json"):
+			return FormatGeoJSON, nil
+		}
+		return "", fmt.Errorf("cannot tell the format of %s; use geojson, csv, osm or osm.pbf", filepath.Base(filename))
+	}
+
+	switch strings.ToLower(name) {
+	case "geojson", "json":
+		return FormatGeoJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "osm", "xml":
+		return FormatOSM, nil
+	case "osm.pbf", "pbf":
+		return FormatOSMPBF, nil
+	}
+	return "", fmt.Errorf("unknown format %s; use geojson, csv, osm or osm.pbf", name)
+}
+
+// Read parses the courts in r. PBF extracts are read twice, once for the
+// courts and once for the coordinates of their outlines, so r should be an
+// io.ReadSeeker for them; anything else is buffered in memory.
+func Read(r io.Reader, format Format) ([]Record, error) {
+	switch format {
+	case FormatGeoJSON:
+		return ReadGeoJSON(r)
+	case FormatCSV:
+		return ReadCSV(r)
+	case FormatOSM:
+		return ReadOSM(r)
+	case FormatOSMPBF:
+		seeker, ok := r.(io.ReadSeeker)
+		if !ok {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read extract: %w", err)
+			}
+			seeker = bytes.NewReader(data)
+		}
+		return ReadOSMPBF(seeker)
+	}
+	return nil, fmt.Errorf("unknown format %s", format)
+}
+
+// isTennisPitch returns true if OpenStreetMap tags describe a tennis court
+func isTennisPitch(tags map[string]string) bool {
+	if tags["leisure"] != "pitch" {
+		return false
+	}
+	for _, sport := range strings.Split(tags["sport"], ";") {
+		if strings.TrimSpace(sport) == "tennis" {
+			return true
+		}
+	}
+	return false
+}
+
+// courtFromTags builds a court from OpenStreetMap tags
+func courtFromTags(tags map[string]string, latitude, longitude float64) models.Court {
+	court := models.Court{
+		Name:        tags["name"],
+		Description: tags["description"],
+		CourtType:   courtTypeFromSurface(tags["surface"], tags["indoor"] == "yes"),
+		IsPublic:    true,
+		Website:     firstTag(tags, "website", "contact:website", "url"),
+		ContactInfo: firstTag(tags, "phone", "contact:phone", "email", "contact:email"),
+		Location: models.Location{
+			Latitude:  latitude,
+			Longitude: longitude,
+			City:      tags["addr:city"],
+			State:     tags["addr:state"],
+			ZipCode:   tags["addr:postcode"],
+		},
+	}
+	switch tags["access"] {
+	case "private", "members", "customers", "no":
+		court.IsPublic = false
+	}
+	if tags["lit"] == "yes" {
+		court.Amenities = append(court.Amenities, "Lights")
+	}
+	return court
+}
+
+func firstTag(tags map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := tags[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// courtTypeFromSurface maps an OpenStreetMap surface to one of our court
+// types. Unknown surfaces are left empty for the default to fill in.
+func courtTypeFromSurface(surface string, indoor bool) string {
+	if indoor {
+		return "Indoor"
+	}
+	switch surface {
+	case "clay", "red_clay", "green_clay", "artificial_clay":
+		return "Clay"
+	case "grass", "artificial_turf", "artificial_grass":
+		return "Grass"
+	case "hard", "asphalt", "concrete", "acrylic", "tartan", "paved", "rubber", "carpet":
+		return "Hard"
+	}
+	return ""
+}
+
+// normalize trims a court read from a file and fills in the defaults, so it
+// can be validated and compared
+func normalize(court *models.Court) {
+	court.Name = strings.TrimSpace(court.Name)
+	if court.Name == "" {
+		court.Name = DefaultName
+	}
+	court.CourtType = strings.TrimSpace(court.CourtType)
+	if court.CourtType == "" {
+		court.CourtType = DefaultCourtType
+	}
+	court.Description = strings.TrimSpace(court.Description)
+	court.Website = strings.TrimSpace(court.Website)
+	court.ContactInfo = strings.TrimSpace(court.ContactInfo)
+	if len(court.Units) == 0 {
+		court.Units = models.DefaultCourtUnits(1)
+	}
+}
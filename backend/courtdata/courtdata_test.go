@@ -0,0 +1,212 @@
+package courtdata
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/tennis-connect/models"
+)
+
+// The sample extracts describe the same few courts around San Francisco.
+// sample.osm and sample.osm.pbf hold identical data.
+
+func readSample(t *testing.T, name string, format Format) []Record {
+	t.Helper()
+	file, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	defer file.Close()
+	records, err := Read(file, format)
+	require.NoError(t, err)
+	return records
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name, filename string
+		want           Format
+	}{
+		{"", "courts.geojson", FormatGeoJSON},
+		{"", "export.JSON", FormatGeoJSON},
+		{"", "courts.csv", FormatCSV},
+		{"", "map.osm", FormatOSM},
+		{"", "taiwan-latest.osm.pbf", FormatOSMPBF},
+		{"pbf", "upload", FormatOSMPBF},
+		{"CSV", "courts.txt", FormatCSV},
+	}
+	for _, tt := range tests {
+		format, err := ParseFormat(tt.name, tt.filename)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, format, tt.filename)
+	}
+
+	_, err := ParseFormat("", "courts.txt")
+	assert.EqualError(t, err, "cannot tell the format of courts.txt; use geojson, csv, osm or osm.pbf")
+	_, err = ParseFormat("kml", "courts.kml")
+	assert.Error(t, err)
+}
+
+func TestReadOSM(t *testing.T) {
+	records := readSample(t, "sample.osm", FormatOSM)
+
+	// Nodes come before ways in an extract, the football pitch is skipped
+	// and the three Golden Gate Park courts are one facility
+	var sources []string
+	for _, record := range records {
+		sources = append(sources, record.Source)
+	}
+	assert.Equal(t, []string{"node/29", "way/101,way/102,way/103", "way/110", "way/201,way/202", "way/401"}, sources)
+
+	dolores := records[0].Court
+	assert.Equal(t, "Dolores Park Tennis Courts", dolores.Name)
+	assert.Equal(t, 37.7596, dolores.Location.Latitude)
+	assert.Equal(t, "San Francisco", dolores.Location.City)
+	assert.Equal(t, "94114", dolores.Location.ZipCode)
+	assert.Equal(t, []string{"Lights"}, dolores.Amenities)
+
+	goldenGate := records[1].Court
+	assert.Equal(t, "Golden Gate Park Tennis Center", goldenGate.Name)
+	assert.Equal(t, "Hard", goldenGate.CourtType)
+	assert.Len(t, goldenGate.Units, 3)
+	assert.Equal(t, []string{"Lights"}, goldenGate.Amenities)
+	assert.Equal(t, "https://goldengateparktennis.org", goldenGate.Website)
+	assert.InDelta(t, 37.7705, goldenGate.Location.Latitude, 1e-7)
+	assert.InDelta(t, -122.4588, goldenGate.Location.Longitude, 1e-7)
+
+	// Close by, but named differently
+	assert.Equal(t, "Park Presidio Courts", records[2].Court.Name)
+	assert.Len(t, records[2].Court.Units, 1)
+
+	private := records[3].Court
+	assert.Empty(t, private.Name)
+	assert.Equal(t, "Clay", private.CourtType)
+	assert.False(t, private.IsPublic)
+	assert.Len(t, private.Units, 2)
+
+	// Its nodes are outside the extract
+	edge := records[4].Court
+	assert.Equal(t, "Edge Courts", edge.Name)
+	assert.Zero(t, edge.Location.Latitude)
+	assert.Zero(t, edge.Location.Longitude)
+}
+
+func TestReadOSMPBFMatchesXML(t *testing.T) {
+	xmlRecords := readSample(t, "sample.osm", FormatOSM)
+	pbfRecords := readSample(t, "sample.osm.pbf", FormatOSMPBF)
+
+	require.Len(t, pbfRecords, len(xmlRecords))
+	for i, want := range xmlRecords {
+		got := pbfRecords[i]
+		assert.Equal(t, want.Source, got.Source)
+		assert.Equal(t, want.Court.Name, got.Court.Name)
+		assert.Equal(t, want.Court.CourtType, got.Court.CourtType)
+		assert.Equal(t, want.Court.IsPublic, got.Court.IsPublic)
+		assert.Equal(t, want.Court.Amenities, got.Court.Amenities)
+		assert.Len(t, got.Court.Units, len(want.Court.Units))
+		assert.InDelta(t, want.Court.Location.Latitude, got.Court.Location.Latitude, 1e-7)
+		assert.InDelta(t, want.Court.Location.Longitude, got.Court.Location.Longitude, 1e-7)
+	}
+}
+
+func TestReadOSMPBFErrors(t *testing.T) {
+	_, err := ReadOSMPBF(bytes.NewReader([]byte{0, 0, 0, 3, 0xff, 0xff, 0xff}))
+	assert.ErrorIs(t, err, errInvalidPBF)
+
+	data, err := os.ReadFile("testdata/sample.osm.pbf")
+	require.NoError(t, err)
+	_, err = ReadOSMPBF(bytes.NewReader(data[:len(data)-10]))
+	assert.ErrorContains(t, err, "failed to read OSM PBF")
+}
+
+func TestReadGeoJSON(t *testing.T) {
+	records := readSample(t, "sample.geojson", FormatGeoJSON)
+	require.Len(t, records, 3, "the basketball court is skipped")
+
+	dolores := records[0]
+	assert.Equal(t, "feature way/501", dolores.Source)
+	assert.Equal(t, "Mission Dolores Courts", dolores.Court.Name)
+	assert.Equal(t, "Clay", dolores.Court.CourtType)
+	assert.InDelta(t, 37.76131, dolores.Court.Location.Latitude, 1e-9)
+	assert.InDelta(t, -122.42695, dolores.Court.Location.Longitude, 1e-9)
+
+	aliceMarble := records[1]
+	assert.Equal(t, "feature 3", aliceMarble.Source)
+	assert.Equal(t, "Alice Marble Tennis Courts", aliceMarble.Court.Name)
+	assert.Len(t, aliceMarble.Court.Units, 2)
+	assert.Equal(t, []string{"Lights", "Water"}, aliceMarble.Court.Amenities)
+	assert.Equal(t, "CA", aliceMarble.Court.Location.State)
+	assert.True(t, aliceMarble.Court.IsPublic)
+
+	_, err := ReadGeoJSON(strings.NewReader(`{"type": "Feature"}`))
+	assert.EqualError(t, err, `invalid GeoJSON: expected a FeatureCollection, got "Feature"`)
+	_, err = ReadGeoJSON(strings.NewReader(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "LineString", "coordinates": []}, "properties": {}}]}`))
+	assert.EqualError(t, err, "feature 1: unsupported geometry LineString")
+}
+
+func TestReadCSV(t *testing.T) {
+	records := readSample(t, "sample.csv", FormatCSV)
+	require.Len(t, records, 4)
+
+	moscone := records[0]
+	assert.Equal(t, "line 2", moscone.Source)
+	assert.Equal(t, "Moscone Recreation Center", moscone.Court.Name)
+	assert.Equal(t, "Hard", moscone.Court.CourtType)
+	assert.True(t, moscone.Court.IsPublic)
+	assert.Equal(t, []string{"Lights", "Restrooms"}, moscone.Court.Amenities)
+	assert.Len(t, moscone.Court.Units, 4)
+	assert.Equal(t, "https://sfrecpark.org/moscone", moscone.Court.Website)
+	assert.Equal(t, "94123", moscone.Court.Location.ZipCode)
+
+	assert.Equal(t, "Mountain Lake Park, Lower Courts", records[1].Court.Name)
+	assert.Empty(t, records[2].Court.Name)
+	assert.Equal(t, 95.0, records[3].Court.Location.Latitude)
+
+	_, err := ReadCSV(strings.NewReader("name,latitude\nSomewhere,37.7\n"))
+	assert.EqualError(t, err, "invalid CSV: missing longitude column")
+	_, err = ReadCSV(strings.NewReader("name,latitude,longitude\nSomewhere,north,-122.4\n"))
+	assert.EqualError(t, err, `line 2: invalid latitude "north"`)
+	_, err = ReadCSV(strings.NewReader("name,latitude,longitude,courts\nSomewhere,37.7,-122.4,none\n"))
+	assert.EqualError(t, err, `line 2: invalid number of courts "none"`)
+}
+
+func TestWriteGeoJSONRoundTrip(t *testing.T) {
+	court := &models.Court{
+		ID:          uuid.New(),
+		Name:        "Moscone Recreation Center",
+		Description: "Four lit hard courts",
+		CourtType:   "Hard",
+		IsPublic:    true,
+		Amenities:   []string{"Lights", "Restrooms"},
+		Website:     "https://sfrecpark.org/moscone",
+		Location:    models.Location{Latitude: 37.8013, Longitude: -122.4334, City: "San Francisco", State: "CA", ZipCode: "94123"},
+		Units:       models.DefaultCourtUnits(4),
+		Status:      models.CourtStatusApproved,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteGeoJSON(&buf, []*models.Court{court}))
+	assert.Contains(t, buf.String(), `"coordinates": [`)
+
+	records, err := ReadGeoJSON(&buf)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "feature "+court.ID.String(), records[0].Source)
+	imported := records[0].Court
+	assert.Equal(t, court.Name, imported.Name)
+	assert.Equal(t, court.Description, imported.Description)
+	assert.Equal(t, court.CourtType, imported.CourtType)
+	assert.Equal(t, court.Amenities, imported.Amenities)
+	assert.Equal(t, court.Website, imported.Website)
+	assert.Equal(t, court.Location, imported.Location)
+	assert.Len(t, imported.Units, 4)
+
+	// An empty catalogue is still a valid collection
+	buf.Reset()
+	require.NoError(t, WriteGeoJSON(&buf, nil))
+	assert.JSONEq(t, `{"type": "FeatureCollection", "features": []}`, buf.String())
+}
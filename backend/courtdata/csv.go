@@ -0,0 +1,112 @@
+package courtdata
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/user/tennis-connect/models"
+)
+
+// csvColumnAliases maps the other names columns commonly go by to ours
+var csvColumnAliases = map[string]string{
+	"lat":         "latitude",
+	"lng":         "longitude",
+	"lon":         "longitude",
+	"long":        "longitude",
+	"type":        "court_type",
+	"surface":     "court_type",
+	"public":      "is_public",
+	"phone":       "contact_info",
+	"zip":         "zip_code",
+	"postcode":    "zip_code",
+	"postal_code": "zip_code",
+	"url":         "website",
+}
+
+// ReadCSV reads courts from a CSV file with a header row. Latitude and
+// longitude are required; the other columns are the same as the GeoJSON
+// properties, with amenities separated by semicolons.
+func ReadCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []Record{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if alias, ok := csvColumnAliases[name]; ok {
+			name = alias
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"latitude", "longitude"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invalid CSV: missing %s column", required)
+		}
+	}
+
+	records := []Record{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		line, _ := reader.FieldPos(0)
+		source := fmt.Sprintf("line %d", line)
+		latitude, err := strconv.ParseFloat(field("latitude"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid latitude %q", source, field("latitude"))
+		}
+		longitude, err := strconv.ParseFloat(field("longitude"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid longitude %q", source, field("longitude"))
+		}
+
+		court := models.Court{
+			Name:        field("name"),
+			Description: field("description"),
+			CourtType:   field("court_type"),
+			IsPublic:    parseBool(field("is_public"), true),
+			Amenities:   propertyStrings(field("amenities")),
+			Website:     field("website"),
+			ContactInfo: field("contact_info"),
+			ImageURL:    field("image_url"),
+			Location: models.Location{
+				Latitude:  latitude,
+				Longitude: longitude,
+				City:      field("city"),
+				State:     field("state"),
+				ZipCode:   field("zip_code"),
+			},
+		}
+		if courts := field("courts"); courts != "" {
+			n, err := strconv.Atoi(courts)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%s: invalid number of courts %q", source, courts)
+			}
+			court.Units = models.DefaultCourtUnits(n)
+		}
+		records = append(records, Record{Source: source, Court: court})
+	}
+	return records, nil
+}
@@ -0,0 +1,282 @@
+package courtdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/user/tennis-connect/models"
+)
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string           `json:"type"`
+	ID         interface{}      `json:"id,omitempty"`
+	Geometry   *geoJSONGeometry `json:"geometry"`
+	Properties json.RawMessage  `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// geoJSONCourt is the properties of an exported court. Imports read the same
+// names, so an export can be edited and imported again.
+type geoJSONCourt struct {
+	ID          string   `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	CourtType   string   `json:"court_type"`
+	IsPublic    bool     `json:"is_public"`
+	Courts      int      `json:"courts"` // Number of playing courts at the facility
+	Amenities   []string `json:"amenities,omitempty"`
+	Website     string   `json:"website,omitempty"`
+	ContactInfo string   `json:"contact_info,omitempty"`
+	ImageURL    string   `json:"image_url,omitempty"`
+	City        string   `json:"city,omitempty"`
+	State       string   `json:"state,omitempty"`
+	ZipCode     string   `json:"zip_code,omitempty"`
+	Status      string   `json:"status,omitempty"`
+}
+
+// ReadGeoJSON reads courts from a GeoJSON FeatureCollection. Points are used
+// as they are and polygons by their centre. Properties are either our own
+// export's or OpenStreetMap tags, as in an Overpass export, in which case
+// features other than tennis pitches are skipped.
+func ReadGeoJSON(r io.Reader) ([]Record, error) {
+	var collection geoJSONFeatureCollection
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %w", err)
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("invalid GeoJSON: expected a FeatureCollection, got %q", collection.Type)
+	}
+
+	records := []Record{}
+	for i, feature := range collection.Features {
+		source := fmt.Sprintf("feature %d", i+1)
+		if feature.ID != nil {
+			source = fmt.Sprintf("feature %v", feature.ID)
+		}
+
+		var properties map[string]interface{}
+		if len(feature.Properties) > 0 && string(feature.Properties) != "null" {
+			if err := json.Unmarshal(feature.Properties, &properties); err != nil {
+				return nil, fmt.Errorf("%s: invalid properties: %w", source, err)
+			}
+		}
+		latitude, longitude, err := geometryCenter(feature.Geometry)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+
+		var court models.Court
+		if _, ok := properties["sport"]; ok {
+			tags := map[string]string{}
+			for key, value := range properties {
+				tags[key] = propertyString(value)
+			}
+			if !isTennisPitch(tags) {
+				continue
+			}
+			court = courtFromTags(tags, latitude, longitude)
+		} else {
+			court = courtFromProperties(properties, latitude, longitude)
+		}
+		records = append(records, Record{Source: source, Court: court})
+	}
+	return records, nil
+}
+
+// courtFromProperties builds a court from the properties we export
+func courtFromProperties(properties map[string]interface{}, latitude, longitude float64) models.Court {
+	court := models.Court{
+		Name:        propertyString(properties["name"]),
+		Description: propertyString(properties["description"]),
+		CourtType:   propertyString(properties["court_type"]),
+		IsPublic:    true,
+		Amenities:   propertyStrings(properties["amenities"]),
+		Website:     propertyString(properties["website"]),
+		ContactInfo: propertyString(properties["contact_info"]),
+		ImageURL:    propertyString(properties["image_url"]),
+		Location: models.Location{
+			Latitude:  latitude,
+			Longitude: longitude,
+			City:      propertyString(properties["city"]),
+			State:     propertyString(properties["state"]),
+			ZipCode:   propertyString(properties["zip_code"]),
+		},
+	}
+	if value, ok := properties["is_public"]; ok {
+		court.IsPublic = parseBool(propertyString(value), true)
+	}
+	if courts, err := strconv.Atoi(propertyString(properties["courts"])); err == nil && courts > 0 {
+		court.Units = models.DefaultCourtUnits(courts)
+	}
+	return court
+}
+
+// geometryCenter returns the point a geometry is imported at
+func geometryCenter(geometry *geoJSONGeometry) (float64, float64, error) {
+	if geometry == nil {
+		return 0, 0, fmt.Errorf("missing geometry")
+	}
+
+	var points [][2]float64
+	switch geometry.Type {
+	case "Point":
+		var point [2]float64
+		if err := json.Unmarshal(geometry.Coordinates, &point); err != nil {
+			return 0, 0, fmt.Errorf("invalid point: %w", err)
+		}
+		return point[1], point[0], nil
+	case "Polygon":
+		var rings [][][2]float64
+		if err := json.Unmarshal(geometry.Coordinates, &rings); err != nil || len(rings) == 0 {
+			return 0, 0, fmt.Errorf("invalid polygon")
+		}
+		points = ringPoints(rings[0])
+	case "MultiPolygon":
+		var polygons [][][][2]float64
+		if err := json.Unmarshal(geometry.Coordinates, &polygons); err != nil || len(polygons) == 0 {
+			return 0, 0, fmt.Errorf("invalid multipolygon")
+		}
+		for _, rings := range polygons {
+			if len(rings) > 0 {
+				points = append(points, ringPoints(rings[0])...)
+			}
+		}
+	default:
+		return 0, 0, fmt.Errorf("unsupported geometry %s", geometry.Type)
+	}
+
+	latitude, longitude, ok := centroid(points)
+	if !ok {
+		return 0, 0, fmt.Errorf("empty %s", strings.ToLower(geometry.Type))
+	}
+	return latitude, longitude, nil
+}
+
+// ringPoints drops the closing point of a ring, which repeats the first
+func ringPoints(ring [][2]float64) [][2]float64 {
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		return ring[:len(ring)-1]
+	}
+	return ring
+}
+
+// centroid returns the average of [longitude, latitude] points, which for
+// something the size of a court is as good as its true centre
+func centroid(points [][2]float64) (float64, float64, bool) {
+	if len(points) == 0 {
+		return 0, 0, false
+	}
+	var latitude, longitude float64
+	for _, point := range points {
+		longitude += point[0]
+		latitude += point[1]
+	}
+	return latitude / float64(len(points)), longitude / float64(len(points)), true
+}
+
+func propertyString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(value)
+}
+
+// propertyStrings reads a list given either as an array or a string
+// separated by semicolons, the OpenStreetMap convention
+func propertyStrings(value interface{}) []string {
+	var values []string
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			values = append(values, propertyString(item))
+		}
+	default:
+		values = strings.Split(propertyString(v), ";")
+	}
+
+	var result []string
+	for _, item := range values {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// parseBool reads yes/no style flags, returning fallback for anything else
+func parseBool(value string, fallback bool) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1":
+		return true
+	case "false", "no", "n", "0":
+		return false
+	}
+	return fallback
+}
+
+// WriteGeoJSON writes courts as a GeoJSON FeatureCollection of points
+func WriteGeoJSON(w io.Writer, courts []*models.Court) error {
+	type feature struct {
+		Type       string       `json:"type"`
+		ID         string       `json:"id"`
+		Geometry   interface{}  `json:"geometry"`
+		Properties geoJSONCourt `json:"properties"`
+	}
+	collection := struct {
+		Type     string    `json:"type"`
+		Features []feature `json:"features"`
+	}{Type: "FeatureCollection", Features: []feature{}}
+
+	for _, court := range courts {
+		collection.Features = append(collection.Features, feature{
+			Type: "Feature",
+			ID:   court.ID.String(),
+			Geometry: map[string]interface{}{
+				"type":        "Point",
+				"coordinates": [2]float64{court.Location.Longitude, court.Location.Latitude},
+			},
+			Properties: geoJSONCourt{
+				ID:          court.ID.String(),
+				Name:        court.Name,
+				Description: court.Description,
+				CourtType:   court.CourtType,
+				IsPublic:    court.IsPublic,
+				Courts:      len(court.Units),
+				Amenities:   court.Amenities,
+				Website:     court.Website,
+				ContactInfo: court.ContactInfo,
+				ImageURL:    court.ImageURL,
+				City:        court.Location.City,
+				State:       court.Location.State,
+				ZipCode:     court.Location.ZipCode,
+				Status:      court.Status,
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(collection); err != nil {
+		return fmt.Errorf("failed to write GeoJSON: %w", err)
+	}
+	return nil
+}
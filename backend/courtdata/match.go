@@ -0,0 +1,148 @@
+package courtdata
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/utils"
+)
+
+// How close two courts must be to be taken for the same place. Courts at the
+// same site are the same unless their names clearly differ; further apart
+// they are only the same if their names are alike.
+const (
+	SameSiteKm     = 0.05
+	MatchRadiusKm  = 0.25
+	SimilarNames   = 0.8
+	DifferentNames = 0.5
+)
+
+// genericNameWords say what a place is rather than which place it is, so
+// "Dolores Park Tennis Courts" and "Dolores" are alike, "Golden Gate Park"
+// and "Park Presidio" are not, and "Tennis Courts" on its own is no name at all
+var genericNameWords = map[string]bool{
+	"tennis": true, "court": true, "courts": true, "pitch": true, "pitches": true,
+	"center": true, "centre": true, "club": true, "complex": true, "facility": true,
+	"park": true, "playground": true, "public": true, "the": true, "at": true, "of": true, "and": true,
+}
+
+// nameWords returns the distinctive lowercase words of a court name
+func nameWords(name string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !genericNameWords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// NameSimilarity scores how alike two court names are from 0 to 1. A name
+// contained in the other, like "Dolores" in "Mission Dolores", scores 1; the
+// rest are scored by edit distance, which forgives typos. ok is false if
+// either name is generic, in which case names say nothing either way.
+func NameSimilarity(a, b string) (score float64, ok bool) {
+	wordsA, wordsB := nameWords(a), nameWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0, false
+	}
+
+	inB := map[string]bool{}
+	for _, word := range wordsB {
+		inB[word] = true
+	}
+	shared := 0
+	for _, word := range wordsA {
+		if inB[word] {
+			shared++
+		}
+	}
+	overlap := float64(shared) / math.Min(float64(len(wordsA)), float64(len(wordsB)))
+
+	joinedA, joinedB := []rune(strings.Join(wordsA, " ")), []rune(strings.Join(wordsB, " "))
+	longest := math.Max(float64(len(joinedA)), float64(len(joinedB)))
+	edits := 1 - float64(levenshtein(joinedA, joinedB))/longest
+	return math.Min(1, math.Max(overlap, edits)), true
+}
+
+// levenshtein returns the number of single-character edits turning a into b
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// SamePlace returns whether two courts are the same place, and the distance
+// between them in kilometres
+func SamePlace(a, b *models.Court) (bool, float64) {
+	km := utils.DistanceKm(a.Location.Latitude, a.Location.Longitude, b.Location.Latitude, b.Location.Longitude)
+	if km > MatchRadiusKm {
+		return false, km
+	}
+	score, named := NameSimilarity(a.Name, b.Name)
+	if km <= SameSiteKm {
+		return !named || score >= DifferentNames, km
+	}
+	return named && score >= SimilarNames, km
+}
+
+// gridCellDegrees is the size of a grid cell, about a kilometre north to south
+const gridCellDegrees = 0.01
+
+// grid buckets points by latitude and longitude so the points near another
+// can be found without comparing every pair
+type grid struct {
+	cells map[[2]int][]int
+}
+
+func newGrid() *grid {
+	return &grid{cells: map[[2]int][]int{}}
+}
+
+func gridCell(degrees float64) int {
+	return int(math.Floor(degrees / gridCellDegrees))
+}
+
+// add files item i at a point
+func (g *grid) add(i int, latitude, longitude float64) {
+	cell := [2]int{gridCell(latitude), gridCell(longitude)}
+	g.cells[cell] = append(g.cells[cell], i)
+}
+
+// near returns the items in the cells within radiusKm of a point. Some may be
+// further away, so callers still check the distance.
+func (g *grid) near(latitude, longitude, radiusKm float64) []int {
+	box := utils.NewBoundingBox(latitude, longitude, radiusKm)
+	lngRanges := [][2]float64{{box.MinLng, box.MaxLng}}
+	if box.CrossesAntimeridian() {
+		lngRanges = [][2]float64{{box.MinLng, 180}, {-180, box.MaxLng}}
+	}
+
+	var items []int
+	for row := gridCell(box.MinLat); row <= gridCell(box.MaxLat); row++ {
+		for _, lngRange := range lngRanges {
+			for col := gridCell(lngRange[0]); col <= gridCell(lngRange[1]); col++ {
+				items = append(items, g.cells[[2]int{row, col}]...)
+			}
+		}
+	}
+	return items
+}
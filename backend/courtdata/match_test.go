@@ -0,0 +1,63 @@
+package courtdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/user/tennis-connect/models"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		ok    bool
+		alike bool // Scores at least SimilarNames
+	}{
+		{"Dolores Park Tennis Courts", "Dolores Park", true, true},
+		{"Mission Dolores", "Dolores", true, true},
+		{"Golden Gate Park Tennis Centre", "Golden Gate Park Tennis Center", true, true},
+		{"Moscone Recreation Center", "Mosconi Recreation Centre", true, true},
+		{"Alice Marble Courts", "Park Presidio Courts", true, false},
+		{"Mission Bay", "Mission Dolores", true, false},
+		{"Tennis Courts", "Dolores Park", false, false},
+		{"", "Dolores Park", false, false},
+	}
+	for _, tt := range tests {
+		score, ok := NameSimilarity(tt.a, tt.b)
+		assert.Equal(t, tt.ok, ok, "%s / %s", tt.a, tt.b)
+		assert.Equal(t, tt.alike, score >= SimilarNames, "%s / %s scored %.2f", tt.a, tt.b, score)
+	}
+}
+
+func TestSamePlace(t *testing.T) {
+	court := func(name string, metersNorth float64) *models.Court {
+		return &models.Court{Name: name, Location: models.Location{Latitude: 37.7705 + metersNorth/111195, Longitude: -122.4588}}
+	}
+	base := court("Golden Gate Park Tennis Center", 0)
+
+	tests := []struct {
+		other *models.Court
+		same  bool
+	}{
+		{court("Golden Gate Park Tennis Center", 0), true},
+		{court("Tennis Courts", 30), true},                   // Unnamed on the same site
+		{court("Golden Gate Park Tennis Centre", 200), true}, // Alike, nearby
+		{court("Park Presidio Courts", 30), false},           // Named differently on the same site
+		{court("Tennis Courts", 100), false},                 // Unnamed, too far to tell
+		{court("Golden Gate Park Tennis Center", 300), false},
+	}
+	for _, tt := range tests {
+		same, km := SamePlace(base, tt.other)
+		assert.Equal(t, tt.same, same, "%s at %.0fm", tt.other.Name, km*1000)
+	}
+}
+
+func TestGridNear(t *testing.T) {
+	g := newGrid()
+	g.add(0, 37.7705, -122.4588)
+	g.add(1, 37.7805, -122.4588) // 1.1km north
+	g.add(2, -16.5, 179.999)     // Fiji, beside the antimeridian
+
+	assert.ElementsMatch(t, []int{0}, g.near(37.7706, -122.4589, 0.25))
+	assert.ElementsMatch(t, []int{2}, g.near(-16.5, -179.999, 0.25))
+}
@@ -0,0 +1,207 @@
+package courtdata
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/utils"
+)
+
+// PitchGroupKm is how close OpenStreetMap pitches must be to be taken for
+// courts at one facility. OpenStreetMap maps each court separately, while
+// our catalogue lists the facility with a unit per court.
+const PitchGroupKm = 0.06
+
+// osmPitch is a node or way tagged leisure=pitch, sport=tennis
+type osmPitch struct {
+	source    string // e.g. "way/123"
+	tags      map[string]string
+	refs      []int64 // A way's nodes
+	latitude  float64
+	longitude float64
+	located   bool
+}
+
+// locate places a way's pitch at the centre of its nodes. Nodes missing from
+// the extract, as happens at its edges, are left out; a way with none is
+// left unlocated and fails validation when the import is planned.
+func (p *osmPitch) locate(nodes func(id int64) (float64, float64, bool)) {
+	if p.located {
+		return
+	}
+	refs := p.refs
+	if len(refs) > 1 && refs[0] == refs[len(refs)-1] {
+		refs = refs[:len(refs)-1]
+	}
+	var points [][2]float64
+	for _, ref := range refs {
+		if latitude, longitude, ok := nodes(ref); ok {
+			points = append(points, [2]float64{longitude, latitude})
+		}
+	}
+	p.latitude, p.longitude, p.located = centroid(points)
+}
+
+type osmXMLTag struct {
+	Key   string `xml:"k,attr"`
+	Value string `xml:"v,attr"`
+}
+
+type osmXMLNode struct {
+	ID   int64       `xml:"id,attr"`
+	Lat  float64     `xml:"lat,attr"`
+	Lon  float64     `xml:"lon,attr"`
+	Tags []osmXMLTag `xml:"tag"`
+}
+
+type osmXMLWay struct {
+	ID    int64 `xml:"id,attr"`
+	Nodes []struct {
+		Ref int64 `xml:"ref,attr"`
+	} `xml:"nd"`
+	Tags []osmXMLTag `xml:"tag"`
+}
+
+func osmXMLTags(tags []osmXMLTag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[tag.Key] = tag.Value
+	}
+	return result
+}
+
+// ReadOSM reads the tennis courts from an OpenStreetMap XML extract. Every
+// node's position is held in memory to place the courts mapped as ways, which
+// is fine for the city-sized extracts XML is used for; use PBF for larger
+// ones. Courts mapped as multipolygon relations are rare and are skipped.
+func ReadOSM(r io.Reader) ([]Record, error) {
+	decoder := xml.NewDecoder(r)
+	nodes := map[int64][2]float64{}
+	var pitches []osmPitch
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid OSM XML: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "node":
+			var node osmXMLNode
+			if err := decoder.DecodeElement(&node, &start); err != nil {
+				return nil, fmt.Errorf("invalid OSM XML node: %w", err)
+			}
+			nodes[node.ID] = [2]float64{node.Lat, node.Lon}
+			if tags := osmXMLTags(node.Tags); isTennisPitch(tags) {
+				pitches = append(pitches, osmPitch{
+					source: fmt.Sprintf("node/%d", node.ID), tags: tags,
+					latitude: node.Lat, longitude: node.Lon, located: true,
+				})
+			}
+		case "way":
+			var way osmXMLWay
+			if err := decoder.DecodeElement(&way, &start); err != nil {
+				return nil, fmt.Errorf("invalid OSM XML way: %w", err)
+			}
+			if tags := osmXMLTags(way.Tags); isTennisPitch(tags) {
+				pitch := osmPitch{source: fmt.Sprintf("way/%d", way.ID), tags: tags}
+				for _, nd := range way.Nodes {
+					pitch.refs = append(pitch.refs, nd.Ref)
+				}
+				pitches = append(pitches, pitch)
+			}
+		}
+	}
+
+	for i := range pitches {
+		pitches[i].locate(func(id int64) (float64, float64, bool) {
+			node, ok := nodes[id]
+			return node[0], node[1], ok
+		})
+	}
+	return groupPitches(pitches), nil
+}
+
+// groupPitches combines neighbouring pitches into facilities. Pitches within
+// PitchGroupKm of each other belong together unless both are named and the
+// names differ.
+func groupPitches(pitches []osmPitch) []Record {
+	parent := make([]int, len(pitches))
+	names := make([]string, len(pitches)) // The group's name, kept by its root
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+
+	neighbours := newGrid()
+	for i, pitch := range pitches {
+		parent[i] = i
+		names[i] = strings.Join(nameWords(pitch.tags["name"]), " ")
+		if !pitch.located {
+			continue
+		}
+		for _, j := range neighbours.near(pitch.latitude, pitch.longitude, PitchGroupKm) {
+			if utils.DistanceKm(pitch.latitude, pitch.longitude, pitches[j].latitude, pitches[j].longitude) > PitchGroupKm {
+				continue
+			}
+			rootI, rootJ := find(i), find(j)
+			if rootI == rootJ || names[rootI] != "" && names[rootJ] != "" && names[rootI] != names[rootJ] {
+				continue
+			}
+			parent[rootI] = rootJ
+			if names[rootJ] == "" {
+				names[rootJ] = names[rootI]
+			}
+		}
+		neighbours.add(i, pitch.latitude, pitch.longitude)
+	}
+
+	// Groups are listed in the order their first pitch appeared
+	var roots []int
+	members := map[int][]int{}
+	for i := range pitches {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+
+	records := make([]Record, 0, len(roots))
+	for _, root := range roots {
+		group := members[root]
+		var sources []string
+		var court models.Court
+		var latitude, longitude float64
+		for n, i := range group {
+			pitch := pitches[i]
+			sources = append(sources, pitch.source)
+			latitude += pitch.latitude
+			longitude += pitch.longitude
+			pitchCourt := courtFromTags(pitch.tags, pitch.latitude, pitch.longitude)
+			if n == 0 {
+				court = pitchCourt
+			} else {
+				fillMissing(&court, &pitchCourt)
+			}
+		}
+		court.Location.Latitude = latitude / float64(len(group))
+		court.Location.Longitude = longitude / float64(len(group))
+		court.Units = models.DefaultCourtUnits(len(group))
+		records = append(records, Record{Source: strings.Join(sources, ","), Court: court})
+	}
+	return records
+}
@@ -0,0 +1,458 @@
+package courtdata
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Size limits from the PBF format specification
+const (
+	maxPBFHeaderSize = 64 * 1024
+	maxPBFBlobSize   = 32 * 1024 * 1024
+)
+
+var errInvalidPBF = errors.New("invalid OSM PBF data")
+
+// pbfSupportedFeatures are the required features of an extract we can read
+var pbfSupportedFeatures = map[string]bool{
+	"OsmSchema-V0.6": true,
+	"DenseNodes":     true,
+}
+
+// pbfVisitor receives the elements of an extract. tags is nil unless
+// withTags is set, which saves decoding them when only positions are wanted.
+type pbfVisitor struct {
+	withTags bool
+	node     func(id int64, latitude, longitude float64, tags map[string]string)
+	way      func(id int64, refs []int64, tags map[string]string)
+}
+
+// ReadOSMPBF reads the tennis courts from an OpenStreetMap PBF extract. The
+// extract is read twice: first for the courts, then for the positions of
+// just the nodes outlining them, so memory use depends on the number of
+// courts rather than the size of the extract.
+func ReadOSMPBF(r io.ReadSeeker) ([]Record, error) {
+	var pitches []osmPitch
+	outline := map[int64]bool{}
+	err := readPBF(r, &pbfVisitor{
+		withTags: true,
+		node: func(id int64, latitude, longitude float64, tags map[string]string) {
+			if isTennisPitch(tags) {
+				pitches = append(pitches, osmPitch{
+					source: fmt.Sprintf("node/%d", id), tags: tags,
+					latitude: latitude, longitude: longitude, located: true,
+				})
+			}
+		},
+		way: func(id int64, refs []int64, tags map[string]string) {
+			if isTennisPitch(tags) {
+				pitches = append(pitches, osmPitch{source: fmt.Sprintf("way/%d", id), tags: tags, refs: refs})
+				for _, ref := range refs {
+					outline[ref] = true
+				}
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := map[int64][2]float64{}
+	if len(outline) > 0 {
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to rewind extract: %w", err)
+		}
+		err = readPBF(r, &pbfVisitor{
+			node: func(id int64, latitude, longitude float64, tags map[string]string) {
+				if outline[id] {
+					nodes[id] = [2]float64{latitude, longitude}
+				}
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range pitches {
+		pitches[i].locate(func(id int64) (float64, float64, bool) {
+			node, ok := nodes[id]
+			return node[0], node[1], ok
+		})
+	}
+	return groupPitches(pitches), nil
+}
+
+// readPBF reads an extract's blobs in turn, passing the elements in each data
+// block to the visitor
+func readPBF(r io.Reader, visitor *pbfVisitor) error {
+	var sizeBuf [4]byte
+	for {
+		if _, err := io.ReadFull(r, sizeBuf[:]); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read OSM PBF: %w", err)
+		}
+		headerSize := binary.BigEndian.Uint32(sizeBuf[:])
+		if headerSize > maxPBFHeaderSize {
+			return errInvalidPBF
+		}
+		header := make([]byte, headerSize)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return fmt.Errorf("failed to read OSM PBF: %w", err)
+		}
+
+		var blobType string
+		var blobSize uint64
+		err := pbfFields(header, func(num protowire.Number, typ protowire.Type, value uint64, data []byte) error {
+			switch num {
+			case 1:
+				blobType = string(data)
+			case 3:
+				blobSize = value
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if blobSize > maxPBFBlobSize {
+			return errInvalidPBF
+		}
+		blob := make([]byte, blobSize)
+		if _, err := io.ReadFull(r, blob); err != nil {
+			return fmt.Errorf("failed to read OSM PBF: %w", err)
+		}
+
+		switch blobType {
+		case "OSMHeader":
+			data, err := pbfBlobData(blob)
+			if err != nil {
+				return err
+			}
+			if err := checkPBFHeader(data); err != nil {
+				return err
+			}
+		case "OSMData":
+			data, err := pbfBlobData(blob)
+			if err != nil {
+				return err
+			}
+			if err := readPBFBlock(data, visitor); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// pbfBlobData returns a blob's contents, decompressing them if need be
+func pbfBlobData(blob []byte) ([]byte, error) {
+	var raw, compressed []byte
+	var rawSize uint64
+	err := pbfFields(blob, func(num protowire.Number, typ protowire.Type, value uint64, data []byte) error {
+		switch num {
+		case 1:
+			raw = data
+		case 2:
+			rawSize = value
+		case 3:
+			compressed = data
+		case 4, 5, 6, 7:
+			return fmt.Errorf("unsupported OSM PBF compression; convert the extract to zlib, e.g. with osmium")
+		}
+		return nil
+	})
+	if err != nil || raw != nil {
+		return raw, err
+	}
+	if rawSize > maxPBFBlobSize {
+		return nil, errInvalidPBF
+	}
+
+	reader, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress OSM PBF: %w", err)
+	}
+	defer reader.Close()
+	data := make([]byte, rawSize)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, fmt.Errorf("failed to decompress OSM PBF: %w", err)
+	}
+	return data, nil
+}
+
+// checkPBFHeader refuses extracts that need features we don't read, such as
+// history files
+func checkPBFHeader(data []byte) error {
+	return pbfFields(data, func(num protowire.Number, typ protowire.Type, value uint64, field []byte) error {
+		if num == 4 && !pbfSupportedFeatures[string(field)] {
+			return fmt.Errorf("OSM PBF extract requires unsupported feature %s", field)
+		}
+		return nil
+	})
+}
+
+// pbfBlock is the context shared by the elements of a data block
+type pbfBlock struct {
+	strings     []string
+	granularity int64
+	latOffset   int64
+	lonOffset   int64
+}
+
+func (b *pbfBlock) latitude(value int64) float64 {
+	return 1e-9 * float64(b.latOffset+b.granularity*value)
+}
+
+func (b *pbfBlock) longitude(value int64) float64 {
+	return 1e-9 * float64(b.lonOffset+b.granularity*value)
+}
+
+// tags looks up key and value string indexes in the block's string table
+func (b *pbfBlock) tags(keys, values []uint64) (map[string]string, error) {
+	if len(keys) != len(values) {
+		return nil, errInvalidPBF
+	}
+	tags := make(map[string]string, len(keys))
+	for i := range keys {
+		if keys[i] >= uint64(len(b.strings)) || values[i] >= uint64(len(b.strings)) {
+			return nil, errInvalidPBF
+		}
+		tags[b.strings[keys[i]]] = b.strings[values[i]]
+	}
+	return tags, nil
+}
+
+// readPBFBlock passes the nodes and ways in a PrimitiveBlock to the visitor
+func readPBFBlock(data []byte, visitor *pbfVisitor) error {
+	block := &pbfBlock{granularity: 100}
+	var groups [][]byte
+	err := pbfFields(data, func(num protowire.Number, typ protowire.Type, value uint64, field []byte) error {
+		switch num {
+		case 1:
+			return pbfFields(field, func(num protowire.Number, typ protowire.Type, value uint64, s []byte) error {
+				if num == 1 {
+					block.strings = append(block.strings, string(s))
+				}
+				return nil
+			})
+		case 2:
+			groups = append(groups, field)
+		case 17:
+			block.granularity = int64(value)
+		case 19:
+			block.latOffset = int64(value)
+		case 20:
+			block.lonOffset = int64(value)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		err := pbfFields(group, func(num protowire.Number, typ protowire.Type, value uint64, field []byte) error {
+			switch num {
+			case 1:
+				return block.readNode(field, visitor)
+			case 2:
+				return block.readDenseNodes(field, visitor)
+			case 3:
+				return block.readWay(field, visitor)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *pbfBlock) readNode(data []byte, visitor *pbfVisitor) error {
+	if visitor.node == nil {
+		return nil
+	}
+	var id, lat, lon int64
+	var keys, values []uint64
+	err := pbfFields(data, func(num protowire.Number, typ protowire.Type, value uint64, field []byte) error {
+		var err error
+		switch num {
+		case 1:
+			id = protowire.DecodeZigZag(value)
+		case 2:
+			keys, err = pbfVarints(keys, typ, value, field)
+		case 3:
+			values, err = pbfVarints(values, typ, value, field)
+		case 8:
+			lat = protowire.DecodeZigZag(value)
+		case 9:
+			lon = protowire.DecodeZigZag(value)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var tags map[string]string
+	if visitor.withTags {
+		if tags, err = b.tags(keys, values); err != nil {
+			return err
+		}
+	}
+	visitor.node(id, b.latitude(lat), b.longitude(lon), tags)
+	return nil
+}
+
+// readDenseNodes reads delta-coded nodes, whose tags are a single list of
+// key and value indexes with a zero after each node's
+func (b *pbfBlock) readDenseNodes(data []byte, visitor *pbfVisitor) error {
+	if visitor.node == nil {
+		return nil
+	}
+	var ids, lats, lons, keysValues []uint64
+	err := pbfFields(data, func(num protowire.Number, typ protowire.Type, value uint64, field []byte) error {
+		var err error
+		switch num {
+		case 1:
+			ids, err = pbfVarints(ids, typ, value, field)
+		case 8:
+			lats, err = pbfVarints(lats, typ, value, field)
+		case 9:
+			lons, err = pbfVarints(lons, typ, value, field)
+		case 10:
+			keysValues, err = pbfVarints(keysValues, typ, value, field)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(lats) != len(ids) || len(lons) != len(ids) {
+		return errInvalidPBF
+	}
+
+	var id, lat, lon int64
+	next := 0
+	for i := range ids {
+		id += protowire.DecodeZigZag(ids[i])
+		lat += protowire.DecodeZigZag(lats[i])
+		lon += protowire.DecodeZigZag(lons[i])
+
+		var keys, values []uint64
+		for next < len(keysValues) && keysValues[next] != 0 {
+			if next+1 >= len(keysValues) {
+				return errInvalidPBF
+			}
+			keys = append(keys, keysValues[next])
+			values = append(values, keysValues[next+1])
+			next += 2
+		}
+		next++ // The zero ending this node's tags
+
+		var tags map[string]string
+		if visitor.withTags {
+			if tags, err = b.tags(keys, values); err != nil {
+				return err
+			}
+		}
+		visitor.node(id, b.latitude(lat), b.longitude(lon), tags)
+	}
+	return nil
+}
+
+func (b *pbfBlock) readWay(data []byte, visitor *pbfVisitor) error {
+	if visitor.way == nil {
+		return nil
+	}
+	var id int64
+	var keys, values, deltas []uint64
+	err := pbfFields(data, func(num protowire.Number, typ protowire.Type, value uint64, field []byte) error {
+		var err error
+		switch num {
+		case 1:
+			id = int64(value)
+		case 2:
+			keys, err = pbfVarints(keys, typ, value, field)
+		case 3:
+			values, err = pbfVarints(values, typ, value, field)
+		case 8:
+			deltas, err = pbfVarints(deltas, typ, value, field)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	refs := make([]int64, len(deltas))
+	var ref int64
+	for i, delta := range deltas {
+		ref += protowire.DecodeZigZag(delta)
+		refs[i] = ref
+	}
+	var tags map[string]string
+	if visitor.withTags {
+		if tags, err = b.tags(keys, values); err != nil {
+			return err
+		}
+	}
+	visitor.way(id, refs, tags)
+	return nil
+}
+
+// pbfFields calls fn with each field of a protocol buffer message: its
+// number, wire type, and its value if a varint or its bytes if length-delimited
+func pbfFields(data []byte, fn func(num protowire.Number, typ protowire.Type, value uint64, field []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return errInvalidPBF
+		}
+		data = data[n:]
+
+		var value uint64
+		var field []byte
+		switch typ {
+		case protowire.VarintType:
+			value, n = protowire.ConsumeVarint(data)
+		case protowire.BytesType:
+			field, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return errInvalidPBF
+		}
+		data = data[n:]
+		if err := fn(num, typ, value, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pbfVarints appends the values of a repeated varint field, which encoders
+// almost always pack but may not
+func pbfVarints(values []uint64, typ protowire.Type, value uint64, packed []byte) ([]uint64, error) {
+	if typ == protowire.VarintType {
+		return append(values, value), nil
+	}
+	for len(packed) > 0 {
+		v, n := protowire.ConsumeVarint(packed)
+		if n < 0 {
+			return nil, errInvalidPBF
+		}
+		values = append(values, v)
+		packed = packed[n:]
+	}
+	return values, nil
+}
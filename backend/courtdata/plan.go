@@ -0,0 +1,246 @@
+package courtdata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+// What importing a record does
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"    // Fills in details an existing court is missing
+	ActionUnchanged = "unchanged" // An existing court already has everything the record does
+	ActionDuplicate = "duplicate" // Repeats an earlier record in the same import
+	ActionInvalid   = "invalid"
+)
+
+// Catalogue finds existing courts to match imports against
+type Catalogue interface {
+	// FindNear returns the courts within radiusKm of a point, listed or not
+	FindNear(ctx context.Context, latitude, longitude, radiusKm float64) ([]*models.Court, error)
+}
+
+// Store saves imported courts
+type Store interface {
+	CreateImported(ctx context.Context, court *models.Court, edit *models.CourtEdit) error
+	Update(ctx context.Context, court *models.Court, edit *models.CourtEdit) error
+}
+
+// PlanEntry is what importing one record will do
+type PlanEntry struct {
+	Action         string                             `json:"action"`
+	Source         string                             `json:"source"`
+	Court          *models.Court                      `json:"court,omitempty"`      // The court as it will be saved
+	MatchedID      *uuid.UUID                         `json:"matched_id,omitempty"` // Existing court at the same place
+	MatchedName    string                             `json:"matched_name,omitempty"`
+	DuplicateOf    string                             `json:"duplicate_of,omitempty"`    // Source of the earlier record it repeats
+	DistanceMeters int                                `json:"distance_meters,omitempty"` // From the matched court or record
+	Changes        map[string]models.CourtFieldChange `json:"changes,omitempty"`         // What an update fills in
+	Error          string                             `json:"error,omitempty"`           // Why the record is invalid
+}
+
+// Plan is the diff report for an import, with an entry per record in the
+// order they were read
+type Plan struct {
+	Entries []PlanEntry    `json:"entries"`
+	Summary map[string]int `json:"summary"` // Number of entries per action
+}
+
+// NewPlan works out what importing records would do. Each record is matched
+// against the courts already in the catalogue and the records before it:
+// the same place already in the catalogue is updated with any details it is
+// missing, and the same place earlier in the import is a duplicate. Nothing
+// is saved until the plan is applied.
+func NewPlan(ctx context.Context, catalogue Catalogue, records []Record) (*Plan, error) {
+	plan := &Plan{Entries: []PlanEntry{}, Summary: map[string]int{}}
+	created := newGrid()           // Entries creating courts, by location
+	updated := map[uuid.UUID]int{} // Entries updating each existing court
+
+	for _, record := range records {
+		court := record.Court
+		normalize(&court)
+		entry := PlanEntry{Source: record.Source, Court: &court}
+		if err := court.Validate(); err != nil {
+			entry.Action, entry.Court, entry.Error = ActionInvalid, nil, err.Error()
+			plan.add(entry)
+			continue
+		}
+
+		existing, km, err := findExisting(ctx, catalogue, &court)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			entry.MatchedID, entry.MatchedName, entry.DistanceMeters = &existing.ID, existing.Name, meters(km)
+			if earlier, ok := updated[existing.ID]; ok {
+				entry.Action, entry.Court, entry.DuplicateOf = ActionDuplicate, nil, plan.Entries[earlier].Source
+				plan.add(entry)
+				continue
+			}
+			updated[existing.ID] = len(plan.Entries)
+
+			merged := *existing
+			merged.Amenities = append([]string{}, existing.Amenities...)
+			fillMissing(&merged, &court)
+			entry.Court, entry.Changes = &merged, models.DiffCourts(existing, &merged)
+			entry.Action = ActionUpdate
+			if len(entry.Changes) == 0 {
+				entry.Action, entry.Changes = ActionUnchanged, nil
+			}
+			plan.add(entry)
+			continue
+		}
+
+		if earlier, km := plan.findCreated(created, &court); earlier != nil {
+			entry.Action, entry.Court = ActionDuplicate, nil
+			entry.DuplicateOf, entry.DistanceMeters = earlier.Source, meters(km)
+			plan.add(entry)
+			continue
+		}
+		entry.Action = ActionCreate
+		created.add(len(plan.Entries), court.Location.Latitude, court.Location.Longitude)
+		plan.add(entry)
+	}
+	return plan, nil
+}
+
+func (p *Plan) add(entry PlanEntry) {
+	p.Entries = append(p.Entries, entry)
+	p.Summary[entry.Action]++
+}
+
+// findExisting returns the nearest court in the catalogue that is the same place
+func findExisting(ctx context.Context, catalogue Catalogue, court *models.Court) (*models.Court, float64, error) {
+	candidates, err := catalogue.FindNear(ctx, court.Location.Latitude, court.Location.Longitude, MatchRadiusKm)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to look for existing courts: %w", err)
+	}
+	var best *models.Court
+	bestKm := 0.0
+	for _, candidate := range candidates {
+		if same, km := SamePlace(court, candidate); same && (best == nil || km < bestKm) {
+			best, bestKm = candidate, km
+		}
+	}
+	return best, bestKm, nil
+}
+
+// findCreated returns the nearest court the plan already creates that is the same place
+func (p *Plan) findCreated(created *grid, court *models.Court) (*PlanEntry, float64) {
+	var best *PlanEntry
+	bestKm := 0.0
+	for _, i := range created.near(court.Location.Latitude, court.Location.Longitude, MatchRadiusKm) {
+		if same, km := SamePlace(court, p.Entries[i].Court); same && (best == nil || km < bestKm) {
+			best, bestKm = &p.Entries[i], km
+		}
+	}
+	return best, bestKm
+}
+
+func meters(km float64) int {
+	return int(math.Round(km * 1000))
+}
+
+// fillMissing copies the details court lacks from other and adds other's
+// amenities. Details court already has are kept, so an import never
+// overwrites what players and managers have entered.
+func fillMissing(court, other *models.Court) {
+	fill := func(field *string, value string) {
+		if strings.TrimSpace(*field) == "" {
+			*field = value
+		}
+	}
+	if court.Name == DefaultName && other.Name != "" {
+		court.Name = other.Name
+	}
+	fill(&court.Name, other.Name)
+	fill(&court.Description, other.Description)
+	fill(&court.CourtType, other.CourtType)
+	fill(&court.ImageURL, other.ImageURL)
+	fill(&court.Website, other.Website)
+	fill(&court.ContactInfo, other.ContactInfo)
+	fill(&court.Location.City, other.Location.City)
+	fill(&court.Location.State, other.Location.State)
+	fill(&court.Location.ZipCode, other.Location.ZipCode)
+
+	have := map[string]bool{}
+	for _, amenity := range court.Amenities {
+		have[strings.ToLower(amenity)] = true
+	}
+	for _, amenity := range other.Amenities {
+		if !have[strings.ToLower(amenity)] {
+			court.Amenities = append(court.Amenities, amenity)
+			have[strings.ToLower(amenity)] = true
+		}
+	}
+}
+
+// Apply saves the plan's new and updated courts, crediting userID in their
+// history along with the file they came from. It stops at the first failure,
+// returning how many entries were saved; since imports are matched against
+// what is already there, running the import again picks up where it stopped.
+func (p *Plan) Apply(ctx context.Context, store Store, userID uuid.UUID, filename string) (int, error) {
+	saved := 0
+	for _, entry := range p.Entries {
+		edit := &models.CourtEdit{UserID: userID, Note: fmt.Sprintf("Imported from %s (%s)", filename, entry.Source)}
+		var err error
+		switch entry.Action {
+		case ActionCreate:
+			err = store.CreateImported(ctx, entry.Court, edit)
+		case ActionUpdate:
+			edit.Changes = entry.Changes
+			err = store.Update(ctx, entry.Court, edit)
+		default:
+			continue
+		}
+		if err != nil {
+			return saved, fmt.Errorf("failed to import %s: %w", entry.Source, err)
+		}
+		saved++
+	}
+	return saved, nil
+}
+
+// WriteText writes the plan as a readable diff: "+" for courts to create,
+// "~" for updates with their changes, "=" for unchanged, "-" for duplicates
+// and "!" for invalid records, followed by a summary
+func (p *Plan) WriteText(w io.Writer) error {
+	var b strings.Builder
+	for _, entry := range p.Entries {
+		switch entry.Action {
+		case ActionCreate:
+			court := entry.Court
+			fmt.Fprintf(&b, "+ %s: create %q (%s) at %.6f, %.6f with %d court(s)\n", entry.Source, court.Name,
+				court.CourtType, court.Location.Latitude, court.Location.Longitude, len(court.Units))
+		case ActionUpdate:
+			fmt.Fprintf(&b, "~ %s: update %q (%s, %dm away)\n", entry.Source, entry.MatchedName, entry.MatchedID, entry.DistanceMeters)
+			fields := make([]string, 0, len(entry.Changes))
+			for field := range entry.Changes {
+				fields = append(fields, field)
+			}
+			sort.Strings(fields)
+			for _, field := range fields {
+				change := entry.Changes[field]
+				fmt.Fprintf(&b, "    %s: %v -> %v\n", field, change.From, change.To)
+			}
+		case ActionUnchanged:
+			fmt.Fprintf(&b, "= %s: unchanged %q (%s, %dm away)\n", entry.Source, entry.MatchedName, entry.MatchedID, entry.DistanceMeters)
+		case ActionDuplicate:
+			fmt.Fprintf(&b, "- %s: duplicate of %s\n", entry.Source, entry.DuplicateOf)
+		case ActionInvalid:
+			fmt.Fprintf(&b, "! %s: invalid: %s\n", entry.Source, entry.Error)
+		}
+	}
+	fmt.Fprintf(&b, "\n%d to create, %d to update, %d unchanged, %d duplicate, %d invalid\n",
+		p.Summary[ActionCreate], p.Summary[ActionUpdate], p.Summary[ActionUnchanged],
+		p.Summary[ActionDuplicate], p.Summary[ActionInvalid])
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,177 @@
+package courtdata
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/utils"
+)
+
+// fakeCatalogue is an in-memory Catalogue and Store
+type fakeCatalogue struct {
+	courts  []*models.Court
+	created []*models.Court
+	updated []*models.Court
+	edits   []*models.CourtEdit
+	fail    error
+}
+
+func (f *fakeCatalogue) FindNear(ctx context.Context, latitude, longitude, radiusKm float64) ([]*models.Court, error) {
+	var near []*models.Court
+	for _, court := range f.courts {
+		if utils.DistanceKm(latitude, longitude, court.Location.Latitude, court.Location.Longitude) <= radiusKm {
+			near = append(near, court)
+		}
+	}
+	return near, nil
+}
+
+func (f *fakeCatalogue) CreateImported(ctx context.Context, court *models.Court, edit *models.CourtEdit) error {
+	if f.fail != nil {
+		return f.fail
+	}
+	f.created = append(f.created, court)
+	f.edits = append(f.edits, edit)
+	return nil
+}
+
+func (f *fakeCatalogue) Update(ctx context.Context, court *models.Court, edit *models.CourtEdit) error {
+	if f.fail != nil {
+		return f.fail
+	}
+	f.updated = append(f.updated, court)
+	f.edits = append(f.edits, edit)
+	return nil
+}
+
+// sampleCatalogue has two of the courts in sample.csv already
+func sampleCatalogue() *fakeCatalogue {
+	return &fakeCatalogue{courts: []*models.Court{
+		{
+			ID: uuid.New(), Name: "Moscone Rec Center Tennis Courts", CourtType: "Hard", IsPublic: true,
+			Location: models.Location{Latitude: 37.8012, Longitude: -122.4335, City: "San Francisco", ZipCode: "94123"},
+			Status:   models.CourtStatusApproved,
+		},
+		{
+			ID: uuid.New(), Name: "Mountain Lake Park", CourtType: "Hard", IsPublic: true,
+			Location: models.Location{Latitude: 37.7876, Longitude: -122.4707, City: "San Francisco", State: "CA", ZipCode: "94118"},
+			Status:   models.CourtStatusApproved,
+		},
+	}}
+}
+
+func TestNewPlan(t *testing.T) {
+	catalogue := sampleCatalogue()
+	records := readSample(t, "sample.csv", FormatCSV)
+	records = append(records,
+		Record{Source: "line 6", Court: models.Court{Location: models.Location{Latitude: 37.7656, Longitude: -122.4530}}},
+		Record{Source: "line 7", Court: models.Court{Name: "Moscone Recreation Center", Location: models.Location{Latitude: 37.8013, Longitude: -122.4334}}},
+	)
+
+	plan, err := NewPlan(context.Background(), catalogue, records)
+	require.NoError(t, err)
+	require.Len(t, plan.Entries, 6)
+
+	moscone := plan.Entries[0]
+	assert.Equal(t, ActionUpdate, moscone.Action)
+	assert.Equal(t, catalogue.courts[0].ID, *moscone.MatchedID)
+	assert.Equal(t, 14, moscone.DistanceMeters)
+	assert.Equal(t, "Moscone Rec Center Tennis Courts", moscone.Court.Name, "existing details are kept")
+	assert.ElementsMatch(t, []string{"amenities", "website", "location"}, keys(moscone.Changes))
+	assert.Empty(t, catalogue.courts[0].Amenities, "the catalogue's court is left alone")
+
+	assert.Equal(t, ActionUnchanged, plan.Entries[1].Action)
+	assert.Nil(t, plan.Entries[1].Changes)
+
+	unnamed := plan.Entries[2]
+	assert.Equal(t, ActionCreate, unnamed.Action)
+	assert.Equal(t, DefaultName, unnamed.Court.Name)
+	assert.Equal(t, DefaultCourtType, unnamed.Court.CourtType)
+	assert.Len(t, unnamed.Court.Units, 1)
+
+	assert.Equal(t, ActionInvalid, plan.Entries[3].Action)
+	assert.Equal(t, "location is out of range", plan.Entries[3].Error)
+
+	assert.Equal(t, ActionDuplicate, plan.Entries[4].Action)
+	assert.Equal(t, "line 4", plan.Entries[4].DuplicateOf)
+	assert.Equal(t, 11, plan.Entries[4].DistanceMeters)
+
+	assert.Equal(t, ActionDuplicate, plan.Entries[5].Action)
+	assert.Equal(t, "line 2", plan.Entries[5].DuplicateOf, "an existing court is only updated once")
+
+	assert.Equal(t, map[string]int{
+		ActionUpdate: 1, ActionUnchanged: 1, ActionCreate: 1, ActionInvalid: 1, ActionDuplicate: 2,
+	}, plan.Summary)
+
+	var report bytes.Buffer
+	require.NoError(t, plan.WriteText(&report))
+	assert.Contains(t, report.String(), `~ line 2: update "Moscone Rec Center Tennis Courts"`)
+	assert.Contains(t, report.String(), "    website:  -> https://sfrecpark.org/moscone\n")
+	assert.Contains(t, report.String(), `+ line 4: create "Tennis Courts" (Hard) at 37.765500, -122.453000 with 1 court(s)`)
+	assert.Contains(t, report.String(), "! line 5: invalid: location is out of range\n")
+	assert.Contains(t, report.String(), "- line 6: duplicate of line 4\n")
+	assert.Contains(t, report.String(), "1 to create, 1 to update, 1 unchanged, 2 duplicate, 1 invalid\n")
+}
+
+func TestNewPlanFromOSM(t *testing.T) {
+	catalogue := &fakeCatalogue{courts: []*models.Court{{
+		ID: uuid.New(), Name: "Golden Gate Park Tennis Centre", CourtType: "Hard", IsPublic: true,
+		Location: models.Location{Latitude: 37.7704, Longitude: -122.4589},
+		Status:   models.CourtStatusApproved,
+	}}}
+
+	plan, err := NewPlan(context.Background(), catalogue, readSample(t, "sample.osm.pbf", FormatOSMPBF))
+	require.NoError(t, err)
+
+	var actions []string
+	for _, entry := range plan.Entries {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{ActionCreate, ActionUpdate, ActionCreate, ActionCreate, ActionInvalid}, actions)
+	assert.Equal(t, "location is required", plan.Entries[4].Error)
+
+	// The Park Presidio court is near the Golden Gate Park one but named differently
+	assert.Equal(t, "Park Presidio Courts", plan.Entries[2].Court.Name)
+	assert.Len(t, plan.Entries[3].Court.Units, 2)
+}
+
+func TestPlanApply(t *testing.T) {
+	catalogue := sampleCatalogue()
+	plan, err := NewPlan(context.Background(), catalogue, readSample(t, "sample.csv", FormatCSV))
+	require.NoError(t, err)
+	moderatorID := uuid.New()
+
+	saved, err := plan.Apply(context.Background(), catalogue, moderatorID, "sample.csv")
+	require.NoError(t, err)
+	assert.Equal(t, 2, saved)
+	require.Len(t, catalogue.updated, 1)
+	assert.Equal(t, catalogue.courts[0].ID, catalogue.updated[0].ID)
+	require.Len(t, catalogue.created, 1)
+	assert.Equal(t, DefaultName, catalogue.created[0].Name)
+
+	require.Len(t, catalogue.edits, 2)
+	assert.Equal(t, moderatorID, catalogue.edits[0].UserID)
+	assert.Equal(t, "Imported from sample.csv (line 2)", catalogue.edits[0].Note)
+	assert.Contains(t, catalogue.edits[0].Changes, "amenities")
+	assert.Equal(t, "Imported from sample.csv (line 4)", catalogue.edits[1].Note)
+
+	failing := sampleCatalogue()
+	failing.fail = errors.New("connection reset")
+	saved, err = plan.Apply(context.Background(), failing, moderatorID, "sample.csv")
+	assert.Equal(t, 0, saved)
+	assert.EqualError(t, err, "failed to import line 2: connection reset")
+}
+
+func keys(changes map[string]models.CourtFieldChange) []string {
+	var result []string
+	for key := range changes {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// BigDataCloudGeocoder uses BigDataCloud's free geocoding API
+type BigDataCloudGeocoder struct {
+	client  *http.Client
+	BaseURL string // Overridable for tests
+}
+
+// NewBigDataCloudGeocoder creates a BigDataCloudGeocoder. The client's
+// timeout bounds every lookup.
+func NewBigDataCloudGeocoder(client *http.Client) *BigDataCloudGeocoder {
+	return &BigDataCloudGeocoder{
+		client:  client,
+		BaseURL: "https://api.bigdatacloud.net/data",
+	}
+}
+
+// Name implements Geocoder
+func (g *BigDataCloudGeocoder) Name() string {
+	return "bigdatacloud"
+}
+
+// bigDataCloudPlace is the part of BigDataCloud's responses we use
+type bigDataCloudPlace struct {
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	City                 string  `json:"city"`
+	Locality             string  `json:"locality"`
+	PrincipalSubdivision string  `json:"principalSubdivision"`
+	CountryName          string  `json:"countryName"`
+	CountryCode          string  `json:"countryCode"`
+	Postcode             string  `json:"postcode"`
+}
+
+// Geocode implements Geocoder
+func (g *BigDataCloudGeocoder) Geocode(ctx context.Context, query string) (*Result, error) {
+	if query == "" {
+		return nil, ErrNotFound
+	}
+
+	params := url.Values{}
+	params.Add("city", query)
+	var response bigDataCloudPlace
+	if err := g.get(ctx, "/geocode-city", params, &response); err != nil {
+		return nil, err
+	}
+
+	// The service answers with zero coordinates when it doesn't know the city
+	if response.Latitude == 0 && response.Longitude == 0 {
+		return nil, ErrNotFound
+	}
+	return g.result(response), nil
+}
+
+// Reverse implements Geocoder
+func (g *BigDataCloudGeocoder) Reverse(ctx context.Context, latitude, longitude float64) (*Result, error) {
+	params := url.Values{}
+	params.Add("latitude", strconv.FormatFloat(latitude, 'f', -1, 64))
+	params.Add("longitude", strconv.FormatFloat(longitude, 'f', -1, 64))
+	params.Add("localityLanguage", "en")
+	var response bigDataCloudPlace
+	if err := g.get(ctx, "/reverse-geocode-client", params, &response); err != nil {
+		return nil, err
+	}
+
+	if response.City == "" && response.Locality == "" {
+		return nil, ErrNotFound
+	}
+	response.Latitude, response.Longitude = latitude, longitude
+	return g.result(response), nil
+}
+
+func (g *BigDataCloudGeocoder) get(ctx context.Context, path string, params url.Values, response interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.BaseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create geocoding request: %w", err)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make geocoding request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("geocoding service returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+	return nil
+}
+
+func (g *BigDataCloudGeocoder) result(place bigDataCloudPlace) *Result {
+	city := place.City
+	if city == "" {
+		city = place.Locality
+	}
+	return &Result{
+		Latitude:    place.Latitude,
+		Longitude:   place.Longitude,
+		City:        city,
+		State:       place.PrincipalSubdivision,
+		Country:     place.CountryName,
+		CountryCode: place.CountryCode,
+		PostalCode:  place.Postcode,
+		Source:      g.Name(),
+	}
+}
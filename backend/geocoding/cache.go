@@ -0,0 +1,126 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// How long cached lookups are trusted. Places don't move, but providers
+// improve, and a place missing today may be added tomorrow.
+const (
+	CacheMaxAge     = 90 * 24 * time.Hour
+	CacheMissMaxAge = 24 * time.Hour
+)
+
+// CacheEntry is a cached lookup. Result is nil if the place wasn't found.
+type CacheEntry struct {
+	Result   *Result
+	CachedAt time.Time
+}
+
+// Cache stores lookups between requests. Get returns nil for keys it doesn't have.
+type Cache interface {
+	Get(ctx context.Context, key string) (*CacheEntry, error)
+	Put(ctx context.Context, key string, entry CacheEntry) error
+}
+
+// CachedGeocoder answers repeated lookups from a cache, only asking the
+// geocoder it wraps about places it hasn't seen recently. Places that weren't
+// found are cached too; lookups that failed are not.
+type CachedGeocoder struct {
+	next  Geocoder
+	cache Cache
+	now   func() time.Time
+}
+
+// NewCachedGeocoder wraps a geocoder with a cache
+func NewCachedGeocoder(next Geocoder, cache Cache) *CachedGeocoder {
+	return &CachedGeocoder{next: next, cache: cache, now: time.Now}
+}
+
+// Name implements Geocoder
+func (g *CachedGeocoder) Name() string {
+	return g.next.Name()
+}
+
+// Geocode implements Geocoder
+func (g *CachedGeocoder) Geocode(ctx context.Context, query string) (*Result, error) {
+	key := "geocode:" + normalizeName(query)
+	return g.lookup(ctx, key, func() (*Result, error) {
+		return g.next.Geocode(ctx, query)
+	})
+}
+
+// Reverse implements Geocoder. Points are cached to four decimal places,
+// about 10 metres.
+func (g *CachedGeocoder) Reverse(ctx context.Context, latitude, longitude float64) (*Result, error) {
+	key := fmt.Sprintf("reverse:%.4f,%.4f", latitude, longitude)
+	return g.lookup(ctx, key, func() (*Result, error) {
+		return g.next.Reverse(ctx, latitude, longitude)
+	})
+}
+
+func (g *CachedGeocoder) lookup(ctx context.Context, key string, fetch func() (*Result, error)) (*Result, error) {
+	now := g.now()
+	entry, err := g.cache.Get(ctx, key)
+	if err != nil {
+		// The cache only saves work, so carry on without it
+		log.Printf("Warning: failed to read geocoding cache: %v", err)
+	}
+	if entry != nil {
+		maxAge := CacheMaxAge
+		if entry.Result == nil {
+			maxAge = CacheMissMaxAge
+		}
+		if now.Sub(entry.CachedAt) < maxAge {
+			if entry.Result == nil {
+				return nil, ErrNotFound
+			}
+			result := *entry.Result
+			return &result, nil
+		}
+	}
+
+	result, err := fetch()
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if putErr := g.cache.Put(ctx, key, CacheEntry{Result: result, CachedAt: now}); putErr != nil {
+		log.Printf("Warning: failed to write geocoding cache: %v", putErr)
+	}
+	return result, err
+}
+
+// MemoryCache is a Cache held in memory, for when there is no database
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+}
+
+// NewMemoryCache creates an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]CacheEntry)}
+}
+
+// Get implements Cache
+func (c *MemoryCache) Get(ctx context.Context, key string) (*CacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, nil
+	}
+	return &entry, nil
+}
+
+// Put implements Cache
+func (c *MemoryCache) Put(ctx context.Context, key string, entry CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	return nil
+}
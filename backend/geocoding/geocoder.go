@@ -0,0 +1,116 @@
+// Package geocoding turns place names and postcodes into coordinates and
+// coordinates back into places
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when a geocoder has no location for a query.
+// Callers should surface it rather than guess a location.
+var ErrNotFound = errors.New("location not found")
+
+// Result is a geocoded place
+type Result struct {
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	City        string  `json:"city"`
+	State       string  `json:"state,omitempty"` // State, province or region
+	Country     string  `json:"country"`
+	CountryCode string  `json:"country_code,omitempty"` // ISO 3166-1 alpha-2
+	PostalCode  string  `json:"postal_code,omitempty"`
+	Source      string  `json:"source"` // Name of the geocoder that found it
+}
+
+// Geocoder looks places up. Implementations return ErrNotFound when they
+// have no answer, and other errors when the lookup itself failed.
+type Geocoder interface {
+	// Name identifies the geocoder in results and logs
+	Name() string
+	// Geocode finds a place from free text such as "Paris, France", "Taitung" or "94117"
+	Geocode(ctx context.Context, query string) (*Result, error)
+	// Reverse finds the place at or nearest to a point
+	Reverse(ctx context.Context, latitude, longitude float64) (*Result, error)
+}
+
+// NewGeocoder returns the geocoder with the given name. The offline
+// gazetteer answers on its own, with no network access; online providers are
+// only asked about places the gazetteer doesn't know.
+func NewGeocoder(name string, timeout time.Duration) (Geocoder, error) {
+	switch name {
+	case "", "offline":
+		return NewOfflineGeocoder(), nil
+	case "bigdatacloud":
+		client := &http.Client{Timeout: timeout}
+		return NewChain(NewOfflineGeocoder(), NewBigDataCloudGeocoder(client)), nil
+	default:
+		return nil, fmt.Errorf("unknown geocoder %q", name)
+	}
+}
+
+// Query builds a geocoding query from the parts of an address, most specific
+// first, e.g. "94117, San Francisco, CA"
+func Query(postalCode, city, state string) string {
+	var parts []string
+	for _, part := range []string{postalCode, city, state} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Chain asks each geocoder in turn until one finds the place
+type Chain struct {
+	geocoders []Geocoder
+}
+
+// NewChain creates a Chain trying the geocoders in order
+func NewChain(geocoders ...Geocoder) *Chain {
+	return &Chain{geocoders: geocoders}
+}
+
+// Name implements Geocoder
+func (c *Chain) Name() string {
+	names := make([]string, len(c.geocoders))
+	for i, geocoder := range c.geocoders {
+		names[i] = geocoder.Name()
+	}
+	return strings.Join(names, "+")
+}
+
+// Geocode implements Geocoder
+func (c *Chain) Geocode(ctx context.Context, query string) (*Result, error) {
+	return c.first(func(geocoder Geocoder) (*Result, error) {
+		return geocoder.Geocode(ctx, query)
+	})
+}
+
+// Reverse implements Geocoder
+func (c *Chain) Reverse(ctx context.Context, latitude, longitude float64) (*Result, error) {
+	return c.first(func(geocoder Geocoder) (*Result, error) {
+		return geocoder.Reverse(ctx, latitude, longitude)
+	})
+}
+
+// first returns the first result found. If none is found, a lookup failure
+// is reported in preference to ErrNotFound, since a failed provider might
+// have known the place.
+func (c *Chain) first(lookup func(Geocoder) (*Result, error)) (*Result, error) {
+	lastErr := ErrNotFound
+	for _, geocoder := range c.geocoders {
+		result, err := lookup(geocoder)
+		if err == nil {
+			return result, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			lastErr = fmt.Errorf("%s: %w", geocoder.Name(), err)
+		}
+	}
+	return nil, lastErr
+}
@@ -0,0 +1,162 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubGeocoder answers every lookup the same way and counts the calls
+type stubGeocoder struct {
+	name   string
+	result *Result
+	err    error
+	calls  int
+}
+
+func (s *stubGeocoder) Name() string { return s.name }
+
+func (s *stubGeocoder) Geocode(ctx context.Context, query string) (*Result, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func (s *stubGeocoder) Reverse(ctx context.Context, latitude, longitude float64) (*Result, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func TestNewGeocoder(t *testing.T) {
+	geocoder, err := NewGeocoder("", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "offline", geocoder.Name())
+
+	geocoder, err = NewGeocoder("bigdatacloud", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "offline+bigdatacloud", geocoder.Name())
+
+	_, err = NewGeocoder("nominatim", time.Second)
+	assert.EqualError(t, err, `unknown geocoder "nominatim"`)
+}
+
+func TestQuery(t *testing.T) {
+	assert.Equal(t, "94117, San Francisco, CA", Query("94117", "San Francisco", "CA"))
+	assert.Equal(t, "Taitung", Query("", " Taitung ", ""))
+	assert.Equal(t, "", Query("", "", ""))
+}
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	found := &stubGeocoder{name: "found", result: &Result{City: "Taitung"}}
+	missing := &stubGeocoder{name: "missing", err: ErrNotFound}
+	broken := &stubGeocoder{name: "broken", err: errors.New("connection refused")}
+
+	result, err := NewChain(missing, found).Geocode(ctx, "Taitung")
+	require.NoError(t, err)
+	assert.Equal(t, "Taitung", result.City)
+
+	_, err = NewChain(missing, missing).Geocode(ctx, "Atlantis")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// A provider that couldn't be asked might have known the place
+	_, err = NewChain(broken, missing).Reverse(ctx, 0, 0)
+	assert.EqualError(t, err, "broken: connection refused")
+	assert.NotErrorIs(t, err, ErrNotFound)
+}
+
+func TestCachedGeocoder(t *testing.T) {
+	ctx := context.Background()
+	next := &stubGeocoder{name: "stub", result: &Result{City: "Taitung"}}
+	geocoder := NewCachedGeocoder(next, NewMemoryCache())
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	geocoder.now = func() time.Time { return now }
+
+	for _, query := range []string{"Taitung", "taitung ", "TAITUNG"} {
+		result, err := geocoder.Geocode(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, "Taitung", result.City)
+	}
+	assert.Equal(t, 1, next.calls, "equivalent queries share a cache entry")
+
+	now = now.Add(CacheMaxAge)
+	_, err := geocoder.Geocode(ctx, "Taitung")
+	require.NoError(t, err)
+	assert.Equal(t, 2, next.calls, "stale entries are looked up again")
+}
+
+func TestCachedGeocoder_Misses(t *testing.T) {
+	ctx := context.Background()
+	next := &stubGeocoder{name: "stub", err: ErrNotFound}
+	geocoder := NewCachedGeocoder(next, NewMemoryCache())
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	geocoder.now = func() time.Time { return now }
+
+	_, err := geocoder.Reverse(ctx, 0, -140)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = geocoder.Reverse(ctx, 0.00001, -140)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 1, next.calls, "misses are cached")
+
+	now = now.Add(CacheMissMaxAge)
+	_, err = geocoder.Reverse(ctx, 0, -140)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 2, next.calls, "misses expire sooner than places")
+
+	// Failures are retried rather than cached
+	next.err = errors.New("timeout")
+	_, err = geocoder.Geocode(ctx, "Taitung")
+	assert.EqualError(t, err, "timeout")
+	_, err = geocoder.Geocode(ctx, "Taitung")
+	assert.EqualError(t, err, "timeout")
+	assert.Equal(t, 4, next.calls)
+}
+
+func TestBigDataCloudGeocoder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/geocode-city" && r.URL.Query().Get("city") == "Taitung":
+			w.Write([]byte(`{"latitude":22.7583,"longitude":121.1444,"city":"Taitung","principalSubdivision":"Taitung","countryName":"Taiwan","countryCode":"TW"}`))
+		case r.URL.Path == "/geocode-city":
+			w.Write([]byte(`{"latitude":0,"longitude":0}`))
+		case r.URL.Path == "/reverse-geocode-client" && r.URL.Query().Get("latitude") == "22.75":
+			w.Write([]byte(`{"locality":"Taitung","countryName":"Taiwan","countryCode":"TW","postcode":"950"}`))
+		case r.URL.Path == "/reverse-geocode-client":
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	geocoder := NewBigDataCloudGeocoder(server.Client())
+	geocoder.BaseURL = server.URL
+
+	result, err := geocoder.Geocode(ctx, "Taitung")
+	require.NoError(t, err)
+	assert.Equal(t, 22.7583, result.Latitude)
+	assert.Equal(t, "TW", result.CountryCode)
+	assert.Equal(t, "bigdatacloud", result.Source)
+
+	_, err = geocoder.Geocode(ctx, "Atlantis")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	result, err = geocoder.Reverse(ctx, 22.75, 121.15)
+	require.NoError(t, err)
+	assert.Equal(t, "Taitung", result.City)
+	assert.Equal(t, "950", result.PostalCode)
+	assert.Equal(t, 121.15, result.Longitude)
+
+	_, err = geocoder.Reverse(ctx, 0, -140)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	geocoder.BaseURL = server.URL + "/down"
+	_, err = geocoder.Geocode(ctx, "Taitung")
+	assert.EqualError(t, err, "geocoding service returned status 500")
+}
@@ -0,0 +1,304 @@
+package geocoding
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/user/tennis-connect/utils"
+)
+
+//go:embed data/cities.csv
+var citiesCSV []byte
+
+//go:embed data/postcodes.csv
+var postcodesCSV []byte
+
+// Reverse lookups only name a place this close to the point
+const (
+	MaxReverseCityKm     = 75
+	MaxReversePostcodeKm = 3
+)
+
+// place is a gazetteer entry, either a city or a postcode
+type place struct {
+	Result
+	population int // Thousands; ranks cities sharing a name
+}
+
+// gazetteer is the parsed embedded data, loaded once
+type gazetteer struct {
+	cities    []place
+	postcodes []place
+	byName    map[string][]int // normalized city name or alias -> indexes into cities
+	byCode    map[string][]int // normalized postcode -> indexes into postcodes
+}
+
+var (
+	loadGazetteerOnce sync.Once
+	loadedGazetteer   *gazetteer
+)
+
+// OfflineGeocoder looks places up in an embedded gazetteer of world cities
+// and postcodes. It never touches the network, so it answers the same way
+// every time, including in tests and local development.
+type OfflineGeocoder struct {
+	data *gazetteer
+}
+
+// NewOfflineGeocoder creates an OfflineGeocoder
+func NewOfflineGeocoder() *OfflineGeocoder {
+	loadGazetteerOnce.Do(func() {
+		data, err := parseGazetteer(citiesCSV, postcodesCSV)
+		if err != nil {
+			panic(fmt.Sprintf("invalid embedded gazetteer: %v", err))
+		}
+		loadedGazetteer = data
+	})
+	return &OfflineGeocoder{data: loadedGazetteer}
+}
+
+// Name implements Geocoder
+func (g *OfflineGeocoder) Name() string {
+	return "offline"
+}
+
+// Geocode implements Geocoder. The query's first comma-separated part is a
+// postcode or city name; any further parts (state, country or country code)
+// pick between places sharing that name. Without them the most populous
+// city wins.
+func (g *OfflineGeocoder) Geocode(ctx context.Context, query string) (*Result, error) {
+	parts := strings.Split(query, ",")
+	var qualifiers []string
+	for _, part := range parts[1:] {
+		if part = normalizeName(part); part != "" {
+			qualifiers = append(qualifiers, part)
+		}
+	}
+
+	head := parts[0]
+	if matches := g.data.byCode[normalizePostcode(head)]; len(matches) > 0 {
+		if best := bestMatch(g.data.postcodes, matches, qualifiers); best != nil {
+			return best, nil
+		}
+	}
+	if best := bestMatch(g.data.cities, g.data.byName[normalizeName(head)], qualifiers); best != nil {
+		return best, nil
+	}
+	return nil, ErrNotFound
+}
+
+// Reverse implements Geocoder, naming the nearest city and, if the point is
+// close to one, its postcode
+func (g *OfflineGeocoder) Reverse(ctx context.Context, latitude, longitude float64) (*Result, error) {
+	city, cityKm := nearest(g.data.cities, latitude, longitude)
+	if city == nil || cityKm > MaxReverseCityKm {
+		return nil, ErrNotFound
+	}
+	result := city.Result
+	result.Latitude, result.Longitude = latitude, longitude
+
+	if postcode, postcodeKm := nearest(g.data.postcodes, latitude, longitude); postcode != nil && postcodeKm <= MaxReversePostcodeKm &&
+		postcode.CountryCode == city.CountryCode {
+		result.PostalCode = postcode.PostalCode
+	}
+	return &result, nil
+}
+
+// bestMatch picks the entry matching every qualifier, preferring the most populous
+func bestMatch(places []place, indexes []int, qualifiers []string) *Result {
+	var best *place
+	for _, i := range indexes {
+		candidate := &places[i]
+		if !matchesQualifiers(candidate, qualifiers) {
+			continue
+		}
+		if best == nil || candidate.population > best.population {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	result := best.Result
+	return &result
+}
+
+// countryAliases maps common country names that differ from the
+// gazetteer's to country codes
+var countryAliases = map[string]string{
+	"usa":                      "us",
+	"united states of america": "us",
+	"america":                  "us",
+	"uk":                       "gb",
+	"great britain":            "gb",
+	"england":                  "gb",
+	"scotland":                 "gb",
+	"roc":                      "tw",
+	"republic of china":        "tw",
+	"holland":                  "nl",
+}
+
+func matchesQualifiers(p *place, qualifiers []string) bool {
+	countryCode := normalizeName(p.CountryCode)
+	for _, qualifier := range qualifiers {
+		if qualifier != normalizeName(p.State) && qualifier != normalizeName(p.Country) &&
+			qualifier != countryCode && qualifier != normalizeName(p.City) && countryAliases[qualifier] != countryCode {
+			return false
+		}
+	}
+	return true
+}
+
+// nearest returns the place closest to the point and its distance in kilometres
+func nearest(places []place, latitude, longitude float64) (*place, float64) {
+	var best *place
+	bestKm := 0.0
+	for i := range places {
+		km := utils.DistanceKm(latitude, longitude, places[i].Latitude, places[i].Longitude)
+		if best == nil || km < bestKm {
+			best, bestKm = &places[i], km
+		}
+	}
+	return best, bestKm
+}
+
+// parseGazetteer parses the embedded city and postcode files
+func parseGazetteer(cities, postcodes []byte) (*gazetteer, error) {
+	data := &gazetteer{byName: map[string][]int{}, byCode: map[string][]int{}}
+
+	cityRows, err := readCSV(cities, 8)
+	if err != nil {
+		return nil, fmt.Errorf("cities: %w", err)
+	}
+	for line, row := range cityRows {
+		latitude, longitude, err := parseCoordinates(row[4], row[5])
+		if err != nil {
+			return nil, fmt.Errorf("cities line %d: %w", line+2, err)
+		}
+		population, err := strconv.Atoi(row[6])
+		if err != nil {
+			return nil, fmt.Errorf("cities line %d: invalid population %q", line+2, row[6])
+		}
+		data.cities = append(data.cities, place{
+			Result: Result{
+				Latitude: latitude, Longitude: longitude,
+				City: row[0], State: row[1], Country: row[2], CountryCode: row[3],
+				Source: "offline",
+			},
+			population: population,
+		})
+		index := len(data.cities) - 1
+		names := []string{row[0]}
+		if row[7] != "" {
+			names = append(names, strings.Split(row[7], "|")...)
+		}
+		for _, name := range names {
+			key := normalizeName(name)
+			data.byName[key] = append(data.byName[key], index)
+		}
+	}
+
+	countries := map[string]string{}
+	for _, city := range data.cities {
+		countries[city.CountryCode] = city.Country
+	}
+
+	postcodeRows, err := readCSV(postcodes, 6)
+	if err != nil {
+		return nil, fmt.Errorf("postcodes: %w", err)
+	}
+	for line, row := range postcodeRows {
+		latitude, longitude, err := parseCoordinates(row[4], row[5])
+		if err != nil {
+			return nil, fmt.Errorf("postcodes line %d: %w", line+2, err)
+		}
+		country, ok := countries[row[0]]
+		if !ok {
+			return nil, fmt.Errorf("postcodes line %d: no cities in country %s", line+2, row[0])
+		}
+		data.postcodes = append(data.postcodes, place{Result: Result{
+			Latitude: latitude, Longitude: longitude,
+			City: row[2], State: row[3], Country: country, CountryCode: row[0], PostalCode: row[1],
+			Source: "offline",
+		}})
+		key := normalizePostcode(row[1])
+		data.byCode[key] = append(data.byCode[key], len(data.postcodes)-1)
+	}
+	return data, nil
+}
+
+// readCSV reads a CSV file with a header row, returning the other rows
+func readCSV(data []byte, fields int) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = fields
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("missing header")
+	}
+	return rows[1:], nil
+}
+
+func parseCoordinates(lat, lng string) (float64, float64, error) {
+	latitude, err := strconv.ParseFloat(lat, 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return 0, 0, fmt.Errorf("invalid latitude %q", lat)
+	}
+	longitude, err := strconv.ParseFloat(lng, 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return 0, 0, fmt.Errorf("invalid longitude %q", lng)
+	}
+	return latitude, longitude, nil
+}
+
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n", "ß", "ss",
+)
+
+// normalizeName lowercases a place name and strips accents and punctuation,
+// so "Zürich", "zurich" and "Zurich." all match
+func normalizeName(name string) string {
+	name = accentReplacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+	var b strings.Builder
+	space := false
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteRune(r)
+			space = false
+		case r == '\'' || r == '.':
+			// "Cote d'Azur" and "D.C." match without their punctuation
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// normalizePostcode uppercases a postcode and drops spaces and dashes, so
+// "sw19 5ae" matches "SW19 5AE" and "1000001" matches "100-0001"
+func normalizePostcode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package geocoding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineGeocoder_Geocode(t *testing.T) {
+	geocoder := NewOfflineGeocoder()
+
+	tests := []struct {
+		query       string
+		city        string
+		countryCode string
+		postalCode  string
+	}{
+		{"Paris", "Paris", "FR", ""},
+		{"paris, tx", "Paris", "US", ""},
+		{"Paris, United States", "Paris", "US", ""},
+		{"Queenstown", "Queenstown", "ZA", ""},
+		{"Queenstown, New Zealand", "Queenstown", "NZ", ""},
+		{"Taitung City", "Taitung", "TW", ""},
+		{"Taitung, ROC", "Taitung", "TW", ""},
+		{"94117", "San Francisco", "US", "94117"},
+		{"sw19 5ae", "Wimbledon", "GB", "SW19 5AE"},
+		{"75016, Paris", "Paris", "FR", "75016"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			result, err := geocoder.Geocode(context.Background(), tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.city, result.City)
+			assert.Equal(t, tt.countryCode, result.CountryCode)
+			assert.Equal(t, tt.postalCode, result.PostalCode)
+			assert.Equal(t, "offline", result.Source)
+		})
+	}
+}
+
+func TestOfflineGeocoder_NotFound(t *testing.T) {
+	geocoder := NewOfflineGeocoder()
+
+	for _, query := range []string{"", "Atlantis", "Paris, Japan", "00000"} {
+		_, err := geocoder.Geocode(context.Background(), query)
+		assert.ErrorIs(t, err, ErrNotFound, query)
+	}
+}
+
+func TestOfflineGeocoder_Reverse(t *testing.T) {
+	geocoder := NewOfflineGeocoder()
+
+	// A point in Haight-Ashbury gets its postcode
+	result, err := geocoder.Reverse(context.Background(), 37.7700, -122.4450)
+	require.NoError(t, err)
+	assert.Equal(t, "San Francisco", result.City)
+	assert.Equal(t, "94117", result.PostalCode)
+	assert.Equal(t, 37.7700, result.Latitude)
+
+	// Mid-ocean points are nowhere
+	_, err = geocoder.Reverse(context.Background(), 0, -140)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "zurich", normalizeName(" Zürich. "))
+	assert.Equal(t, "washington dc", normalizeName("Washington, D.C."))
+	assert.Equal(t, "cote dazur", normalizeName("Côte d'Azur"))
+}
+
+func TestNormalizePostcode(t *testing.T) {
+	assert.Equal(t, "SW195AE", normalizePostcode("sw19 5ae"))
+	assert.Equal(t, "1000001", normalizePostcode("100-0001"))
+}
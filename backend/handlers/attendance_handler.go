@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/repository"
+)
+
+// AttendanceHandler handles check-in and no-show HTTP requests for events,
+// bookings and match sessions
+type AttendanceHandler struct {
+	attendanceRepo   *repository.AttendanceRepository
+	notificationRepo *repository.NotificationRepository
+}
+
+// NewAttendanceHandler creates a new AttendanceHandler
+func NewAttendanceHandler(attendanceRepo *repository.AttendanceRepository, notificationRepo *repository.NotificationRepository) *AttendanceHandler {
+	return &AttendanceHandler{
+		attendanceRepo:   attendanceRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// GetCheckInCode handles GET /api/attendance/:type/:id/code. The host shows
+// the code as a QR at the court for players to scan.
+func (h *AttendanceHandler) GetCheckInCode(c *gin.Context) {
+	subject, userID, ok := h.loadSubject(c)
+	if !ok {
+		return
+	}
+	if !subject.CanManage(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the host can view the check-in code"})
+		return
+	}
+
+	code, err := h.attendanceRepo.GetCheckInCode(c.Request.Context(), subject.Type, subject.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get check-in code: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": code})
+}
+
+// CheckIn handles POST /api/attendance/:type/:id/check-in for a player
+// checking themselves in with a QR code or their location
+func (h *AttendanceHandler) CheckIn(c *gin.Context) {
+	subject, userID, ok := h.loadSubject(c)
+	if !ok {
+		return
+	}
+
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var code string
+	if req.Code != "" {
+		var err error
+		code, err = h.attendanceRepo.GetCheckInCode(ctx, subject.Type, subject.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get check-in code: " + err.Error()})
+			return
+		}
+	}
+
+	method, err := subject.SelfCheckIn(userID, req, code, time.Now())
+	if err != nil {
+		if strings.Contains(err.Error(), "not a participant") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not signed up for this"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attendance := models.Attendance{
+		SubjectType: subject.Type,
+		SubjectID:   subject.ID,
+		UserID:      userID,
+		Status:      models.AttendanceStatusCheckedIn,
+		Method:      method,
+		RecordedBy:  &userID,
+	}
+	if err := h.attendanceRepo.Record(ctx, &attendance); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attendance)
+}
+
+// RecordAttendance handles POST /api/attendance/:type/:id for the host
+// checking players in or marking them as no-shows
+func (h *AttendanceHandler) RecordAttendance(c *gin.Context) {
+	subject, userID, ok := h.loadSubject(c)
+	if !ok {
+		return
+	}
+	if !subject.CanManage(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the host can take attendance"})
+		return
+	}
+
+	var req models.AttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, playerID := range req.UserIDs {
+		if !subject.IsParticipant(playerID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("User %s is not signed up for this", playerID)})
+			return
+		}
+	}
+	if req.Status == models.AttendanceStatusNoShow && time.Now().Before(subject.StartTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No-shows can only be recorded once it has started"})
+		return
+	}
+
+	records, err := h.record(c.Request.Context(), subject, req.UserIDs, req.Status, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record attendance: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attendance": records})
+}
+
+// CloseAttendance handles POST /api/attendance/:type/:id/close. Every
+// participant who has not checked in is recorded as a no-show.
+func (h *AttendanceHandler) CloseAttendance(c *gin.Context) {
+	subject, userID, ok := h.loadSubject(c)
+	if !ok {
+		return
+	}
+	if !subject.CanManage(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the host can take attendance"})
+		return
+	}
+	if time.Now().Before(subject.StartTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Attendance can only be closed once it has started"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	existing, err := h.attendanceRepo.GetAttendance(ctx, subject.Type, subject.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance: " + err.Error()})
+		return
+	}
+
+	noShows, err := h.record(ctx, subject, subject.MissingParticipants(existing), models.AttendanceStatusNoShow, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record no-shows: " + err.Error()})
+		return
+	}
+
+	checkedIn := 0
+	for _, record := range existing {
+		if record.Status == models.AttendanceStatusCheckedIn {
+			checkedIn++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"checked_in": checkedIn,
+		"no_shows":   noShows,
+	})
+}
+
+// GetAttendance handles GET /api/attendance/:type/:id
+func (h *AttendanceHandler) GetAttendance(c *gin.Context) {
+	subject, userID, ok := h.loadSubject(c)
+	if !ok {
+		return
+	}
+	if !subject.CanManage(userID) && !subject.IsParticipant(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not signed up for this"})
+		return
+	}
+
+	records, err := h.attendanceRepo.GetAttendance(c.Request.Context(), subject.Type, subject.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attendance: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attendance":  records,
+		"not_checked": subject.MissingParticipants(records),
+	})
+}
+
+// GetReliability handles GET /api/users/:userID/reliability
+func (h *AttendanceHandler) GetReliability(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	reliability, err := h.attendanceRepo.GetReliability(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reliability: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reliability)
+}
+
+// loadSubject parses the subject from the URL and loads it along with the
+// authenticated user. It writes an error response and returns false on failure.
+func (h *AttendanceHandler) loadSubject(c *gin.Context) (*models.AttendanceSubject, uuid.UUID, bool) {
+	subjectType := c.Param("type")
+	if !models.IsValidAttendanceSubject(subjectType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attendance type"})
+		return nil, uuid.Nil, false
+	}
+	subjectID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return nil, uuid.Nil, false
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+
+	subject, err := h.attendanceRepo.GetSubject(c.Request.Context(), subjectType, subjectID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "cancelled"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load attendance: " + err.Error()})
+		}
+		return nil, uuid.Nil, false
+	}
+	return subject, userID, true
+}
+
+// record saves the same attendance status for several players and lets
+// anyone marked as a no-show know
+func (h *AttendanceHandler) record(ctx context.Context, subject *models.AttendanceSubject, userIDs []uuid.UUID, status string, recordedBy uuid.UUID) ([]models.Attendance, error) {
+	records := []models.Attendance{}
+	for _, playerID := range userIDs {
+		attendance := models.Attendance{
+			SubjectType: subject.Type,
+			SubjectID:   subject.ID,
+			UserID:      playerID,
+			Status:      status,
+			Method:      models.CheckInMethodHost,
+			RecordedBy:  &recordedBy,
+		}
+		if err := h.attendanceRepo.Record(ctx, &attendance); err != nil {
+			return nil, err
+		}
+		records = append(records, attendance)
+	}
+
+	if status == models.AttendanceStatusNoShow {
+		h.notify(userIDs, models.NotificationTypeNoShow, "Marked as a no-show",
+			fmt.Sprintf("You were marked as not attending %s. No-shows lower your reliability score.", subject.Title), subject.ID)
+	}
+	return records, nil
+}
+
+// notify sends an attendance notification; failures are logged but do not fail the request
+func (h *AttendanceHandler) notify(userIDs []uuid.UUID, notificationType, title, message string, subjectID uuid.UUID) {
+	if h.notificationRepo == nil || len(userIDs) == 0 {
+		return
+	}
+	err := h.notificationRepo.NotifyUsers(context.Background(), userIDs, notificationType, title, message, &subjectID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to send %s notifications: %v\n", notificationType, err)
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// getAuthenticatedUserID reads the user ID set by the auth middleware. It writes
+// an error response and returns false if the ID is missing or malformed.
+func getAuthenticatedUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDValue, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, false
+	}
+
+	switch v := userIDValue.(type) {
+	case uuid.UUID:
+		return v, true
+	case string:
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return uuid.Nil, false
+		}
+		return userID, true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+	return uuid.Nil, false
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+// GetBookingRequests handles GET /api/courts/:id/booking-requests, the queue of
+// bookings waiting for a manager's approval
+func (h *BookingHandlers) GetBookingRequests(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+	h.expireStaleBookings(c.Request.Context())
+
+	bookings, err := h.bookingRepo.GetApprovalQueue(c.Request.Context(), court.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get booking requests: %v", err)})
+		return
+	}
+	if bookings == nil {
+		bookings = []*models.Booking{}
+	}
+	for _, booking := range bookings {
+		booking.ShareToken = ""
+	}
+
+	c.JSON(http.StatusOK, bookings)
+}
+
+// ApproveBookingRequest handles POST /api/courts/:id/booking-requests/:bookingID/approve
+func (h *BookingHandlers) ApproveBookingRequest(c *gin.Context) {
+	h.reviewBookingRequest(c, true)
+}
+
+// RejectBookingRequest handles POST /api/courts/:id/booking-requests/:bookingID/reject
+func (h *BookingHandlers) RejectBookingRequest(c *gin.Context) {
+	h.reviewBookingRequest(c, false)
+}
+
+func (h *BookingHandlers) reviewBookingRequest(c *gin.Context, approve bool) {
+	court, userID, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+	bookingID, err := uuid.Parse(c.Param("bookingID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	var req models.BookingReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if err := req.Validate(approve); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.expireStaleBookings(c.Request.Context())
+
+	booking, offer, err := h.bookingRepo.ReviewBooking(c.Request.Context(), court.ID, bookingID, userID, approve, strings.TrimSpace(req.Reason))
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "booking not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+		case strings.Contains(err.Error(), "not awaiting approval"), strings.Contains(err.Error(), "deadline has passed"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to review booking: %v", err)})
+		}
+		return
+	}
+	h.notifyWaitlistOffers(offer)
+
+	when := formatBookingStart(booking)
+	if !approve {
+		h.notify([]uuid.UUID{booking.UserID}, models.NotificationTypeBookingRejected, "Booking request declined",
+			fmt.Sprintf("Your booking at %s on %s was declined: %s", court.Name, when, booking.ReviewNote), booking.ID)
+		booking.ShareToken = ""
+		c.JSON(http.StatusOK, booking)
+		return
+	}
+
+	// Approved bookings are confirmed now, or once the player pays at paid courts
+	if !h.startPayment(c, booking) {
+		return
+	}
+	message := fmt.Sprintf("Your booking at %s on %s has been approved", court.Name, when)
+	if booking.Payment != nil {
+		message += fmt.Sprintf(". Pay within %d minutes to confirm it", int(models.PaymentWindow.Minutes()))
+	}
+	h.notify([]uuid.UUID{booking.UserID}, models.NotificationTypeBookingApproved, "Booking request approved", message, booking.ID)
+
+	// The client secret is for the player, not the manager
+	if booking.Payment != nil {
+		booking.Payment.ClientSecret = ""
+	}
+	booking.ShareToken = ""
+	c.JSON(http.StatusOK, booking)
+}
+
+// submitBooking finishes a newly created booking: requests at courts that need
+// approval are sent to the managers, everything else goes on to payment. It
+// writes an error response and returns false on failure.
+func (h *BookingHandlers) submitBooking(c *gin.Context, booking *models.Booking) bool {
+	if !booking.AwaitingApproval() {
+		return h.startPayment(c, booking)
+	}
+
+	court, err := h.courtRepo.GetByID(c.Request.Context(), booking.CourtID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load court managers for booking request: %v\n", err)
+		return true
+	}
+	h.notify(court.ManagerIDs, models.NotificationTypeBookingRequest, "New booking request",
+		fmt.Sprintf("A player has asked to book %s on %s", court.Name, formatBookingStart(booking)), booking.ID)
+	return true
+}
+
+// expireStaleBookings releases courts held by booking requests and unpaid
+// bookings that ran out of time
+func (h *BookingHandlers) expireStaleBookings(ctx context.Context) {
+	h.expireApprovalRequests(ctx)
+	h.expireUnpaidBookings(ctx)
+}
+
+// StartBookingMaintenance starts a background goroutine that expires
+// unanswered booking requests, unpaid bookings and unclaimed waitlist holds,
+// so courts are released and players told even when nobody is booking
+func (h *BookingHandlers) StartBookingMaintenance(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			h.expireWaitlistHolds(ctx)
+			h.expireStaleBookings(ctx)
+			cancel()
+
+			<-ticker.C
+		}
+	}()
+}
+
+// expireApprovalRequests cancels requests no manager answered in time and
+// tells the players
+func (h *BookingHandlers) expireApprovalRequests(ctx context.Context) {
+	expired, offers, err := h.bookingRepo.ExpireApprovalRequests(ctx)
+	if err != nil {
+		fmt.Printf("Warning: Failed to expire booking requests: %v\n", err)
+		return
+	}
+	for _, booking := range expired {
+		h.notify([]uuid.UUID{booking.UserID}, models.NotificationTypeBookingExpired, "Booking request expired",
+			fmt.Sprintf("No manager responded to your booking request for %s, so it has been cancelled", formatBookingStart(booking)), booking.ID)
+	}
+	h.notifyWaitlistOffers(offers...)
+}
+
+// loadManagedCourt loads the court in the URL and checks the authenticated
+// user manages it or is a moderator. It writes an error response and returns
+// false on failure.
+func (h *BookingHandlers) loadManagedCourt(c *gin.Context) (*models.Court, uuid.UUID, bool) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return nil, uuid.Nil, false
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+
+	court, err := h.courtRepo.GetByID(c.Request.Context(), courtID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return nil, uuid.Nil, false
+	}
+	if !court.CanManage(userID) {
+		isModerator, err := h.courtRepo.IsModerator(c.Request.Context(), userID)
+		if err != nil || !isModerator {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only a court manager or moderator can do this"})
+			return nil, uuid.Nil, false
+		}
+	}
+	return court, userID, true
+}
+
+// formatBookingStart formats a booking's start in the court's local time for notifications
+func formatBookingStart(booking *models.Booking) string {
+	start := booking.StartTime
+	if booking.LocalStartTime != nil {
+		start = *booking.LocalStartTime
+	}
+	return start.Format("Mon Jan 2 15:04")
+}
@@ -10,22 +10,27 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/payments"
 	"github.com/user/tennis-connect/repository"
 )
 
 // BookingHandlers handles HTTP requests for booking operations
 type BookingHandlers struct {
-	bookingRepo *repository.BookingRepository
-	courtRepo   *repository.CourtRepository
-	userRepo    *repository.UserRepository
+	bookingRepo      *repository.BookingRepository
+	courtRepo        *repository.CourtRepository
+	userRepo         *repository.UserRepository
+	notificationRepo *repository.NotificationRepository
+	payments         payments.Provider
 }
 
 // NewBookingHandlers creates a new BookingHandlers instance
-func NewBookingHandlers(bookingRepo *repository.BookingRepository, courtRepo *repository.CourtRepository, userRepo *repository.UserRepository) *BookingHandlers {
+func NewBookingHandlers(bookingRepo *repository.BookingRepository, courtRepo *repository.CourtRepository, userRepo *repository.UserRepository, notificationRepo *repository.NotificationRepository, paymentProvider payments.Provider) *BookingHandlers {
 	return &BookingHandlers{
-		bookingRepo: bookingRepo,
-		courtRepo:   courtRepo,
-		userRepo:    userRepo,
+		bookingRepo:      bookingRepo,
+		courtRepo:        courtRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		payments:         paymentProvider,
 	}
 }
 
@@ -49,48 +54,25 @@ func (h *BookingHandlers) CreateBooking(c *gin.Context) {
 		return
 	}
 
-	// Set defaults
-	if req.StartTime == "" {
-		req.StartTime = "17:00" // Default to 5:00 PM
-	}
-	if req.Duration == 0 {
-		req.Duration = 60 // Default to 1 hour
-	}
-	if req.PlayerCount == 0 {
-		req.PlayerCount = 2 // Default to 2 players
-	}
-	if req.GameType == "" {
-		req.GameType = "Singles"
-	}
+	setBookingDefaults(&req)
 
-	// Parse date and time
-	date, err := time.Parse("2006-01-02", req.Date)
+	// Validate court exists
+	court, err := h.courtRepo.GetByID(c.Request.Context(), req.CourtID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
 		return
 	}
 
-	startTime, err := time.Parse("15:04", req.StartTime)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start time format. Use HH:MM"})
+	startDateTime, ok := parseBookingStart(c, &req, court)
+	if !ok {
 		return
 	}
-
-	// Combine date and time
-	startDateTime := time.Date(date.Year(), date.Month(), date.Day(),
-		startTime.Hour(), startTime.Minute(), 0, 0, time.Local)
 	endDateTime := startDateTime.Add(time.Duration(req.Duration) * time.Minute)
 
-	// Validate court exists
-	court, err := h.courtRepo.GetByID(c.Request.Context(), req.CourtID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
-		return
-	}
-
 	// Create booking
 	booking := &models.Booking{
 		CourtID:     req.CourtID,
+		CourtUnitID: req.CourtUnitID,
 		UserID:      userID,
 		StartTime:   startDateTime,
 		EndTime:     endDateTime,
@@ -98,25 +80,38 @@ func (h *BookingHandlers) CreateBooking(c *gin.Context) {
 		PlayerCount: req.PlayerCount,
 		GameType:    req.GameType,
 		Notes:       req.Notes,
+
+		PublishOpenSpots: req.PublishOpenSpots,
 	}
 
+	h.expireStaleBookings(c.Request.Context())
+
 	err = h.bookingRepo.Create(c.Request.Context(), booking)
 	if err != nil {
 		if strings.Contains(err.Error(), "not available") {
-			c.JSON(http.StatusConflict, gin.H{"error": "Court is not available during the requested time"})
+			c.JSON(http.StatusConflict, gin.H{"error": "Court is not available during the requested time", "can_join_waitlist": true})
+			return
+		}
+		if strings.Contains(err.Error(), "booking not allowed") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.TrimPrefix(err.Error(), "booking not allowed: ")})
+			return
+		}
+		if strings.Contains(err.Error(), "court is closed") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if strings.Contains(err.Error(), "no bookable units") {
+			c.JSON(http.StatusConflict, gin.H{"error": "Court has no bookable courts"})
 			return
 		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create booking: %v", err)})
 		return
 	}
 
-	// Auto-confirm booking (in a real system, this might require payment or approval)
-	err = h.bookingRepo.UpdateStatus(c.Request.Context(), booking.ID, models.BookingStatusConfirmed)
-	if err != nil {
-		// Log error but don't fail the request
-		fmt.Printf("Warning: Failed to auto-confirm booking: %v\n", err)
-	} else {
-		booking.Status = models.BookingStatusConfirmed
+	// Requests at courts that need approval wait for a manager; free bookings
+	// are confirmed now and paid ones stay pending until paid
+	if !h.submitBooking(c, booking) {
+		return
 	}
 
 	// Populate court information
@@ -125,6 +120,41 @@ func (h *BookingHandlers) CreateBooking(c *gin.Context) {
 	c.JSON(http.StatusCreated, booking)
 }
 
+// setBookingDefaults fills in the optional fields of a booking request
+func setBookingDefaults(req *models.BookingRequest) {
+	if req.StartTime == "" {
+		req.StartTime = "17:00" // Default to 5:00 PM
+	}
+	if req.Duration == 0 {
+		req.Duration = 60 // Default to 1 hour
+	}
+	if req.PlayerCount == 0 {
+		req.PlayerCount = 2 // Default to 2 players
+	}
+	if req.GameType == "" {
+		req.GameType = "Singles"
+	}
+}
+
+// parseBookingStart combines the request's date and time in the court's
+// timezone, writing a 400 response and returning false if either is invalid
+func parseBookingStart(c *gin.Context, req *models.BookingRequest, court *models.Court) (time.Time, bool) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		return time.Time{}, false
+	}
+
+	startTime, err := time.Parse("15:04", req.StartTime)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start time format. Use HH:MM"})
+		return time.Time{}, false
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(),
+		startTime.Hour(), startTime.Minute(), 0, 0, court.TimeLocation()), true
+}
+
 // GetBooking handles GET /api/bookings/:id
 func (h *BookingHandlers) GetBooking(c *gin.Context) {
 	bookingID, err := uuid.Parse(c.Param("id"))
@@ -154,6 +184,11 @@ func (h *BookingHandlers) GetBooking(c *gin.Context) {
 		booking.User = user
 	}
 
+	// Only the owner hands out the share link
+	if requesterID, _ := c.Get("userID"); requesterID != booking.UserID {
+		booking.ShareToken = ""
+	}
+
 	c.JSON(http.StatusOK, booking)
 }
 
@@ -185,33 +220,47 @@ func (h *BookingHandlers) GetUserBookings(c *gin.Context) {
 		return
 	}
 
-	// Populate court information for each booking
+	// Populate court information for each booking; only owners see share links
 	for _, booking := range bookings {
 		court, err := h.courtRepo.GetByID(c.Request.Context(), booking.CourtID)
 		if err == nil {
 			booking.Court = court
 		}
+		if booking.UserID != userID {
+			booking.ShareToken = ""
+		}
 	}
 
 	c.JSON(http.StatusOK, bookings)
 }
 
-// GetCourtAvailability handles GET /api/courts/:courtID/availability
+// GetCourtAvailability handles GET /api/courts/:id/availability, returning
+// free slots for the facility as a whole and for each of its courts
 func (h *BookingHandlers) GetCourtAvailability(c *gin.Context) {
-	courtID, err := uuid.Parse(c.Param("courtID"))
+	courtID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
 		return
 	}
 
-	dateStr := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
+	court, err := h.courtRepo.GetByID(c.Request.Context(), courtID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+
+	// Dates are days in the court's timezone
+	loc := court.TimeLocation()
+	dateStr := c.DefaultQuery("date", time.Now().In(loc).Format("2006-01-02"))
 
-	date, err := time.Parse("2006-01-02", dateStr)
+	date, err := time.ParseInLocation("2006-01-02", dateStr, loc)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
 		return
 	}
 
+	h.expireStaleBookings(c.Request.Context())
+
 	availability, err := h.bookingRepo.GetAvailability(c.Request.Context(), courtID, date)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get court availability: %v", err)})
@@ -240,7 +289,12 @@ func (h *BookingHandlers) CancelBooking(c *gin.Context) {
 		return
 	}
 
-	err = h.bookingRepo.Cancel(c.Request.Context(), bookingID, userID)
+	offer, err := h.bookingRepo.Cancel(c.Request.Context(), bookingID, userID)
+	if err != nil && strings.Contains(err.Error(), "waitlist offer failed") {
+		// The booking is cancelled; the next sweep will retry the offer
+		fmt.Printf("Warning: %v\n", err)
+		err = nil
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
@@ -257,6 +311,11 @@ func (h *BookingHandlers) CancelBooking(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to cancel booking: %v", err)})
 		return
 	}
+	h.notifyWaitlistOffers(offer)
+
+	if payment, err := h.bookingRepo.GetPayment(c.Request.Context(), bookingID); err == nil {
+		h.settleCancelledPayment(c, payment)
+	}
 
 	c.Status(http.StatusNoContent)
 }
@@ -304,22 +363,31 @@ func (h *BookingHandlers) GetUpcomingBookings(c *gin.Context) {
 	c.JSON(http.StatusOK, bookings)
 }
 
-// GetCourtBookings handles GET /api/courts/:courtID/bookings
+// GetCourtBookings handles GET /api/courts/:id/bookings
 func (h *BookingHandlers) GetCourtBookings(c *gin.Context) {
-	courtID, err := uuid.Parse(c.Param("courtID"))
+	courtID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
 		return
 	}
 
+	court, err := h.courtRepo.GetByID(c.Request.Context(), courtID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+
 	startDateStr := c.Query("start_date")
 	endDateStr := c.Query("end_date")
 
+	// Dates are days in the court's timezone
+	loc := court.TimeLocation()
 	var startDate, endDate time.Time
 	if startDateStr == "" {
-		startDate = time.Now().Truncate(24 * time.Hour) // Start of today
+		now := time.Now().In(loc)
+		startDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc) // Start of today
 	} else {
-		startDate, err = time.Parse("2006-01-02", startDateStr)
+		startDate, err = time.ParseInLocation("2006-01-02", startDateStr, loc)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_date format. Use YYYY-MM-DD"})
 			return
@@ -329,7 +397,7 @@ func (h *BookingHandlers) GetCourtBookings(c *gin.Context) {
 	if endDateStr == "" {
 		endDate = startDate.Add(7 * 24 * time.Hour) // Default to 7 days from start
 	} else {
-		endDate, err = time.Parse("2006-01-02", endDateStr)
+		endDate, err = time.ParseInLocation("2006-01-02", endDateStr, loc)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date format. Use YYYY-MM-DD"})
 			return
@@ -355,6 +423,7 @@ func (h *BookingHandlers) GetCourtBookings(c *gin.Context) {
 				Name: user.Name,
 			}
 		}
+		booking.ShareToken = ""
 	}
 
 	c.JSON(http.StatusOK, bookings)
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+// InviteBookingParticipants handles POST /api/bookings/:id/participants,
+// letting the owner invite players by user ID
+func (h *BookingHandlers) InviteBookingParticipants(c *gin.Context) {
+	bookingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	var req models.BookingInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.UserIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	invited, err := h.bookingRepo.InviteParticipants(c.Request.Context(), bookingID, userID, req.UserIDs)
+	if err != nil {
+		h.rosterError(c, err)
+		return
+	}
+
+	booking, err := h.bookingRepo.GetByID(c.Request.Context(), bookingID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get booking: %v", err)})
+		return
+	}
+	start := booking.StartTime
+	if booking.LocalStartTime != nil {
+		start = *booking.LocalStartTime
+	}
+	h.notify(invited, models.NotificationTypeBookingInvite, "You're invited to play",
+		fmt.Sprintf("You've been invited to a %s booking on %s", booking.GameType, start.Format("Mon Jan 2 15:04")), bookingID)
+
+	c.JSON(http.StatusOK, booking)
+}
+
+// RespondToBookingInvite handles POST /api/bookings/:id/respond, where an
+// invitee accepts or declines. Players on the roster can decline to leave.
+func (h *BookingHandlers) RespondToBookingInvite(c *gin.Context) {
+	bookingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	var req models.BookingInviteResponse
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	booking, err := h.bookingRepo.RespondToInvite(c.Request.Context(), bookingID, userID, req.Status)
+	if err != nil {
+		h.rosterError(c, err)
+		return
+	}
+
+	if p := booking.Participant(userID); p != nil {
+		h.notify([]uuid.UUID{booking.UserID}, models.NotificationTypeBookingRoster, "Booking roster updated",
+			fmt.Sprintf("%s has %s your invitation", p.UserName, req.Status), bookingID)
+	}
+
+	booking.ShareToken = ""
+	c.JSON(http.StatusOK, booking)
+}
+
+// JoinBookingByLink handles POST /api/bookings/join/:token, adding the player
+// to the roster of the booking the share link belongs to
+func (h *BookingHandlers) JoinBookingByLink(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	booking, err := h.bookingRepo.JoinByToken(c.Request.Context(), c.Param("token"), userID)
+	if err != nil {
+		h.rosterError(c, err)
+		return
+	}
+
+	if p := booking.Participant(userID); p != nil {
+		h.notify([]uuid.UUID{booking.UserID}, models.NotificationTypeBookingRoster, "Booking roster updated",
+			fmt.Sprintf("%s joined your booking", p.UserName), booking.ID)
+	}
+
+	booking.ShareToken = ""
+	c.JSON(http.StatusOK, booking)
+}
+
+// RemoveBookingParticipant handles DELETE /api/bookings/:id/participants/:userID.
+// The owner can remove any player; players can remove themselves.
+func (h *BookingHandlers) RemoveBookingParticipant(c *gin.Context) {
+	bookingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+	participantID, err := uuid.Parse(c.Param("userID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	booking, err := h.bookingRepo.RemoveParticipant(c.Request.Context(), bookingID, userID, participantID)
+	if err != nil {
+		h.rosterError(c, err)
+		return
+	}
+
+	if userID != booking.UserID {
+		booking.ShareToken = ""
+	}
+	c.JSON(http.StatusOK, booking)
+}
+
+// GetBookingShareLink handles GET /api/bookings/:id/share-link, returning the
+// link the owner can send to let players join
+func (h *BookingHandlers) GetBookingShareLink(c *gin.Context) {
+	bookingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	booking, err := h.bookingRepo.GetByID(c.Request.Context(), bookingID)
+	if err != nil {
+		h.rosterError(c, err)
+		return
+	}
+	if booking.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the booking owner can share it"})
+		return
+	}
+
+	token, err := h.bookingRepo.GetShareToken(c.Request.Context(), bookingID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create share link: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewBookingShareLink(token))
+}
+
+// PublishBookingOpenSpots handles PUT /api/bookings/:id/publish, turning on or
+// off a bulletin that advertises the booking's open spots until it is full
+func (h *BookingHandlers) PublishBookingOpenSpots(c *gin.Context) {
+	bookingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	var req struct {
+		Publish bool `json:"publish"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	booking, err := h.bookingRepo.SetPublishOpenSpots(c.Request.Context(), bookingID, userID, req.Publish)
+	if err != nil {
+		h.rosterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, booking)
+}
+
+// rosterError maps booking roster errors to responses
+func (h *BookingHandlers) rosterError(c *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "booking not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Booking not found"})
+	case strings.Contains(msg, "user not found"), strings.Contains(msg, "participant not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case strings.Contains(msg, "does not belong"):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the booking owner can do that"})
+	case strings.Contains(msg, "no invitation"):
+		c.JSON(http.StatusForbidden, gin.H{"error": "You have not been invited to this booking"})
+	case strings.Contains(msg, "booking is full"), strings.Contains(msg, "already on the roster"):
+		c.JSON(http.StatusConflict, gin.H{"error": msg})
+	case strings.Contains(msg, "no longer active"), strings.Contains(msg, "already started"),
+		strings.Contains(msg, "cannot leave"), strings.Contains(msg, "must be accepted or declined"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update booking roster: %v", err)})
+	}
+}
+
+func (h *BookingHandlers) notify(userIDs []uuid.UUID, notificationType, title, message string, bookingID uuid.UUID) {
+	if h.notificationRepo == nil || len(userIDs) == 0 {
+		return
+	}
+	err := h.notificationRepo.NotifyUsers(context.Background(), userIDs, notificationType, title, message, &bookingID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to send %s notifications: %v\n", notificationType, err)
+	}
+}
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/payments"
+	"github.com/user/tennis-connect/repository"
+)
+
+// GetBookingQuote handles GET /api/courts/:id/quote, pricing a booking for
+// the player before they make it. Takes the same date, start_time and
+// duration as a booking request.
+func (h *BookingHandlers) GetBookingQuote(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	court, err := h.courtRepo.GetByID(c.Request.Context(), courtID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+
+	req := models.BookingRequest{
+		CourtID:   courtID,
+		Date:      c.Query("date"),
+		StartTime: c.Query("start_time"),
+	}
+	if duration := c.Query("duration"); duration != "" {
+		if req.Duration, err = strconv.Atoi(duration); err != nil || req.Duration <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duration"})
+			return
+		}
+	}
+	setBookingDefaults(&req)
+
+	start, ok := parseBookingStart(c, &req, court)
+	if !ok {
+		return
+	}
+
+	quote, err := h.bookingRepo.Quote(c.Request.Context(), courtID, userID, start, start.Add(time.Duration(req.Duration)*time.Minute))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to price booking: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
+
+// GetBookingPayment handles GET /api/bookings/:id/payment for the booking owner
+func (h *BookingHandlers) GetBookingPayment(c *gin.Context) {
+	payment, ok := h.loadOwnPayment(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, payment)
+}
+
+// PayForBooking handles POST /api/bookings/:id/pay. The payment method is
+// authorized and captured with the provider, then the booking is confirmed.
+func (h *BookingHandlers) PayForBooking(c *gin.Context) {
+	var req models.PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	h.expireUnpaidBookings(c.Request.Context())
+
+	payment, ok := h.loadOwnPayment(c)
+	if !ok {
+		return
+	}
+	if !payment.IsOpen() {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Booking is not awaiting payment (payment %s)", payment.Status)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.payments.Confirm(ctx, payment.IntentID, req.PaymentMethod); err != nil {
+		if errors.Is(err, payments.ErrDeclined) {
+			payment.Status = models.PaymentStatusFailed
+			payment.FailureReason = err.Error()
+			if err := h.bookingRepo.UpdatePayment(ctx, payment); err != nil {
+				fmt.Printf("Warning: Failed to record declined payment: %v\n", err)
+			}
+			c.JSON(http.StatusPaymentRequired, gin.H{"error": "Payment was declined", "payment": payment})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Payment provider error: %v", err)})
+		return
+	}
+	if _, err := h.payments.Capture(ctx, payment.IntentID); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Payment provider error: %v", err)})
+		return
+	}
+
+	if err := h.bookingRepo.MarkPaid(ctx, payment); err != nil {
+		// The booking expired or was cancelled while the player was paying
+		if _, refundErr := h.payments.Refund(ctx, payment.IntentID, payment.AmountCents); refundErr != nil {
+			fmt.Printf("Warning: Failed to refund payment %s: %v\n", payment.ID, refundErr)
+		} else {
+			payment.Status = models.PaymentStatusRefunded
+			payment.RefundedCents = payment.AmountCents
+			if err := h.bookingRepo.UpdatePayment(ctx, payment); err != nil {
+				fmt.Printf("Warning: Failed to record refund: %v\n", err)
+			}
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": "Booking expired before payment completed; the payment has been refunded"})
+		return
+	}
+
+	booking, err := h.bookingRepo.GetByID(ctx, payment.BookingID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get booking: %v", err)})
+		return
+	}
+	booking.Payment = payment
+
+	c.JSON(http.StatusOK, booking)
+}
+
+// loadOwnPayment loads the payment for the booking in the URL and checks it
+// belongs to the authenticated user. It writes an error response and returns
+// false on failure.
+func (h *BookingHandlers) loadOwnPayment(c *gin.Context) (*models.BookingPayment, bool) {
+	bookingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return nil, false
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return nil, false
+	}
+
+	payment, err := h.bookingRepo.GetPayment(c.Request.Context(), bookingID)
+	if err != nil {
+		if err.Error() == "payment not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking has no payment"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get payment: %v", err)})
+		return nil, false
+	}
+	if payment.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the booking owner can pay for it"})
+		return nil, false
+	}
+	return payment, true
+}
+
+// startPayment prices a newly created booking. Free bookings are confirmed
+// straight away; paid ones stay pending with a payment intent the player has
+// models.PaymentWindow to pay. It writes an error response and returns false
+// on failure, cancelling the booking so it does not hold the court.
+func (h *BookingHandlers) startPayment(c *gin.Context, booking *models.Booking) bool {
+	err := h.preparePayment(c, booking)
+	if err == nil {
+		return true
+	}
+
+	if cancelErr := h.bookingRepo.UpdateStatus(c.Request.Context(), booking.ID, models.BookingStatusCancelled); cancelErr != nil {
+		fmt.Printf("Warning: Failed to cancel unpayable booking: %v\n", cancelErr)
+	}
+	c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Failed to start payment: %v", err)})
+	return false
+}
+
+// preparePayment confirms a pending booking if it's free, or creates the
+// payment intent the player has to pay to confirm it
+func (h *BookingHandlers) preparePayment(c *gin.Context, booking *models.Booking) error {
+	ctx := c.Request.Context()
+	quote, err := h.bookingRepo.Quote(ctx, booking.CourtID, booking.UserID, booking.StartTime, booking.EndTime)
+	if err != nil {
+		return err
+	}
+	booking.Quote = quote
+	if !quote.IsFree() {
+		return h.createPayment(c, booking, quote)
+	}
+
+	if err = h.bookingRepo.UpdateStatus(ctx, booking.ID, models.BookingStatusConfirmed); err != nil {
+		// Log error but don't fail the request
+		fmt.Printf("Warning: Failed to auto-confirm booking: %v\n", err)
+	} else {
+		booking.Status = models.BookingStatusConfirmed
+	}
+	return nil
+}
+
+func (h *BookingHandlers) createPayment(c *gin.Context, booking *models.Booking, quote *models.BookingQuote) error {
+	ctx := c.Request.Context()
+	intent, err := h.payments.CreateIntent(ctx, quote.AmountCents, quote.Currency, booking.ID.String())
+	if err != nil {
+		return fmt.Errorf("failed to create payment intent: %w", err)
+	}
+
+	payment := &models.BookingPayment{
+		BookingID:   booking.ID,
+		UserID:      booking.UserID,
+		Provider:    h.payments.Name(),
+		IntentID:    intent.ID,
+		AmountCents: quote.AmountCents,
+		Currency:    quote.Currency,
+		Status:      models.PaymentStatusRequiresPayment,
+	}
+	if err := h.bookingRepo.CreatePayment(ctx, payment); err != nil {
+		if _, cancelErr := h.payments.Cancel(ctx, intent.ID); cancelErr != nil {
+			fmt.Printf("Warning: Failed to cancel payment intent %s: %v\n", intent.ID, cancelErr)
+		}
+		return err
+	}
+	payment.ClientSecret = intent.ClientSecret
+	booking.Payment = payment
+	return nil
+}
+
+// settleCancelledPayment refunds a cancelled booking's payment as far as the
+// court's cancellation policy allows, or abandons it if it was never paid
+func (h *BookingHandlers) settleCancelledPayment(c *gin.Context, payment *models.BookingPayment) {
+	ctx := c.Request.Context()
+	refund := 0
+	if payment.Status == models.PaymentStatusSucceeded {
+		var err error
+		if refund, err = h.bookingRepo.RefundDue(ctx, payment, time.Now()); err != nil {
+			fmt.Printf("Warning: Failed to work out refund for payment %s: %v\n", payment.ID, err)
+			return
+		}
+	}
+	settlePayment(ctx, h.payments, h.bookingRepo, payment, refund)
+}
+
+// settlePayment settles the payment of a cancelled booking, cancelling its
+// intent if it was never paid or refunding the given amount if it was
+func settlePayment(ctx context.Context, provider payments.Provider, bookingRepo *repository.BookingRepository, payment *models.BookingPayment, refund int) {
+	switch {
+	case payment.IsOpen():
+		if _, err := provider.Cancel(ctx, payment.IntentID); err != nil {
+			fmt.Printf("Warning: Failed to cancel payment intent %s: %v\n", payment.IntentID, err)
+		}
+		payment.Status = models.PaymentStatusCancelled
+	case payment.Status == models.PaymentStatusSucceeded:
+		if refund == 0 {
+			return
+		}
+		if _, err := provider.Refund(ctx, payment.IntentID, refund); err != nil {
+			fmt.Printf("Warning: Failed to refund payment %s: %v\n", payment.ID, err)
+			return
+		}
+		payment.RefundedCents += refund
+		payment.Status = models.PaymentStatusPartiallyRefunded
+		if payment.RefundedCents == payment.AmountCents {
+			payment.Status = models.PaymentStatusRefunded
+		}
+	default:
+		return
+	}
+
+	if err := bookingRepo.UpdatePayment(ctx, payment); err != nil {
+		fmt.Printf("Warning: Failed to update payment %s: %v\n", payment.ID, err)
+	}
+}
+
+// settleCancelledPayments settles the payments of bookings that have just
+// been cancelled
+func (h *BookingHandlers) settleCancelledPayments(c *gin.Context, bookings ...*models.Booking) {
+	for _, booking := range bookings {
+		if payment, err := h.bookingRepo.GetPayment(c.Request.Context(), booking.ID); err == nil {
+			h.settleCancelledPayment(c, payment)
+		}
+	}
+}
+
+// expireUnpaidBookings releases courts held by bookings that were not paid in
+// time, cancelling their payment intents
+func (h *BookingHandlers) expireUnpaidBookings(ctx context.Context) {
+	expired, offers, err := h.bookingRepo.ExpireUnpaidBookings(ctx)
+	if err != nil {
+		fmt.Printf("Warning: Failed to expire unpaid bookings: %v\n", err)
+	}
+	for _, payment := range expired {
+		if _, err := h.payments.Cancel(ctx, payment.IntentID); err != nil {
+			fmt.Printf("Warning: Failed to cancel payment intent %s: %v\n", payment.IntentID, err)
+		}
+	}
+	h.notifyWaitlistOffers(offers...)
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+// CreateBookingSeries handles POST /api/bookings/series, booking a court on
+// a daily or weekly basis until an end date. Every occurrence is checked up
+// front; unless skip_conflicts is set, any unavailable occurrence rejects the
+// series with a 409 and a report on each occurrence. At paid courts each
+// occurrence has its own payment, returned with its booking; at courts that
+// need approval each is a request for the managers.
+func (h *BookingHandlers) CreateBookingSeries(c *gin.Context) {
+	var req models.BookingSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	setBookingDefaults(&req.BookingRequest)
+
+	court, err := h.courtRepo.GetByID(c.Request.Context(), req.CourtID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+
+	firstStart, ok := parseBookingStart(c, &req.BookingRequest, court)
+	if !ok {
+		return
+	}
+
+	series := &models.BookingSeries{
+		CourtID:         req.CourtID,
+		CourtUnitID:     req.CourtUnitID,
+		UserID:          userID,
+		FirstStartTime:  firstStart,
+		DurationMinutes: req.Duration,
+		PlayerCount:     req.PlayerCount,
+		GameType:        req.GameType,
+		Notes:           req.Notes,
+		RecurrenceRule:  req.RecurrenceRule,
+	}
+	if err := series.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.bookingRepo.CreateSeries(c.Request.Context(), series, req.SkipConflicts)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "series has conflicts"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "occurrences": series.Occurrences})
+		case strings.Contains(err.Error(), "no bookable units"):
+			c.JSON(http.StatusConflict, gin.H{"error": "Court has no bookable courts"})
+		case strings.Contains(err.Error(), "court unit not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Court unit not found"})
+		case strings.Contains(err.Error(), "in the past"), strings.Contains(err.Error(), "no occurrences"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create booking series: %v", err)})
+		}
+		return
+	}
+
+	// Each occurrence is confirmed now if it's free, or waits for its own
+	// payment like a single booking. At courts that need approval they wait
+	// for a manager first and are paid for once approved.
+	requests := 0
+	for _, booking := range series.Bookings {
+		if booking.AwaitingApproval() {
+			requests++
+			continue
+		}
+		if err := h.preparePayment(c, booking); err != nil {
+			h.abandonSeries(c, series)
+			c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Failed to start payment: %v", err)})
+			return
+		}
+	}
+	if requests > 0 {
+		h.notify(court.ManagerIDs, models.NotificationTypeBookingRequest, "New booking request",
+			fmt.Sprintf("A player has asked to book %s %s from %s until %s (%d bookings)", court.Name,
+				series.Frequency, formatBookingStart(series.Bookings[0]), series.Until, requests), series.Bookings[0].ID)
+	}
+
+	c.JSON(http.StatusCreated, series)
+}
+
+// abandonSeries cancels a series whose payments could not be set up, along
+// with its bookings and any payment intents already created for them
+func (h *BookingHandlers) abandonSeries(c *gin.Context, series *models.BookingSeries) {
+	if err := h.bookingRepo.AbandonSeries(c.Request.Context(), series.ID); err != nil {
+		fmt.Printf("Warning: Failed to cancel unpayable booking series: %v\n", err)
+	}
+	for _, booking := range series.Bookings {
+		if booking.Payment != nil {
+			h.settleCancelledPayment(c, booking.Payment)
+		}
+	}
+}
+
+// GetBookingSeries handles GET /api/bookings/series/:id, returning the series
+// with the status of each occurrence
+func (h *BookingHandlers) GetBookingSeries(c *gin.Context) {
+	seriesID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.bookingRepo.GetSeries(c.Request.Context(), seriesID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking series not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get booking series: %v", err)})
+		return
+	}
+	if series.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Booking series does not belong to you"})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// GetMyBookingSeries handles GET /api/bookings/series
+func (h *BookingHandlers) GetMyBookingSeries(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.bookingRepo.GetSeriesByUserID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get booking series: %v", err)})
+		return
+	}
+	if series == nil {
+		series = []*models.BookingSeries{}
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// SkipBookingSeriesOccurrence handles DELETE /api/bookings/series/:id/occurrences/:date,
+// skipping one occurrence and cancelling its booking
+func (h *BookingHandlers) SkipBookingSeriesOccurrence(c *gin.Context) {
+	seriesID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+	date := c.Param("date")
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format. Use YYYY-MM-DD"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	cancelled, err := h.bookingRepo.SkipOccurrence(c.Request.Context(), seriesID, userID, date)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking series not found"})
+		case strings.Contains(err.Error(), "does not belong"):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Booking series does not belong to you"})
+		case strings.Contains(err.Error(), "no occurrence"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "cannot be cancelled"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Booking cannot be cancelled (too close to start time)"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to skip occurrence: %v", err)})
+		}
+		return
+	}
+
+	if cancelled != nil {
+		h.settleCancelledPayments(c, cancelled)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Occurrence skipped"})
+}
+
+// CancelBookingSeries handles DELETE /api/bookings/series/:id, cancelling the
+// series and its upcoming bookings. Bookings already inside the court's
+// cancellation cutoff are kept and listed in the response.
+func (h *BookingHandlers) CancelBookingSeries(c *gin.Context) {
+	seriesID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid series ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	cancelled, kept, err := h.bookingRepo.CancelSeries(c.Request.Context(), seriesID, userID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Booking series not found"})
+		case strings.Contains(err.Error(), "does not belong"):
+			c.JSON(http.StatusForbidden, gin.H{"error": "Booking series does not belong to you"})
+		case strings.Contains(err.Error(), "already cancelled"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Booking series is already cancelled"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to cancel booking series: %v", err)})
+		}
+		return
+	}
+	h.settleCancelledPayments(c, cancelled...)
+	if kept == nil {
+		kept = []*models.Booking{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Booking series cancelled", "kept_bookings": kept})
+}
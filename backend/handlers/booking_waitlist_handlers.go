@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+// JoinBookingWaitlist handles POST /api/bookings/waitlist, putting the player
+// in line for a court and time that is already taken
+func (h *BookingHandlers) JoinBookingWaitlist(c *gin.Context) {
+	var req models.BookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	setBookingDefaults(&req)
+
+	court, err := h.courtRepo.GetByID(c.Request.Context(), req.CourtID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+
+	startDateTime, ok := parseBookingStart(c, &req, court)
+	if !ok {
+		return
+	}
+	h.expireWaitlistHolds(c.Request.Context())
+	h.expireStaleBookings(c.Request.Context())
+
+	entry := &models.BookingWaitlistEntry{
+		CourtID:     req.CourtID,
+		CourtUnitID: req.CourtUnitID,
+		UserID:      userID,
+		StartTime:   startDateTime,
+		EndTime:     startDateTime.Add(time.Duration(req.Duration) * time.Minute),
+		PlayerCount: req.PlayerCount,
+		GameType:    req.GameType,
+		Notes:       req.Notes,
+	}
+	if err := h.bookingRepo.JoinWaitlist(c.Request.Context(), entry); err != nil {
+		h.waitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// GetMyBookingWaitlist handles GET /api/bookings/waitlist, listing the
+// player's place in line and any courts being held for them
+func (h *BookingHandlers) GetMyBookingWaitlist(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+	h.expireWaitlistHolds(c.Request.Context())
+
+	entries, err := h.bookingRepo.GetWaitlistByUserID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get waitlist: %v", err)})
+		return
+	}
+	if entries == nil {
+		entries = []*models.BookingWaitlistEntry{}
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// LeaveBookingWaitlist handles DELETE /api/bookings/waitlist/:id. A court held
+// for the player passes to the next in line.
+func (h *BookingHandlers) LeaveBookingWaitlist(c *gin.Context) {
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid waitlist entry ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	offer, err := h.bookingRepo.LeaveWaitlist(c.Request.Context(), entryID, userID)
+	if err != nil {
+		h.waitlistError(c, err)
+		return
+	}
+	h.notifyWaitlistOffers(offer)
+
+	c.Status(http.StatusNoContent)
+}
+
+// ClaimBookingWaitlistHold handles POST /api/bookings/waitlist/claim/:token,
+// turning the court held for the player into a booking
+func (h *BookingHandlers) ClaimBookingWaitlistHold(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+	h.expireWaitlistHolds(c.Request.Context())
+
+	booking, err := h.bookingRepo.ClaimHold(c.Request.Context(), c.Param("token"), userID)
+	if err != nil {
+		h.waitlistError(c, err)
+		return
+	}
+
+	if !h.submitBooking(c, booking) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, booking)
+}
+
+// expireWaitlistHolds lapses unclaimed holds and notifies whoever the courts
+// pass to. Holds are swept whenever the waitlist is used as well as by
+// StartBookingMaintenance.
+func (h *BookingHandlers) expireWaitlistHolds(ctx context.Context) {
+	offers, err := h.bookingRepo.ExpireHolds(ctx)
+	if err != nil {
+		fmt.Printf("Warning: Failed to expire waitlist holds: %v\n", err)
+		return
+	}
+	h.notifyWaitlistOffers(offers...)
+}
+
+func (h *BookingHandlers) notifyWaitlistOffers(offers ...*models.BookingWaitlistEntry) {
+	for _, offer := range offers {
+		if offer == nil {
+			continue
+		}
+		start := offer.StartTime
+		if offer.LocalStartTime != nil {
+			start = *offer.LocalStartTime
+		}
+		h.notify([]uuid.UUID{offer.UserID}, models.NotificationTypeBookingWaitlistOffer, "A court has opened up",
+			fmt.Sprintf("A court is free on %s. It's held for you until %s: %s",
+				start.Format("Mon Jan 2 15:04"), offer.HoldExpiresAt.In(start.Location()).Format("15:04"), offer.ClaimLink.Path),
+			offer.ID)
+	}
+}
+
+// waitlistError maps booking waitlist errors to responses
+func (h *BookingHandlers) waitlistError(c *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "entry not found"), strings.Contains(msg, "hold not found"),
+		strings.Contains(msg, "unit not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case strings.Contains(msg, "does not belong"):
+		c.JSON(http.StatusForbidden, gin.H{"error": msg})
+	case strings.Contains(msg, "court is available"), strings.Contains(msg, "already on the waitlist"),
+		strings.Contains(msg, "not available"), strings.Contains(msg, "court is closed"):
+		c.JSON(http.StatusConflict, gin.H{"error": msg})
+	case strings.Contains(msg, "hold has"), strings.Contains(msg, "no longer open"), strings.Contains(msg, "past"),
+		strings.Contains(msg, "must be after"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+	case strings.Contains(msg, "booking not allowed"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": strings.TrimPrefix(msg, "booking not allowed: ")})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to update waitlist: %v", err)})
+	}
+}
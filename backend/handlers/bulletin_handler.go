@@ -8,19 +8,26 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/user/tennis-connect/geocoding"
 	"github.com/user/tennis-connect/models"
 	"github.com/user/tennis-connect/repository"
 )
 
 // BulletinHandler handles bulletin-related HTTP requests
 type BulletinHandler struct {
-	bulletinRepo *repository.BulletinRepository
+	bulletinRepo     *repository.BulletinRepository
+	courtRepo        *repository.CourtRepository
+	notificationRepo *repository.NotificationRepository
+	geocoder         geocoding.Geocoder
 }
 
 // NewBulletinHandler creates a new BulletinHandler
-func NewBulletinHandler(bulletinRepo *repository.BulletinRepository) *BulletinHandler {
+func NewBulletinHandler(bulletinRepo *repository.BulletinRepository, courtRepo *repository.CourtRepository, notificationRepo *repository.NotificationRepository, geocoder geocoding.Geocoder) *BulletinHandler {
 	return &BulletinHandler{
-		bulletinRepo: bulletinRepo,
+		bulletinRepo:     bulletinRepo,
+		courtRepo:        courtRepo,
+		notificationRepo: notificationRepo,
+		geocoder:         geocoder,
 	}
 }
 
@@ -31,8 +38,13 @@ func (h *BulletinHandler) GetBulletins(c *gin.Context) {
 	lng, _ := strconv.ParseFloat(c.DefaultQuery("longitude", "-122.4194"), 64)
 	radiusStr := c.Query("radius")
 	var radius float64 = -1 // -1 means no radius limit
+	unit, ok := parseDistanceUnit(c)
+	if !ok {
+		return
+	}
 	if radiusStr != "" {
 		radius, _ = strconv.ParseFloat(radiusStr, 64)
+		radius = unit.ToKm(radius)
 	}
 
 	// Optional filters
@@ -127,15 +139,16 @@ func (h *BulletinHandler) CreateBulletin(c *gin.Context) {
 	bulletin.UserName = userName.(string)
 	bulletin.IsActive = true
 
-	// If latitude and longitude are not provided, set default values based on city
+	// If latitude and longitude are not provided, geocode the city/state/zipcode
 	if bulletin.Location.Latitude == 0 && bulletin.Location.Longitude == 0 {
-		// Set default coordinates based on city or use a geocoding service
-		// For now, use San Francisco as default if no coordinates provided
-		bulletin.Location.Latitude = 37.7749
-		bulletin.Location.Longitude = -122.4194
-		
-		// In a production system, you would geocode the city/state/zipcode
-		// to get actual coordinates
+		if query, err := geocodeLocation(c.Request.Context(), h.geocoder, &bulletin.Location); err != nil {
+			if query == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "A location is required: coordinates, or a city or zip code"})
+				return
+			}
+			writeGeocodingError(c, query, err, http.StatusBadRequest)
+			return
+		}
 	}
 
 	// Save to database
@@ -145,6 +158,9 @@ func (h *BulletinHandler) CreateBulletin(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create bulletin: " + err.Error()})
 		return
 	}
+	if bulletin.CourtID != nil {
+		notifyCourtSubscribers(h.courtRepo, h.notificationRepo, *bulletin.CourtID, models.CourtActivityBulletins, userID, bulletin.Title, bulletin.ID)
+	}
 
 	c.JSON(http.StatusCreated, bulletin)
 }
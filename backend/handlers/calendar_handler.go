@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/repository"
+	"github.com/user/tennis-connect/utils"
+)
+
+// CalendarHandler handles calendar feeds, .ics downloads and availability
+// imported from players' own calendars
+type CalendarHandler struct {
+	calendarRepo *repository.CalendarRepository
+}
+
+// NewCalendarHandler creates a new CalendarHandler
+func NewCalendarHandler(calendarRepo *repository.CalendarRepository) *CalendarHandler {
+	return &CalendarHandler{calendarRepo: calendarRepo}
+}
+
+// GetCalendarFeed handles GET /api/calendar/feed, returning the player's
+// subscription links
+func (h *CalendarHandler) GetCalendarFeed(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	token, err := h.calendarRepo.GetFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get calendar feed: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, newCalendarFeed(c, token))
+}
+
+// ResetCalendarFeed handles POST /api/calendar/feed/reset for a player whose
+// link has leaked. Calendars subscribed to the old link stop updating.
+func (h *CalendarHandler) ResetCalendarFeed(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	token, err := h.calendarRepo.ResetFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to reset calendar feed: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, newCalendarFeed(c, token))
+}
+
+// GetCalendarFeedICS handles GET /api/calendar/feed/:token. Calendar apps
+// can't log in, so the secret token in the URL is the only credential.
+func (h *CalendarHandler) GetCalendarFeedICS(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	userID, err := h.calendarRepo.GetUserIDByFeedToken(c.Request.Context(), token)
+	if err != nil {
+		if err.Error() == "calendar feed not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Calendar feed not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get calendar feed: %v", err)})
+		return
+	}
+
+	now := time.Now()
+	entries, err := h.calendarRepo.GetEntries(c.Request.Context(), userID, now.Add(-models.CalendarFeedHistory))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get calendar: %v", err)})
+		return
+	}
+
+	writeICS(c, "tennis-connect.ics", "Tennis Connect", entries, now)
+}
+
+// DownloadBookingICS handles GET /api/bookings/:id/ics
+func (h *CalendarHandler) DownloadBookingICS(c *gin.Context) {
+	h.downloadEntry(c, "booking", h.calendarRepo.GetBookingEntry)
+}
+
+// DownloadEventICS handles GET /api/events/:id/ics
+func (h *CalendarHandler) DownloadEventICS(c *gin.Context) {
+	h.downloadEntry(c, "event", h.calendarRepo.GetEventEntry)
+}
+
+// DownloadMatchICS handles GET /api/matching/pairings/:id/ics
+func (h *CalendarHandler) DownloadMatchICS(c *gin.Context) {
+	h.downloadEntry(c, "match", h.calendarRepo.GetMatchEntry)
+}
+
+func (h *CalendarHandler) downloadEntry(c *gin.Context, kind string,
+	get func(ctx context.Context, id, userID uuid.UUID) (*models.CalendarEntry, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid %s ID", kind)})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := get(c.Request.Context(), id, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": strings.ToUpper(kind[:1]) + kind[1:] + " not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get %s: %v", kind, err)})
+		return
+	}
+
+	writeICS(c, fmt.Sprintf("%s-%s.ics", kind, entry.ID), entry.Summary, []*models.CalendarEntry{entry}, time.Now())
+}
+
+// GetAvailability handles GET /api/calendar/availability, listing the busy
+// time imported from the player's calendar for the next few days
+func (h *CalendarHandler) GetAvailability(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	days := 14
+	if value := c.Query("days"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > int(models.AvailabilityImportHorizon.Hours()/24) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days"})
+			return
+		}
+		days = n
+	}
+
+	now := time.Now()
+	availability, err := h.calendarRepo.GetAvailability(c.Request.Context(), userID, now, now.AddDate(0, 0, days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get availability: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+// ImportAvailability handles PUT /api/calendar/availability. The player
+// uploads an .ics export as the "file" form field or pastes it in as JSON.
+// Only the busy times are kept, replacing any earlier import.
+func (h *CalendarHandler) ImportAvailability(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	// Leave room for the form or JSON around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, models.MaxCalendarImportBytes+64<<10)
+	var ics io.Reader
+	var timezone string
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Upload the calendar as the file field"})
+			return
+		}
+		if header.Size > models.MaxCalendarImportBytes {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Calendar file is too large"})
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read calendar file"})
+			return
+		}
+		defer file.Close()
+		ics, timezone = file, c.PostForm("timezone")
+	} else {
+		var req models.CalendarImportRequest
+		if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.ICS) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		ics, timezone = strings.NewReader(req.ICS), req.Timezone
+	}
+
+	// Times without a zone are read in the player's own timezone
+	if timezone == "" {
+		var err error
+		if timezone, err = h.calendarRepo.GetUserTimezone(c.Request.Context(), userID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get timezone: %v", err)})
+			return
+		}
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+		return
+	}
+
+	now := time.Now()
+	until := now.Add(models.AvailabilityImportHorizon)
+	periods, err := utils.ParseICalBusy(ics, loc, now, until)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Could not read calendar: %v", err)})
+		return
+	}
+	busy := make([]models.TimeRange, len(periods))
+	for i, period := range periods {
+		busy[i] = models.TimeRange{Start: period.Start.UTC(), End: period.End.UTC()}
+	}
+	busy = models.MergeTimeRanges(busy)
+	if err := models.ValidateBusyTimes(busy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	availability, err := h.calendarRepo.ReplaceBusyTimes(c.Request.Context(), userID, busy, until)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to save availability: %v", err)})
+		return
+	}
+	if availability.Busy == nil {
+		availability.Busy = []models.TimeRange{}
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+// ClearAvailability handles DELETE /api/calendar/availability, forgetting the
+// player's imported calendar
+func (h *CalendarHandler) ClearAvailability(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.calendarRepo.ClearAvailability(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to clear availability: %v", err)})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// writeICS renders entries as an iCalendar document
+func writeICS(c *gin.Context, filename, name string, entries []*models.CalendarEntry, now time.Time) {
+	events := make([]utils.ICalEvent, len(entries))
+	for i, entry := range entries {
+		events[i] = utils.ICalEvent{
+			UID:          entry.UID(),
+			Sequence:     entry.Sequence(),
+			Summary:      entry.Summary,
+			Description:  entry.Description,
+			Location:     entry.Location,
+			Status:       entry.Status,
+			Start:        entry.StartTime,
+			End:          entry.EndTime,
+			Timezone:     entry.Timezone,
+			Created:      entry.CreatedAt,
+			LastModified: entry.UpdatedAt,
+		}
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(utils.RenderICalendar(name, events, now)))
+}
+
+// newCalendarFeed builds the feed links for the host the request came in on
+func newCalendarFeed(c *gin.Context, token string) models.CalendarFeed {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return models.NewCalendarFeed(token, scheme, c.Request.Host)
+}
@@ -28,7 +28,11 @@ func (h *CommunityHandler) GetCommunities(c *gin.Context) {
 	// Parse location parameters
 	lat, _ := strconv.ParseFloat(c.DefaultQuery("latitude", "37.7749"), 64)
 	lng, _ := strconv.ParseFloat(c.DefaultQuery("longitude", "-122.4194"), 64)
-	radius, _ := strconv.ParseFloat(c.DefaultQuery("radius", "25"), 64) // Default 25 miles for communities
+	radius, _ := strconv.ParseFloat(c.DefaultQuery("radius", "25"), 64) // Communities search further by default
+	unit, ok := parseDistanceUnit(c)
+	if !ok {
+		return
+	}
 
 	// Optional filters
 	communityType := c.Query("type") // General, Location-based, Women-only, etc.
@@ -45,7 +49,7 @@ func (h *CommunityHandler) GetCommunities(c *gin.Context) {
 
 	// Query the database
 	ctx := context.Background()
-	communities, totalCount, err := h.communityRepo.GetCommunities(ctx, lat, lng, radius, filters, page, limit)
+	communities, totalCount, err := h.communityRepo.GetCommunities(ctx, lat, lng, unit.ToKm(radius), filters, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch communities: " + err.Error()})
 		return
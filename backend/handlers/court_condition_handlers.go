@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+// ReportCourtCondition handles POST /api/courts/:id/conditions, where players
+// report a court as wet, crowded, closed and so on. Managers are alerted once
+// enough reports come in.
+func (h *CourtHandler) ReportCourtCondition(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CourtConditionReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	req.PhotoURL = strings.TrimSpace(req.PhotoURL)
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	court, err := h.courtRepo.GetByID(ctx, courtID)
+	if err != nil || !court.IsListed() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+
+	report := &models.CourtConditionReport{
+		CourtID:   court.ID,
+		UserID:    userID,
+		Condition: req.Condition,
+		Comment:   req.Comment,
+		PhotoURL:  req.PhotoURL,
+	}
+	condition, flag, err := h.courtRepo.ReportCondition(ctx, report, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to report condition: " + err.Error()})
+		return
+	}
+	if flag != nil {
+		h.notify(court.ManagerIDs, models.NotificationTypeConditionFlagged, "Court condition reported",
+			models.ConditionFlagMessage(court.Name, *condition), court.ID)
+	}
+	message := condition.Label
+	if report.Comment != "" {
+		message += ": " + report.Comment
+	}
+	notifyCourtSubscribers(h.courtRepo, h.notificationRepo, court.ID, models.CourtActivityConditions, userID, message, court.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"report":    report,
+		"condition": condition,
+	})
+}
+
+// GetCourtConditions handles GET /api/courts/:id/conditions
+func (h *CourtHandler) GetCourtConditions(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+
+	conditions, err := h.courtRepo.GetConditions(c.Request.Context(), courtID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch court conditions: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"conditions": conditions})
+}
+
+// GetConditionFlags handles GET /api/courts/:id/condition-flags, the
+// conditions awaiting a manager's attention
+func (h *CourtHandler) GetConditionFlags(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+
+	flags, err := h.courtRepo.GetConditionFlags(c.Request.Context(), court.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch condition flags: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flags": flags})
+}
+
+// ResolveCourtCondition handles POST /api/courts/:id/conditions/:condition/resolve.
+// Managers mark a condition fixed, or dismiss the reports as wrong.
+func (h *CourtHandler) ResolveCourtCondition(c *gin.Context) {
+	court, userID, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+	condition := c.Param("condition")
+	if !models.IsValidCondition(condition) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown condition %s", condition)})
+		return
+	}
+
+	var req models.CourtConditionResolveRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	resolved, err := h.courtRepo.ResolveCondition(c.Request.Context(), court.ID, condition, userID, req.Dismissed, time.Now())
+	if err != nil {
+		if strings.Contains(err.Error(), "no open reports") {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve condition: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Condition resolved successfully",
+		"resolved_reports": resolved,
+	})
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/repository"
+)
+
+// GetFavoriteCourts handles GET /api/courts/favorites, returning the
+// authenticated player's favorite courts with their home court first
+func (h *CourtHandler) GetFavoriteCourts(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	favorites, err := h.courtRepo.GetFavorites(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch favorite courts: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"favorites": favorites})
+}
+
+// FavoriteCourt handles PUT /api/courts/:id/favorite, favoriting the court or
+// changing what the player is notified of there. Subscriptions are any of
+// bulletins, events, check_ins and conditions, or all; none just favorites it.
+func (h *CourtHandler) FavoriteCourt(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	// An empty body favorites the court without any subscriptions
+	var req models.FavoriteCourtRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	subscriptions, err := models.NormalizeCourtActivities(req.Subscriptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	court, err := h.courtRepo.GetByID(ctx, courtID)
+	if err != nil || !court.IsListed() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+
+	favorite := &models.FavoriteCourt{UserID: userID, CourtID: court.ID, Subscriptions: subscriptions}
+	if err := h.courtRepo.SaveFavorite(ctx, favorite); err != nil {
+		if strings.Contains(err.Error(), "limit") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to favorite court: " + err.Error()})
+		return
+	}
+	favorite.Court = court
+
+	c.JSON(http.StatusOK, favorite)
+}
+
+// UnfavoriteCourt handles DELETE /api/courts/:id/favorite. Unfavoriting the
+// player's home court also clears it.
+func (h *CourtHandler) UnfavoriteCourt(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.courtRepo.RemoveFavorite(c.Request.Context(), userID, courtID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Court is not a favorite"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unfavorite court: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Court removed from favorites"})
+}
+
+// SetHomeCourt handles PUT /api/courts/:id/home, making the court the
+// player's home court. It's shown on their profile and favorited with every
+// subscription if it isn't a favorite already.
+func (h *CourtHandler) SetHomeCourt(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	court, err := h.courtRepo.GetByID(ctx, courtID)
+	if err != nil || !court.IsListed() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+	if err := h.courtRepo.SetHomeCourt(ctx, userID, &court.ID); err != nil {
+		if strings.Contains(err.Error(), "limit") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set home court: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"home_court": models.HomeCourt{ID: court.ID, Name: court.Name, City: court.Location.City},
+	})
+}
+
+// ClearHomeCourt handles DELETE /api/courts/home. The court stays a favorite.
+func (h *CourtHandler) ClearHomeCourt(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.courtRepo.SetHomeCourt(c.Request.Context(), userID, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear home court: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Home court cleared"})
+}
+
+// notifyCourtSubscribers tells the players subscribed to an activity at a
+// court about it, leaving out the player behind it. Failures are logged
+// rather than failing the request that caused the activity.
+func notifyCourtSubscribers(courtRepo *repository.CourtRepository, notificationRepo *repository.NotificationRepository, courtID uuid.UUID, activity string, actorID uuid.UUID, message string, referenceID uuid.UUID) {
+	if courtRepo == nil || notificationRepo == nil || courtID == uuid.Nil {
+		return
+	}
+
+	ctx := context.Background()
+	subscribers, err := courtRepo.GetCourtSubscribers(ctx, courtID, activity, actorID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load subscribers for court %s: %v\n", courtID, err)
+		return
+	}
+	if len(subscribers) == 0 {
+		return
+	}
+	court, err := courtRepo.GetByID(ctx, courtID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to load court %s for subscribers: %v\n", courtID, err)
+		return
+	}
+
+	notificationType, title := models.CourtActivityNotification(activity, court.Name)
+	if err := notificationRepo.NotifyUsers(ctx, subscribers, notificationType, title, message, &referenceID); err != nil {
+		fmt.Printf("Warning: Failed to send %s notifications: %v\n", notificationType, err)
+	}
+}
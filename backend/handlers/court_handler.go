@@ -2,52 +2,92 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/payments"
 	"github.com/user/tennis-connect/repository"
 )
 
 // CourtHandler handles court-related HTTP requests
 type CourtHandler struct {
-	courtRepo *repository.CourtRepository
+	courtRepo        *repository.CourtRepository
+	bookingRepo      *repository.BookingRepository
+	notificationRepo *repository.NotificationRepository
+	payments         payments.Provider
 }
 
 // NewCourtHandler creates a new CourtHandler
-func NewCourtHandler(courtRepo *repository.CourtRepository) *CourtHandler {
+func NewCourtHandler(courtRepo *repository.CourtRepository, bookingRepo *repository.BookingRepository, notificationRepo *repository.NotificationRepository, paymentProvider payments.Provider) *CourtHandler {
 	return &CourtHandler{
-		courtRepo: courtRepo,
+		courtRepo:        courtRepo,
+		bookingRepo:      bookingRepo,
+		notificationRepo: notificationRepo,
+		payments:         paymentProvider,
 	}
 }
 
 // GetCourts returns a list of courts filtered by location
 func (h *CourtHandler) GetCourts(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
 	// Parse location parameters
 	lat, _ := strconv.ParseFloat(c.DefaultQuery("latitude", "37.7749"), 64)
 	lng, _ := strconv.ParseFloat(c.DefaultQuery("longitude", "-122.4194"), 64)
 	radius, _ := strconv.ParseFloat(c.DefaultQuery("radius", "10"), 64)
+	unit, ok := parseDistanceUnit(c)
+	if !ok {
+		return
+	}
 
 	// Optional filters
 	courtType := c.Query("court_type")
 	amenities := c.QueryArray("amenities")
 	isPublicOnly := c.Query("public_only") == "true"
 	hasActivePlayers := c.Query("has_active_players") == "true"
+	favoritesOnly := c.Query("favorites_only") == "true"
+	minRating, _ := strconv.ParseFloat(c.DefaultQuery("min_rating", "0"), 64)
+	sortBy := c.DefaultQuery("sort", models.CourtSortDistance)
+	if sortBy != models.CourtSortDistance && sortBy != models.CourtSortRating {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort. Use distance or rating"})
+		return
+	}
 
 	// Pagination parameters
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
+	// Occupancy is shown for the hour starting at "at", or now
+	at := time.Now()
+	if atStr := c.Query("at"); atStr != "" {
+		var err error
+		at, err = time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid at time. Use RFC3339"})
+			return
+		}
+	}
+
 	// Query the database
 	ctx := context.Background()
-	courts, totalCount, err := h.courtRepo.GetCourts(ctx, lat, lng, radius, courtType, amenities, isPublicOnly, hasActivePlayers, page, limit)
+	courts, totalCount, err := h.courtRepo.GetCourts(ctx, userID, lat, lng, unit.ToKm(radius), courtType, amenities, isPublicOnly, hasActivePlayers, favoritesOnly, minRating, sortBy, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch courts: " + err.Error()})
 		return
 	}
+	if err := h.courtRepo.AttachOccupancy(ctx, courts, at, at.Add(time.Hour)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch court occupancy: " + err.Error()})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"courts": courts,
@@ -75,6 +115,30 @@ func (h *CourtHandler) GetCourtDetails(c *gin.Context) {
 		return
 	}
 
+	// Courts still in the approval queue are only visible to their submitter and moderators
+	if court.Status == models.CourtStatusPending || court.Status == models.CourtStatusRejected {
+		userID, ok := getAuthenticatedUserID(c)
+		if !ok {
+			return
+		}
+		if !h.canManage(c, court, userID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+			return
+		}
+	}
+
+	// Player-reported conditions, e.g. wet or lights out, and ratings
+	if court.IsListed() {
+		court.Conditions, err = h.courtRepo.GetConditions(ctx, courtID, time.Now())
+		if err != nil {
+			fmt.Printf("Warning: Failed to load conditions for court %s: %v\n", courtID, err)
+		}
+		court.Rating, err = h.courtRepo.GetRating(ctx, courtID)
+		if err != nil {
+			fmt.Printf("Warning: Failed to load rating for court %s: %v\n", courtID, err)
+		}
+	}
+
 	c.JSON(http.StatusOK, court)
 }
 
@@ -104,13 +168,19 @@ func (h *CourtHandler) CheckInToCourt(c *gin.Context) {
 	userName, _ := c.Get("userName")
 
 	var checkInData struct {
-		Message string `json:"message"`
+		Message         string `json:"message"`
+		DurationMinutes int    `json:"duration_minutes"` // How long the player expects to stay; defaults to two hours
 	}
 
 	if err := c.ShouldBindJSON(&checkInData); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	duration, err := models.CheckInDuration(checkInData.DurationMinutes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Create check-in
 	checkIn := &models.CheckIn{
@@ -123,11 +193,16 @@ func (h *CourtHandler) CheckInToCourt(c *gin.Context) {
 
 	// Save to database
 	ctx := context.Background()
-	err = h.courtRepo.CheckInUser(ctx, checkIn)
+	err = h.courtRepo.CheckInUser(ctx, checkIn, duration)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check in: " + err.Error()})
 		return
 	}
+	message := checkIn.UserName + " checked in"
+	if checkIn.Message != "" {
+		message += ": " + checkIn.Message
+	}
+	notifyCourtSubscribers(h.courtRepo, h.notificationRepo, courtID, models.CourtActivityCheckIns, userID, message, courtID)
 
 	c.JSON(http.StatusCreated, checkIn)
 }
@@ -166,3 +241,791 @@ func (h *CourtHandler) CheckOutFromCourt(c *gin.Context) {
 
 	c.JSON(http.StatusOK, checkIn)
 }
+
+// GetCourtOccupancy handles GET /api/courts/:id/occupancy: players checked in
+// now, courts in use versus free, and typical busyness by hour of the week
+func (h *CourtHandler) GetCourtOccupancy(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+
+	occupancy, err := h.courtRepo.GetOccupancy(c.Request.Context(), courtID, time.Now())
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch court occupancy: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, occupancy)
+}
+
+// CreateCourt handles POST /api/courts. Courts created by moderators are
+// listed straight away; everyone else's go into the approval queue.
+func (h *CourtHandler) CreateCourt(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var court models.Court
+	if err := c.ShouldBindJSON(&court); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := court.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	isModerator, err := h.courtRepo.IsModerator(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create court: " + err.Error()})
+		return
+	}
+
+	// Server-managed fields
+	court.ID = uuid.Nil
+	court.Popularity = 0
+	court.CheckIns = nil
+	court.MergedInto = nil
+	court.ReviewNote = ""
+	court.SubmittedBy = &userID
+	court.Occupancy = nil
+	court.Conditions = nil
+	court.Rating = nil
+	court.Status = models.CourtStatusPending
+	for i := range court.Units {
+		court.Units[i].ID = uuid.Nil
+		court.Units[i].Position = i
+		court.Units[i].IsActive = true
+		if strings.TrimSpace(court.Units[i].Name) == "" {
+			court.Units[i].Name = fmt.Sprintf("Court %d", i+1)
+		}
+	}
+	if isModerator {
+		court.Status = models.CourtStatusApproved
+	}
+
+	if err := h.courtRepo.Create(ctx, &court); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create court: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, court)
+}
+
+// UpdateCourt handles PUT /api/courts/:id for court managers and moderators
+func (h *CourtHandler) UpdateCourt(c *gin.Context) {
+	court, userID, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+
+	var req models.CourtUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	before := *court
+	court.ApplyUpdate(req)
+	if err := court.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changes := models.DiffCourts(&before, court)
+	if len(changes) == 0 {
+		c.JSON(http.StatusOK, court)
+		return
+	}
+
+	ctx := c.Request.Context()
+	edit := &models.CourtEdit{UserID: userID, Changes: changes}
+	if err := h.courtRepo.Update(ctx, court, edit); err != nil {
+		h.writeCourtError(c, "Failed to update court", err)
+		return
+	}
+
+	updated, err := h.courtRepo.GetByID(ctx, court.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload court: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// ArchiveCourt handles DELETE /api/courts/:id. Archived courts stop being
+// listed but keep their history, events and bookings.
+func (h *CourtHandler) ArchiveCourt(c *gin.Context) {
+	court, userID, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	_ = c.ShouldBindJSON(&req) // Reason is optional
+
+	edit := &models.CourtEdit{UserID: userID, Action: models.CourtEditArchived, Note: req.Reason}
+	if err := h.courtRepo.SetStatus(c.Request.Context(), court.ID, models.CourtStatusArchived, edit); err != nil {
+		h.writeCourtError(c, "Failed to archive court", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Court archived successfully"})
+}
+
+// MergeCourts handles POST /api/courts/:id/merge, folding the court into
+// target_id. The user must manage both courts or be a moderator.
+func (h *CourtHandler) MergeCourts(c *gin.Context) {
+	court, userID, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+
+	var req models.CourtMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	target, err := h.courtRepo.GetByID(ctx, req.TargetID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Target court not found"})
+		return
+	}
+	if !h.canManage(c, target, userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You must manage both courts to merge them"})
+		return
+	}
+
+	if err := h.courtRepo.Merge(ctx, court.ID, target.ID, userID); err != nil {
+		h.writeCourtError(c, "Failed to merge courts", err)
+		return
+	}
+
+	merged, err := h.courtRepo.GetByID(ctx, target.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload court: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, merged)
+}
+
+// GetCourtHistory handles GET /api/courts/:id/history
+func (h *CourtHandler) GetCourtHistory(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+
+	history, err := h.courtRepo.GetHistory(c.Request.Context(), court.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch court history: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+// GetPendingCourts handles GET /api/courts/pending, the moderators' approval queue
+func (h *CourtHandler) GetPendingCourts(c *gin.Context) {
+	if !h.requireModerator(c) {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	courts, total, err := h.courtRepo.GetPending(c.Request.Context(), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pending courts: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"courts": courts,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// ReviewCourt handles POST /api/courts/:id/review for moderators approving
+// or rejecting a submitted court
+func (h *CourtHandler) ReviewCourt(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+	if !h.requireModerator(c) {
+		return
+	}
+
+	var req models.CourtReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, action := models.CourtStatusRejected, models.CourtEditRejected
+	if req.Approve {
+		status, action = models.CourtStatusApproved, models.CourtEditApproved
+	}
+
+	ctx := c.Request.Context()
+	edit := &models.CourtEdit{UserID: userID, Action: action, Note: req.Note}
+	if err := h.courtRepo.SetStatus(ctx, courtID, status, edit); err != nil {
+		h.writeCourtError(c, "Failed to review court", err)
+		return
+	}
+
+	court, err := h.courtRepo.GetByID(ctx, courtID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload court: " + err.Error()})
+		return
+	}
+
+	if court.SubmittedBy != nil {
+		title, message := "Court approved", fmt.Sprintf("%s is now listed. Thanks for adding it!", court.Name)
+		notificationType := models.NotificationTypeCourtApproved
+		if !req.Approve {
+			title, message = "Court not approved", fmt.Sprintf("%s was not approved.", court.Name)
+			if req.Note != "" {
+				message += " " + req.Note
+			}
+			notificationType = models.NotificationTypeCourtRejected
+		}
+		h.notify([]uuid.UUID{*court.SubmittedBy}, notificationType, title, message, court.ID)
+	}
+
+	c.JSON(http.StatusOK, court)
+}
+
+// GetCourtUnits handles GET /api/courts/:id/units
+func (h *CourtHandler) GetCourtUnits(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+
+	units, err := h.courtRepo.GetUnits(c.Request.Context(), courtID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch court units: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"units": units})
+}
+
+// CreateCourtUnit handles POST /api/courts/:id/units for court managers and moderators
+func (h *CourtHandler) CreateCourtUnit(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+
+	var req models.CourtUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	unit := models.CourtUnit{CourtID: court.ID, Name: fmt.Sprintf("Court %d", len(court.Units)+1), IsActive: true}
+	unit.ApplyUpdate(req)
+	if strings.TrimSpace(unit.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	if err := h.courtRepo.CreateUnit(c.Request.Context(), &unit); err != nil {
+		h.writeCourtError(c, "Failed to create court unit", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, unit)
+}
+
+// UpdateCourtUnit handles PUT /api/courts/:id/units/:unitID. Deactivating a
+// unit stops new bookings on it without touching existing ones.
+func (h *CourtHandler) UpdateCourtUnit(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+	unitID, err := uuid.Parse(c.Param("unitID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court unit ID"})
+		return
+	}
+
+	var req models.CourtUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	unit, err := h.courtRepo.GetUnit(ctx, court.ID, unitID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court unit not found"})
+		return
+	}
+	unit.ApplyUpdate(req)
+	if strings.TrimSpace(unit.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	if err := h.courtRepo.UpdateUnit(ctx, unit); err != nil {
+		h.writeCourtError(c, "Failed to update court unit", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, unit)
+}
+
+// GetCourtSchedule handles GET /api/courts/:id/schedule, returning the weekly
+// hours plus holidays and closures between from and to (default the next 30 days)
+func (h *CourtHandler) GetCourtSchedule(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+
+	court, err := h.courtRepo.GetByID(c.Request.Context(), courtID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+
+	// Dates are days in the court's timezone
+	loc := court.TimeLocation()
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, 30)
+	if fromStr := c.Query("from"); fromStr != "" {
+		if from, err = time.ParseInLocation("2006-01-02", fromStr, loc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date. Use YYYY-MM-DD"})
+			return
+		}
+		to = from.AddDate(0, 0, 30)
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		if to, err = time.ParseInLocation("2006-01-02", toStr, loc); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date. Use YYYY-MM-DD"})
+			return
+		}
+		to = to.AddDate(0, 0, 1) // Include the whole of the last day
+	}
+
+	schedule, err := h.courtRepo.GetSchedule(c.Request.Context(), courtID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch court schedule: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// SetCourtHours handles PUT /api/courts/:id/hours, replacing the weekly
+// operating hours. Days left out are closed; an empty list restores the defaults.
+func (h *CourtHandler) SetCourtHours(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Hours []models.OperatingHours `json:"hours"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	seen := map[time.Weekday]bool{}
+	for _, hours := range req.Hours {
+		if err := hours.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if seen[hours.DayOfWeek] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Hours for %s are given more than once", hours.DayOfWeek)})
+			return
+		}
+		seen[hours.DayOfWeek] = true
+	}
+
+	ctx := c.Request.Context()
+	if err := h.courtRepo.SetHours(ctx, court.ID, req.Hours); err != nil {
+		h.writeCourtError(c, "Failed to update court hours", err)
+		return
+	}
+
+	now := time.Now()
+	schedule, err := h.courtRepo.GetSchedule(ctx, court.ID, now, now.AddDate(0, 0, 30))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload court schedule: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// AddCourtHoliday handles POST /api/courts/:id/holidays. A holiday either
+// closes the court for the day or replaces its hours.
+func (h *CourtHandler) AddCourtHoliday(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+
+	var holiday models.CourtHoliday
+	if err := c.ShouldBindJSON(&holiday); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := holiday.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	holiday.ID = uuid.Nil
+	holiday.CourtID = court.ID
+	if err := h.courtRepo.SaveHoliday(c.Request.Context(), &holiday); err != nil {
+		h.writeCourtError(c, "Failed to save holiday", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, holiday)
+}
+
+// DeleteCourtHoliday handles DELETE /api/courts/:id/holidays/:holidayID
+func (h *CourtHandler) DeleteCourtHoliday(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+	holidayID, err := uuid.Parse(c.Param("holidayID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid holiday ID"})
+		return
+	}
+
+	if err := h.courtRepo.DeleteHoliday(c.Request.Context(), court.ID, holidayID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Holiday not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete holiday: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Holiday deleted successfully"})
+}
+
+// CreateCourtClosure handles POST /api/courts/:id/closures for maintenance
+// and other one-off closures. Players with affected bookings are notified.
+func (h *CourtHandler) CreateCourtClosure(c *gin.Context) {
+	court, userID, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+
+	var req models.CourtClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.EndTime.After(req.StartTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "End time must be after start time"})
+		return
+	}
+
+	closure := &models.CourtClosure{
+		CourtID:     court.ID,
+		CourtUnitID: req.CourtUnitID,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		Reason:      req.Reason,
+		CreatedBy:   userID,
+	}
+	affected, cancelledPayments, err := h.courtRepo.CreateClosure(c.Request.Context(), closure, req.CancelBookings)
+	if err != nil {
+		if strings.Contains(err.Error(), "court unit not found") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Court unit not found at this court"})
+			return
+		}
+		h.writeCourtError(c, "Failed to create closure", err)
+		return
+	}
+
+	// The court cancelled these bookings, so players get all their money back
+	// whatever the cancellation cutoff
+	for _, payment := range cancelledPayments {
+		settlePayment(c.Request.Context(), h.payments, h.bookingRepo, payment, payment.AmountCents-payment.RefundedCents)
+	}
+
+	// One notification per player, however many of their bookings are affected
+	seen := map[uuid.UUID]bool{}
+	var userIDs []uuid.UUID
+	for _, booking := range affected {
+		for _, playerID := range booking.PlayerIDs() {
+			if !seen[playerID] {
+				seen[playerID] = true
+				userIDs = append(userIDs, playerID)
+			}
+		}
+	}
+	outcome := "is affected, please rebook"
+	if req.CancelBookings {
+		outcome = "has been cancelled"
+	}
+	loc := court.TimeLocation()
+	h.notify(userIDs, models.NotificationTypeCourtClosure, "Court closure",
+		fmt.Sprintf("%s is closed from %s to %s for %s. Your booking during this time %s.", court.Name,
+			closure.StartTime.In(loc).Format("Mon Jan 2 15:04"), closure.EndTime.In(loc).Format("Mon Jan 2 15:04"), closure.Reason, outcome),
+		court.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"closure":           closure,
+		"affected_bookings": affected,
+	})
+}
+
+// DeleteCourtClosure handles DELETE /api/courts/:id/closures/:closureID
+func (h *CourtHandler) DeleteCourtClosure(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+	closureID, err := uuid.Parse(c.Param("closureID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid closure ID"})
+		return
+	}
+
+	if err := h.courtRepo.DeleteClosure(c.Request.Context(), court.ID, closureID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Closure not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete closure: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Closure deleted successfully"})
+}
+
+// GetBookingRules handles GET /api/courts/:id/booking-rules
+func (h *CourtHandler) GetBookingRules(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+
+	rules, err := h.courtRepo.GetBookingRules(c.Request.Context(), courtID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch booking rules: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// SetBookingRules handles PUT /api/courts/:id/booking-rules for court managers and moderators
+func (h *CourtHandler) SetBookingRules(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+
+	var rules models.BookingRules
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rules.CourtID = court.ID
+	if err := rules.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.courtRepo.SetBookingRules(c.Request.Context(), &rules); err != nil {
+		h.writeCourtError(c, "Failed to save booking rules", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// GetCourtPricing handles GET /api/courts/:id/pricing
+func (h *CourtHandler) GetCourtPricing(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+
+	pricing, err := h.courtRepo.GetPricing(c.Request.Context(), courtID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch court pricing: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pricing)
+}
+
+// SetCourtPricing handles PUT /api/courts/:id/pricing for court managers and
+// moderators. Only private courts can charge for bookings.
+func (h *CourtHandler) SetCourtPricing(c *gin.Context) {
+	court, _, ok := h.loadManagedCourt(c)
+	if !ok {
+		return
+	}
+	if court.IsPublic {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Public courts are free to book; make the court private to charge for it"})
+		return
+	}
+
+	var pricing models.CourtPricing
+	if err := c.ShouldBindJSON(&pricing); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	pricing.CourtID = court.ID
+	pricing.Currency = strings.ToUpper(pricing.Currency)
+	if err := pricing.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.courtRepo.SetPricing(c.Request.Context(), &pricing); err != nil {
+		if err.Error() == "user not found" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "member_ids contains an unknown user"})
+			return
+		}
+		h.writeCourtError(c, "Failed to save court pricing", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pricing)
+}
+
+// loadManagedCourt loads the court in the URL and checks the authenticated
+// user may manage it. It writes an error response and returns false on failure.
+func (h *CourtHandler) loadManagedCourt(c *gin.Context) (*models.Court, uuid.UUID, bool) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return nil, uuid.Nil, false
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+
+	court, err := h.courtRepo.GetByID(c.Request.Context(), courtID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return nil, uuid.Nil, false
+	}
+	if !h.canManage(c, court, userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only a court manager or moderator can do this"})
+		return nil, uuid.Nil, false
+	}
+	return court, userID, true
+}
+
+// canManage returns true if the user manages the court or is a moderator
+func (h *CourtHandler) canManage(c *gin.Context, court *models.Court, userID uuid.UUID) bool {
+	if court.CanManage(userID) {
+		return true
+	}
+	isModerator, err := h.courtRepo.IsModerator(c.Request.Context(), userID)
+	return err == nil && isModerator
+}
+
+// requireModerator writes a 403 response and returns false unless the
+// authenticated user is a moderator
+func (h *CourtHandler) requireModerator(c *gin.Context) bool {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return false
+	}
+	isModerator, err := h.courtRepo.IsModerator(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check permissions: " + err.Error()})
+		return false
+	}
+	if !isModerator {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only moderators can do this"})
+		return false
+	}
+	return true
+}
+
+// writeCourtError maps court repository errors to HTTP responses
+func (h *CourtHandler) writeCourtError(c *gin.Context, message string, err error) {
+	switch {
+	case strings.Contains(err.Error(), "not found"):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+	case strings.Contains(err.Error(), "merged"), strings.Contains(err.Error(), "already"),
+		strings.Contains(err.Error(), "not awaiting review"):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case strings.Contains(err.Error(), "into itself"):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message + ": " + err.Error()})
+	}
+}
+
+// notify sends a court notification; failures are logged but do not fail the request
+func (h *CourtHandler) notify(userIDs []uuid.UUID, notificationType, title, message string, courtID uuid.UUID) {
+	if h.notificationRepo == nil || len(userIDs) == 0 {
+		return
+	}
+	err := h.notificationRepo.NotifyUsers(context.Background(), userIDs, notificationType, title, message, &courtID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to send %s notifications: %v\n", notificationType, err)
+	}
+}
+
+// checkCourtOpen writes a 409 response and returns false if the court's hours,
+// holidays or closures rule out using it between start and end
+func checkCourtOpen(c *gin.Context, courtRepo *repository.CourtRepository, courtID uuid.UUID, start, end time.Time) bool {
+	schedule, err := courtRepo.GetSchedule(c.Request.Context(), courtID, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check court hours: " + err.Error()})
+		return false
+	}
+	if err := schedule.CheckOpen(start, end); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
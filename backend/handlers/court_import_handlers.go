@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/tennis-connect/courtdata"
+	"github.com/user/tennis-connect/models"
+)
+
+// ImportCourts handles POST /api/courts/import. A moderator uploads a
+// GeoJSON, CSV or OpenStreetMap file as the "file" form field, with its
+// format as "format" unless the file name shows it. Courts are matched
+// against the catalogue so duplicates are skipped; with dry_run=true nothing
+// is saved and the response shows what would change.
+func (h *CourtHandler) ImportCourts(c *gin.Context) {
+	if !h.requireModerator(c) {
+		return
+	}
+	userID, _ := getAuthenticatedUserID(c)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, courtdata.MaxImportBytes+64<<10)
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload the courts as the file field"})
+		return
+	}
+	if header.Size > courtdata.MaxImportBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large; import large extracts with the courts command"})
+		return
+	}
+	format, err := courtdata.ParseFormat(c.PostForm("format"), header.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	dryRun := c.PostForm("dry_run") == "true"
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+	records, err := courtdata.Read(file, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Could not read courts: %v", err)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	plan, err := courtdata.NewPlan(ctx, h.courtRepo, records)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to plan import: " + err.Error()})
+		return
+	}
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "plan": plan, "saved": 0})
+		return
+	}
+
+	saved, err := plan.Apply(ctx, h.courtRepo, userID, filepath.Base(header.Filename))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "plan": plan, "saved": saved})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"dry_run": false, "plan": plan, "saved": saved})
+}
+
+// ExportCourts handles GET /api/courts/export, downloading the catalogue as
+// GeoJSON. Approved courts are exported unless status says otherwise.
+func (h *CourtHandler) ExportCourts(c *gin.Context) {
+	if !h.requireModerator(c) {
+		return
+	}
+	status := c.DefaultQuery("status", models.CourtStatusApproved)
+	switch status {
+	case models.CourtStatusApproved, models.CourtStatusPending, models.CourtStatusRejected,
+		models.CourtStatusArchived, models.CourtStatusMerged:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	courts, err := h.courtRepo.GetCatalogue(c.Request.Context(), status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch courts: " + err.Error()})
+		return
+	}
+
+	var body bytes.Buffer
+	if err := courtdata.WriteGeoJSON(&body, courts); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export courts: " + err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="courts-%s.geojson"`, status))
+	c.Data(http.StatusOK, "application/geo+json", body.Bytes())
+}
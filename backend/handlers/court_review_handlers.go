@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+// GetCourtReviews handles GET /api/courts/:id/reviews, returning the court's
+// published reviews and its rating summary
+func (h *CourtHandler) GetCourtReviews(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	ctx := c.Request.Context()
+	reviews, total, err := h.courtRepo.GetReviews(ctx, courtID, userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reviews: " + err.Error()})
+		return
+	}
+	rating, err := h.courtRepo.GetRating(ctx, courtID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch court rating: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+		"rating":  rating,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// SubmitCourtReview handles POST /api/courts/:id/reviews. A player's new
+// review replaces their old one; reviews with a comment are held for a
+// moderator before they're published.
+func (h *CourtHandler) SubmitCourtReview(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SubmitCourtReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	court, err := h.courtRepo.GetByID(ctx, courtID)
+	if err != nil || !court.IsListed() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+	isModerator, err := h.courtRepo.IsModerator(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check permissions: " + err.Error()})
+		return
+	}
+
+	review := &models.CourtReview{
+		CourtID:        court.ID,
+		UserID:         userID,
+		Overall:        req.Overall,
+		SurfaceQuality: req.SurfaceQuality,
+		Lighting:       req.Lighting,
+		Cleanliness:    req.Cleanliness,
+		Comment:        req.Comment,
+		Status:         models.InitialReviewStatus(req.Comment, isModerator),
+	}
+	if err := h.courtRepo.SubmitReview(ctx, review); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save review: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// DeleteCourtReview handles DELETE /api/courts/:id/reviews, removing the
+// authenticated player's own review
+func (h *CourtHandler) DeleteCourtReview(c *gin.Context) {
+	courtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid court ID"})
+		return
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.courtRepo.DeleteReview(c.Request.Context(), courtID, userID); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete review: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review deleted successfully"})
+}
+
+// GetPendingReviews handles GET /api/courts/reviews/pending, the moderators'
+// review queue
+func (h *CourtHandler) GetPendingReviews(c *gin.Context) {
+	if !h.requireModerator(c) {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	reviews, total, err := h.courtRepo.GetPendingReviews(c.Request.Context(), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pending reviews: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews": reviews,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// ModerateCourtReview handles POST /api/courts/reviews/:reviewID/moderate for
+// moderators publishing or rejecting a review
+func (h *CourtHandler) ModerateCourtReview(c *gin.Context) {
+	reviewID, err := uuid.Parse(c.Param("reviewID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+	if !h.requireModerator(c) {
+		return
+	}
+
+	var req models.ModerateCourtReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.courtRepo.ModerateReview(c.Request.Context(), reviewID, userID, req.Approve, strings.TrimSpace(req.Note))
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		case strings.Contains(err.Error(), "not awaiting moderation"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to moderate review: " + err.Error()})
+		}
+		return
+	}
+
+	title, message := "Review published", "Your court review is now published. Thanks for sharing!"
+	notificationType := models.NotificationTypeReviewApproved
+	if !req.Approve {
+		title, message = "Review not published", "Your court review was not published."
+		if review.ReviewNote != "" {
+			message += " " + review.ReviewNote
+		}
+		notificationType = models.NotificationTypeReviewRejected
+	}
+	h.notify([]uuid.UUID{review.UserID}, notificationType, title, message, review.CourtID)
+
+	c.JSON(http.StatusOK, review)
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,13 +16,19 @@ import (
 
 // EventHandler handles event-related HTTP requests
 type EventHandler struct {
-	eventRepo *repository.EventRepository
+	eventRepo        *repository.EventRepository
+	notificationRepo *repository.NotificationRepository
+	attendanceRepo   *repository.AttendanceRepository
+	courtRepo        *repository.CourtRepository
 }
 
 // NewEventHandler creates a new EventHandler
-func NewEventHandler(eventRepo *repository.EventRepository) *EventHandler {
+func NewEventHandler(eventRepo *repository.EventRepository, notificationRepo *repository.NotificationRepository, attendanceRepo *repository.AttendanceRepository, courtRepo *repository.CourtRepository) *EventHandler {
 	return &EventHandler{
-		eventRepo: eventRepo,
+		eventRepo:        eventRepo,
+		notificationRepo: notificationRepo,
+		attendanceRepo:   attendanceRepo,
+		courtRepo:        courtRepo,
 	}
 }
 
@@ -30,6 +38,10 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 	lat, _ := strconv.ParseFloat(c.DefaultQuery("latitude", "37.7749"), 64)
 	lng, _ := strconv.ParseFloat(c.DefaultQuery("longitude", "-122.4194"), 64)
 	radius, _ := strconv.ParseFloat(c.DefaultQuery("radius", "10"), 64)
+	unit, ok := parseDistanceUnit(c)
+	if !ok {
+		return
+	}
 
 	// Optional filters
 	skillLevel := c.Query("skill_level")
@@ -82,7 +94,7 @@ func (h *EventHandler) GetEvents(c *gin.Context) {
 
 	// Query the database
 	ctx := context.Background()
-	events, totalCount, err := h.eventRepo.GetEvents(ctx, lat, lng, radius, filters, page, limit)
+	events, totalCount, err := h.eventRepo.GetEvents(ctx, lat, lng, unit.ToKm(radius), filters, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch events: " + err.Error()})
 		return
@@ -153,6 +165,14 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "End time must be after start time"})
 		return
 	}
+	if event.MinReliability < 0 || event.MinReliability > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Minimum reliability must be between 0 and 1"})
+		return
+	}
+
+	if event.CourtID != uuid.Nil && !checkCourtOpen(c, h.courtRepo, event.CourtID, event.StartTime, event.EndTime) {
+		return
+	}
 
 	// Set host information
 	event.HostID = userID
@@ -165,6 +185,7 @@ func (h *EventHandler) CreateEvent(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create event: " + err.Error()})
 		return
 	}
+	notifyCourtSubscribers(h.courtRepo, h.notificationRepo, event.CourtID, models.CourtActivityEvents, userID, event.Title, event.ID)
 
 	c.JSON(http.StatusCreated, event)
 }
@@ -204,6 +225,30 @@ func (h *EventHandler) RSVPToEvent(c *gin.Context) {
 		return
 	}
 
+	// Hosts may require a track record of showing up before players can join
+	ctx := context.Background()
+	if rsvpData.Status == "Confirmed" {
+		event, err := h.eventRepo.GetByID(ctx, eventID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		if event.MinReliability > 0 && h.attendanceRepo != nil {
+			reliability, err := h.attendanceRepo.GetReliability(ctx, userID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check reliability: " + err.Error()})
+				return
+			}
+			if reliability.Score < event.MinReliability {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error":       "Your reliability score is below the minimum the host requires",
+					"reliability": reliability,
+				})
+				return
+			}
+		}
+	}
+
 	// Create RSVP
 	rsvp := models.RSVP{
 		EventID:  eventID,
@@ -213,12 +258,209 @@ func (h *EventHandler) RSVPToEvent(c *gin.Context) {
 	}
 
 	// Save to database
-	ctx := context.Background()
-	err = h.eventRepo.CreateRSVP(ctx, &rsvp)
+	promoted, err := h.eventRepo.CreateRSVP(ctx, &rsvp)
 	if err != nil {
+		if strings.Contains(err.Error(), "cancelled") {
+			c.JSON(http.StatusConflict, gin.H{"error": "Event has been cancelled"})
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create RSVP: " + err.Error()})
 		return
 	}
+	if len(promoted) > 0 {
+		if event, err := h.eventRepo.GetByID(ctx, eventID); err != nil {
+			fmt.Printf("Warning: failed to load event for waitlist notifications: %v\n", err)
+		} else {
+			h.notifyRSVPChanges(event, promoted, nil)
+		}
+	}
 
 	c.JSON(http.StatusCreated, rsvp)
 }
+
+// UpdateEvent handles PUT /api/events/:id for the host and co-hosts
+func (h *EventHandler) UpdateEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.EventUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	event, err := h.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	if !event.CanManage(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the host or a co-host can edit this event"})
+		return
+	}
+	if req.CoHostIDs != nil && event.HostID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the host can change co-hosts"})
+		return
+	}
+	if event.IsCancelled() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Event has been cancelled"})
+		return
+	}
+
+	previousStart, previousEnd := event.StartTime, event.EndTime
+	event.ApplyUpdate(req)
+
+	// Validate the resulting event
+	if req.StartTime != nil && event.StartTime.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Start time must be in the future"})
+		return
+	}
+	if event.EndTime.Before(event.StartTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "End time must be after start time"})
+		return
+	}
+	if event.MaxPlayers < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Max players must be at least 1"})
+		return
+	}
+	if event.MinReliability < 0 || event.MinReliability > 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Minimum reliability must be between 0 and 1"})
+		return
+	}
+
+	timesChanged := !event.StartTime.Equal(previousStart) || !event.EndTime.Equal(previousEnd)
+	if timesChanged && event.CourtID != uuid.Nil && !checkCourtOpen(c, h.courtRepo, event.CourtID, event.StartTime, event.EndTime) {
+		return
+	}
+
+	promoted, demoted, err := h.eventRepo.Update(ctx, event)
+	if err != nil {
+		if strings.Contains(err.Error(), "cancelled") {
+			c.JSON(http.StatusConflict, gin.H{"error": "Event has been cancelled"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update event: " + err.Error()})
+		return
+	}
+
+	h.notifyRSVPChanges(event, promoted, demoted)
+
+	// Confirmed players need to hear about schedule changes
+	if !event.StartTime.Equal(previousStart) || !event.EndTime.Equal(previousEnd) {
+		updated, err := h.eventRepo.GetByID(ctx, eventID)
+		if err == nil {
+			var attendeeIDs []uuid.UUID
+			for _, rsvp := range updated.RSVPs {
+				if rsvp.UserID != userID {
+					attendeeIDs = append(attendeeIDs, rsvp.UserID)
+				}
+			}
+			h.notify(attendeeIDs, models.NotificationTypeEventUpdated, "Event rescheduled",
+				fmt.Sprintf("%s now starts at %s.", event.Title, event.StartTime.Format(time.RFC1123)), eventID)
+		}
+	}
+
+	updated, err := h.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload event: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// CancelEvent handles DELETE /api/events/:id for the host and co-hosts
+func (h *EventHandler) CancelEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	// The cancellation reason is optional
+	var cancelData struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&cancelData); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	event, err := h.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	if !event.CanManage(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the host or a co-host can cancel this event"})
+		return
+	}
+
+	attendeeIDs, err := h.eventRepo.Cancel(ctx, eventID, cancelData.Reason)
+	if err != nil {
+		if strings.Contains(err.Error(), "already cancelled") {
+			c.JSON(http.StatusConflict, gin.H{"error": "Event has already been cancelled"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel event: " + err.Error()})
+		return
+	}
+
+	message := fmt.Sprintf("%s on %s has been cancelled.", event.Title, event.StartTime.Format(time.RFC1123))
+	if cancelData.Reason != "" {
+		message += " Reason: " + cancelData.Reason
+	}
+	h.notify(attendeeIDs, models.NotificationTypeEventCancelled, "Event cancelled", message, eventID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":            "Event cancelled successfully",
+		"notified_attendees": len(attendeeIDs),
+	})
+}
+
+// notifyRSVPChanges lets players know their RSVP was moved on or off the
+// waitlist
+func (h *EventHandler) notifyRSVPChanges(event *models.Event, promoted, demoted []models.RSVP) {
+	for _, rsvp := range promoted {
+		h.notify([]uuid.UUID{rsvp.UserID}, models.NotificationTypeWaitlistPromoted, "You're in!",
+			fmt.Sprintf("A spot opened up and you are now confirmed for %s.", event.Title), event.ID)
+	}
+	for _, rsvp := range demoted {
+		h.notify([]uuid.UUID{rsvp.UserID}, models.NotificationTypeWaitlistDemoted, "Moved to waitlist",
+			fmt.Sprintf("%s now has fewer spots and you have been moved to the front of the waitlist.", event.Title), event.ID)
+	}
+}
+
+// notify sends a notification about an event; failures are logged but do not fail the request
+func (h *EventHandler) notify(userIDs []uuid.UUID, notificationType, title, message string, eventID uuid.UUID) {
+	if h.notificationRepo == nil || len(userIDs) == 0 {
+		return
+	}
+	err := h.notificationRepo.NotifyUsers(context.Background(), userIDs, notificationType, title, message, &eventID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to send %s notifications: %v\n", notificationType, err)
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/tennis-connect/geocoding"
+	"github.com/user/tennis-connect/models"
+)
+
+// GeocodingHandler handles place lookups for the location pickers
+type GeocodingHandler struct {
+	geocoder geocoding.Geocoder
+}
+
+// NewGeocodingHandler creates a new GeocodingHandler
+func NewGeocodingHandler(geocoder geocoding.Geocoder) *GeocodingHandler {
+	return &GeocodingHandler{geocoder: geocoder}
+}
+
+// Geocode handles GET /api/geocode?q=..., finding a city or postcode
+func (h *GeocodingHandler) Geocode(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	result, err := h.geocoder.Geocode(c.Request.Context(), query)
+	if err != nil {
+		writeGeocodingError(c, query, err, http.StatusNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ReverseGeocode handles GET /api/geocode/reverse?latitude=...&longitude=...,
+// naming the place at a point
+func (h *GeocodingHandler) ReverseGeocode(c *gin.Context) {
+	latitude, latErr := strconv.ParseFloat(c.Query("latitude"), 64)
+	longitude, lngErr := strconv.ParseFloat(c.Query("longitude"), 64)
+	if latErr != nil || lngErr != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Valid latitude and longitude are required"})
+		return
+	}
+
+	result, err := h.geocoder.Reverse(c.Request.Context(), latitude, longitude)
+	if err != nil {
+		writeGeocodingError(c, fmt.Sprintf("%.4f, %.4f", latitude, longitude), err, http.StatusNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// geocodeLocation fills in the coordinates of a location given by postcode,
+// city and state, along with any of those parts that were left out
+func geocodeLocation(ctx context.Context, geocoder geocoding.Geocoder, location *models.Location) (string, error) {
+	query := geocoding.Query(location.ZipCode, location.City, location.State)
+	if query == "" {
+		return query, geocoding.ErrNotFound
+	}
+
+	result, err := geocoder.Geocode(ctx, query)
+	if errors.Is(err, geocoding.ErrNotFound) && location.ZipCode != "" && location.City != "" {
+		// Gazetteers don't know every postcode, but may know the city
+		query = geocoding.Query("", location.City, location.State)
+		result, err = geocoder.Geocode(ctx, query)
+	}
+	if err != nil {
+		return query, err
+	}
+	location.Latitude = result.Latitude
+	location.Longitude = result.Longitude
+	if location.City == "" {
+		location.City = result.City
+	}
+	if location.State == "" {
+		location.State = result.State
+	}
+	if location.ZipCode == "" {
+		location.ZipCode = result.PostalCode
+	}
+	return query, nil
+}
+
+// writeGeocodingError responds to a failed lookup with notFoundStatus if the
+// place doesn't exist as far as the geocoder knows, or 502 if the geocoder
+// couldn't be asked
+func writeGeocodingError(c *gin.Context, query string, err error, notFoundStatus int) {
+	if errors.Is(err, geocoding.ErrNotFound) {
+		c.JSON(notFoundStatus, gin.H{"error": fmt.Sprintf("Could not find location %q", query)})
+		return
+	}
+	c.JSON(http.StatusBadGateway, gin.H{"error": "Geocoding failed: " + err.Error()})
+}
@@ -0,0 +1,519 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/repository"
+)
+
+// LeagueHandler handles league and ladder HTTP requests
+type LeagueHandler struct {
+	leagueRepo       *repository.LeagueRepository
+	communityRepo    *repository.CommunityRepository
+	eventRepo        *repository.EventRepository
+	notificationRepo *repository.NotificationRepository
+}
+
+// NewLeagueHandler creates a new LeagueHandler
+func NewLeagueHandler(leagueRepo *repository.LeagueRepository, communityRepo *repository.CommunityRepository, eventRepo *repository.EventRepository, notificationRepo *repository.NotificationRepository) *LeagueHandler {
+	return &LeagueHandler{
+		leagueRepo:       leagueRepo,
+		communityRepo:    communityRepo,
+		eventRepo:        eventRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// CreateLeague handles POST /api/leagues. A league may belong to a community
+// (created by one of its admins) or an event (created by its host or a co-host).
+func (h *LeagueHandler) CreateLeague(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.LeagueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (req.CommunityID == nil) == (req.EventID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A league must belong to either a community or an event"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if req.CommunityID != nil {
+		community, err := h.communityRepo.GetByID(ctx, *req.CommunityID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Community not found"})
+			return
+		}
+		if !community.IsAdmin(userID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only community admins can create a league"})
+			return
+		}
+	} else {
+		event, err := h.eventRepo.GetByID(ctx, *req.EventID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		if !event.CanManage(userID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the host or a co-host can create a league for this event"})
+			return
+		}
+	}
+
+	league, err := models.NewLeague(req, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.leagueRepo.Create(ctx, league); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create league: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, league)
+}
+
+// GetLeague handles GET /api/leagues/:id
+func (h *LeagueHandler) GetLeague(c *gin.Context) {
+	league, ok := h.loadLeague(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, league)
+}
+
+// JoinLeague handles POST /api/leagues/:id/join. The player is placed in the
+// division matching their NTRP level.
+func (h *LeagueHandler) JoinLeague(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+	league, ok := h.loadLeague(c)
+	if !ok {
+		return
+	}
+	if time.Now().After(league.SeasonEnd) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The season has ended"})
+		return
+	}
+
+	player, err := h.leagueRepo.AddPlayer(c.Request.Context(), league, userID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "already"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "no division"):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join league: " + err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, player)
+}
+
+// GenerateSchedule handles POST /api/leagues/:id/schedule for flex leagues.
+// Every division with at least two players gets a round robin spread across the season.
+func (h *LeagueHandler) GenerateSchedule(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+	league, ok := h.loadLeague(c)
+	if !ok {
+		return
+	}
+	if !league.CanManage(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the league owner can generate the schedule"})
+		return
+	}
+	if league.Type != models.LeagueTypeFlex {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Schedules are only generated for flex leagues"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var scheduled []models.LeagueMatch
+	for i := range league.Divisions {
+		division := &league.Divisions[i]
+		matches := league.ScheduleDivision(division)
+		if len(matches) == 0 {
+			continue
+		}
+		if err := h.leagueRepo.CreateSchedule(ctx, division.ID, matches); err != nil {
+			if strings.Contains(err.Error(), "already has a schedule") {
+				c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Division %s already has a schedule", division.Name)})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create schedule: " + err.Error()})
+			return
+		}
+		scheduled = append(scheduled, matches...)
+
+		var playerIDs []uuid.UUID
+		for _, p := range division.Players {
+			playerIDs = append(playerIDs, p.UserID)
+		}
+		h.notify(playerIDs, models.NotificationTypeLeagueScheduled, "League schedule published",
+			fmt.Sprintf("The %s schedule for %s is out.", division.Name, league.Name), league.ID)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"matches": scheduled})
+}
+
+// GetMatches handles GET /api/leagues/:id/matches with an optional division_id filter
+func (h *LeagueHandler) GetMatches(c *gin.Context) {
+	leagueID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league ID"})
+		return
+	}
+	divisionID, ok := parseOptionalUUID(c, "division_id")
+	if !ok {
+		return
+	}
+
+	matches, err := h.leagueRepo.GetMatches(c.Request.Context(), leagueID, divisionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch matches: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"matches": matches})
+}
+
+// ReportResult handles POST /api/leagues/:id/matches/:matchID/result.
+// Either player or the league owner may report.
+func (h *LeagueHandler) ReportResult(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+	league, ok := h.loadLeague(c)
+	if !ok {
+		return
+	}
+	matchID, err := uuid.Parse(c.Param("matchID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	var req models.LeagueResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	match, err := h.leagueRepo.GetMatch(ctx, matchID)
+	if err != nil || match.LeagueID != league.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+	if !match.IsPlayer(userID) && !league.CanManage(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the players or the league owner can report this result"})
+		return
+	}
+
+	match, err = h.leagueRepo.RecordResult(ctx, matchID, req, userID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "already been reported"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "failed to"):
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record result: " + err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	// Let the opponent know a result was entered against them
+	for _, playerID := range []uuid.UUID{match.Player1ID, match.Player2ID} {
+		if playerID != userID {
+			h.notify([]uuid.UUID{playerID}, models.NotificationTypeLeagueResult, "League result reported",
+				fmt.Sprintf("A result has been reported for your %s match.", league.Name), league.ID)
+		}
+	}
+
+	c.JSON(http.StatusOK, match)
+}
+
+// GetStandings handles GET /api/leagues/:id/standings, returning the table
+// for each division (or just the one given by division_id)
+func (h *LeagueHandler) GetStandings(c *gin.Context) {
+	league, ok := h.loadLeague(c)
+	if !ok {
+		return
+	}
+	divisionID, ok := parseOptionalUUID(c, "division_id")
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if league.Type == models.LeagueTypeLadder {
+		// Resolve lapsed challenges first so the ladder is up to date
+		if h.expireChallenges(ctx, league) {
+			if league, ok = h.loadLeague(c); !ok {
+				return
+			}
+		}
+	}
+
+	matches, err := h.leagueRepo.GetMatches(ctx, league.ID, divisionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch matches: " + err.Error()})
+		return
+	}
+
+	var divisions []gin.H
+	for _, division := range league.Divisions {
+		if divisionID != nil && division.ID != *divisionID {
+			continue
+		}
+		divisions = append(divisions, gin.H{
+			"division_id": division.ID,
+			"name":        division.Name,
+			"standings":   league.ComputeLeagueStandings(division.Players, matches),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"divisions": divisions})
+}
+
+// GetChallenges handles GET /api/leagues/:id/challenges
+func (h *LeagueHandler) GetChallenges(c *gin.Context) {
+	league, ok := h.loadLeague(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	h.expireChallenges(ctx, league)
+
+	challenges, err := h.leagueRepo.GetChallenges(ctx, league.ID, c.Query("open_only") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch challenges: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"challenges": challenges})
+}
+
+// CreateChallenge handles POST /api/leagues/:id/challenges
+func (h *LeagueHandler) CreateChallenge(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+	league, ok := h.loadLeague(c)
+	if !ok {
+		return
+	}
+
+	var challengeData struct {
+		DefenderID uuid.UUID `json:"defender_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&challengeData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if h.expireChallenges(ctx, league) {
+		if league, ok = h.loadLeague(c); !ok {
+			return
+		}
+	}
+
+	challenger := league.FindPlayer(userID)
+	if challenger == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not in this league"})
+		return
+	}
+	defender := league.FindPlayer(challengeData.DefenderID)
+	if defender == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Defender is not in this league"})
+		return
+	}
+	if err := league.CanChallenge(*challenger, *defender); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	challenge := league.NewChallenge(*challenger, *defender, time.Now())
+	if err := h.leagueRepo.CreateChallenge(ctx, &challenge); err != nil {
+		if strings.Contains(err.Error(), "open challenge") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create challenge: " + err.Error()})
+		return
+	}
+
+	h.notify([]uuid.UUID{defender.UserID}, models.NotificationTypeLadderChallenge, "You've been challenged",
+		fmt.Sprintf("%s has challenged you on the %s ladder. Respond by %s or forfeit your place.",
+			challenger.UserName, league.Name, challenge.RespondBy.Format(time.RFC1123)), league.ID)
+
+	c.JSON(http.StatusCreated, challenge)
+}
+
+// RespondToChallenge handles POST /api/leagues/:id/challenges/:challengeID/respond.
+// Only the defender may respond; declining forfeits the challenge.
+func (h *LeagueHandler) RespondToChallenge(c *gin.Context) {
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+	league, ok := h.loadLeague(c)
+	if !ok {
+		return
+	}
+	challengeID, err := uuid.Parse(c.Param("challengeID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge ID"})
+		return
+	}
+
+	var responseData struct {
+		Accept bool `json:"accept"`
+	}
+	if err := c.ShouldBindJSON(&responseData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	challenge, err := h.leagueRepo.GetChallenge(ctx, challengeID)
+	if err != nil || challenge.LeagueID != league.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+		return
+	}
+	if challenge.DefenderID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the challenged player can respond"})
+		return
+	}
+
+	challenge, err = h.leagueRepo.RespondToChallenge(ctx, league, challengeID, responseData.Accept)
+	if err != nil {
+		if strings.Contains(err.Error(), "no longer pending") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to respond to challenge: " + err.Error()})
+		return
+	}
+
+	message := fmt.Sprintf("Your %s ladder challenge was accepted.", league.Name)
+	if !responseData.Accept {
+		message = fmt.Sprintf("Your %s ladder challenge was declined, so you take your opponent's place.", league.Name)
+	}
+	h.notify([]uuid.UUID{challenge.ChallengerID}, models.NotificationTypeLadderChallenge, "Challenge answered", message, league.ID)
+
+	c.JSON(http.StatusOK, challenge)
+}
+
+// loadLeague parses the league ID from the path and loads the league,
+// writing the error response itself on failure
+func (h *LeagueHandler) loadLeague(c *gin.Context) (*models.League, bool) {
+	leagueID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league ID"})
+		return nil, false
+	}
+
+	league, err := h.leagueRepo.GetByID(c.Request.Context(), leagueID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "League not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch league: " + err.Error()})
+		return nil, false
+	}
+	return league, true
+}
+
+// expireChallenges settles lapsed ladder challenges and notifies the players.
+// It returns true if any ladder ranks may have changed.
+func (h *LeagueHandler) expireChallenges(ctx context.Context, league *models.League) bool {
+	if league.Type != models.LeagueTypeLadder {
+		return false
+	}
+	expired, err := h.leagueRepo.ExpireChallenges(ctx, league.ID, time.Now())
+	if err != nil {
+		fmt.Printf("Warning: Failed to expire ladder challenges: %v\n", err)
+		return false
+	}
+	for _, challenge := range expired {
+		message := fmt.Sprintf("A %s ladder challenge was not answered in time and has been awarded to the challenger.", league.Name)
+		if challenge.RespondedAt != nil {
+			message = fmt.Sprintf("A %s ladder challenge was not played by its due date and has been awarded to the defender.", league.Name)
+		}
+		h.notify([]uuid.UUID{challenge.ChallengerID, challenge.DefenderID}, models.NotificationTypeLadderChallenge, "Challenge expired",
+			message, league.ID)
+	}
+	return len(expired) > 0
+}
+
+// StartChallengeMaintenance starts a background goroutine that expires
+// lapsed ladder challenges in every league, so unanswered and unplayed
+// challenges are settled even when nobody looks at the ladder
+func (h *LeagueHandler) StartChallengeMaintenance(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			h.expireAllChallenges(ctx)
+			cancel()
+
+			<-ticker.C
+		}
+	}()
+}
+
+func (h *LeagueHandler) expireAllChallenges(ctx context.Context) {
+	leagueIDs, err := h.leagueRepo.GetLeaguesWithLapsedChallenges(ctx, time.Now())
+	if err != nil {
+		fmt.Printf("Warning: Failed to find lapsed ladder challenges: %v\n", err)
+		return
+	}
+	for _, leagueID := range leagueIDs {
+		league, err := h.leagueRepo.GetByID(ctx, leagueID)
+		if err != nil {
+			fmt.Printf("Warning: Failed to load league %s: %v\n", leagueID, err)
+			continue
+		}
+		h.expireChallenges(ctx, league)
+	}
+}
+
+// notify sends a league notification; failures are logged but do not fail the request
+func (h *LeagueHandler) notify(userIDs []uuid.UUID, notificationType, title, message string, leagueID uuid.UUID) {
+	if h.notificationRepo == nil || len(userIDs) == 0 {
+		return
+	}
+	err := h.notificationRepo.NotifyUsers(context.Background(), userIDs, notificationType, title, message, &leagueID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to send %s notifications: %v\n", notificationType, err)
+	}
+}
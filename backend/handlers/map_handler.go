@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/repository"
+	"github.com/user/tennis-connect/utils"
+)
+
+// MapHandler serves clustered markers for the map
+type MapHandler struct {
+	mapRepo *repository.MapRepository
+}
+
+// NewMapHandler creates a new MapHandler
+func NewMapHandler(mapRepo *repository.MapRepository) *MapHandler {
+	return &MapHandler{mapRepo: mapRepo}
+}
+
+// mapTileLayer is the vector tile layer markers are drawn in
+const mapTileLayer = "markers"
+
+// GetMapMarkers handles GET /api/map/markers?min_lat=...&min_lng=...&max_lat=...&max_lng=...&zoom=...,
+// returning the markers in the visible area clustered for the zoom level.
+// types limits them to some of courts, events, bulletins and players. A box
+// crossing the antimeridian has min_lng greater than max_lng.
+func (h *MapHandler) GetMapMarkers(c *gin.Context) {
+	var box utils.BoundingBox
+	var errs [4]error
+	box.MinLat, errs[0] = strconv.ParseFloat(c.Query("min_lat"), 64)
+	box.MinLng, errs[1] = strconv.ParseFloat(c.Query("min_lng"), 64)
+	box.MaxLat, errs[2] = strconv.ParseFloat(c.Query("max_lat"), 64)
+	box.MaxLng, errs[3] = strconv.ParseFloat(c.Query("max_lng"), 64)
+	for _, err := range errs {
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "min_lat, min_lng, max_lat and max_lng are required"})
+			return
+		}
+	}
+	if box.MinLat < -90 || box.MaxLat > 90 || box.MinLat > box.MaxLat ||
+		box.MinLng < -180 || box.MinLng > 180 || box.MaxLng < -180 || box.MaxLng > 180 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bounding box"})
+		return
+	}
+
+	zoom, err := strconv.Atoi(c.Query("zoom"))
+	if err != nil || zoom < 0 || zoom > utils.MaxMapZoom {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "zoom must be a whole number between 0 and " + strconv.Itoa(utils.MaxMapZoom)})
+		return
+	}
+	types, ok := parseMapTypes(c)
+	if !ok {
+		return
+	}
+
+	precision := utils.GeohashPrecisionForZoom(zoom)
+	if utils.GeohashCellCount(box, precision) > utils.MaxMapCells {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Area is too large for this zoom level"})
+		return
+	}
+
+	markers, err := h.mapRepo.GetMarkers(c.Request.Context(), box, precision, types)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch map markers: " + err.Error()})
+		return
+	}
+
+	counts := map[string]int{}
+	for _, mapType := range types {
+		counts[mapType] = 0
+	}
+	for _, marker := range markers {
+		for mapType, count := range marker.Counts {
+			counts[mapType] += count
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"markers": markers,
+		"counts":  counts,
+		"zoom":    zoom,
+	})
+}
+
+// GetMapTile handles GET /api/map/tiles/:z/:x/:y, returning the markers in a
+// web map tile as a Mapbox Vector Tile with a single "markers" layer. The y
+// may have a .mvt or .pbf extension. Each point has count, cluster and a
+// count per type; single courts, events and bulletins also have type, id
+// and label.
+func (h *MapHandler) GetMapTile(c *gin.Context) {
+	z, zErr := strconv.Atoi(c.Param("z"))
+	x, xErr := strconv.Atoi(c.Param("x"))
+	y, yErr := strconv.Atoi(strings.TrimSuffix(strings.TrimSuffix(c.Param("y"), ".mvt"), ".pbf"))
+	if zErr != nil || xErr != nil || yErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tile"})
+		return
+	}
+	box, err := utils.TileBounds(z, x, y)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	types, ok := parseMapTypes(c)
+	if !ok {
+		return
+	}
+
+	markers, err := h.mapRepo.GetMarkers(c.Request.Context(), box, utils.GeohashPrecisionForZoom(z), types)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch map markers: " + err.Error()})
+		return
+	}
+
+	layer := utils.VectorTileLayer{Name: mapTileLayer}
+	for _, marker := range markers {
+		point := utils.VectorTilePoint{Properties: map[string]interface{}{
+			"cell":    marker.Cell,
+			"count":   marker.Count,
+			"cluster": marker.IsCluster(),
+		}}
+		point.X, point.Y = utils.TilePoint(z, x, y, marker.Latitude, marker.Longitude, utils.VectorTileExtent)
+		for _, mapType := range types {
+			point.Properties[mapType] = marker.Counts[mapType]
+		}
+		if marker.ID != nil {
+			point.Properties["type"] = marker.Type
+			point.Properties["id"] = marker.ID.String()
+			point.Properties["label"] = marker.Label
+		}
+		layer.Features = append(layer.Features, point)
+	}
+
+	tile, err := utils.EncodeVectorTile([]utils.VectorTileLayer{layer})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode tile: " + err.Error()})
+		return
+	}
+	// Markers change as courts, events and bulletins do, so tiles are only
+	// briefly reusable
+	c.Header("Cache-Control", "private, max-age=60")
+	c.Data(http.StatusOK, utils.VectorTileContentType, tile)
+}
+
+// parseMapTypes parses the optional types query parameter, writing a 400
+// response if it names an unknown type
+func parseMapTypes(c *gin.Context) ([]string, bool) {
+	types, err := models.ParseMapTypes(c.Query("types"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid types. Use courts, events, bulletins or players"})
+		return nil, false
+	}
+	return types, true
+}
@@ -69,6 +69,13 @@ func (h *MatchingHandlers) CreateMatchSession(c *gin.Context) {
 		req.SkillLevel = user.SkillLevel // Use user's skill level
 	}
 
+	// Validate court exists
+	court, err := h.courtRepo.GetByID(c.Request.Context(), req.CourtID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+		return
+	}
+
 	// Parse date and time
 	date, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
@@ -82,15 +89,12 @@ func (h *MatchingHandlers) CreateMatchSession(c *gin.Context) {
 		return
 	}
 
-	// Combine date and time
+	// Combine date and time in the court's timezone
 	startDateTime := time.Date(date.Year(), date.Month(), date.Day(),
-		startTime.Hour(), startTime.Minute(), 0, 0, time.Local)
+		startTime.Hour(), startTime.Minute(), 0, 0, court.TimeLocation())
 	endDateTime := startDateTime.Add(time.Duration(req.Duration) * time.Minute)
 
-	// Validate court exists
-	court, err := h.courtRepo.GetByID(c.Request.Context(), req.CourtID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Court not found"})
+	if !checkCourtOpen(c, h.courtRepo, court.ID, startDateTime, endDateTime) {
 		return
 	}
 
@@ -126,6 +130,7 @@ func (h *MatchingHandlers) CreateMatchSession(c *gin.Context) {
 
 	// Populate court information
 	session.Court = court
+	session.Localize(&session.StartTime, &session.EndTime, court.Timezone)
 
 	c.JSON(http.StatusCreated, session)
 }
@@ -192,6 +197,7 @@ func (h *MatchingHandlers) GetMatchSession(c *gin.Context) {
 	court, err := h.courtRepo.GetByID(c.Request.Context(), session.CourtID)
 	if err == nil {
 		session.Court = court
+		session.Localize(&session.StartTime, &session.EndTime, court.Timezone)
 	}
 
 	// Populate user information for players
@@ -259,6 +265,7 @@ func (h *MatchingHandlers) GetAvailableMatchSessions(c *gin.Context) {
 		court, err := h.courtRepo.GetByID(c.Request.Context(), session.CourtID)
 		if err == nil {
 			session.Court = court
+			session.Localize(&session.StartTime, &session.EndTime, court.Timezone)
 		}
 	}
 
@@ -324,6 +331,10 @@ func (h *MatchingHandlers) TriggerMatching(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough players for matching"})
 			return
 		}
+		if strings.Contains(err.Error(), "not enough available players") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough players are free for this session"})
+			return
+		}
 		if strings.Contains(err.Error(), "not found") {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Match session not found"})
 			return
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/repository"
+)
+
+// MixerHandler handles social mixer HTTP requests
+type MixerHandler struct {
+	mixerRepo *repository.MixerRepository
+	eventRepo *repository.EventRepository
+}
+
+// NewMixerHandler creates a new MixerHandler
+func NewMixerHandler(mixerRepo *repository.MixerRepository, eventRepo *repository.EventRepository) *MixerHandler {
+	return &MixerHandler{
+		mixerRepo: mixerRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+// GenerateMixer handles POST /api/events/:id/mixer. With from_round set, rounds
+// before it are kept and the rest are regenerated for the players now present,
+// e.g. after late arrivals check in.
+func (h *MixerHandler) GenerateMixer(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.MixerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	event, err := h.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	if !event.CanManage(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the host or a co-host can run the mixer"})
+		return
+	}
+	if event.IsCancelled() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Event has been cancelled"})
+		return
+	}
+
+	// Only confirmed RSVPs and players who checked in can be in the rotation
+	eventPlayerIDs, err := h.mixerRepo.GetEventPlayerIDs(ctx, eventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load players: " + err.Error()})
+		return
+	}
+	playerIDs := req.PlayerIDs
+	if len(playerIDs) == 0 {
+		playerIDs = eventPlayerIDs
+	}
+	eligible := map[uuid.UUID]bool{}
+	for _, id := range eventPlayerIDs {
+		eligible[id] = true
+	}
+	for _, id := range playerIDs {
+		if !eligible[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Player %s has not RSVPed or checked in for this event", id)})
+			return
+		}
+	}
+	players, err := h.mixerRepo.GetPlayers(ctx, playerIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load players: " + err.Error()})
+		return
+	}
+
+	// Keep the rounds already played when regenerating
+	var kept []models.MixerRound
+	if req.FromRound > 1 {
+		existing, err := h.mixerRepo.GetByEventID(ctx, eventID)
+		if err != nil && !strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load mixer: " + err.Error()})
+			return
+		}
+		if existing != nil {
+			for _, round := range existing.Schedule {
+				if round.Round < req.FromRound {
+					kept = append(kept, round)
+				}
+			}
+		}
+	}
+	if len(kept) >= req.Rounds {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from_round must not be after the last round"})
+		return
+	}
+
+	mixer := &models.Mixer{
+		EventID:      eventID,
+		Courts:       req.Courts,
+		Rounds:       req.Rounds,
+		MixedDoubles: req.MixedDoubles,
+		Players:      players,
+	}
+	rounds, err := mixer.GenerateRounds(kept)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mixer.Schedule = append(kept, rounds...)
+
+	if err := h.mixerRepo.Save(ctx, mixer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save mixer: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, mixerResponse(mixer))
+}
+
+// GetMixer handles GET /api/events/:id/mixer
+func (h *MixerHandler) GetMixer(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	mixer, err := h.mixerRepo.GetByEventID(c.Request.Context(), eventID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Mixer not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch mixer: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, mixerResponse(mixer))
+}
+
+// mixerResponse renders a mixer along with how many repeat pairings it contains
+func mixerResponse(mixer *models.Mixer) gin.H {
+	partnerRepeats, opponentRepeats := mixer.RepeatCounts()
+	return gin.H{
+		"mixer":            mixer,
+		"repeat_partners":  partnerRepeats,
+		"repeat_opponents": opponentRepeats,
+	}
+}
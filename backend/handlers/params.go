@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/utils"
+)
+
+// parseOptionalUUID parses an optional UUID query parameter. It returns nil
+// if the parameter is absent and writes a 400 response if it is malformed.
+func parseOptionalUUID(c *gin.Context, name string) (*uuid.UUID, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+		return nil, false
+	}
+	return &id, true
+}
+
+// parseDistanceUnit parses the optional units query parameter, km (the
+// default) or mi, that radii and distances are given in. It writes a 400
+// response if the unit is unknown.
+func parseDistanceUnit(c *gin.Context) (utils.DistanceUnit, bool) {
+	unit, err := utils.ParseDistanceUnit(c.Query("units"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid units. Use km or mi"})
+		return "", false
+	}
+	return unit, true
+}
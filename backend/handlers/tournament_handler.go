@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/repository"
+)
+
+// TournamentHandler handles tournament-related HTTP requests
+type TournamentHandler struct {
+	tournamentRepo   *repository.TournamentRepository
+	eventRepo        *repository.EventRepository
+	notificationRepo *repository.NotificationRepository
+}
+
+// NewTournamentHandler creates a new TournamentHandler
+func NewTournamentHandler(tournamentRepo *repository.TournamentRepository, eventRepo *repository.EventRepository, notificationRepo *repository.NotificationRepository) *TournamentHandler {
+	return &TournamentHandler{
+		tournamentRepo:   tournamentRepo,
+		eventRepo:        eventRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// CreateTournament handles POST /api/events/:id/tournament. The draw is made
+// from the event's confirmed players and scheduled within the event window
+// around other events at the same court.
+func (h *TournamentHandler) CreateTournament(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.TournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.SeedingMethod == "" {
+		req.SeedingMethod = models.SeedingMethodNTRP
+	}
+	if req.MatchDuration <= 0 {
+		req.MatchDuration = 90
+	}
+	if req.CourtCount <= 0 {
+		req.CourtCount = 1
+	}
+
+	ctx := c.Request.Context()
+	event, err := h.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	if !event.CanManage(userID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the host or a co-host can create a tournament for this event"})
+		return
+	}
+	if event.IsCancelled() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Event has been cancelled"})
+		return
+	}
+
+	tournament := &models.Tournament{
+		ID:            uuid.New(),
+		EventID:       eventID,
+		Format:        req.Format,
+		SeedingMethod: req.SeedingMethod,
+		MatchDuration: req.MatchDuration,
+		CourtCount:    req.CourtCount,
+	}
+	for _, rsvp := range event.RSVPs {
+		tournament.Entrants = append(tournament.Entrants, models.TournamentEntrant{
+			UserID:   rsvp.UserID,
+			UserName: rsvp.UserName,
+			Rating:   req.Ratings[rsvp.UserID],
+			Seed:     req.Seeds[rsvp.UserID],
+		})
+	}
+
+	if req.SeedingMethod == models.SeedingMethodNTRP {
+		userIDs := make([]uuid.UUID, len(tournament.Entrants))
+		for i, e := range tournament.Entrants {
+			userIDs[i] = e.UserID
+		}
+		levels, err := h.tournamentRepo.GetSkillLevels(ctx, userIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load skill levels: " + err.Error()})
+			return
+		}
+		for i := range tournament.Entrants {
+			tournament.Entrants[i].Rating = levels[tournament.Entrants[i].UserID]
+		}
+	}
+
+	if err := models.SeedEntrants(tournament.Entrants, req.SeedingMethod); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := tournament.GenerateDraw(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var blocked []models.TimeRange
+	if event.CourtID != uuid.Nil {
+		blocked, err = h.eventRepo.GetCourtConflicts(ctx, event.CourtID, eventID, event.StartTime, event.EndTime)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load court schedule: " + err.Error()})
+			return
+		}
+	}
+	unscheduled := tournament.Schedule(event.StartTime, event.EndTime, blocked)
+
+	if err := h.tournamentRepo.Create(ctx, tournament); err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": "This event already has a tournament"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tournament: " + err.Error()})
+		return
+	}
+
+	var playerIDs []uuid.UUID
+	for _, e := range tournament.Entrants {
+		playerIDs = append(playerIDs, e.UserID)
+	}
+	h.notify(playerIDs, models.NotificationTypeTournamentDraw, "Draw is out",
+		fmt.Sprintf("The draw for %s has been published.", event.Title), tournament.ID)
+
+	c.JSON(http.StatusCreated, tournamentResponse(tournament, unscheduled))
+}
+
+// GetTournament handles GET /api/tournaments/:id
+func (h *TournamentHandler) GetTournament(c *gin.Context) {
+	tournamentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+
+	tournament, err := h.tournamentRepo.GetByID(c.Request.Context(), tournamentID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tournament not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tournament: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tournamentResponse(tournament, -1))
+}
+
+// GetEventTournament handles GET /api/events/:id/tournament
+func (h *TournamentHandler) GetEventTournament(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+		return
+	}
+
+	tournament, err := h.tournamentRepo.GetByEventID(c.Request.Context(), eventID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Tournament not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tournament: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tournamentResponse(tournament, -1))
+}
+
+// RecordMatchResult handles POST /api/tournaments/:id/matches/:matchID/result.
+// Results may be reported by either player or by the event's host or co-hosts.
+func (h *TournamentHandler) RecordMatchResult(c *gin.Context) {
+	tournamentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tournament ID"})
+		return
+	}
+	matchID, err := uuid.Parse(c.Param("matchID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid match ID"})
+		return
+	}
+
+	userID, ok := getAuthenticatedUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.MatchResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tournament, err := h.tournamentRepo.GetByID(ctx, tournamentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tournament not found"})
+		return
+	}
+
+	var match *models.TournamentMatch
+	for i := range tournament.Matches {
+		if tournament.Matches[i].ID == matchID {
+			match = &tournament.Matches[i]
+			break
+		}
+	}
+	if match == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
+		return
+	}
+
+	if !match.HasPlayer(userID) {
+		event, err := h.eventRepo.GetByID(ctx, tournament.EventID)
+		if err != nil || !event.CanManage(userID) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the players or the event host can report this result"})
+			return
+		}
+	}
+
+	previouslyReady := map[uuid.UUID]bool{}
+	for _, m := range tournament.Matches {
+		previouslyReady[m.ID] = m.Status == models.MatchStatusReady
+	}
+
+	tournament, err = h.tournamentRepo.RecordResult(ctx, tournamentID, matchID, req.WinnerID, req.Score)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "not ready"):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case strings.Contains(err.Error(), "failed to"):
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record result: " + err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	// Let players know when their next match is ready to play
+	for _, m := range tournament.Matches {
+		if m.Status == models.MatchStatusReady && !previouslyReady[m.ID] {
+			h.notify([]uuid.UUID{*m.Player1ID, *m.Player2ID}, models.NotificationTypeTournamentMatchReady, "Your next match is ready",
+				fmt.Sprintf("Your %s round %d match is ready to play.", m.Bracket, m.Round), tournament.ID)
+		}
+	}
+
+	c.JSON(http.StatusOK, tournamentResponse(tournament, -1))
+}
+
+// notify sends a tournament notification; failures are logged but do not fail the request
+func (h *TournamentHandler) notify(userIDs []uuid.UUID, notificationType, title, message string, tournamentID uuid.UUID) {
+	if h.notificationRepo == nil || len(userIDs) == 0 {
+		return
+	}
+	err := h.notificationRepo.NotifyUsers(context.Background(), userIDs, notificationType, title, message, &tournamentID)
+	if err != nil {
+		fmt.Printf("Warning: Failed to send %s notifications: %v\n", notificationType, err)
+	}
+}
+
+// tournamentResponse renders a tournament with its brackets and, for round
+// robins, the standings table. unscheduled is omitted when negative.
+func tournamentResponse(tournament *models.Tournament, unscheduled int) gin.H {
+	response := gin.H{
+		"tournament": tournament,
+		"brackets":   tournament.Brackets(),
+	}
+	if tournament.Format == models.TournamentFormatRoundRobin {
+		response["standings"] = tournament.Standings()
+	}
+	if unscheduled >= 0 {
+		response["unscheduled_matches"] = unscheduled
+	}
+	return response
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/user/tennis-connect/geocoding"
 	"github.com/user/tennis-connect/models"
 	"github.com/user/tennis-connect/utils"
 )
@@ -27,12 +28,14 @@ type UserRepositoryInterface interface {
 // UserHandler handles user-related HTTP requests
 type UserHandler struct {
 	userRepo UserRepositoryInterface
+	geocoder geocoding.Geocoder
 }
 
 // NewUserHandler creates a new UserHandler
-func NewUserHandler(userRepo UserRepositoryInterface) *UserHandler {
+func NewUserHandler(userRepo UserRepositoryInterface, geocoder geocoding.Geocoder) *UserHandler {
 	return &UserHandler{
 		userRepo: userRepo,
+		geocoder: geocoder,
 	}
 }
 
@@ -217,19 +220,12 @@ func (h *UserHandler) UpdateUserProfile(c *gin.Context) {
 	// Ensure the user ID in the path matches the authenticated user
 	user.ID = userID
 
-	// If city is provided but coordinates are missing or zero, try to geocode
+	// If city is provided but coordinates are missing or zero, geocode it.
+	// A place we can't find is an error rather than a guess.
 	if user.Location.City != "" && (user.Location.Latitude == 0 && user.Location.Longitude == 0) {
-		fmt.Printf("Geocoding city: %s\n", user.Location.City)
-		
-		geocodeResult, err := utils.GeocodeWithFallback(user.Location.City)
-		if err != nil {
-			fmt.Printf("Geocoding failed for city %s: %v\n", user.Location.City, err)
-			// Continue with the update even if geocoding fails
-		} else {
-			fmt.Printf("Geocoding successful: %s -> (%.6f, %.6f)\n", 
-				user.Location.City, geocodeResult.Latitude, geocodeResult.Longitude)
-			user.Location.Latitude = geocodeResult.Latitude
-			user.Location.Longitude = geocodeResult.Longitude
+		if query, err := geocodeLocation(c.Request.Context(), h.geocoder, &user.Location); err != nil {
+			writeGeocodingError(c, query, err, http.StatusBadRequest)
+			return
 		}
 	}
 
@@ -265,7 +261,11 @@ func (h *UserHandler) GetNearbyUsers(c *gin.Context) {
 	skillLevel := c.Query("skill_level")
 	gameStyles := c.Query("game_styles")
 	preferredDays := c.Query("preferred_days")
-	radius, _ := strconv.ParseFloat(c.DefaultQuery("radius", "10"), 64) // Default to 10 miles
+	radius, _ := strconv.ParseFloat(c.DefaultQuery("radius", "10"), 64) // In the requested units, km by default
+	unit, ok := parseDistanceUnit(c)
+	if !ok {
+		return
+	}
 	isNewcomer := c.Query("is_newcomer") == "true"
 	gender := c.Query("gender")
 
@@ -303,7 +303,7 @@ func (h *UserHandler) GetNearbyUsers(c *gin.Context) {
 
 	// Find nearby users
 	ctx := context.Background()
-	nearbyUsers, err := h.userRepo.GetNearbyUsers(ctx, lat, lng, radius, filters)
+	nearbyUsers, err := h.userRepo.GetNearbyUsers(ctx, lat, lng, unit.ToKm(radius), filters)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch nearby users: " + err.Error()})
 		return
@@ -313,6 +313,7 @@ func (h *UserHandler) GetNearbyUsers(c *gin.Context) {
 	usersInRange := 0
 	usersOutOfRange := 0
 	for _, user := range nearbyUsers {
+		user.Distance = unit.FromKm(user.Distance)
 		if user.Distance <= radius {
 			usersInRange++
 		} else {
@@ -328,6 +329,7 @@ func (h *UserHandler) GetNearbyUsers(c *gin.Context) {
 			"users_in_range":     usersInRange,
 			"users_out_of_range": usersOutOfRange,
 			"search_radius":      radius,
+			"units":              unit,
 			"showing_fallback":   usersOutOfRange > 0 && usersInRange == 0,
 		},
 	}
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/user/tennis-connect/geocoding"
 	"github.com/user/tennis-connect/models"
 	"github.com/user/tennis-connect/utils"
 )
@@ -258,7 +259,7 @@ func TestUserHandler_RegisterUser(t *testing.T) {
 			mockRepo := new(MockUserRepository)
 			tt.mockSetup(mockRepo)
 
-			userHandler := NewUserHandler(mockRepo)
+			userHandler := NewUserHandler(mockRepo, geocoding.NewOfflineGeocoder())
 			router := setupTestRouter(userHandler)
 
 			// Create request
@@ -339,7 +340,7 @@ func TestUserHandler_LoginUser(t *testing.T) {
 			mockRepo := new(MockUserRepository)
 			tt.mockSetup(mockRepo)
 
-			userHandler := NewUserHandler(mockRepo)
+			userHandler := NewUserHandler(mockRepo, geocoding.NewOfflineGeocoder())
 			router := setupTestRouter(userHandler)
 
 			// Create request
@@ -373,7 +374,7 @@ func TestUserHandler_LoginUser(t *testing.T) {
 // TestUserHandler_GetUserProfile tests the GetUserProfile method
 func TestUserHandler_GetUserProfile(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	userHandler := NewUserHandler(mockRepo)
+	userHandler := NewUserHandler(mockRepo, geocoding.NewOfflineGeocoder())
 
 	// Test invalid UUID
 	router := setupTestRouter(userHandler)
@@ -391,7 +392,7 @@ func TestUserHandler_GetUserProfile(t *testing.T) {
 // TestUserHandler_UpdateUserProfile tests the UpdateUserProfile method
 func TestUserHandler_UpdateUserProfile(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	userHandler := NewUserHandler(mockRepo)
+	userHandler := NewUserHandler(mockRepo, geocoding.NewOfflineGeocoder())
 
 	// Test missing auth context
 	router := setupTestRouter(userHandler)
@@ -416,7 +417,7 @@ func TestUserHandler_UpdateUserProfile(t *testing.T) {
 // TestUserHandler_GetNearbyUsers tests the GetNearbyUsers method
 func TestUserHandler_GetNearbyUsers(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	userHandler := NewUserHandler(mockRepo)
+	userHandler := NewUserHandler(mockRepo, geocoding.NewOfflineGeocoder())
 
 	// Test missing auth context
 	router := setupTestRouter(userHandler)
@@ -430,7 +431,7 @@ func TestUserHandler_GetNearbyUsers(t *testing.T) {
 // TestUserHandler_LikeUser tests the LikeUser method
 func TestUserHandler_LikeUser(t *testing.T) {
 	mockRepo := new(MockUserRepository)
-	userHandler := NewUserHandler(mockRepo)
+	userHandler := NewUserHandler(mockRepo, geocoding.NewOfflineGeocoder())
 
 	// Test missing auth context
 	router := setupTestRouter(userHandler)
@@ -465,7 +466,7 @@ func TestUserHandler_GetNearbyUsers_WithMetadata(t *testing.T) {
 
 	mockRepo.On("GetNearbyUsers", mock.Anything, mock.AnythingOfType("float64"), mock.AnythingOfType("float64"), mock.AnythingOfType("float64"), mock.Anything).Return(users, nil)
 
-	userHandler := NewUserHandler(mockRepo)
+	userHandler := NewUserHandler(mockRepo, geocoding.NewOfflineGeocoder())
 
 	// Create request with auth context
 	req, _ := http.NewRequest("GET", "/api/users/nearby?latitude=37.7749&longitude=-122.4194&radius=10", nil)
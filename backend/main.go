@@ -6,13 +6,16 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/user/tennis-connect/config"
 	"github.com/user/tennis-connect/database"
+	"github.com/user/tennis-connect/geocoding"
 	"github.com/user/tennis-connect/handlers"
+	"github.com/user/tennis-connect/payments"
 	"github.com/user/tennis-connect/repository"
 	"github.com/user/tennis-connect/utils"
 )
@@ -78,6 +81,15 @@ func main() {
 	var bulletinRepo *repository.BulletinRepository
 	var eventRepo *repository.EventRepository
 	var communityRepo *repository.CommunityRepository
+	var notificationRepo *repository.NotificationRepository
+	var tournamentRepo *repository.TournamentRepository
+	var leagueRepo *repository.LeagueRepository
+	var mixerRepo *repository.MixerRepository
+	var attendanceRepo *repository.AttendanceRepository
+	var bookingRepo *repository.BookingRepository
+	var matchingRepo *repository.MatchingRepository
+	var calendarRepo *repository.CalendarRepository
+	var mapRepo *repository.MapRepository
 	
 	if db != nil {
 		userRepo = repository.NewUserRepository(db)
@@ -85,26 +97,83 @@ func main() {
 		bulletinRepo = repository.NewBulletinRepository(db)
 		eventRepo = repository.NewEventRepository(db)
 		communityRepo = repository.NewCommunityRepository(db)
+		notificationRepo = repository.NewNotificationRepository(db)
+		tournamentRepo = repository.NewTournamentRepository(db)
+		leagueRepo = repository.NewLeagueRepository(db)
+		mixerRepo = repository.NewMixerRepository(db)
+		attendanceRepo = repository.NewAttendanceRepository(db)
+		bookingRepo = repository.NewBookingRepository(db)
+		matchingRepo = repository.NewMatchingRepository(db)
+		calendarRepo = repository.NewCalendarRepository(db)
+		mapRepo = repository.NewMapRepository(db)
+
+		// Expire forgotten check-ins and keep court popularity up to date
+		courtRepo.StartOccupancyMaintenance(5 * time.Minute)
 	}
 
 	// Initialize JWT manager
 	jwtManager := utils.NewJWTManager(cfg.JWT.Secret, cfg.JWT.Expiration)
 
+	// Initialize the payment provider that charges for private court bookings
+	paymentProvider, err := payments.NewProvider(cfg.Payments.Provider)
+	if err != nil {
+		log.Fatalf("Failed to initialize payment provider: %v", err)
+	}
+
+	// Initialize the geocoder, caching lookups in the database when there is one
+	geocoder, err := geocoding.NewGeocoder(cfg.Geocoding.Provider, time.Duration(cfg.Geocoding.Timeout)*time.Second)
+	if err != nil {
+		log.Fatalf("Failed to initialize geocoder: %v", err)
+	}
+	if db != nil {
+		geocoder = geocoding.NewCachedGeocoder(geocoder, repository.NewGeocodeCacheRepository(db))
+	} else {
+		geocoder = geocoding.NewCachedGeocoder(geocoder, geocoding.NewMemoryCache())
+	}
+
 	// Initialize handlers (will be nil if database connection failed)
 	var userHandler *handlers.UserHandler
 	var courtHandler *handlers.CourtHandler
 	var bulletinHandler *handlers.BulletinHandler
 	var eventHandler *handlers.EventHandler
 	var communityHandler *handlers.CommunityHandler
+	var notificationHandler *handlers.NotificationHandler
+	var tournamentHandler *handlers.TournamentHandler
+	var leagueHandler *handlers.LeagueHandler
+	var mixerHandler *handlers.MixerHandler
+	var attendanceHandler *handlers.AttendanceHandler
+	var bookingHandler *handlers.BookingHandlers
+	var matchingHandler *handlers.MatchingHandlers
+	var calendarHandler *handlers.CalendarHandler
+	var mapHandler *handlers.MapHandler
 	
 	if db != nil {
-		userHandler = handlers.NewUserHandler(userRepo)
-		courtHandler = handlers.NewCourtHandler(courtRepo)
-		bulletinHandler = handlers.NewBulletinHandler(bulletinRepo)
-		eventHandler = handlers.NewEventHandler(eventRepo)
+		userHandler = handlers.NewUserHandler(userRepo, geocoder)
+		courtHandler = handlers.NewCourtHandler(courtRepo, bookingRepo, notificationRepo, paymentProvider)
+		bulletinHandler = handlers.NewBulletinHandler(bulletinRepo, courtRepo, notificationRepo, geocoder)
+		eventHandler = handlers.NewEventHandler(eventRepo, notificationRepo, attendanceRepo, courtRepo)
 		communityHandler = handlers.NewCommunityHandler(communityRepo)
+		notificationHandler = handlers.NewNotificationHandler(notificationRepo)
+		tournamentHandler = handlers.NewTournamentHandler(tournamentRepo, eventRepo, notificationRepo)
+		leagueHandler = handlers.NewLeagueHandler(leagueRepo, communityRepo, eventRepo, notificationRepo)
+		mixerHandler = handlers.NewMixerHandler(mixerRepo, eventRepo)
+		attendanceHandler = handlers.NewAttendanceHandler(attendanceRepo, notificationRepo)
+		bookingHandler = handlers.NewBookingHandlers(bookingRepo, courtRepo, userRepo, notificationRepo, paymentProvider)
+		matchingHandler = handlers.NewMatchingHandlers(matchingRepo, courtRepo, userRepo)
+		calendarHandler = handlers.NewCalendarHandler(calendarRepo)
+		mapHandler = handlers.NewMapHandler(mapRepo)
+
+		// Release courts held by unanswered requests, unpaid bookings and
+		// unclaimed waitlist holds
+		bookingHandler.StartBookingMaintenance(time.Minute)
+
+		// Settle ladder challenges that were never answered or played
+		leagueHandler.StartChallengeMaintenance(15 * time.Minute)
 	}
 
+	// Place lookups work without the database
+	geocodingHandler := handlers.NewGeocodingHandler(geocoder)
+
 	// Initialize Gin router
 	r := gin.Default()
 
@@ -153,7 +222,7 @@ func main() {
 	}
 
 	// Routes
-	setupRoutes(r, userHandler, courtHandler, bulletinHandler, eventHandler, communityHandler, jwtManager, dbManager)
+	setupRoutes(r, userHandler, courtHandler, bulletinHandler, eventHandler, communityHandler, notificationHandler, tournamentHandler, leagueHandler, mixerHandler, attendanceHandler, bookingHandler, matchingHandler, calendarHandler, mapHandler, geocodingHandler, jwtManager, dbManager)
 
 	// Start server
 	serverAddr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
@@ -190,11 +259,17 @@ func loadEnvForEnvironment() {
 
 func setupRoutes(r *gin.Engine, userHandler *handlers.UserHandler,
 	courtHandler *handlers.CourtHandler, bulletinHandler *handlers.BulletinHandler,
-	eventHandler *handlers.EventHandler, communityHandler *handlers.CommunityHandler, jwtManager *utils.JWTManager, dbManager *database.ConnectionManager) {
+	eventHandler *handlers.EventHandler, communityHandler *handlers.CommunityHandler,
+	notificationHandler *handlers.NotificationHandler, tournamentHandler *handlers.TournamentHandler,
+	leagueHandler *handlers.LeagueHandler, mixerHandler *handlers.MixerHandler,
+	attendanceHandler *handlers.AttendanceHandler, bookingHandler *handlers.BookingHandlers,
+	matchingHandler *handlers.MatchingHandlers, calendarHandler *handlers.CalendarHandler,
+	mapHandler *handlers.MapHandler, geocodingHandler *handlers.GeocodingHandler,
+	jwtManager *utils.JWTManager, dbManager *database.ConnectionManager) {
 	
 	// Middleware to check database connection
 	requireDatabase := func(c *gin.Context) {
-		if !dbManager.IsHealthy() || userHandler == nil || courtHandler == nil || bulletinHandler == nil || eventHandler == nil || communityHandler == nil {
+		if !dbManager.IsHealthy() || userHandler == nil || courtHandler == nil || bulletinHandler == nil || eventHandler == nil || communityHandler == nil || notificationHandler == nil || tournamentHandler == nil || leagueHandler == nil || mixerHandler == nil || attendanceHandler == nil || bookingHandler == nil || matchingHandler == nil || calendarHandler == nil || mapHandler == nil {
 			c.JSON(503, gin.H{
 				"error": "Database connection unavailable",
 				"message": "This endpoint requires database connectivity which is currently unavailable",
@@ -238,6 +313,10 @@ func setupRoutes(r *gin.Engine, userHandler *handlers.UserHandler,
 			userRoutes.GET("/nearby", authMiddleware(jwtManager), userHandler.GetNearbyUsers)
 			userRoutes.GET("/by-city", authMiddleware(jwtManager), userHandler.GetUsersByCity)
 			userRoutes.POST("/like/:id", authMiddleware(jwtManager), userHandler.LikeUser)
+			userRoutes.GET("/:userID/reliability", authMiddleware(jwtManager), attendanceHandler.GetReliability)
+			userRoutes.GET("/:userID/bookings", authMiddleware(jwtManager), bookingHandler.GetUserBookings)
+			userRoutes.GET("/:userID/bookings/upcoming", authMiddleware(jwtManager), bookingHandler.GetUpcomingBookings)
+			userRoutes.GET("/:userID/matches", authMiddleware(jwtManager), matchingHandler.GetUserMatchHistory)
 		}
 
 		// Courts routes
@@ -246,6 +325,49 @@ func setupRoutes(r *gin.Engine, userHandler *handlers.UserHandler,
 		{
 			courtRoutes.GET("/", authMiddleware(jwtManager), courtHandler.GetCourts)
 			courtRoutes.GET("/:id", authMiddleware(jwtManager), courtHandler.GetCourtDetails)
+			courtRoutes.POST("/", authMiddleware(jwtManager), courtHandler.CreateCourt)
+			courtRoutes.PUT("/:id", authMiddleware(jwtManager), courtHandler.UpdateCourt)
+			courtRoutes.DELETE("/:id", authMiddleware(jwtManager), courtHandler.ArchiveCourt)
+			courtRoutes.POST("/:id/merge", authMiddleware(jwtManager), courtHandler.MergeCourts)
+			courtRoutes.GET("/:id/history", authMiddleware(jwtManager), courtHandler.GetCourtHistory)
+			courtRoutes.GET("/pending", authMiddleware(jwtManager), courtHandler.GetPendingCourts)
+			courtRoutes.POST("/import", authMiddleware(jwtManager), courtHandler.ImportCourts)
+			courtRoutes.GET("/export", authMiddleware(jwtManager), courtHandler.ExportCourts)
+			courtRoutes.GET("/favorites", authMiddleware(jwtManager), courtHandler.GetFavoriteCourts)
+			courtRoutes.PUT("/:id/favorite", authMiddleware(jwtManager), courtHandler.FavoriteCourt)
+			courtRoutes.DELETE("/:id/favorite", authMiddleware(jwtManager), courtHandler.UnfavoriteCourt)
+			courtRoutes.PUT("/:id/home", authMiddleware(jwtManager), courtHandler.SetHomeCourt)
+			courtRoutes.DELETE("/home", authMiddleware(jwtManager), courtHandler.ClearHomeCourt)
+			courtRoutes.POST("/:id/review", authMiddleware(jwtManager), courtHandler.ReviewCourt)
+			courtRoutes.GET("/:id/units", authMiddleware(jwtManager), courtHandler.GetCourtUnits)
+			courtRoutes.POST("/:id/units", authMiddleware(jwtManager), courtHandler.CreateCourtUnit)
+			courtRoutes.PUT("/:id/units/:unitID", authMiddleware(jwtManager), courtHandler.UpdateCourtUnit)
+			courtRoutes.GET("/:id/schedule", authMiddleware(jwtManager), courtHandler.GetCourtSchedule)
+			courtRoutes.PUT("/:id/hours", authMiddleware(jwtManager), courtHandler.SetCourtHours)
+			courtRoutes.POST("/:id/holidays", authMiddleware(jwtManager), courtHandler.AddCourtHoliday)
+			courtRoutes.DELETE("/:id/holidays/:holidayID", authMiddleware(jwtManager), courtHandler.DeleteCourtHoliday)
+			courtRoutes.POST("/:id/closures", authMiddleware(jwtManager), courtHandler.CreateCourtClosure)
+			courtRoutes.DELETE("/:id/closures/:closureID", authMiddleware(jwtManager), courtHandler.DeleteCourtClosure)
+			courtRoutes.GET("/:id/booking-rules", authMiddleware(jwtManager), courtHandler.GetBookingRules)
+			courtRoutes.PUT("/:id/booking-rules", authMiddleware(jwtManager), courtHandler.SetBookingRules)
+			courtRoutes.GET("/:id/pricing", authMiddleware(jwtManager), courtHandler.GetCourtPricing)
+			courtRoutes.PUT("/:id/pricing", authMiddleware(jwtManager), courtHandler.SetCourtPricing)
+			courtRoutes.GET("/:id/quote", authMiddleware(jwtManager), bookingHandler.GetBookingQuote)
+			courtRoutes.GET("/:id/booking-requests", authMiddleware(jwtManager), bookingHandler.GetBookingRequests)
+			courtRoutes.POST("/:id/booking-requests/:bookingID/approve", authMiddleware(jwtManager), bookingHandler.ApproveBookingRequest)
+			courtRoutes.POST("/:id/booking-requests/:bookingID/reject", authMiddleware(jwtManager), bookingHandler.RejectBookingRequest)
+			courtRoutes.GET("/:id/availability", authMiddleware(jwtManager), bookingHandler.GetCourtAvailability)
+			courtRoutes.GET("/:id/bookings", authMiddleware(jwtManager), bookingHandler.GetCourtBookings)
+			courtRoutes.GET("/:id/occupancy", authMiddleware(jwtManager), courtHandler.GetCourtOccupancy)
+			courtRoutes.GET("/:id/conditions", authMiddleware(jwtManager), courtHandler.GetCourtConditions)
+			courtRoutes.POST("/:id/conditions", authMiddleware(jwtManager), courtHandler.ReportCourtCondition)
+			courtRoutes.POST("/:id/conditions/:condition/resolve", authMiddleware(jwtManager), courtHandler.ResolveCourtCondition)
+			courtRoutes.GET("/:id/condition-flags", authMiddleware(jwtManager), courtHandler.GetConditionFlags)
+			courtRoutes.GET("/:id/reviews", authMiddleware(jwtManager), courtHandler.GetCourtReviews)
+			courtRoutes.POST("/:id/reviews", authMiddleware(jwtManager), courtHandler.SubmitCourtReview)
+			courtRoutes.DELETE("/:id/reviews", authMiddleware(jwtManager), courtHandler.DeleteCourtReview)
+			courtRoutes.GET("/reviews/pending", authMiddleware(jwtManager), courtHandler.GetPendingReviews)
+			courtRoutes.POST("/reviews/:reviewID/moderate", authMiddleware(jwtManager), courtHandler.ModerateCourtReview)
 			courtRoutes.POST("/checkin/:id", authMiddleware(jwtManager), courtHandler.CheckInToCourt)
 			courtRoutes.POST("/checkout/:id", authMiddleware(jwtManager), courtHandler.CheckOutFromCourt)
 		}
@@ -257,7 +379,14 @@ func setupRoutes(r *gin.Engine, userHandler *handlers.UserHandler,
 			eventRoutes.GET("/", authMiddleware(jwtManager), eventHandler.GetEvents)
 			eventRoutes.GET("/:id", authMiddleware(jwtManager), eventHandler.GetEventDetails)
 			eventRoutes.POST("/", authMiddleware(jwtManager), eventHandler.CreateEvent)
+			eventRoutes.PUT("/:id", authMiddleware(jwtManager), eventHandler.UpdateEvent)
+			eventRoutes.DELETE("/:id", authMiddleware(jwtManager), eventHandler.CancelEvent)
 			eventRoutes.POST("/:id/rsvp", authMiddleware(jwtManager), eventHandler.RSVPToEvent)
+			eventRoutes.GET("/:id/ics", authMiddleware(jwtManager), calendarHandler.DownloadEventICS)
+			eventRoutes.POST("/:id/tournament", authMiddleware(jwtManager), tournamentHandler.CreateTournament)
+			eventRoutes.GET("/:id/tournament", authMiddleware(jwtManager), tournamentHandler.GetEventTournament)
+			eventRoutes.POST("/:id/mixer", authMiddleware(jwtManager), mixerHandler.GenerateMixer)
+			eventRoutes.GET("/:id/mixer", authMiddleware(jwtManager), mixerHandler.GetMixer)
 		}
 
 		// Looking-to-play bulletin routes
@@ -287,6 +416,117 @@ func setupRoutes(r *gin.Engine, userHandler *handlers.UserHandler,
 			communityRoutes.POST("/:id/message", authMiddleware(jwtManager), communityHandler.PostCommunityMessage)
 			communityRoutes.GET("/:id/messages", authMiddleware(jwtManager), communityHandler.GetCommunityMessages)
 		}
+
+		// Notification routes
+		notificationRoutes := api.Group("/notifications")
+		notificationRoutes.Use(requireDatabase)
+		{
+			notificationRoutes.GET("/", authMiddleware(jwtManager), notificationHandler.GetNotifications)
+			notificationRoutes.POST("/:id/read", authMiddleware(jwtManager), notificationHandler.MarkNotificationRead)
+		}
+
+		// Tournament routes
+		tournamentRoutes := api.Group("/tournaments")
+		tournamentRoutes.Use(requireDatabase)
+		{
+			tournamentRoutes.GET("/:id", authMiddleware(jwtManager), tournamentHandler.GetTournament)
+			tournamentRoutes.POST("/:id/matches/:matchID/result", authMiddleware(jwtManager), tournamentHandler.RecordMatchResult)
+		}
+
+		// League and ladder routes
+		leagueRoutes := api.Group("/leagues")
+		leagueRoutes.Use(requireDatabase)
+		{
+			leagueRoutes.POST("/", authMiddleware(jwtManager), leagueHandler.CreateLeague)
+			leagueRoutes.GET("/:id", authMiddleware(jwtManager), leagueHandler.GetLeague)
+			leagueRoutes.POST("/:id/join", authMiddleware(jwtManager), leagueHandler.JoinLeague)
+			leagueRoutes.POST("/:id/schedule", authMiddleware(jwtManager), leagueHandler.GenerateSchedule)
+			leagueRoutes.GET("/:id/matches", authMiddleware(jwtManager), leagueHandler.GetMatches)
+			leagueRoutes.POST("/:id/matches/:matchID/result", authMiddleware(jwtManager), leagueHandler.ReportResult)
+			leagueRoutes.GET("/:id/standings", authMiddleware(jwtManager), leagueHandler.GetStandings)
+			leagueRoutes.GET("/:id/challenges", authMiddleware(jwtManager), leagueHandler.GetChallenges)
+			leagueRoutes.POST("/:id/challenges", authMiddleware(jwtManager), leagueHandler.CreateChallenge)
+			leagueRoutes.POST("/:id/challenges/:challengeID/respond", authMiddleware(jwtManager), leagueHandler.RespondToChallenge)
+		}
+
+		// Court booking routes
+		bookingRoutes := api.Group("/bookings")
+		bookingRoutes.Use(requireDatabase)
+		{
+			bookingRoutes.POST("/", authMiddleware(jwtManager), bookingHandler.CreateBooking)
+			bookingRoutes.GET("/series", authMiddleware(jwtManager), bookingHandler.GetMyBookingSeries)
+			bookingRoutes.POST("/series", authMiddleware(jwtManager), bookingHandler.CreateBookingSeries)
+			bookingRoutes.GET("/series/:id", authMiddleware(jwtManager), bookingHandler.GetBookingSeries)
+			bookingRoutes.DELETE("/series/:id", authMiddleware(jwtManager), bookingHandler.CancelBookingSeries)
+			bookingRoutes.DELETE("/series/:id/occurrences/:date", authMiddleware(jwtManager), bookingHandler.SkipBookingSeriesOccurrence)
+			bookingRoutes.POST("/join/:token", authMiddleware(jwtManager), bookingHandler.JoinBookingByLink)
+			bookingRoutes.GET("/waitlist", authMiddleware(jwtManager), bookingHandler.GetMyBookingWaitlist)
+			bookingRoutes.POST("/waitlist", authMiddleware(jwtManager), bookingHandler.JoinBookingWaitlist)
+			bookingRoutes.DELETE("/waitlist/:id", authMiddleware(jwtManager), bookingHandler.LeaveBookingWaitlist)
+			bookingRoutes.POST("/waitlist/claim/:token", authMiddleware(jwtManager), bookingHandler.ClaimBookingWaitlistHold)
+			bookingRoutes.GET("/:id", authMiddleware(jwtManager), bookingHandler.GetBooking)
+			bookingRoutes.DELETE("/:id", authMiddleware(jwtManager), bookingHandler.CancelBooking)
+			bookingRoutes.GET("/:id/payment", authMiddleware(jwtManager), bookingHandler.GetBookingPayment)
+			bookingRoutes.POST("/:id/pay", authMiddleware(jwtManager), bookingHandler.PayForBooking)
+			bookingRoutes.POST("/:id/participants", authMiddleware(jwtManager), bookingHandler.InviteBookingParticipants)
+			bookingRoutes.DELETE("/:id/participants/:userID", authMiddleware(jwtManager), bookingHandler.RemoveBookingParticipant)
+			bookingRoutes.POST("/:id/respond", authMiddleware(jwtManager), bookingHandler.RespondToBookingInvite)
+			bookingRoutes.GET("/:id/share-link", authMiddleware(jwtManager), bookingHandler.GetBookingShareLink)
+			bookingRoutes.PUT("/:id/publish", authMiddleware(jwtManager), bookingHandler.PublishBookingOpenSpots)
+			bookingRoutes.GET("/:id/ics", authMiddleware(jwtManager), calendarHandler.DownloadBookingICS)
+		}
+
+		// Player matching routes
+		matchingRoutes := api.Group("/matching")
+		matchingRoutes.Use(requireDatabase)
+		{
+			matchingRoutes.POST("/sessions", authMiddleware(jwtManager), matchingHandler.CreateMatchSession)
+			matchingRoutes.GET("/sessions/available", authMiddleware(jwtManager), matchingHandler.GetAvailableMatchSessions)
+			matchingRoutes.GET("/sessions/:sessionID", authMiddleware(jwtManager), matchingHandler.GetMatchSession)
+			matchingRoutes.POST("/sessions/:sessionID/join", authMiddleware(jwtManager), matchingHandler.JoinMatchSession)
+			matchingRoutes.POST("/sessions/:sessionID/match", authMiddleware(jwtManager), matchingHandler.TriggerMatching)
+			matchingRoutes.POST("/feedback", authMiddleware(jwtManager), matchingHandler.SubmitFeedback)
+			matchingRoutes.GET("/stats", authMiddleware(jwtManager), matchingHandler.GetMatchingStats)
+			matchingRoutes.GET("/pairings/:id/ics", authMiddleware(jwtManager), calendarHandler.DownloadMatchICS)
+		}
+
+		// Calendar routes; the feed itself is read by calendar apps without logging in
+		calendarRoutes := api.Group("/calendar")
+		calendarRoutes.Use(requireDatabase)
+		{
+			calendarRoutes.GET("/feed", authMiddleware(jwtManager), calendarHandler.GetCalendarFeed)
+			calendarRoutes.POST("/feed/reset", authMiddleware(jwtManager), calendarHandler.ResetCalendarFeed)
+			calendarRoutes.GET("/feed/:token", calendarHandler.GetCalendarFeedICS)
+			calendarRoutes.GET("/availability", authMiddleware(jwtManager), calendarHandler.GetAvailability)
+			calendarRoutes.PUT("/availability", authMiddleware(jwtManager), calendarHandler.ImportAvailability)
+			calendarRoutes.DELETE("/availability", authMiddleware(jwtManager), calendarHandler.ClearAvailability)
+		}
+
+		// Map routes; tiles can be used as a Mapbox vector tile source
+		mapRoutes := api.Group("/map")
+		mapRoutes.Use(requireDatabase)
+		{
+			mapRoutes.GET("/markers", authMiddleware(jwtManager), mapHandler.GetMapMarkers)
+			mapRoutes.GET("/tiles/:z/:x/:y", authMiddleware(jwtManager), mapHandler.GetMapTile)
+		}
+
+		// Geocoding routes
+		geocodeRoutes := api.Group("/geocode")
+		{
+			geocodeRoutes.GET("", authMiddleware(jwtManager), geocodingHandler.Geocode)
+			geocodeRoutes.GET("/reverse", authMiddleware(jwtManager), geocodingHandler.ReverseGeocode)
+		}
+
+		// Attendance routes; :type is event, booking or match_session
+		attendanceRoutes := api.Group("/attendance")
+		attendanceRoutes.Use(requireDatabase)
+		{
+			attendanceRoutes.GET("/:type/:id", authMiddleware(jwtManager), attendanceHandler.GetAttendance)
+			attendanceRoutes.POST("/:type/:id", authMiddleware(jwtManager), attendanceHandler.RecordAttendance)
+			attendanceRoutes.GET("/:type/:id/code", authMiddleware(jwtManager), attendanceHandler.GetCheckInCode)
+			attendanceRoutes.POST("/:type/:id/check-in", authMiddleware(jwtManager), attendanceHandler.CheckIn)
+			attendanceRoutes.POST("/:type/:id/close", authMiddleware(jwtManager), attendanceHandler.CloseAttendance)
+		}
 	}
 }
 
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS court_closures;
+DROP TABLE IF EXISTS court_holidays;
+DROP TABLE IF EXISTS court_hours;
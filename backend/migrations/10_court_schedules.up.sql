@@ -0,0 +1,37 @@
+-- Weekly operating hours; a court with no rows uses the default 06:00-22:00 every day
+CREATE TABLE IF NOT EXISTS court_hours (
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6), -- 0 = Sunday
+    opens_at TIME,
+    closes_at TIME,
+    lights_off_at TIME, -- Play must finish by then even if the venue stays open
+    is_closed BOOLEAN DEFAULT FALSE,
+    PRIMARY KEY (court_id, day_of_week)
+);
+
+-- Holiday overrides: closed all day or open with special hours
+CREATE TABLE IF NOT EXISTS court_holidays (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    is_closed BOOLEAN DEFAULT TRUE,
+    opens_at TIME,
+    closes_at TIME,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (court_id, date)
+);
+
+-- One-off maintenance or closure windows for a facility or one of its courts
+CREATE TABLE IF NOT EXISTS court_closures (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    court_unit_id UUID REFERENCES court_units(id) ON DELETE CASCADE, -- NULL closes the whole facility
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_court_closures_time ON court_closures (court_id, start_time, end_time);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bookings_user_court;
+DROP TABLE IF EXISTS court_booking_rules;
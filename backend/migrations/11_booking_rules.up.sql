@@ -0,0 +1,18 @@
+-- Per-court booking rules; courts without a row use the defaults in models.DefaultBookingRules
+CREATE TABLE IF NOT EXISTS court_booking_rules (
+    court_id UUID PRIMARY KEY REFERENCES courts(id) ON DELETE CASCADE,
+    slot_minutes INTEGER NOT NULL DEFAULT 60 CHECK (slot_minutes IN (30, 45, 60, 90)),
+    align_to_slots BOOLEAN DEFAULT FALSE,
+    min_duration_minutes INTEGER NOT NULL DEFAULT 30,
+    max_duration_minutes INTEGER NOT NULL DEFAULT 0, -- 0 means no limit
+    advance_days INTEGER NOT NULL DEFAULT 0,
+    max_active_bookings INTEGER NOT NULL DEFAULT 0,
+    cancel_cutoff_minutes INTEGER NOT NULL DEFAULT 30,
+    prime_time_start TIME,
+    prime_time_end TIME,
+    prime_time_max_minutes INTEGER NOT NULL DEFAULT 0,
+    prime_time_max_per_week INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_user_court ON bookings (user_id, court_id, start_time);
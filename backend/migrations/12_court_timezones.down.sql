@@ -0,0 +1 @@
+ALTER TABLE courts DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,2 @@
+-- Timezone override for courts; NULL means the zone is derived from the court's coordinates
+ALTER TABLE courts ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
@@ -0,0 +1,33 @@
+-- Recurring bookings; each occurrence is an ordinary booking linked to its series
+CREATE TABLE IF NOT EXISTS booking_series (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    court_unit_id UUID REFERENCES court_units(id) ON DELETE SET NULL, -- NULL means any free court
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    frequency VARCHAR(10) NOT NULL, -- daily, weekly
+    repeat_interval INTEGER NOT NULL DEFAULT 1,
+    first_start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    until_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, cancelled
+    player_count INTEGER DEFAULT 2,
+    game_type VARCHAR(50),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_series_user ON booking_series (user_id);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES booking_series(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_series ON bookings (series_id, start_time);
+
+-- Occurrences that were skipped by the player or left out because the court was unavailable
+CREATE TABLE IF NOT EXISTS booking_series_skips (
+    series_id UUID REFERENCES booking_series(id) ON DELETE CASCADE,
+    occurrence_date DATE NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (series_id, occurrence_date)
+);
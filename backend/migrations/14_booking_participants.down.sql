@@ -0,0 +1,4 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS bulletin_id;
+ALTER TABLE bookings DROP COLUMN IF EXISTS publish_open_spots;
+ALTER TABLE bookings DROP COLUMN IF EXISTS share_token;
+DROP TABLE IF EXISTS booking_participants;
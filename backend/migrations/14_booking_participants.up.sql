@@ -0,0 +1,24 @@
+-- Booking rosters: the owner plus invited players and players who joined by link
+CREATE TABLE IF NOT EXISTS booking_participants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    booking_id UUID REFERENCES bookings(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'invited', -- invited, accepted, declined
+    is_owner BOOLEAN DEFAULT FALSE,
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (booking_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_participants_user ON booking_participants (user_id, status);
+
+-- Every existing booking's owner is on its roster
+INSERT INTO booking_participants (booking_id, user_id, status, is_owner, responded_at, created_at)
+SELECT b.id, b.user_id, 'accepted', TRUE, b.created_at, b.created_at
+FROM bookings b
+WHERE NOT EXISTS (SELECT 1 FROM booking_participants p WHERE p.booking_id = b.id AND p.user_id = b.user_id);
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS share_token VARCHAR(64) UNIQUE;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS publish_open_spots BOOLEAN DEFAULT FALSE;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS bulletin_id UUID REFERENCES bulletins(id) ON DELETE SET NULL;
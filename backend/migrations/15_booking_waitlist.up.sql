@@ -0,0 +1,22 @@
+-- Players waiting for a court to free up; cancelled bookings are offered to them in order
+CREATE TABLE IF NOT EXISTS booking_waitlist (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    court_unit_id UUID REFERENCES court_units(id) ON DELETE CASCADE, -- NULL means any court at the facility
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    player_count INTEGER DEFAULT 2,
+    game_type VARCHAR(50),
+    notes TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting', -- waiting, offered, claimed, expired, cancelled
+    hold_unit_id UUID REFERENCES court_units(id) ON DELETE SET NULL,
+    hold_token VARCHAR(64) UNIQUE,
+    hold_expires_at TIMESTAMP WITH TIME ZONE,
+    booking_id UUID REFERENCES bookings(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_waitlist_court ON booking_waitlist (court_id, status, start_time);
+CREATE INDEX IF NOT EXISTS idx_booking_waitlist_user ON booking_waitlist (user_id, status);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS booking_payments;
+DROP TABLE IF EXISTS court_members;
+DROP TABLE IF EXISTS court_pricing;
@@ -0,0 +1,40 @@
+-- What private courts charge per hour; courts without a row are free to book
+CREATE TABLE IF NOT EXISTS court_pricing (
+    court_id UUID PRIMARY KEY REFERENCES courts(id) ON DELETE CASCADE,
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    off_peak_rate_cents INTEGER NOT NULL DEFAULT 0 CHECK (off_peak_rate_cents >= 0),
+    peak_rate_cents INTEGER NOT NULL DEFAULT 0 CHECK (peak_rate_cents >= 0),
+    member_off_peak_rate_cents INTEGER NOT NULL DEFAULT 0 CHECK (member_off_peak_rate_cents >= 0),
+    member_peak_rate_cents INTEGER NOT NULL DEFAULT 0 CHECK (member_peak_rate_cents >= 0),
+    peak_start TIME,
+    peak_end TIME,
+    full_refund_hours INTEGER NOT NULL DEFAULT 24,
+    late_refund_percent INTEGER NOT NULL DEFAULT 0 CHECK (late_refund_percent BETWEEN 0 AND 100),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Players who pay member rates at a court
+CREATE TABLE IF NOT EXISTS court_members (
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (court_id, user_id)
+);
+
+-- Payments for paid bookings, tracked against the provider's payment intent
+CREATE TABLE IF NOT EXISTS booking_payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    booking_id UUID UNIQUE REFERENCES bookings(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    provider VARCHAR(50) NOT NULL,
+    intent_id VARCHAR(255) NOT NULL,
+    amount_cents INTEGER NOT NULL,
+    currency CHAR(3) NOT NULL,
+    status VARCHAR(30) NOT NULL DEFAULT 'requires_payment', -- requires_payment, failed, succeeded, cancelled, refunded, partially_refunded
+    refunded_cents INTEGER NOT NULL DEFAULT 0,
+    failure_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_payments_status ON booking_payments (status, created_at);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_bookings_approval;
+ALTER TABLE bookings DROP COLUMN IF EXISTS review_note;
+ALTER TABLE bookings DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE bookings DROP COLUMN IF EXISTS reviewed_by;
+ALTER TABLE bookings DROP COLUMN IF EXISTS approval_deadline;
+ALTER TABLE bookings DROP COLUMN IF EXISTS approval_status;
+ALTER TABLE court_booking_rules DROP COLUMN IF EXISTS approval_hours;
+ALTER TABLE court_booking_rules DROP COLUMN IF EXISTS require_approval;
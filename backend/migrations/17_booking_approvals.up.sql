@@ -0,0 +1,11 @@
+-- Courts can hold bookings for a manager's approval
+ALTER TABLE court_booking_rules ADD COLUMN IF NOT EXISTS require_approval BOOLEAN DEFAULT FALSE;
+ALTER TABLE court_booking_rules ADD COLUMN IF NOT EXISTS approval_hours INTEGER NOT NULL DEFAULT 24;
+
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS approval_status VARCHAR(20); -- awaiting_approval, approved, rejected, expired
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS approval_deadline TIMESTAMP WITH TIME ZONE;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS review_note TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_approval ON bookings (court_id, approval_deadline) WHERE approval_status = 'awaiting_approval';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_calendar_imports;
+DROP TABLE IF EXISTS user_busy_times;
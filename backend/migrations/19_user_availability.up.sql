@@ -0,0 +1,16 @@
+-- Busy time imported from players' own calendars. Only the times are kept,
+-- never what the player is doing.
+CREATE TABLE IF NOT EXISTS user_busy_times (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_busy_times_user_time ON user_busy_times (user_id, start_time, end_time);
+
+CREATE TABLE IF NOT EXISTS user_calendar_imports (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    imported_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    covers_until TIMESTAMP WITH TIME ZONE NOT NULL
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_check_ins_court_time;
+DROP INDEX IF EXISTS idx_check_ins_active;
+ALTER TABLE check_ins DROP COLUMN IF EXISTS expires_at;
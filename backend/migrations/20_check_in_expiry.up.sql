@@ -0,0 +1,13 @@
+-- Check-ins expire after the duration the player expects to stay, so
+-- forgotten check-outs don't leave people "at the court" forever
+ALTER TABLE check_ins ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE check_ins SET expires_at = checked_in + INTERVAL '2 hours' WHERE expires_at IS NULL;
+
+-- Close check-ins that were left open before expiry existed
+UPDATE check_ins SET checked_out = expires_at WHERE checked_out IS NULL AND expires_at <= NOW();
+
+ALTER TABLE check_ins ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_check_ins_active ON check_ins (court_id, expires_at) WHERE checked_out IS NULL;
+CREATE INDEX IF NOT EXISTS idx_check_ins_court_time ON check_ins (court_id, checked_in);
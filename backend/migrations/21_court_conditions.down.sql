@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS court_condition_flags;
+DROP TABLE IF EXISTS court_condition_reports;
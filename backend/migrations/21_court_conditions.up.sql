@@ -0,0 +1,31 @@
+-- Player reports of court conditions (wet, lights out, net damaged, crowded,
+-- closed). Reports fade with age; resolved ones stay for reporter reliability.
+CREATE TABLE IF NOT EXISTS court_condition_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    condition VARCHAR(30) NOT NULL,
+    comment TEXT,
+    photo_url TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolution VARCHAR(20) CHECK (resolution IN ('fixed', 'dismissed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_court_condition_reports_open ON court_condition_reports (court_id, created_at) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_court_condition_reports_user ON court_condition_reports (user_id) WHERE resolution IS NOT NULL;
+
+-- Conditions reported often enough to alert the court's managers. Only one
+-- open flag per condition.
+CREATE TABLE IF NOT EXISTS court_condition_flags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    condition VARCHAR(30) NOT NULL,
+    score FLOAT NOT NULL,
+    flagged_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    dismissed BOOLEAN DEFAULT FALSE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_court_condition_flags_open ON court_condition_flags (court_id, condition) WHERE resolved_at IS NULL;
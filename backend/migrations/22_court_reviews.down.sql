@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS court_ratings;
+DROP TABLE IF EXISTS court_reviews;
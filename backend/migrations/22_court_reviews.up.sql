@@ -0,0 +1,48 @@
+-- Standalone court reviews, one per player per court. Reviews with a comment
+-- wait for a moderator before they're published.
+CREATE TABLE IF NOT EXISTS court_reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    overall INTEGER NOT NULL CHECK (overall BETWEEN 1 AND 5),
+    surface_quality INTEGER CHECK (surface_quality BETWEEN 1 AND 5),
+    lighting INTEGER CHECK (lighting BETWEEN 1 AND 5),
+    cleanliness INTEGER CHECK (cleanliness BETWEEN 1 AND 5),
+    comment TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    review_note TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (court_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_court_reviews_court_status ON court_reviews (court_id, status);
+CREATE INDEX IF NOT EXISTS idx_court_reviews_pending ON court_reviews (created_at) WHERE status = 'pending';
+
+-- Per-court aggregates of published reviews and post-match court ratings,
+-- kept up to date as ratings come in so search can filter and sort on them
+CREATE TABLE IF NOT EXISTS court_ratings (
+    court_id UUID PRIMARY KEY REFERENCES courts(id) ON DELETE CASCADE,
+    overall_rating FLOAT NOT NULL DEFAULT 0,
+    rating_count INTEGER NOT NULL DEFAULT 0,
+    review_count INTEGER NOT NULL DEFAULT 0,
+    surface_rating FLOAT,
+    lighting_rating FLOAT,
+    cleanliness_rating FLOAT,
+    rating_updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Court ratings already left after matches, one vote per player
+INSERT INTO court_ratings (court_id, overall_rating, rating_count)
+SELECT court_id, ROUND(AVG(rating)::numeric, 1), COUNT(*)
+FROM (
+    SELECT ms.court_id, pf.from_user_id, AVG(pf.court_rating) AS rating
+    FROM player_feedback pf
+    JOIN player_pairings pp ON pp.id = pf.pairing_id
+    JOIN match_sessions ms ON ms.id = pp.match_session_id
+    WHERE pf.court_rating BETWEEN 1 AND 5 AND ms.court_id IS NOT NULL
+    GROUP BY ms.court_id, pf.from_user_id
+) votes
+GROUP BY court_id
+ON CONFLICT (court_id) DO NOTHING;
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_users_geohash;
+DROP INDEX IF EXISTS idx_communities_geohash;
+DROP INDEX IF EXISTS idx_bulletins_geohash;
+DROP INDEX IF EXISTS idx_events_geohash;
+DROP INDEX IF EXISTS idx_courts_geohash;
+
+ALTER TABLE users DROP COLUMN IF EXISTS geohash;
+ALTER TABLE communities DROP COLUMN IF EXISTS geohash;
+ALTER TABLE bulletins DROP COLUMN IF EXISTS geohash;
+ALTER TABLE events DROP COLUMN IF EXISTS geohash;
+ALTER TABLE courts DROP COLUMN IF EXISTS geohash;
+
+DROP FUNCTION IF EXISTS geohash_encode(DOUBLE PRECISION, DOUBLE PRECISION, INTEGER);
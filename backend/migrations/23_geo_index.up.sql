@@ -0,0 +1,71 @@
+-- Radius searches look rows up by geohash prefix instead of computing the
+-- distance to every row. Each located table gets a stored geohash computed
+-- from its coordinates and a byte-ordered index, so a prefix becomes a range
+-- scan; the exact distance is only checked for rows in the matching cells.
+CREATE OR REPLACE FUNCTION geohash_encode(lat DOUBLE PRECISION, lng DOUBLE PRECISION, precision INTEGER)
+RETURNS TEXT AS $$
+DECLARE
+    alphabet CONSTANT TEXT := '0123456789bcdefghjkmnpqrstuvwxyz';
+    min_lat DOUBLE PRECISION := -90;
+    max_lat DOUBLE PRECISION := 90;
+    min_lng DOUBLE PRECISION := -180;
+    max_lng DOUBLE PRECISION := 180;
+    mid DOUBLE PRECISION;
+    hash TEXT := '';
+    bits INTEGER := 0;
+    bit_count INTEGER := 0;
+    even BOOLEAN := TRUE;
+BEGIN
+    IF lat IS NULL OR lng IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    WHILE length(hash) < precision LOOP
+        IF even THEN
+            mid := (min_lng + max_lng) / 2;
+            IF lng >= mid THEN
+                bits := bits * 2 + 1;
+                min_lng := mid;
+            ELSE
+                bits := bits * 2;
+                max_lng := mid;
+            END IF;
+        ELSE
+            mid := (min_lat + max_lat) / 2;
+            IF lat >= mid THEN
+                bits := bits * 2 + 1;
+                min_lat := mid;
+            ELSE
+                bits := bits * 2;
+                max_lat := mid;
+            END IF;
+        END IF;
+        even := NOT even;
+        bit_count := bit_count + 1;
+        IF bit_count = 5 THEN
+            hash := hash || substr(alphabet, bits + 1, 1);
+            bits := 0;
+            bit_count := 0;
+        END IF;
+    END LOOP;
+
+    RETURN hash;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+ALTER TABLE courts ADD COLUMN IF NOT EXISTS geohash TEXT COLLATE "C"
+    GENERATED ALWAYS AS (geohash_encode(latitude, longitude, 9)) STORED;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS geohash TEXT COLLATE "C"
+    GENERATED ALWAYS AS (geohash_encode(latitude, longitude, 9)) STORED;
+ALTER TABLE bulletins ADD COLUMN IF NOT EXISTS geohash TEXT COLLATE "C"
+    GENERATED ALWAYS AS (geohash_encode(latitude, longitude, 9)) STORED;
+ALTER TABLE communities ADD COLUMN IF NOT EXISTS geohash TEXT COLLATE "C"
+    GENERATED ALWAYS AS (geohash_encode(latitude, longitude, 9)) STORED;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS geohash TEXT COLLATE "C"
+    GENERATED ALWAYS AS (geohash_encode(latitude, longitude, 9)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_courts_geohash ON courts (geohash);
+CREATE INDEX IF NOT EXISTS idx_events_geohash ON events (geohash);
+CREATE INDEX IF NOT EXISTS idx_bulletins_geohash ON bulletins (geohash);
+CREATE INDEX IF NOT EXISTS idx_communities_geohash ON communities (geohash);
+CREATE INDEX IF NOT EXISTS idx_users_geohash ON users (geohash);
@@ -0,0 +1,15 @@
+-- Geocoding lookups are cached so repeated cities and points don't go back
+-- to the provider. A row with found = FALSE remembers a place that wasn't found.
+CREATE TABLE IF NOT EXISTS geocode_cache (
+    key TEXT PRIMARY KEY,
+    found BOOLEAN NOT NULL,
+    latitude FLOAT,
+    longitude FLOAT,
+    city VARCHAR(255),
+    state VARCHAR(255),
+    country VARCHAR(255),
+    country_code VARCHAR(2),
+    postal_code VARCHAR(20),
+    source VARCHAR(50),
+    cached_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS home_court_id;
+DROP TABLE IF EXISTS favorite_courts;
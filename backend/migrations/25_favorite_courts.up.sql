@@ -0,0 +1,15 @@
+-- Courts a player has favourited, and which of their activity (new
+-- bulletins, events, check-ins, condition reports) they want to hear about
+CREATE TABLE IF NOT EXISTS favorite_courts (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    subscriptions TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, court_id)
+);
+
+-- Subscribers are looked up by court when there's activity
+CREATE INDEX IF NOT EXISTS idx_favorite_courts_subscribed ON favorite_courts (court_id) WHERE subscriptions <> '{}';
+
+-- A player's home court is shown on their profile and used in matching
+ALTER TABLE users ADD COLUMN IF NOT EXISTS home_court_id UUID REFERENCES courts(id) ON DELETE SET NULL;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS notifications;
+DROP INDEX IF EXISTS idx_event_rsvps_event_status;
+DROP INDEX IF EXISTS idx_event_rsvps_event_user;
+DROP TABLE IF EXISTS event_cohosts;
+ALTER TABLE events DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE events DROP COLUMN IF EXISTS cancellation_reason;
+ALTER TABLE events DROP COLUMN IF EXISTS status;
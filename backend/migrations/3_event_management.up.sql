@@ -0,0 +1,42 @@
+-- Event lifecycle: status and cancellation details
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'Scheduled'; -- Scheduled, Cancelled
+ALTER TABLE events ADD COLUMN IF NOT EXISTS cancellation_reason TEXT;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP WITH TIME ZONE;
+
+-- Event co-hosts table (users who may manage an event alongside the host)
+CREATE TABLE IF NOT EXISTS event_cohosts (
+    event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+-- One RSVP per user per event so waitlist ordering is unambiguous. Older
+-- duplicate RSVPs are dropped first, keeping each user's latest.
+DELETE FROM event_rsvps
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY event_id, user_id
+            ORDER BY updated_at DESC NULLS LAST, created_at DESC NULLS LAST, id DESC
+        ) AS position
+        FROM event_rsvps
+    ) ranked
+    WHERE position > 1
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_rsvps_event_user ON event_rsvps (event_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_event_rsvps_event_status ON event_rsvps (event_id, status, created_at);
+
+-- Notifications table (in-app notifications delivered to users)
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT,
+    reference_id UUID,
+    is_read BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications (user_id, created_at DESC);
@@ -0,0 +1,49 @@
+-- Tournaments table (one draw per event)
+CREATE TABLE IF NOT EXISTS tournaments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID UNIQUE REFERENCES events(id) ON DELETE CASCADE,
+    format VARCHAR(30) NOT NULL, -- single_elimination, double_elimination, round_robin, compass
+    seeding_method VARCHAR(20) NOT NULL, -- ntrp, rating, manual, random
+    status VARCHAR(20) NOT NULL DEFAULT 'in_progress', -- in_progress, completed
+    match_duration INTEGER NOT NULL DEFAULT 90, -- minutes
+    court_count INTEGER NOT NULL DEFAULT 1,
+    champion_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Tournament entrants table
+CREATE TABLE IF NOT EXISTS tournament_entrants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tournament_id UUID REFERENCES tournaments(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    seed INTEGER NOT NULL,
+    rating DECIMAL(6,2) DEFAULT 0,
+    UNIQUE (tournament_id, user_id)
+);
+
+-- Tournament matches table; next/loser_next links route players through the draw
+CREATE TABLE IF NOT EXISTS tournament_matches (
+    id UUID PRIMARY KEY,
+    tournament_id UUID REFERENCES tournaments(id) ON DELETE CASCADE,
+    bracket VARCHAR(50) NOT NULL,
+    round INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    player1_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    player2_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    player1_bye BOOLEAN DEFAULT FALSE,
+    player2_bye BOOLEAN DEFAULT FALSE,
+    winner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    score VARCHAR(100),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, ready, completed, bye, skipped
+    scheduled_at TIMESTAMP WITH TIME ZONE,
+    court_number INTEGER,
+    next_match_id UUID,
+    next_match_slot INTEGER,
+    loser_next_match_id UUID,
+    loser_next_match_slot INTEGER,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    sequence INTEGER NOT NULL DEFAULT 0 -- Generation order, keeps brackets in display order
+);
+
+CREATE INDEX IF NOT EXISTS idx_tournament_matches_tournament ON tournament_matches (tournament_id, sequence);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS league_matches;
+DROP TABLE IF EXISTS ladder_challenges;
+DROP TABLE IF EXISTS league_players;
+DROP TABLE IF EXISTS league_divisions;
+DROP TABLE IF EXISTS leagues;
@@ -0,0 +1,77 @@
+-- Leagues table (flex leagues and ladders run by a community or event)
+CREATE TABLE IF NOT EXISTS leagues (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL, -- flex, ladder
+    community_id UUID REFERENCES communities(id) ON DELETE CASCADE,
+    event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+    owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    season_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    season_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    points_win INTEGER NOT NULL DEFAULT 3,
+    points_loss INTEGER NOT NULL DEFAULT 1,
+    points_default INTEGER NOT NULL DEFAULT 3,
+    challenge_range INTEGER NOT NULL DEFAULT 0,
+    challenge_response_hours INTEGER NOT NULL DEFAULT 0,
+    challenge_play_days INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- League divisions table (NTRP bands; max_ntrp 0 means no upper limit)
+CREATE TABLE IF NOT EXISTS league_divisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    league_id UUID REFERENCES leagues(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    min_ntrp DECIMAL(2,1) NOT NULL DEFAULT 0,
+    max_ntrp DECIMAL(2,1) NOT NULL DEFAULT 0
+);
+
+-- League players table
+CREATE TABLE IF NOT EXISTS league_players (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    league_id UUID REFERENCES leagues(id) ON DELETE CASCADE,
+    division_id UUID REFERENCES league_divisions(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    skill_level FLOAT NOT NULL DEFAULT 0,
+    ladder_rank INTEGER NOT NULL DEFAULT 0,
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (league_id, user_id)
+);
+
+-- Ladder challenges table
+CREATE TABLE IF NOT EXISTS ladder_challenges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    league_id UUID REFERENCES leagues(id) ON DELETE CASCADE,
+    division_id UUID REFERENCES league_divisions(id) ON DELETE CASCADE,
+    challenger_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    defender_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, accepted, declined, expired, completed
+    respond_by TIMESTAMP WITH TIME ZONE NOT NULL,
+    match_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    responded_at TIMESTAMP WITH TIME ZONE
+);
+
+-- League matches table (flex schedule and accepted challenges)
+CREATE TABLE IF NOT EXISTS league_matches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    league_id UUID REFERENCES leagues(id) ON DELETE CASCADE,
+    division_id UUID REFERENCES league_divisions(id) ON DELETE CASCADE,
+    round INTEGER NOT NULL DEFAULT 0,
+    player1_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    player2_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    due_date TIMESTAMP WITH TIME ZONE,
+    challenge_id UUID REFERENCES ladder_challenges(id) ON DELETE SET NULL,
+    winner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    score VARCHAR(100),
+    outcome VARCHAR(20), -- played, default
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled', -- scheduled, completed
+    reported_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_league_players_division ON league_players (division_id, ladder_rank);
+CREATE INDEX IF NOT EXISTS idx_league_matches_division ON league_matches (division_id, round);
+CREATE INDEX IF NOT EXISTS idx_ladder_challenges_division_status ON ladder_challenges (division_id, status);
@@ -0,0 +1,11 @@
+-- Event mixers table (rotating doubles schedule for social events)
+CREATE TABLE IF NOT EXISTS event_mixers (
+    event_id UUID PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    courts INTEGER NOT NULL,
+    rounds INTEGER NOT NULL,
+    mixed_doubles BOOLEAN DEFAULT FALSE,
+    players JSONB NOT NULL DEFAULT '[]',
+    schedule JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
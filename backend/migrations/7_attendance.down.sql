@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS check_in_codes;
+DROP TABLE IF EXISTS attendance;
+ALTER TABLE events DROP COLUMN IF EXISTS min_reliability;
+DROP TABLE IF EXISTS player_feedback;
+DROP TABLE IF EXISTS player_pairings;
+DROP TABLE IF EXISTS match_players;
+DROP TABLE IF EXISTS match_sessions;
+DROP TABLE IF EXISTS bookings;
@@ -0,0 +1,100 @@
+-- Bookings table (court reservations made through the booking API)
+CREATE TABLE IF NOT EXISTS bookings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, confirmed, cancelled, completed
+    player_count INTEGER DEFAULT 2,
+    game_type VARCHAR(50),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookings_court_time ON bookings (court_id, start_time, end_time);
+CREATE INDEX IF NOT EXISTS idx_bookings_user_time ON bookings (user_id, start_time);
+
+-- Match sessions table (open slots players join to be paired up)
+CREATE TABLE IF NOT EXISTS match_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    game_type VARCHAR(50) NOT NULL,
+    skill_level FLOAT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    max_players INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Match players table (players who joined a match session)
+CREATE TABLE IF NOT EXISTS match_players (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    match_session_id UUID REFERENCES match_sessions(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    joined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    preference_score FLOAT DEFAULT 0,
+    priority INTEGER DEFAULT 0,
+    UNIQUE (match_session_id, user_id)
+);
+
+-- Player pairings table (matches produced for a session)
+CREATE TABLE IF NOT EXISTS player_pairings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    match_session_id UUID REFERENCES match_sessions(id) ON DELETE CASCADE,
+    player1_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    player2_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    player3_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    player4_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    compatibility_score FLOAT DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Player feedback table (ratings left after a pairing is played)
+CREATE TABLE IF NOT EXISTS player_feedback (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pairing_id UUID REFERENCES player_pairings(id) ON DELETE CASCADE,
+    from_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    rating INTEGER NOT NULL,
+    comments TEXT,
+    court_rating INTEGER,
+    court_comments TEXT,
+    match_quality INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (pairing_id, from_user_id, to_user_id)
+);
+
+-- Minimum reliability a player needs to RSVP to an event; 0 means anyone may join
+ALTER TABLE events ADD COLUMN IF NOT EXISTS min_reliability FLOAT NOT NULL DEFAULT 0;
+
+-- Attendance table (check-ins and no-shows for events, bookings and match sessions)
+CREATE TABLE IF NOT EXISTS attendance (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subject_type VARCHAR(20) NOT NULL, -- event, booking, match_session
+    subject_id UUID NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL, -- checked_in, no_show
+    method VARCHAR(20), -- host, qr, geofence; NULL for no-shows
+    recorded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    checked_in_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (subject_type, subject_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_attendance_user_status ON attendance (user_id, status);
+
+-- Check-in codes table (secret shown as a QR code at the court)
+CREATE TABLE IF NOT EXISTS check_in_codes (
+    subject_type VARCHAR(20) NOT NULL,
+    subject_id UUID NOT NULL,
+    code VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (subject_type, subject_id)
+);
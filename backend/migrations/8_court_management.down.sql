@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS court_edits;
+DROP TABLE IF EXISTS court_photos;
+DROP TABLE IF EXISTS court_managers;
+ALTER TABLE users DROP COLUMN IF EXISTS is_moderator;
+DROP INDEX IF EXISTS idx_courts_status;
+ALTER TABLE courts DROP COLUMN IF EXISTS merged_into;
+ALTER TABLE courts DROP COLUMN IF EXISTS submitted_by;
+ALTER TABLE courts DROP COLUMN IF EXISTS review_note;
+ALTER TABLE courts DROP COLUMN IF EXISTS status;
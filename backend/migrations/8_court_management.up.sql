@@ -0,0 +1,42 @@
+-- Court lifecycle: approval queue, archiving and merging of duplicates
+ALTER TABLE courts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'approved'; -- pending, approved, rejected, archived, merged
+ALTER TABLE courts ADD COLUMN IF NOT EXISTS review_note TEXT;
+ALTER TABLE courts ADD COLUMN IF NOT EXISTS submitted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE courts ADD COLUMN IF NOT EXISTS merged_into UUID REFERENCES courts(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_courts_status ON courts (status);
+
+-- Moderators review user-submitted courts
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_moderator BOOLEAN DEFAULT FALSE;
+
+-- Court managers table (users who may edit a court's details)
+CREATE TABLE IF NOT EXISTS court_managers (
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (court_id, user_id)
+);
+
+-- Court photos table
+CREATE TABLE IF NOT EXISTS court_photos (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    url VARCHAR(500) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_court_photos_court ON court_photos (court_id, position);
+
+-- Court edit history table (who changed what and when)
+CREATE TABLE IF NOT EXISTS court_edits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL, -- created, updated, approved, rejected, archived, merged
+    changes JSONB NOT NULL DEFAULT '{}', -- field -> {from, to}
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_court_edits_court_created ON court_edits (court_id, created_at DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bookings_unit_time;
+ALTER TABLE bookings DROP COLUMN IF EXISTS court_unit_id;
+DROP TABLE IF EXISTS court_units;
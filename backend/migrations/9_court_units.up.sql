@@ -0,0 +1,34 @@
+-- Court units table (the individual courts at a facility; courts rows are the facilities)
+CREATE TABLE IF NOT EXISTS court_units (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    court_id UUID REFERENCES courts(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    surface VARCHAR(50), -- NULL means the facility's court type
+    has_lights BOOLEAN DEFAULT FALSE,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (court_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_court_units_court ON court_units (court_id, position);
+
+-- Every existing facility starts with a single court
+INSERT INTO court_units (court_id, name, position, has_lights)
+SELECT c.id, 'Court 1', 0, EXISTS (
+    SELECT 1 FROM court_amenities ca JOIN amenities a ON ca.amenity_id = a.id
+    WHERE ca.court_id = c.id AND a.name = 'Lights'
+)
+FROM courts c
+WHERE NOT EXISTS (SELECT 1 FROM court_units u WHERE u.court_id = c.id);
+
+-- Bookings hold a single court unit rather than the whole facility
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS court_unit_id UUID REFERENCES court_units(id) ON DELETE SET NULL;
+
+UPDATE bookings b SET court_unit_id = (
+    SELECT u.id FROM court_units u WHERE u.court_id = b.court_id ORDER BY u.position LIMIT 1
+)
+WHERE b.court_unit_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_unit_time ON bookings (court_unit_id, start_time, end_time);
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/utils"
+)
+
+// Things a player can check in to
+const (
+	AttendanceSubjectEvent        = "event"
+	AttendanceSubjectBooking      = "booking"
+	AttendanceSubjectMatchSession = "match_session"
+)
+
+// Attendance statuses
+const (
+	AttendanceStatusCheckedIn = "checked_in"
+	AttendanceStatusNoShow    = "no_show"
+)
+
+// Check-in methods
+const (
+	CheckInMethodHost     = "host"
+	CheckInMethodQR       = "qr"
+	CheckInMethodGeofence = "geofence"
+)
+
+const (
+	// CheckInOpensBefore is how long before the start time self check-in opens
+	CheckInOpensBefore = 30 * time.Minute
+	// CheckInGeofenceKm is how close to the court a player must be to check in by location
+	CheckInGeofenceKm = 0.2
+
+	// reliabilityPrior is the number of attended sessions every player starts
+	// with, so a single early no-show does not lock a new player out
+	reliabilityPrior = 3
+)
+
+// IsValidAttendanceSubject returns true for the subject types that support check-in
+func IsValidAttendanceSubject(subjectType string) bool {
+	switch subjectType {
+	case AttendanceSubjectEvent, AttendanceSubjectBooking, AttendanceSubjectMatchSession:
+		return true
+	}
+	return false
+}
+
+// Attendance records whether a player showed up to an event, booking or match session
+type Attendance struct {
+	ID          uuid.UUID  `json:"id"`
+	SubjectType string     `json:"subject_type"`
+	SubjectID   uuid.UUID  `json:"subject_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	UserName    string     `json:"user_name"`
+	Status      string     `json:"status"`           // checked_in, no_show
+	Method      string     `json:"method,omitempty"` // host, qr, geofence
+	RecordedBy  *uuid.UUID `json:"recorded_by,omitempty"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AttendanceSubject is the common view of an event, booking or match session
+// needed to take attendance
+type AttendanceSubject struct {
+	Type           string
+	ID             uuid.UUID
+	Title          string
+	Location       Location // Where the court is; used for geofenced check-in
+	StartTime      time.Time
+	EndTime        time.Time
+	ManagerIDs     []uuid.UUID // Users who may check others in and record no-shows
+	ParticipantIDs []uuid.UUID // Users expected to attend
+}
+
+// CanManage returns true if the user may take attendance for others
+func (s *AttendanceSubject) CanManage(userID uuid.UUID) bool {
+	for _, id := range s.ManagerIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsParticipant returns true if the user is expected to attend
+func (s *AttendanceSubject) IsParticipant(userID uuid.UUID) bool {
+	for _, id := range s.ParticipantIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckInOpen returns true from CheckInOpensBefore the start until the end
+func (s *AttendanceSubject) CheckInOpen(now time.Time) bool {
+	return !now.Before(s.StartTime.Add(-CheckInOpensBefore)) && now.Before(s.EndTime)
+}
+
+// CheckInRequest is a player's request to check themselves in, either with the
+// code from the QR shown at the court or with their current position
+type CheckInRequest struct {
+	Code      string   `json:"code"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// SelfCheckIn validates a player's own check-in against the subject and
+// returns the method used. code is the subject's current check-in code.
+func (s *AttendanceSubject) SelfCheckIn(userID uuid.UUID, req CheckInRequest, code string, now time.Time) (string, error) {
+	if !s.IsParticipant(userID) {
+		return "", fmt.Errorf("user is not a participant")
+	}
+	if !s.CheckInOpen(now) {
+		return "", fmt.Errorf("check-in is not open")
+	}
+
+	switch {
+	case req.Code != "":
+		if req.Code != code {
+			return "", fmt.Errorf("invalid check-in code")
+		}
+		return CheckInMethodQR, nil
+	case req.Latitude != nil && req.Longitude != nil:
+		distance := utils.DistanceKm(s.Location.Latitude, s.Location.Longitude, *req.Latitude, *req.Longitude)
+		if distance > CheckInGeofenceKm {
+			return "", fmt.Errorf("too far from the court to check in (%.0fm away)", distance*1000)
+		}
+		return CheckInMethodGeofence, nil
+	}
+	return "", fmt.Errorf("a check-in code or location is required")
+}
+
+// MissingParticipants returns the participants with no attendance recorded
+func (s *AttendanceSubject) MissingParticipants(records []Attendance) []uuid.UUID {
+	recorded := map[uuid.UUID]bool{}
+	for _, record := range records {
+		recorded[record.UserID] = true
+	}
+	var missing []uuid.UUID
+	for _, id := range s.ParticipantIDs {
+		if !recorded[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// AttendanceRequest is a host's request to record attendance for players
+type AttendanceRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required,min=1"`
+	Status  string      `json:"status" binding:"required,oneof=checked_in no_show"`
+}
+
+// Reliability summarises how often a player shows up
+type Reliability struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Attended int       `json:"attended"`
+	NoShows  int       `json:"no_shows"`
+	Score    float64   `json:"score"` // 0-1; new players start at 1
+}
+
+// NewReliability computes a player's reliability score. Every player is
+// credited with a few attended sessions so the score moves gradually.
+func NewReliability(userID uuid.UUID, attended, noShows int) Reliability {
+	return Reliability{
+		UserID:   userID,
+		Attended: attended,
+		NoShows:  noShows,
+		Score:    float64(attended+reliabilityPrior) / float64(attended+noShows+reliabilityPrior),
+	}
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSubject(start time.Time) (*AttendanceSubject, uuid.UUID, uuid.UUID) {
+	host, player := uuid.New(), uuid.New()
+	return &AttendanceSubject{
+		Type:           AttendanceSubjectEvent,
+		ID:             uuid.New(),
+		Location:       Location{Latitude: 37.7694, Longitude: -122.4862},
+		StartTime:      start,
+		EndTime:        start.Add(2 * time.Hour),
+		ManagerIDs:     []uuid.UUID{host},
+		ParticipantIDs: []uuid.UUID{host, player},
+	}, host, player
+}
+
+func TestAttendanceSubject_SelfCheckIn(t *testing.T) {
+	start := time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC)
+	subject, host, player := newTestSubject(start)
+	now := start.Add(-10 * time.Minute)
+	lat, lng := 37.7700, -122.4860 // About 70m from the court
+	farLat, farLng := 37.7800, -122.4862
+
+	t.Run("QR code", func(t *testing.T) {
+		method, err := subject.SelfCheckIn(player, CheckInRequest{Code: "abc"}, "abc", now)
+		assert.NoError(t, err)
+		assert.Equal(t, CheckInMethodQR, method)
+
+		_, err = subject.SelfCheckIn(player, CheckInRequest{Code: "wrong"}, "abc", now)
+		assert.EqualError(t, err, "invalid check-in code")
+	})
+
+	t.Run("geofence", func(t *testing.T) {
+		method, err := subject.SelfCheckIn(host, CheckInRequest{Latitude: &lat, Longitude: &lng}, "abc", now)
+		assert.NoError(t, err)
+		assert.Equal(t, CheckInMethodGeofence, method)
+
+		_, err = subject.SelfCheckIn(player, CheckInRequest{Latitude: &farLat, Longitude: &farLng}, "abc", now)
+		assert.ErrorContains(t, err, "too far from the court")
+	})
+
+	t.Run("window and participants", func(t *testing.T) {
+		_, err := subject.SelfCheckIn(player, CheckInRequest{Code: "abc"}, "abc", start.Add(-time.Hour))
+		assert.EqualError(t, err, "check-in is not open")
+
+		_, err = subject.SelfCheckIn(player, CheckInRequest{Code: "abc"}, "abc", subject.EndTime)
+		assert.EqualError(t, err, "check-in is not open")
+
+		_, err = subject.SelfCheckIn(uuid.New(), CheckInRequest{Code: "abc"}, "abc", now)
+		assert.EqualError(t, err, "user is not a participant")
+
+		_, err = subject.SelfCheckIn(player, CheckInRequest{}, "abc", now)
+		assert.EqualError(t, err, "a check-in code or location is required")
+	})
+}
+
+func TestAttendanceSubject_MissingParticipants(t *testing.T) {
+	subject, host, player := newTestSubject(time.Now())
+
+	assert.Equal(t, []uuid.UUID{host, player}, subject.MissingParticipants(nil))
+	assert.Equal(t, []uuid.UUID{player}, subject.MissingParticipants([]Attendance{{UserID: host, Status: AttendanceStatusCheckedIn}}))
+	assert.True(t, subject.CanManage(host))
+	assert.False(t, subject.CanManage(player))
+}
+
+func TestNewReliability(t *testing.T) {
+	userID := uuid.New()
+
+	assert.Equal(t, 1.0, NewReliability(userID, 0, 0).Score)
+	assert.Equal(t, 1.0, NewReliability(userID, 10, 0).Score)
+	assert.InDelta(t, 0.75, NewReliability(userID, 0, 1).Score, 1e-9)
+	assert.InDelta(t, 0.8, NewReliability(userID, 9, 3).Score, 1e-9)
+
+	// More history means each no-show matters less
+	assert.Greater(t, NewReliability(userID, 20, 1).Score, NewReliability(userID, 2, 1).Score)
+}
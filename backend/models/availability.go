@@ -0,0 +1,98 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Limits on imported calendars
+const (
+	AvailabilityImportHorizon = 90 * 24 * time.Hour // How far ahead busy time is imported
+	MaxBusyTimes              = 2000                // Busy blocks kept per player after merging
+	MaxCalendarImportBytes    = 2 << 20             // Largest .ics file accepted
+)
+
+// UnknownAvailabilityScore is the availability score for a player who has
+// not imported a calendar, so we can't tell if they are free
+const UnknownAvailabilityScore = float32(0.8)
+
+// Availability is a player's free/busy time, imported from their own
+// calendar. Only when they are busy is stored, never what they are doing.
+type Availability struct {
+	UserID      uuid.UUID   `json:"user_id"`
+	Busy        []TimeRange `json:"busy"`
+	ImportedAt  *time.Time  `json:"imported_at,omitempty"` // Empty if no calendar has been imported
+	CoversUntil *time.Time  `json:"covers_until,omitempty"`
+}
+
+// CalendarImportRequest is an .ics file pasted in as text
+type CalendarImportRequest struct {
+	ICS      string `json:"ics"`
+	Timezone string `json:"timezone"` // IANA zone for times without one; defaults to the player's location
+}
+
+// MergeTimeRanges sorts ranges and joins those that overlap or touch
+func MergeTimeRanges(ranges []TimeRange) []TimeRange {
+	sorted := make([]TimeRange, 0, len(ranges))
+	for _, r := range ranges {
+		if r.End.After(r.Start) {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var merged []TimeRange
+	for _, r := range sorted {
+		if last := len(merged) - 1; last >= 0 && !r.Start.After(merged[last].End) {
+			if r.End.After(merged[last].End) {
+				merged[last].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// ValidateBusyTimes checks an import is small enough to store
+func ValidateBusyTimes(busy []TimeRange) error {
+	if len(busy) > MaxBusyTimes {
+		return fmt.Errorf("calendar has too many busy times (%d, the limit is %d)", len(busy), MaxBusyTimes)
+	}
+	return nil
+}
+
+// FreeFraction returns how much of slot is free of busy time, from 0 to 1
+func FreeFraction(busy []TimeRange, slot TimeRange) float32 {
+	length := slot.End.Sub(slot.Start)
+	if length <= 0 {
+		return 1
+	}
+	var taken time.Duration
+	for _, r := range MergeTimeRanges(busy) {
+		if !r.Overlaps(slot) {
+			continue
+		}
+		start, end := r.Start, r.End
+		if start.Before(slot.Start) {
+			start = slot.Start
+		}
+		if end.After(slot.End) {
+			end = slot.End
+		}
+		taken += end.Sub(start)
+	}
+	return float32(1 - float64(taken)/float64(length))
+}
+
+// AvailabilityScore rates how free a player is for a slot, from 0 to 1. A
+// nil busy list means the player has no calendar imported.
+func AvailabilityScore(busy []TimeRange, slot TimeRange) float32 {
+	if busy == nil || slot.Start.IsZero() {
+		return UnknownAvailabilityScore
+	}
+	return FreeFraction(busy, slot)
+}
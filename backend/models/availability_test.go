@@ -0,0 +1,68 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTimeRanges(t *testing.T) {
+	base := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	at := func(hours float64) time.Time { return base.Add(time.Duration(hours * float64(time.Hour))) }
+
+	merged := MergeTimeRanges([]TimeRange{
+		{Start: at(3), End: at(4)},
+		{Start: at(0), End: at(1)},
+		{Start: at(0.5), End: at(2)},
+		{Start: at(2), End: at(2.5)}, // Touches the one before
+		{Start: at(5), End: at(5)},   // Empty
+	})
+
+	assert.Equal(t, []TimeRange{
+		{Start: at(0), End: at(2.5)},
+		{Start: at(3), End: at(4)},
+	}, merged)
+	assert.Empty(t, MergeTimeRanges(nil))
+}
+
+func TestAvailabilityScore(t *testing.T) {
+	base := time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)
+	slot := TimeRange{Start: base, End: base.Add(time.Hour)}
+	halfBusy := []TimeRange{{Start: base.Add(-time.Hour), End: base.Add(30 * time.Minute)}}
+
+	assert.Equal(t, float32(1), FreeFraction(nil, slot))
+	assert.Equal(t, float32(0.5), FreeFraction(halfBusy, slot))
+	assert.Equal(t, float32(0), FreeFraction([]TimeRange{{Start: base, End: base.Add(2 * time.Hour)}}, slot))
+
+	// No calendar imported means we can't tell
+	assert.Equal(t, UnknownAvailabilityScore, AvailabilityScore(nil, slot))
+	assert.Equal(t, float32(1), AvailabilityScore([]TimeRange{}, slot))
+	assert.Equal(t, float32(0.5), AvailabilityScore(halfBusy, slot))
+}
+
+func TestCalculateCompatibilityScore_Availability(t *testing.T) {
+	base := time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)
+	player1 := &User{ID: uuid.New(), SkillLevel: 4.0}
+	player2 := &User{ID: uuid.New(), SkillLevel: 4.0}
+	pairing := &PlayerPairing{}
+
+	criteria := DefaultMatchingCriteria()
+	unknown := pairing.CalculateCompatibilityScore(player1, player2, criteria)
+
+	criteria.Slot = TimeRange{Start: base, End: base.Add(time.Hour)}
+	criteria.BusyTimes = map[uuid.UUID][]TimeRange{player1.ID: {}, player2.ID: {}}
+	free := pairing.CalculateCompatibilityScore(player1, player2, criteria)
+
+	criteria.BusyTimes[player2.ID] = []TimeRange{criteria.Slot}
+	busy := pairing.CalculateCompatibilityScore(player1, player2, criteria)
+
+	assert.Greater(t, free, unknown)
+	assert.Less(t, busy, unknown)
+}
+
+func TestValidateBusyTimes(t *testing.T) {
+	assert.NoError(t, ValidateBusyTimes(make([]TimeRange, MaxBusyTimes)))
+	assert.Error(t, ValidateBusyTimes(make([]TimeRange, MaxBusyTimes+1)))
+}
@@ -20,6 +20,7 @@ const (
 type Booking struct {
 	ID          uuid.UUID     `json:"id"`
 	CourtID     uuid.UUID     `json:"court_id"`
+	CourtUnitID *uuid.UUID    `json:"court_unit_id,omitempty"` // The individual court at the facility
 	UserID      uuid.UUID     `json:"user_id"`
 	StartTime   time.Time     `json:"start_time"`
 	EndTime     time.Time     `json:"end_time"`
@@ -27,12 +28,36 @@ type Booking struct {
 	PlayerCount int           `json:"player_count"` // Number of players expected
 	GameType    string        `json:"game_type"`    // Singles, Doubles, etc.
 	Notes       string        `json:"notes,omitempty"`
+	SeriesID    *uuid.UUID    `json:"series_id,omitempty"` // Set for occurrences of a recurring booking
 	CreatedAt   time.Time     `json:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at"`
 
+	// Sharing the roster
+	ShareToken       string     `json:"share_token,omitempty"` // Only shown to the owner
+	PublishOpenSpots bool       `json:"publish_open_spots"`    // Advertise open spots as a bulletin
+	BulletinID       *uuid.UUID `json:"bulletin_id,omitempty"`
+
+	// Approval by a court manager, for courts whose rules require it
+	ApprovalStatus   string     `json:"approval_status,omitempty"` // awaiting_approval, approved, rejected, expired
+	ApprovalDeadline *time.Time `json:"approval_deadline,omitempty"`
+	ReviewedBy       *uuid.UUID `json:"reviewed_by,omitempty"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote       string     `json:"review_note,omitempty"` // The manager's reason for a rejection
+
 	// Populated fields (not stored in DB)
-	Court *Court `json:"court,omitempty"`
-	User  *User  `json:"user,omitempty"`
+	LocalTimes
+	CourtUnitName string               `json:"court_unit_name,omitempty"`
+	Court         *Court               `json:"court,omitempty"`
+	User          *User                `json:"user,omitempty"`
+	Participants  []BookingParticipant `json:"participants,omitempty"`
+	OpenSpots     int                  `json:"open_spots"`
+	Quote         *BookingQuote        `json:"quote,omitempty"`
+	Payment       *BookingPayment      `json:"payment,omitempty"`
+}
+
+// Overlaps returns true if the booking holds its court at any point between start and end
+func (b *Booking) Overlaps(start, end time.Time) bool {
+	return b.StartTime.Before(end) && b.EndTime.After(start)
 }
 
 // IsActive returns true if the booking is active (confirmed and not past end time)
@@ -40,21 +65,24 @@ func (b *Booking) IsActive() bool {
 	return b.Status == BookingStatusConfirmed && time.Now().Before(b.EndTime)
 }
 
-// CanBeCancelled returns true if the booking can be cancelled
+// CanBeCancelled returns true if the booking can be cancelled under the
+// default rules; courts with their own rules use BookingRules.CanCancel
 func (b *Booking) CanBeCancelled() bool {
-	return (b.Status == BookingStatusPending || b.Status == BookingStatusConfirmed) &&
-		time.Now().Before(b.StartTime.Add(-30*time.Minute)) // Can cancel up to 30 minutes before
+	return DefaultBookingRules(b.CourtID).CanCancel(b, time.Now())
 }
 
 // BookingRequest represents a request to book a court
 type BookingRequest struct {
-	CourtID     uuid.UUID `json:"court_id" validate:"required"`
-	Date        string    `json:"date" validate:"required"` // YYYY-MM-DD format
-	StartTime   string    `json:"start_time"`               // HH:MM format, defaults to 17:00
-	Duration    int       `json:"duration"`                 // Duration in minutes, defaults to 60
-	PlayerCount int       `json:"player_count"`             // Number of players, defaults to 2
-	GameType    string    `json:"game_type"`                // Singles, Doubles, etc.
-	Notes       string    `json:"notes,omitempty"`
+	CourtID     uuid.UUID  `json:"court_id" validate:"required"`
+	CourtUnitID *uuid.UUID `json:"court_unit_id"`            // Leave empty to book any free court at the facility
+	Date        string     `json:"date" validate:"required"` // YYYY-MM-DD format
+	StartTime   string     `json:"start_time"`               // HH:MM format, defaults to 17:00
+	Duration    int        `json:"duration"`                 // Duration in minutes, defaults to 60
+	PlayerCount int        `json:"player_count"`             // Number of players, defaults to 2
+	GameType    string     `json:"game_type"`                // Singles, Doubles, etc.
+	Notes       string     `json:"notes,omitempty"`
+
+	PublishOpenSpots bool `json:"publish_open_spots"` // Advertise open spots as a bulletin
 }
 
 // TimeSlotAvailability represents the availability of a time slot
@@ -62,12 +90,24 @@ type TimeSlotAvailability struct {
 	StartTime   time.Time  `json:"start_time"`
 	EndTime     time.Time  `json:"end_time"`
 	IsAvailable bool       `json:"is_available"`
+	FreeUnits   int        `json:"free_units"`
+	TotalUnits  int        `json:"total_units"`
 	BookingID   *uuid.UUID `json:"booking_id,omitempty"` // If not available, which booking is using it
+	IsPrimeTime bool       `json:"is_prime_time"`
+
+	// If not available for a reason other than a booking, e.g. a maintenance closure
+	ClosedReason string `json:"closed_reason,omitempty"`
 }
 
 // CourtAvailability represents the availability of a court for a specific date
 type CourtAvailability struct {
 	CourtID   uuid.UUID              `json:"court_id"`
 	Date      string                 `json:"date"`
-	TimeSlots []TimeSlotAvailability `json:"time_slots"`
+	OpensAt   *time.Time             `json:"opens_at,omitempty"`
+	ClosesAt  *time.Time             `json:"closes_at,omitempty"`
+	Timezone  string                 `json:"timezone,omitempty"` // Slot times are in the court's zone
+	IsClosed  bool                   `json:"is_closed"`          // Closed all day, e.g. a holiday
+	TimeSlots []TimeSlotAvailability `json:"time_slots"`         // Facility-wide; available while any unit is free
+	Units     []UnitAvailability     `json:"units"`
+	Rules     *BookingRules          `json:"rules,omitempty"`
 }
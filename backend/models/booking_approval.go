@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Booking approval statuses. Bookings at courts that do not require approval
+// have no approval status.
+const (
+	ApprovalStatusAwaiting = "awaiting_approval"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+	ApprovalStatusExpired  = "expired" // No manager responded before the deadline
+)
+
+// BookingReviewRequest represents a manager approving or rejecting a booking request
+type BookingReviewRequest struct {
+	Reason string `json:"reason"` // Required when rejecting
+}
+
+// AwaitingApproval returns true if the booking is a request waiting for a manager
+func (b *Booking) AwaitingApproval() bool {
+	return b.Status == BookingStatusPending && b.ApprovalStatus == ApprovalStatusAwaiting
+}
+
+// CheckReview returns an error unless a manager can still approve or reject the booking
+func (b *Booking) CheckReview(now time.Time) error {
+	if !b.AwaitingApproval() {
+		return fmt.Errorf("booking is not awaiting approval")
+	}
+	if b.ApprovalDeadline != nil && !now.Before(*b.ApprovalDeadline) {
+		return fmt.Errorf("approval deadline has passed")
+	}
+	return nil
+}
+
+// Validate checks a rejection gives the player a reason
+func (r *BookingReviewRequest) Validate(approve bool) error {
+	if !approve && strings.TrimSpace(r.Reason) == "" {
+		return fmt.Errorf("a reason is required to reject a booking")
+	}
+	return nil
+}
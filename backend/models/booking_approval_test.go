@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBooking_CheckReview(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	deadline := now.Add(time.Hour)
+	booking := &Booking{
+		Status:           BookingStatusPending,
+		ApprovalStatus:   ApprovalStatusAwaiting,
+		ApprovalDeadline: &deadline,
+	}
+
+	assert.True(t, booking.AwaitingApproval())
+	assert.NoError(t, booking.CheckReview(now))
+	assert.EqualError(t, booking.CheckReview(deadline), "approval deadline has passed")
+
+	booking.ApprovalStatus = ApprovalStatusApproved
+	assert.False(t, booking.AwaitingApproval())
+	assert.EqualError(t, booking.CheckReview(now), "booking is not awaiting approval")
+
+	booking.ApprovalStatus = ApprovalStatusAwaiting
+	booking.Status = BookingStatusCancelled
+	assert.EqualError(t, booking.CheckReview(now), "booking is not awaiting approval")
+
+	assert.False(t, (&Booking{Status: BookingStatusPending}).AwaitingApproval(), "courts without approval have no request")
+}
+
+func TestBookingReviewRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&BookingReviewRequest{}).Validate(true))
+	assert.EqualError(t, (&BookingReviewRequest{Reason: "  "}).Validate(false), "a reason is required to reject a booking")
+	assert.NoError(t, (&BookingReviewRequest{Reason: "Court reserved for league play"}).Validate(false))
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Booking participant statuses
+const (
+	ParticipantStatusInvited  = "invited"
+	ParticipantStatusAccepted = "accepted"
+	ParticipantStatusDeclined = "declined"
+)
+
+// BookingParticipant is a player on a booking's roster. The owner is always
+// on the roster as an accepted participant.
+type BookingParticipant struct {
+	ID          uuid.UUID  `json:"id"`
+	BookingID   uuid.UUID  `json:"booking_id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	UserName    string     `json:"user_name"`
+	Status      string     `json:"status"` // invited, accepted, declined
+	IsOwner     bool       `json:"is_owner"`
+	InvitedBy   *uuid.UUID `json:"invited_by,omitempty"` // Empty for the owner and players who joined by link
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// BookingInviteRequest represents a request to invite players to a booking
+type BookingInviteRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required"`
+}
+
+// BookingInviteResponse represents an invitee accepting or declining
+type BookingInviteResponse struct {
+	Status string `json:"status" binding:"required"` // accepted or declined
+}
+
+// BookingShareLink is the link an owner shares so players can join a booking
+type BookingShareLink struct {
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
+
+// NewBookingShareLink returns the share link for a token
+func NewBookingShareLink(token string) BookingShareLink {
+	return BookingShareLink{Token: token, Path: "/bookings/join/" + token}
+}
+
+// AcceptedCount returns how many players have a confirmed place on the roster
+func (b *Booking) AcceptedCount() int {
+	count := 0
+	for _, p := range b.Participants {
+		if p.Status == ParticipantStatusAccepted {
+			count++
+		}
+	}
+	return count
+}
+
+// PlayerIDs returns the owner and every player with a confirmed place on
+// the roster, the owner first
+func (b *Booking) PlayerIDs() []uuid.UUID {
+	ids := []uuid.UUID{b.UserID}
+	for _, p := range b.Participants {
+		if p.Status == ParticipantStatusAccepted && p.UserID != b.UserID {
+			ids = append(ids, p.UserID)
+		}
+	}
+	return ids
+}
+
+// SpotsOpen returns how many places are left before the booking reaches its player count
+func (b *Booking) SpotsOpen() int {
+	if open := b.PlayerCount - b.AcceptedCount(); open > 0 {
+		return open
+	}
+	return 0
+}
+
+// Participant returns the user's roster entry, or nil if they are not on it
+func (b *Booking) Participant(userID uuid.UUID) *BookingParticipant {
+	for i := range b.Participants {
+		if b.Participants[i].UserID == userID {
+			return &b.Participants[i]
+		}
+	}
+	return nil
+}
+
+// CheckJoin returns an error unless the user can take a place on the booking
+func (b *Booking) CheckJoin(userID uuid.UUID, now time.Time) error {
+	if b.Status != BookingStatusPending && b.Status != BookingStatusConfirmed {
+		return fmt.Errorf("booking is no longer active")
+	}
+	if !now.Before(b.StartTime) {
+		return fmt.Errorf("booking has already started")
+	}
+	if p := b.Participant(userID); p != nil && p.Status == ParticipantStatusAccepted {
+		return fmt.Errorf("already on the roster")
+	}
+	if b.SpotsOpen() == 0 {
+		return fmt.Errorf("booking is full")
+	}
+	return nil
+}
+
+// OpenSpotsTitle is the title of the bulletin advertising a booking's open spots
+func (b *Booking) OpenSpotsTitle(courtName string) string {
+	noun := "spots"
+	if b.SpotsOpen() == 1 {
+		noun = "spot"
+	}
+	return fmt.Sprintf("%d %s open for %s at %s", b.SpotsOpen(), noun, b.GameType, courtName)
+}
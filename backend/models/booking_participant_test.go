@@ -0,0 +1,52 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBooking_Roster(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	owner, invitee, stranger := uuid.New(), uuid.New(), uuid.New()
+	booking := &Booking{
+		UserID:      owner,
+		StartTime:   now.Add(24 * time.Hour),
+		Status:      BookingStatusConfirmed,
+		PlayerCount: 4,
+		GameType:    "Doubles",
+		Participants: []BookingParticipant{
+			{UserID: owner, Status: ParticipantStatusAccepted, IsOwner: true},
+			{UserID: invitee, Status: ParticipantStatusInvited},
+		},
+	}
+
+	assert.Equal(t, 1, booking.AcceptedCount())
+	assert.Equal(t, 3, booking.SpotsOpen())
+	assert.Equal(t, "3 spots open for Doubles at Golden Gate Park", booking.OpenSpotsTitle("Golden Gate Park"))
+	assert.NoError(t, booking.CheckJoin(invitee, now))
+	assert.EqualError(t, booking.CheckJoin(owner, now), "already on the roster")
+	assert.Nil(t, booking.Participant(stranger))
+	assert.Equal(t, []uuid.UUID{owner}, booking.PlayerIDs(), "invitees haven't accepted yet")
+
+	booking.Participants[1].Status = ParticipantStatusAccepted
+	assert.Equal(t, []uuid.UUID{owner, invitee}, booking.PlayerIDs())
+	booking.Participants = append(booking.Participants, BookingParticipant{UserID: uuid.New(), Status: ParticipantStatusAccepted})
+	assert.Equal(t, "1 spot open for Doubles at Golden Gate Park", booking.OpenSpotsTitle("Golden Gate Park"))
+
+	booking.Participants = append(booking.Participants, BookingParticipant{UserID: uuid.New(), Status: ParticipantStatusAccepted})
+	assert.Equal(t, 0, booking.SpotsOpen())
+	assert.EqualError(t, booking.CheckJoin(stranger, now), "booking is full")
+
+	assert.EqualError(t, booking.CheckJoin(stranger, booking.StartTime), "booking has already started")
+	booking.Status = BookingStatusCancelled
+	assert.EqualError(t, booking.CheckJoin(stranger, now), "booking is no longer active")
+}
+
+func TestNewBookingShareLink(t *testing.T) {
+	link := NewBookingShareLink("abc123")
+	assert.Equal(t, "abc123", link.Token)
+	assert.Equal(t, "/bookings/join/abc123", link.Path)
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Booking payment statuses
+const (
+	PaymentStatusRequiresPayment   = "requires_payment"
+	PaymentStatusFailed            = "failed" // The last attempt was declined; the player can try again
+	PaymentStatusSucceeded         = "succeeded"
+	PaymentStatusCancelled         = "cancelled" // The booking was cancelled or expired before it was paid
+	PaymentStatusRefunded          = "refunded"
+	PaymentStatusPartiallyRefunded = "partially_refunded"
+)
+
+// PaymentWindow is how long an unpaid booking holds its court before it is cancelled
+const PaymentWindow = 15 * time.Minute
+
+// CourtPricing is what a private court charges per hour. Public courts are free to book.
+type CourtPricing struct {
+	CourtID                uuid.UUID   `json:"court_id"`
+	Currency               string      `json:"currency"`                   // ISO 4217 code, e.g. USD
+	OffPeakRateCents       int         `json:"off_peak_rate_cents"`        // Per hour
+	PeakRateCents          int         `json:"peak_rate_cents"`            // Per hour
+	MemberOffPeakRateCents int         `json:"member_off_peak_rate_cents"` // Per hour
+	MemberPeakRateCents    int         `json:"member_peak_rate_cents"`     // Per hour
+	PeakStart              string      `json:"peak_start,omitempty"`       // HH:MM
+	PeakEnd                string      `json:"peak_end,omitempty"`         // HH:MM
+	FullRefundHours        int         `json:"full_refund_hours"`          // Cancelling at least this far ahead is refunded in full
+	LateRefundPercent      int         `json:"late_refund_percent"`        // Share refunded for later cancellations
+	MemberIDs              []uuid.UUID `json:"member_ids,omitempty"`       // Players charged member rates
+	UpdatedAt              time.Time   `json:"updated_at"`
+}
+
+// DefaultCourtPricing is used for courts that have not set their own prices
+func DefaultCourtPricing(courtID uuid.UUID) *CourtPricing {
+	return &CourtPricing{
+		CourtID:         courtID,
+		Currency:        "USD",
+		FullRefundHours: 24,
+	}
+}
+
+// Validate checks the pricing is consistent
+func (p *CourtPricing) Validate() error {
+	if len(p.Currency) != 3 {
+		return fmt.Errorf("currency must be a three-letter code")
+	}
+	if p.OffPeakRateCents < 0 || p.PeakRateCents < 0 || p.MemberOffPeakRateCents < 0 || p.MemberPeakRateCents < 0 {
+		return fmt.Errorf("rates cannot be negative")
+	}
+	if p.FullRefundHours < 0 {
+		return fmt.Errorf("full refund hours cannot be negative")
+	}
+	if p.LateRefundPercent < 0 || p.LateRefundPercent > 100 {
+		return fmt.Errorf("late refund percent must be between 0 and 100")
+	}
+	if p.PeakStart != "" || p.PeakEnd != "" {
+		if _, _, err := parseOpeningTimes(p.PeakStart, p.PeakEnd); err != nil {
+			return fmt.Errorf("peak hours: %w", err)
+		}
+	}
+	return nil
+}
+
+// IsMember returns true if the player pays member rates
+func (p *CourtPricing) IsMember(userID uuid.UUID) bool {
+	for _, id := range p.MemberIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Quote prices a booking between start and end, given in the court's local
+// time. Minutes inside the peak window on start's day are charged the peak rate.
+func (p *CourtPricing) Quote(userID uuid.UUID, start, end time.Time) *BookingQuote {
+	quote := &BookingQuote{
+		Currency:         p.Currency,
+		OffPeakRateCents: p.OffPeakRateCents,
+		PeakRateCents:    p.PeakRateCents,
+		MemberRate:       p.IsMember(userID),
+	}
+	if quote.MemberRate {
+		quote.OffPeakRateCents, quote.PeakRateCents = p.MemberOffPeakRateCents, p.MemberPeakRateCents
+	}
+
+	total := int(end.Sub(start) / time.Minute)
+	if p.PeakStart != "" {
+		peakStart, peakEnd := clockOn(start, p.PeakStart), clockOn(start, p.PeakEnd)
+		if peakStart.Before(start) {
+			peakStart = start
+		}
+		if peakEnd.After(end) {
+			peakEnd = end
+		}
+		if peakEnd.After(peakStart) {
+			quote.PeakMinutes = int(peakEnd.Sub(peakStart) / time.Minute)
+		}
+	}
+	quote.OffPeakMinutes = total - quote.PeakMinutes
+
+	// Rates are per hour; round the total to the nearest cent
+	quote.AmountCents = (quote.PeakMinutes*quote.PeakRateCents + quote.OffPeakMinutes*quote.OffPeakRateCents + 30) / 60
+	return quote
+}
+
+// RefundCents returns how much of a payment is refunded when a booking
+// starting at start is cancelled at now
+func (p *CourtPricing) RefundCents(amountCents int, start, now time.Time) int {
+	if !now.After(start.Add(-time.Duration(p.FullRefundHours) * time.Hour)) {
+		return amountCents
+	}
+	return amountCents * p.LateRefundPercent / 100
+}
+
+// BookingQuote is the price of a booking
+type BookingQuote struct {
+	Currency         string `json:"currency"`
+	AmountCents      int    `json:"amount_cents"`
+	PeakMinutes      int    `json:"peak_minutes"`
+	OffPeakMinutes   int    `json:"off_peak_minutes"`
+	PeakRateCents    int    `json:"peak_rate_cents"`
+	OffPeakRateCents int    `json:"off_peak_rate_cents"`
+	MemberRate       bool   `json:"member_rate"`
+}
+
+// IsFree returns true if the booking can be confirmed without payment
+func (q *BookingQuote) IsFree() bool {
+	return q.AmountCents == 0
+}
+
+// BookingPayment tracks the payment for a paid booking with the payment provider
+type BookingPayment struct {
+	ID            uuid.UUID `json:"id"`
+	BookingID     uuid.UUID `json:"booking_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	Provider      string    `json:"provider"`
+	IntentID      string    `json:"intent_id"`
+	ClientSecret  string    `json:"client_secret,omitempty"` // Only returned to the player when the booking is created
+	AmountCents   int       `json:"amount_cents"`
+	Currency      string    `json:"currency"`
+	Status        string    `json:"status"`
+	RefundedCents int       `json:"refunded_cents"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// IsOpen returns true if the payment can still be made
+func (p *BookingPayment) IsOpen() bool {
+	return p.Status == PaymentStatusRequiresPayment || p.Status == PaymentStatusFailed
+}
+
+// PaymentRequest is a player paying for a pending booking
+type PaymentRequest struct {
+	PaymentMethod string `json:"payment_method" binding:"required"` // Token from the payment provider
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourtPricing_Validate(t *testing.T) {
+	pricing := DefaultCourtPricing(uuid.New())
+	assert.NoError(t, pricing.Validate())
+
+	pricing.Currency = "dollars"
+	assert.EqualError(t, pricing.Validate(), "currency must be a three-letter code")
+
+	pricing = DefaultCourtPricing(uuid.New())
+	pricing.PeakRateCents = -100
+	assert.EqualError(t, pricing.Validate(), "rates cannot be negative")
+
+	pricing = DefaultCourtPricing(uuid.New())
+	pricing.LateRefundPercent = 150
+	assert.EqualError(t, pricing.Validate(), "late refund percent must be between 0 and 100")
+
+	pricing = DefaultCourtPricing(uuid.New())
+	pricing.PeakStart = "17:00"
+	assert.ErrorContains(t, pricing.Validate(), "peak hours")
+}
+
+func TestCourtPricing_Quote(t *testing.T) {
+	member, guest := uuid.New(), uuid.New()
+	pricing := &CourtPricing{
+		Currency:               "USD",
+		OffPeakRateCents:       2000,
+		PeakRateCents:          3000,
+		MemberOffPeakRateCents: 1000,
+		MemberPeakRateCents:    1500,
+		PeakStart:              "17:00",
+		PeakEnd:                "21:00",
+		MemberIDs:              []uuid.UUID{member},
+	}
+	day := time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)
+
+	// Entirely off-peak
+	quote := pricing.Quote(guest, day.Add(10*time.Hour), day.Add(11*time.Hour+30*time.Minute))
+	assert.Equal(t, 3000, quote.AmountCents)
+	assert.Equal(t, 0, quote.PeakMinutes)
+	assert.Equal(t, 90, quote.OffPeakMinutes)
+	assert.False(t, quote.MemberRate)
+	assert.False(t, quote.IsFree())
+
+	// Straddling the start of peak hours
+	quote = pricing.Quote(guest, day.Add(16*time.Hour+30*time.Minute), day.Add(17*time.Hour+30*time.Minute))
+	assert.Equal(t, 30, quote.PeakMinutes)
+	assert.Equal(t, 30, quote.OffPeakMinutes)
+	assert.Equal(t, 2500, quote.AmountCents)
+
+	// Members pay member rates
+	quote = pricing.Quote(member, day.Add(18*time.Hour), day.Add(19*time.Hour))
+	assert.True(t, quote.MemberRate)
+	assert.Equal(t, 1500, quote.AmountCents)
+
+	// Courts without prices are free
+	quote = DefaultCourtPricing(uuid.New()).Quote(guest, day.Add(18*time.Hour), day.Add(19*time.Hour))
+	assert.True(t, quote.IsFree())
+}
+
+func TestCourtPricing_RefundCents(t *testing.T) {
+	start := time.Date(2025, 6, 3, 18, 0, 0, 0, time.UTC)
+	pricing := &CourtPricing{FullRefundHours: 24, LateRefundPercent: 50}
+
+	assert.Equal(t, 3000, pricing.RefundCents(3000, start, start.Add(-48*time.Hour)))
+	assert.Equal(t, 3000, pricing.RefundCents(3000, start, start.Add(-24*time.Hour)))
+	assert.Equal(t, 1500, pricing.RefundCents(3000, start, start.Add(-2*time.Hour)))
+
+	pricing.LateRefundPercent = 0
+	assert.Equal(t, 0, pricing.RefundCents(3000, start, start.Add(-2*time.Hour)))
+}
+
+func TestBookingPayment_IsOpen(t *testing.T) {
+	assert.True(t, (&BookingPayment{Status: PaymentStatusRequiresPayment}).IsOpen())
+	assert.True(t, (&BookingPayment{Status: PaymentStatusFailed}).IsOpen())
+	assert.False(t, (&BookingPayment{Status: PaymentStatusSucceeded}).IsOpen())
+	assert.False(t, (&BookingPayment{Status: PaymentStatusCancelled}).IsOpen())
+}
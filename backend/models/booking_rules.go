@@ -0,0 +1,195 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultCancelCutoffMinutes is how long before the start a booking can still
+// be cancelled at courts that have not set their own rules
+const DefaultCancelCutoffMinutes = 30
+
+// DefaultApprovalHours is how long managers have to approve a booking request
+const DefaultApprovalHours = 24
+
+// BookingRules are a court manager's limits on how its courts can be booked
+type BookingRules struct {
+	CourtID             uuid.UUID `json:"court_id"`
+	SlotMinutes         int       `json:"slot_minutes"`          // 30, 45, 60 or 90
+	AlignToSlots        bool      `json:"align_to_slots"`        // Bookings must start on a slot and last whole slots
+	MinDurationMinutes  int       `json:"min_duration_minutes"`  // Shortest booking allowed
+	MaxDurationMinutes  int       `json:"max_duration_minutes"`  // Longest booking allowed, 0 for no limit
+	AdvanceDays         int       `json:"advance_days"`          // How far ahead booking opens, 0 for no limit
+	MaxActiveBookings   int       `json:"max_active_bookings"`   // Upcoming bookings one player may hold here, 0 for no limit
+	CancelCutoffMinutes int       `json:"cancel_cutoff_minutes"` // Bookings cannot be cancelled closer to the start than this
+
+	// Prime time is a daily window, e.g. 17:00-21:00, with tighter limits
+	PrimeTimeStart      string    `json:"prime_time_start,omitempty"` // HH:MM
+	PrimeTimeEnd        string    `json:"prime_time_end,omitempty"`   // HH:MM
+	PrimeTimeMaxMinutes int       `json:"prime_time_max_minutes"`     // Longest prime-time booking, 0 for no limit
+	PrimeTimeMaxPerWeek int       `json:"prime_time_max_per_week"`    // Prime-time bookings one player may hold per week, 0 for no limit
+	UpdatedAt           time.Time `json:"updated_at"`
+
+	// Bookings by players who do not manage the court can wait for a manager's approval
+	RequireApproval bool `json:"require_approval"`
+	ApprovalHours   int  `json:"approval_hours"` // How long managers have to respond before a request expires
+}
+
+// DefaultBookingRules are used for courts that have not set their own
+func DefaultBookingRules(courtID uuid.UUID) *BookingRules {
+	return &BookingRules{
+		CourtID:             courtID,
+		SlotMinutes:         60,
+		MinDurationMinutes:  30,
+		CancelCutoffMinutes: DefaultCancelCutoffMinutes,
+		ApprovalHours:       DefaultApprovalHours,
+	}
+}
+
+// Validate checks the rules are consistent
+func (r *BookingRules) Validate() error {
+	switch r.SlotMinutes {
+	case 30, 45, 60, 90:
+	default:
+		return fmt.Errorf("slot length must be 30, 45, 60 or 90 minutes")
+	}
+	if r.MinDurationMinutes < 0 || r.MaxDurationMinutes < 0 || r.AdvanceDays < 0 || r.MaxActiveBookings < 0 ||
+		r.CancelCutoffMinutes < 0 || r.PrimeTimeMaxMinutes < 0 || r.PrimeTimeMaxPerWeek < 0 || r.ApprovalHours < 0 {
+		return fmt.Errorf("limits cannot be negative")
+	}
+	if r.RequireApproval && r.ApprovalHours == 0 {
+		return fmt.Errorf("approval hours are required when bookings need approval")
+	}
+	if r.MaxDurationMinutes > 0 && r.MaxDurationMinutes < r.MinDurationMinutes {
+		return fmt.Errorf("maximum duration must be at least the minimum duration")
+	}
+	if r.PrimeTimeStart != "" || r.PrimeTimeEnd != "" {
+		if _, _, err := parseOpeningTimes(r.PrimeTimeStart, r.PrimeTimeEnd); err != nil {
+			return fmt.Errorf("prime time: %w", err)
+		}
+	}
+	return nil
+}
+
+// SlotDuration returns the slot length availability is shown in
+func (r *BookingRules) SlotDuration() time.Duration {
+	return time.Duration(r.SlotMinutes) * time.Minute
+}
+
+// IsPrimeTime returns true if start-end overlaps the prime-time window on start's day
+func (r *BookingRules) IsPrimeTime(start, end time.Time) bool {
+	if r.PrimeTimeStart == "" {
+		return false
+	}
+	primeStart, primeEnd := clockOn(start, r.PrimeTimeStart), clockOn(start, r.PrimeTimeEnd)
+	return start.Before(primeEnd) && end.After(primeStart)
+}
+
+// CheckTimes checks a booking's length, slot alignment and how far ahead it
+// is. opens is when the court opens that day; slots are counted from it.
+func (r *BookingRules) CheckTimes(start, end, opens, now time.Time) error {
+	minutes := int(end.Sub(start) / time.Minute)
+	if minutes < r.MinDurationMinutes {
+		return fmt.Errorf("bookings must be at least %d minutes", r.MinDurationMinutes)
+	}
+	if r.MaxDurationMinutes > 0 && minutes > r.MaxDurationMinutes {
+		return fmt.Errorf("bookings can be at most %d minutes", r.MaxDurationMinutes)
+	}
+	if r.AlignToSlots {
+		if minutes%r.SlotMinutes != 0 {
+			return fmt.Errorf("bookings must last a whole number of %d-minute slots", r.SlotMinutes)
+		}
+		if int(start.Sub(opens)/time.Minute)%r.SlotMinutes != 0 {
+			return fmt.Errorf("bookings must start on a %d-minute slot from %s", r.SlotMinutes, opens.Format("15:04"))
+		}
+	}
+	if r.AdvanceDays > 0 && start.After(now.AddDate(0, 0, r.AdvanceDays)) {
+		return fmt.Errorf("bookings open %d days ahead", r.AdvanceDays)
+	}
+	if r.PrimeTimeMaxMinutes > 0 && minutes > r.PrimeTimeMaxMinutes && r.IsPrimeTime(start, end) {
+		return fmt.Errorf("prime-time bookings can be at most %d minutes", r.PrimeTimeMaxMinutes)
+	}
+	return nil
+}
+
+// CheckLimits checks a new booking against the player's existing bookings at
+// the court: how many upcoming bookings they hold and their prime-time use
+// in the week of the new booking
+func (r *BookingRules) CheckLimits(existing []*Booking, start, end, now time.Time) error {
+	active := 0
+	for _, booking := range existing {
+		if booking.EndTime.After(now) {
+			active++
+		}
+	}
+	if r.MaxActiveBookings > 0 && active >= r.MaxActiveBookings {
+		return fmt.Errorf("players can hold at most %d upcoming bookings here", r.MaxActiveBookings)
+	}
+
+	if r.PrimeTimeMaxPerWeek > 0 && r.IsPrimeTime(start, end) {
+		weekStart := StartOfWeek(start)
+		weekEnd := weekStart.AddDate(0, 0, 7)
+		prime := 0
+		for _, booking := range existing {
+			if !booking.StartTime.Before(weekStart) && booking.StartTime.Before(weekEnd) && r.IsPrimeTime(booking.StartTime, booking.EndTime) {
+				prime++
+			}
+		}
+		if prime >= r.PrimeTimeMaxPerWeek {
+			return fmt.Errorf("players can hold at most %d prime-time bookings per week here", r.PrimeTimeMaxPerWeek)
+		}
+	}
+	return nil
+}
+
+// ApprovalDeadline returns when a booking request made at now for a booking
+// starting at start expires. Requests never outlast the booking's start.
+func (r *BookingRules) ApprovalDeadline(start, now time.Time) time.Time {
+	deadline := now.Add(time.Duration(r.ApprovalHours) * time.Hour)
+	if deadline.After(start) {
+		return start
+	}
+	return deadline
+}
+
+// CanCancel returns true if the booking is active and the cancellation cutoff has not passed
+func (r *BookingRules) CanCancel(b *Booking, now time.Time) bool {
+	return (b.Status == BookingStatusPending || b.Status == BookingStatusConfirmed) &&
+		now.Before(b.StartTime.Add(-time.Duration(r.CancelCutoffMinutes)*time.Minute))
+}
+
+// ApplyTo marks prime-time slots and closes slots that have started or are
+// beyond the advance booking window
+func (r *BookingRules) ApplyTo(availability *CourtAvailability, now time.Time) {
+	availability.Rules = r
+	apply := func(slots []TimeSlotAvailability) {
+		for i := range slots {
+			slot := &slots[i]
+			slot.IsPrimeTime = r.IsPrimeTime(slot.StartTime, slot.EndTime)
+			reason := ""
+			switch {
+			case slot.StartTime.Before(now):
+				reason = "slot has already started"
+			case r.AdvanceDays > 0 && slot.StartTime.After(now.AddDate(0, 0, r.AdvanceDays)):
+				reason = fmt.Sprintf("bookings open %d days ahead", r.AdvanceDays)
+			}
+			if reason != "" && slot.IsAvailable {
+				slot.IsAvailable = false
+				slot.FreeUnits = 0
+				slot.ClosedReason = reason
+			}
+		}
+	}
+	apply(availability.TimeSlots)
+	for i := range availability.Units {
+		apply(availability.Units[i].TimeSlots)
+	}
+}
+
+// StartOfWeek returns midnight on the Monday of t's week, in t's location
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookingRules_Validate(t *testing.T) {
+	rules := DefaultBookingRules(uuid.New())
+	assert.NoError(t, rules.Validate())
+
+	rules.SlotMinutes = 50
+	assert.EqualError(t, rules.Validate(), "slot length must be 30, 45, 60 or 90 minutes")
+
+	rules = DefaultBookingRules(uuid.New())
+	rules.MaxDurationMinutes = 15
+	assert.EqualError(t, rules.Validate(), "maximum duration must be at least the minimum duration")
+
+	rules = DefaultBookingRules(uuid.New())
+	rules.PrimeTimeStart = "17:00"
+	assert.ErrorContains(t, rules.Validate(), "prime time")
+}
+
+func TestBookingRules_CheckTimes(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	opens := time.Date(2025, 6, 3, 6, 0, 0, 0, time.UTC)
+	start := time.Date(2025, 6, 3, 10, 30, 0, 0, time.UTC)
+	rules := &BookingRules{
+		SlotMinutes:         45,
+		AlignToSlots:        true,
+		MinDurationMinutes:  45,
+		MaxDurationMinutes:  135,
+		AdvanceDays:         7,
+		PrimeTimeStart:      "17:00",
+		PrimeTimeEnd:        "21:00",
+		PrimeTimeMaxMinutes: 90,
+	}
+
+	assert.NoError(t, rules.CheckTimes(start, start.Add(90*time.Minute), opens, now))
+	assert.EqualError(t, rules.CheckTimes(start, start.Add(30*time.Minute), opens, now), "bookings must be at least 45 minutes")
+	assert.EqualError(t, rules.CheckTimes(start, start.Add(180*time.Minute), opens, now), "bookings can be at most 135 minutes")
+	assert.EqualError(t, rules.CheckTimes(start, start.Add(60*time.Minute), opens, now), "bookings must last a whole number of 45-minute slots")
+	assert.EqualError(t, rules.CheckTimes(start.Add(15*time.Minute), start.Add(60*time.Minute), opens, now),
+		"bookings must start on a 45-minute slot from 06:00")
+	assert.EqualError(t, rules.CheckTimes(start.AddDate(0, 0, 7), start.AddDate(0, 0, 7).Add(45*time.Minute), opens.AddDate(0, 0, 7), now),
+		"bookings open 7 days ahead")
+
+	evening := time.Date(2025, 6, 3, 17, 15, 0, 0, time.UTC)
+	assert.EqualError(t, rules.CheckTimes(evening, evening.Add(135*time.Minute), opens, now), "prime-time bookings can be at most 90 minutes")
+}
+
+func TestBookingRules_CheckLimits(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC) // Monday
+	evening := func(day int) *Booking {
+		start := time.Date(2025, 6, day, 18, 0, 0, 0, time.UTC)
+		return &Booking{StartTime: start, EndTime: start.Add(time.Hour)}
+	}
+	rules := &BookingRules{SlotMinutes: 60, MaxActiveBookings: 3, PrimeTimeStart: "17:00", PrimeTimeEnd: "21:00", PrimeTimeMaxPerWeek: 1}
+
+	next := evening(4)
+	assert.NoError(t, rules.CheckLimits(nil, next.StartTime, next.EndTime, now))
+	assert.EqualError(t, rules.CheckLimits([]*Booking{evening(3)}, next.StartTime, next.EndTime, now),
+		"players can hold at most 1 prime-time bookings per week here")
+
+	// Prime time last week does not count, and daytime bookings are not limited
+	assert.NoError(t, rules.CheckLimits([]*Booking{evening(1)}, next.StartTime, next.EndTime, now))
+	morning := next.StartTime.Add(-8 * time.Hour)
+	assert.NoError(t, rules.CheckLimits([]*Booking{evening(3)}, morning, morning.Add(time.Hour), now))
+
+	assert.EqualError(t, rules.CheckLimits([]*Booking{evening(9), evening(10), evening(11)}, morning, morning.Add(time.Hour), now),
+		"players can hold at most 3 upcoming bookings here")
+}
+
+func TestBookingRules_CanCancel(t *testing.T) {
+	start := time.Date(2025, 6, 3, 18, 0, 0, 0, time.UTC)
+	booking := &Booking{StartTime: start, EndTime: start.Add(time.Hour), Status: BookingStatusConfirmed}
+	rules := &BookingRules{CancelCutoffMinutes: 24 * 60}
+
+	assert.True(t, rules.CanCancel(booking, start.Add(-25*time.Hour)))
+	assert.False(t, rules.CanCancel(booking, start.Add(-23*time.Hour)))
+
+	booking.Status = BookingStatusCancelled
+	assert.False(t, rules.CanCancel(booking, start.Add(-25*time.Hour)))
+}
+
+func TestBookingRules_ApplyTo(t *testing.T) {
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	rules := &BookingRules{SlotMinutes: 90, PrimeTimeStart: "18:00", PrimeTimeEnd: "21:00"}
+	availability := BuildAvailability(uuid.New(), newTestUnits(1), nil, &CourtSchedule{}, day, rules.SlotDuration())
+	rules.ApplyTo(availability, day.Add(8*time.Hour))
+
+	// 06:00, 07:30, ... 19:30: ten 90-minute slots before 22:00
+	assert.Len(t, availability.TimeSlots, 10)
+	assert.False(t, availability.TimeSlots[0].IsAvailable)
+	assert.Equal(t, "slot has already started", availability.TimeSlots[0].ClosedReason)
+	assert.True(t, availability.TimeSlots[2].IsAvailable)
+	assert.False(t, availability.TimeSlots[7].IsPrimeTime) // 16:30-18:00
+	assert.True(t, availability.TimeSlots[8].IsPrimeTime)
+	assert.Equal(t, rules, availability.Rules)
+}
+
+func TestBookingRules_ApprovalDeadline(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	rules := DefaultBookingRules(uuid.New())
+	rules.RequireApproval = true
+	assert.NoError(t, rules.Validate())
+
+	assert.Equal(t, now.Add(24*time.Hour), rules.ApprovalDeadline(now.AddDate(0, 0, 3), now))
+	assert.Equal(t, now.Add(6*time.Hour), rules.ApprovalDeadline(now.Add(6*time.Hour), now), "requests expire when the booking starts")
+
+	rules.ApprovalHours = 0
+	assert.EqualError(t, rules.Validate(), "approval hours are required when bookings need approval")
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Recurrence frequencies for booking series
+const (
+	RecurrenceDaily  = "daily"
+	RecurrenceWeekly = "weekly"
+)
+
+// Booking series statuses
+const (
+	BookingSeriesStatusActive    = "active"
+	BookingSeriesStatusCancelled = "cancelled"
+)
+
+// Occurrence statuses in a series report
+const (
+	OccurrenceBooked     = "booked"
+	OccurrenceConflict   = "conflict"    // Another booking holds the court
+	OccurrenceClosed     = "closed"      // Outside opening hours, a holiday or a closure
+	OccurrenceNotAllowed = "not_allowed" // Breaks the court's booking rules
+	OccurrenceSkipped    = "skipped"
+	OccurrenceCancelled  = "cancelled"
+)
+
+// MaxSeriesOccurrences caps how many bookings one series can hold
+const MaxSeriesOccurrences = 52
+
+// RecurrenceRule describes how often a series repeats and until when
+type RecurrenceRule struct {
+	Frequency string `json:"frequency" binding:"required"` // daily or weekly
+	Interval  int    `json:"interval"`                     // Every N days or weeks, defaults to 1
+	Until     string `json:"until" binding:"required"`     // YYYY-MM-DD, the last day an occurrence can fall on
+}
+
+// Validate checks the frequency, interval and end date
+func (r RecurrenceRule) Validate() error {
+	if r.Frequency != RecurrenceDaily && r.Frequency != RecurrenceWeekly {
+		return fmt.Errorf("frequency must be daily or weekly")
+	}
+	if r.Interval < 0 || r.Interval > 4 {
+		return fmt.Errorf("interval must be between 1 and 4")
+	}
+	if _, err := time.Parse("2006-01-02", r.Until); err != nil {
+		return fmt.Errorf("invalid until date format, use YYYY-MM-DD")
+	}
+	return nil
+}
+
+// Starts returns the start of each occurrence from first until the end
+// date, keeping the same wall-clock time in first's location across DST
+// changes. The result is capped at MaxSeriesOccurrences.
+func (r RecurrenceRule) Starts(first time.Time) []time.Time {
+	until, err := time.ParseInLocation("2006-01-02", r.Until, first.Location())
+	if err != nil {
+		return nil
+	}
+	until = until.AddDate(0, 0, 1)
+
+	interval := r.Interval
+	if interval == 0 {
+		interval = 1
+	}
+	days := interval
+	if r.Frequency == RecurrenceWeekly {
+		days = 7 * interval
+	}
+
+	var starts []time.Time
+	for i := 0; len(starts) < MaxSeriesOccurrences; i++ {
+		start := first.AddDate(0, 0, i*days)
+		if !start.Before(until) {
+			break
+		}
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// BookingSeries is a standing booking, e.g. every Thursday at 7pm, made up
+// of one booking per occurrence
+type BookingSeries struct {
+	ID              uuid.UUID  `json:"id"`
+	CourtID         uuid.UUID  `json:"court_id"`
+	CourtUnitID     *uuid.UUID `json:"court_unit_id,omitempty"` // Requested court; occurrences fall back to any free court when unset
+	UserID          uuid.UUID  `json:"user_id"`
+	FirstStartTime  time.Time  `json:"first_start_time"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Status          string     `json:"status"` // active, cancelled
+	PlayerCount     int        `json:"player_count"`
+	GameType        string     `json:"game_type"`
+	Notes           string     `json:"notes,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	RecurrenceRule
+
+	// Populated fields (not stored in DB)
+	Timezone    string             `json:"timezone,omitempty"`
+	Occurrences []SeriesOccurrence `json:"occurrences,omitempty"`
+	Bookings    []*Booking         `json:"bookings,omitempty"` // Made when the series is created, with their payments
+}
+
+// Validate checks the recurrence rule and that occurrences cannot overlap each other
+func (s *BookingSeries) Validate() error {
+	if err := s.RecurrenceRule.Validate(); err != nil {
+		return err
+	}
+	if s.DurationMinutes <= 0 || s.DurationMinutes > 24*60 {
+		return fmt.Errorf("duration must be between 1 minute and 24 hours")
+	}
+	return nil
+}
+
+// Duration returns how long each occurrence lasts
+func (s *BookingSeries) Duration() time.Duration {
+	return time.Duration(s.DurationMinutes) * time.Minute
+}
+
+// SeriesOccurrence reports what happened to one occurrence of a series
+type SeriesOccurrence struct {
+	Date      string      `json:"date"` // YYYY-MM-DD in the court's timezone
+	StartTime time.Time   `json:"start_time"`
+	EndTime   time.Time   `json:"end_time"`
+	Status    string      `json:"status"`
+	Reason    string      `json:"reason,omitempty"`
+	BookingID *uuid.UUID  `json:"booking_id,omitempty"`
+	Conflicts []uuid.UUID `json:"conflicting_booking_ids,omitempty"`
+}
+
+// BookingSeriesRequest represents a request to book a court on a recurring basis
+type BookingSeriesRequest struct {
+	BookingRequest
+	RecurrenceRule
+	SkipConflicts bool `json:"skip_conflicts"` // Book the free occurrences rather than rejecting the whole series
+}
+
+// BuildSeriesOccurrences reports each occurrence of a series from its
+// bookings and skipped dates. starts must be in the court's timezone;
+// skipped maps dates to the reason they were skipped.
+func BuildSeriesOccurrences(series *BookingSeries, starts []time.Time, bookings []*Booking, skipped map[string]string) []SeriesOccurrence {
+	occurrences := make([]SeriesOccurrence, 0, len(starts))
+	for _, start := range starts {
+		occurrence := SeriesOccurrence{
+			Date:      start.Format("2006-01-02"),
+			StartTime: start,
+			EndTime:   start.Add(series.Duration()),
+			Status:    OccurrenceSkipped,
+		}
+		for _, booking := range bookings {
+			if booking.StartTime.Equal(start) {
+				bookingID := booking.ID
+				occurrence.BookingID = &bookingID
+				occurrence.Status = OccurrenceBooked
+				if booking.Status == BookingStatusCancelled {
+					occurrence.Status = OccurrenceCancelled
+				}
+				break
+			}
+		}
+		if reason, ok := skipped[occurrence.Date]; ok {
+			occurrence.Status = OccurrenceSkipped
+			occurrence.Reason = reason
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecurrenceRule_Validate(t *testing.T) {
+	assert.NoError(t, RecurrenceRule{Frequency: RecurrenceWeekly, Until: "2025-08-28"}.Validate())
+	assert.EqualError(t, RecurrenceRule{Frequency: "monthly", Until: "2025-08-28"}.Validate(), "frequency must be daily or weekly")
+	assert.EqualError(t, RecurrenceRule{Frequency: RecurrenceWeekly, Interval: 5, Until: "2025-08-28"}.Validate(), "interval must be between 1 and 4")
+	assert.EqualError(t, RecurrenceRule{Frequency: RecurrenceDaily, Until: "28/08/2025"}.Validate(), "invalid until date format, use YYYY-MM-DD")
+
+	series := &BookingSeries{DurationMinutes: 25 * 60, RecurrenceRule: RecurrenceRule{Frequency: RecurrenceDaily, Until: "2025-08-28"}}
+	assert.EqualError(t, series.Validate(), "duration must be between 1 minute and 24 hours")
+}
+
+func TestRecurrenceRule_Starts(t *testing.T) {
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	t.Run("weekly keeps the wall-clock time across DST", func(t *testing.T) {
+		first := time.Date(2025, 10, 23, 19, 0, 0, 0, newYork) // Thursday 7pm
+		starts := RecurrenceRule{Frequency: RecurrenceWeekly, Until: "2025-11-13"}.Starts(first)
+
+		assert.Len(t, starts, 4)
+		for _, start := range starts {
+			assert.Equal(t, time.Thursday, start.Weekday())
+			assert.Equal(t, 19, start.Hour())
+		}
+		assert.NotEqual(t, starts[0].UTC().Hour(), starts[3].UTC().Hour())
+	})
+
+	t.Run("interval and until", func(t *testing.T) {
+		first := time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)
+		starts := RecurrenceRule{Frequency: RecurrenceDaily, Interval: 2, Until: "2025-06-08"}.Starts(first)
+		assert.Equal(t, []time.Time{first, first.AddDate(0, 0, 2), first.AddDate(0, 0, 4), first.AddDate(0, 0, 6)}, starts)
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		first := time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)
+		starts := RecurrenceRule{Frequency: RecurrenceWeekly, Until: "2030-01-01"}.Starts(first)
+		assert.Len(t, starts, MaxSeriesOccurrences)
+	})
+}
+
+func TestBuildSeriesOccurrences(t *testing.T) {
+	first := time.Date(2025, 6, 5, 19, 0, 0, 0, time.UTC)
+	series := &BookingSeries{DurationMinutes: 90, RecurrenceRule: RecurrenceRule{Frequency: RecurrenceWeekly, Until: "2025-06-26"}}
+	starts := series.Starts(first)
+
+	bookings := []*Booking{
+		{ID: uuid.New(), StartTime: starts[0], Status: BookingStatusConfirmed},
+		{ID: uuid.New(), StartTime: starts[1], Status: BookingStatusCancelled},
+		{ID: uuid.New(), StartTime: starts[3], Status: BookingStatusCancelled},
+	}
+	skipped := map[string]string{
+		"2025-06-19": "court is closed for club tournament until Thu Jun 19 22:00",
+		"2025-06-26": "skipped by player",
+	}
+
+	occurrences := BuildSeriesOccurrences(series, starts, bookings, skipped)
+	assert.Len(t, occurrences, 4)
+	assert.Equal(t, OccurrenceBooked, occurrences[0].Status)
+	assert.Equal(t, bookings[0].ID, *occurrences[0].BookingID)
+	assert.Equal(t, first.Add(90*time.Minute), occurrences[0].EndTime)
+	assert.Equal(t, OccurrenceCancelled, occurrences[1].Status)
+	assert.Equal(t, OccurrenceSkipped, occurrences[2].Status)
+	assert.Nil(t, occurrences[2].BookingID)
+	assert.Equal(t, "skipped by player", occurrences[3].Reason)
+	assert.Equal(t, OccurrenceSkipped, occurrences[3].Status)
+}
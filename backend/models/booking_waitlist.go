@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Waitlist entry statuses
+const (
+	WaitlistStatusWaiting   = "waiting"
+	WaitlistStatusOffered   = "offered" // Holding a freed court until HoldExpiresAt
+	WaitlistStatusClaimed   = "claimed"
+	WaitlistStatusExpired   = "expired" // The hold ran out before it was claimed
+	WaitlistStatusCancelled = "cancelled"
+)
+
+// WaitlistHoldDuration is how long a freed court is held for the player it is offered to
+const WaitlistHoldDuration = 30 * time.Minute
+
+// BookingWaitlistEntry is a player waiting for a court to free up for a time window
+type BookingWaitlistEntry struct {
+	ID            uuid.UUID  `json:"id"`
+	CourtID       uuid.UUID  `json:"court_id"`
+	CourtUnitID   *uuid.UUID `json:"court_unit_id,omitempty"` // Empty to take any court at the facility
+	UserID        uuid.UUID  `json:"user_id"`
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       time.Time  `json:"end_time"`
+	PlayerCount   int        `json:"player_count"`
+	GameType      string     `json:"game_type"`
+	Notes         string     `json:"notes,omitempty"`
+	Status        string     `json:"status"`
+	HoldUnitID    *uuid.UUID `json:"hold_unit_id,omitempty"` // The court being held while offered
+	HoldToken     string     `json:"-"`
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty"`
+	BookingID     *uuid.UUID `json:"booking_id,omitempty"` // Set once the hold is claimed
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Populated fields (not stored in DB)
+	LocalTimes
+	Position  int                `json:"position,omitempty"` // Place in line among players waiting for the same court
+	ClaimLink *WaitlistClaimLink `json:"claim_link,omitempty"`
+}
+
+// WaitlistClaimLink is the link a player follows to turn their hold into a booking
+type WaitlistClaimLink struct {
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
+
+// NewWaitlistClaimLink returns the claim link for a hold token
+func NewWaitlistClaimLink(token string) *WaitlistClaimLink {
+	return &WaitlistClaimLink{Token: token, Path: "/bookings/waitlist/claim/" + token}
+}
+
+// Fits returns true if a court freed between start and end covers the
+// window the player is waiting for
+func (e *BookingWaitlistEntry) Fits(unitID *uuid.UUID, start, end time.Time) bool {
+	if e.CourtUnitID != nil && (unitID == nil || *e.CourtUnitID != *unitID) {
+		return false
+	}
+	return !e.StartTime.Before(start) && !e.EndTime.After(end)
+}
+
+// HoldActive returns true if the entry is holding a court that has not expired
+func (e *BookingWaitlistEntry) HoldActive(now time.Time) bool {
+	return e.Status == WaitlistStatusOffered && e.HoldExpiresAt != nil && now.Before(*e.HoldExpiresAt)
+}
+
+// NextInLine returns the first waiting entry, in the order given, that fits
+// a court freed between start and end
+func NextInLine(entries []*BookingWaitlistEntry, unitID *uuid.UUID, start, end time.Time) *BookingWaitlistEntry {
+	for _, entry := range entries {
+		if entry.Status == WaitlistStatusWaiting && entry.Fits(unitID, start, end) {
+			return entry
+		}
+	}
+	return nil
+}
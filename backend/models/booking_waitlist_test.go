@@ -0,0 +1,62 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookingWaitlistEntry_Fits(t *testing.T) {
+	start := time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)
+	unitA, unitB := uuid.New(), uuid.New()
+
+	anyCourt := &BookingWaitlistEntry{StartTime: start, EndTime: start.Add(time.Hour)}
+	assert.True(t, anyCourt.Fits(&unitA, start, start.Add(time.Hour)))
+	assert.True(t, anyCourt.Fits(nil, start.Add(-30*time.Minute), start.Add(90*time.Minute)))
+	assert.False(t, anyCourt.Fits(&unitA, start.Add(30*time.Minute), start.Add(2*time.Hour)), "freed slot starts too late")
+	assert.False(t, anyCourt.Fits(&unitA, start, start.Add(30*time.Minute)), "freed slot ends too early")
+
+	courtA := &BookingWaitlistEntry{CourtUnitID: &unitA, StartTime: start, EndTime: start.Add(time.Hour)}
+	assert.True(t, courtA.Fits(&unitA, start, start.Add(time.Hour)))
+	assert.False(t, courtA.Fits(&unitB, start, start.Add(time.Hour)))
+	assert.False(t, courtA.Fits(nil, start, start.Add(time.Hour)))
+}
+
+func TestBookingWaitlistEntry_HoldActive(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	expires := now.Add(WaitlistHoldDuration)
+	entry := &BookingWaitlistEntry{Status: WaitlistStatusOffered, HoldExpiresAt: &expires}
+
+	assert.True(t, entry.HoldActive(now))
+	assert.False(t, entry.HoldActive(expires))
+
+	entry.Status = WaitlistStatusClaimed
+	assert.False(t, entry.HoldActive(now))
+
+	assert.False(t, (&BookingWaitlistEntry{Status: WaitlistStatusOffered}).HoldActive(now))
+}
+
+func TestNextInLine(t *testing.T) {
+	start := time.Date(2025, 6, 2, 18, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	unitA, unitB := uuid.New(), uuid.New()
+
+	offered := &BookingWaitlistEntry{Status: WaitlistStatusOffered, StartTime: start, EndTime: end}
+	wantsB := &BookingWaitlistEntry{Status: WaitlistStatusWaiting, CourtUnitID: &unitB, StartTime: start, EndTime: end}
+	tooLong := &BookingWaitlistEntry{Status: WaitlistStatusWaiting, StartTime: start, EndTime: end.Add(time.Hour)}
+	anyCourt := &BookingWaitlistEntry{Status: WaitlistStatusWaiting, StartTime: start, EndTime: end}
+	entries := []*BookingWaitlistEntry{offered, wantsB, tooLong, anyCourt}
+
+	assert.Same(t, anyCourt, NextInLine(entries, &unitA, start, end))
+	assert.Same(t, wantsB, NextInLine(entries, &unitB, start, end))
+	assert.Nil(t, NextInLine(entries, &unitA, start.Add(30*time.Minute), end))
+	assert.Nil(t, NextInLine(nil, &unitA, start, end))
+}
+
+func TestNewWaitlistClaimLink(t *testing.T) {
+	link := NewWaitlistClaimLink("abc123")
+	assert.Equal(t, "abc123", link.Token)
+	assert.Equal(t, "/bookings/waitlist/claim/abc123", link.Path)
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Calendar entry types, used in entry UIDs and download URLs
+const (
+	CalendarEntryBooking = "booking"
+	CalendarEntryEvent   = "event"
+	CalendarEntryMatch   = "match"
+)
+
+// Calendar entry statuses, as iCalendar STATUS values
+const (
+	CalendarStatusConfirmed = "CONFIRMED"
+	CalendarStatusTentative = "TENTATIVE"
+	CalendarStatusCancelled = "CANCELLED"
+)
+
+// CalendarFeedHistory is how far back a calendar feed reaches, so recent
+// plans stay in subscribers' calendars without the feed growing forever
+const CalendarFeedHistory = 90 * 24 * time.Hour
+
+// CalendarEntry is a booking, event or match as it appears in a player's calendar
+type CalendarEntry struct {
+	Type        string    `json:"type"` // booking, event, match
+	ID          uuid.UUID `json:"id"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Timezone    string    `json:"timezone"` // IANA name of the court's timezone
+	Status      string    `json:"status"`   // CONFIRMED, TENTATIVE, CANCELLED
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CalendarFeed is a player's secret calendar subscription. Anyone with the
+// link can read the calendar, so it can be reset.
+type CalendarFeed struct {
+	Token     string `json:"token"`
+	Path      string `json:"path"`
+	URL       string `json:"url"`        // For Google Calendar and downloads
+	WebcalURL string `json:"webcal_url"` // Opens the subscription in Apple Calendar
+}
+
+// NewCalendarFeed returns the subscription links for a token served from host
+func NewCalendarFeed(token, scheme, host string) CalendarFeed {
+	path := "/api/calendar/feed/" + token + ".ics"
+	return CalendarFeed{
+		Token:     token,
+		Path:      path,
+		URL:       scheme + "://" + host + path,
+		WebcalURL: "webcal://" + host + path,
+	}
+}
+
+// UID returns the entry's iCalendar UID, which stays the same across updates
+// so calendars replace the entry rather than adding a copy
+func (e *CalendarEntry) UID() string {
+	return fmt.Sprintf("%s-%s@tennis-connect", e.Type, e.ID)
+}
+
+// Sequence returns the entry's iCalendar revision number. It is the number of
+// seconds between the entry being created and last changed, so it goes up
+// with every update without us storing a counter.
+func (e *CalendarEntry) Sequence() int {
+	if !e.UpdatedAt.After(e.CreatedAt) {
+		return 0
+	}
+	return int(e.UpdatedAt.Sub(e.CreatedAt) / time.Second)
+}
+
+// CalendarLocation joins the non-empty parts of a place into one line
+func CalendarLocation(parts ...string) string {
+	var kept []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
+
+// BookingCalendarStatus maps a booking to a calendar status for one of its
+// players; participantStatus is empty for the booking owner
+func BookingCalendarStatus(status BookingStatus, participantStatus string) string {
+	switch {
+	case status == BookingStatusCancelled, participantStatus == ParticipantStatusDeclined:
+		return CalendarStatusCancelled
+	case status == BookingStatusPending:
+		// Waiting for approval or payment
+		return CalendarStatusTentative
+	}
+	return CalendarStatusConfirmed
+}
+
+// EventCalendarStatus maps an event and a player's RSVP to a calendar status
+func EventCalendarStatus(eventStatus, rsvpStatus string) string {
+	if eventStatus == EventStatusCancelled || rsvpStatus != "Confirmed" {
+		return CalendarStatusCancelled
+	}
+	return CalendarStatusConfirmed
+}
+
+// MatchCalendarStatus maps a pairing and its match session to a calendar status
+func MatchCalendarStatus(pairingStatus, sessionStatus MatchingStatus) string {
+	switch {
+	case pairingStatus == MatchingStatusCancelled, sessionStatus == MatchingStatusCancelled:
+		return CalendarStatusCancelled
+	case pairingStatus == MatchingStatusPending, pairingStatus == MatchingStatusMatched:
+		// Not every player has confirmed yet
+		return CalendarStatusTentative
+	}
+	return CalendarStatusConfirmed
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarEntry_UIDAndSequence(t *testing.T) {
+	created := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	id := uuid.MustParse("6f1c2a9e-4b1d-4f8e-9a77-1d2c3b4a5e6f")
+	entry := &CalendarEntry{Type: CalendarEntryBooking, ID: id, CreatedAt: created, UpdatedAt: created}
+
+	assert.Equal(t, "booking-6f1c2a9e-4b1d-4f8e-9a77-1d2c3b4a5e6f@tennis-connect", entry.UID())
+	assert.Equal(t, 0, entry.Sequence())
+
+	// Each later update gives a higher sequence; the UID never changes
+	entry.UpdatedAt = created.Add(90 * time.Second)
+	first := entry.Sequence()
+	entry.UpdatedAt = created.Add(48 * time.Hour)
+	assert.Greater(t, entry.Sequence(), first)
+	assert.Equal(t, "booking-6f1c2a9e-4b1d-4f8e-9a77-1d2c3b4a5e6f@tennis-connect", entry.UID())
+
+	entry.UpdatedAt = created.Add(-time.Second)
+	assert.Equal(t, 0, entry.Sequence())
+}
+
+func TestCalendarStatuses(t *testing.T) {
+	assert.Equal(t, CalendarStatusConfirmed, BookingCalendarStatus(BookingStatusConfirmed, ""))
+	assert.Equal(t, CalendarStatusConfirmed, BookingCalendarStatus(BookingStatusCompleted, ParticipantStatusAccepted))
+	assert.Equal(t, CalendarStatusTentative, BookingCalendarStatus(BookingStatusPending, ""))
+	assert.Equal(t, CalendarStatusCancelled, BookingCalendarStatus(BookingStatusCancelled, ""))
+	assert.Equal(t, CalendarStatusCancelled, BookingCalendarStatus(BookingStatusConfirmed, ParticipantStatusDeclined))
+
+	assert.Equal(t, CalendarStatusConfirmed, EventCalendarStatus(EventStatusScheduled, "Confirmed"))
+	assert.Equal(t, CalendarStatusCancelled, EventCalendarStatus(EventStatusCancelled, "Confirmed"))
+	assert.Equal(t, CalendarStatusCancelled, EventCalendarStatus(EventStatusScheduled, "Cancelled"))
+
+	assert.Equal(t, CalendarStatusConfirmed, MatchCalendarStatus(MatchingStatusConfirmed, MatchingStatusMatched))
+	assert.Equal(t, CalendarStatusTentative, MatchCalendarStatus(MatchingStatusMatched, MatchingStatusMatched))
+	assert.Equal(t, CalendarStatusCancelled, MatchCalendarStatus(MatchingStatusCancelled, MatchingStatusMatched))
+	assert.Equal(t, CalendarStatusCancelled, MatchCalendarStatus(MatchingStatusConfirmed, MatchingStatusCancelled))
+}
+
+func TestCalendarLocation(t *testing.T) {
+	assert.Equal(t, "Riverside Courts, Taipei", CalendarLocation("Riverside Courts", " ", "Taipei"))
+	assert.Equal(t, "", CalendarLocation("", ""))
+}
+
+func TestNewCalendarFeed(t *testing.T) {
+	feed := NewCalendarFeed("abc123", "https", "api.example.com")
+	assert.Equal(t, "/api/calendar/feed/abc123.ics", feed.Path)
+	assert.Equal(t, "https://api.example.com/api/calendar/feed/abc123.ics", feed.URL)
+	assert.Equal(t, "webcal://api.example.com/api/calendar/feed/abc123.ics", feed.WebcalURL)
+}
@@ -52,6 +52,19 @@ func (c *Community) HasMember(userID uuid.UUID) bool {
 	return false
 }
 
+// IsAdmin checks if a user created the community or is one of its admins
+func (c *Community) IsAdmin(userID uuid.UUID) bool {
+	if c.CreatedBy == userID {
+		return true
+	}
+	for _, member := range c.Members {
+		if member.UserID == userID && member.Role == "Admin" {
+			return true
+		}
+	}
+	return false
+}
+
 // AddMember adds a new member to the community
 func (c *Community) AddMember(userID uuid.UUID, userName string) {
 	if c.HasMember(userID) {
@@ -1,26 +1,233 @@
 package models
 
 import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Court statuses. Only approved courts are listed; courts submitted by regular
+// users wait in the approval queue as pending.
+const (
+	CourtStatusPending  = "pending"
+	CourtStatusApproved = "approved"
+	CourtStatusRejected = "rejected"
+	CourtStatusArchived = "archived"
+	CourtStatusMerged   = "merged"
+)
+
+// Court edit history actions
+const (
+	CourtEditCreated  = "created"
+	CourtEditUpdated  = "updated"
+	CourtEditApproved = "approved"
+	CourtEditRejected = "rejected"
+	CourtEditArchived = "archived"
+	CourtEditMerged   = "merged"
+)
+
 type Court struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description,omitempty"`
-	Location    Location  `json:"location"`
-	ImageURL    string    `json:"image_url,omitempty"`
-	CourtType   string    `json:"court_type"` // Clay, Hard, Grass, etc.
-	IsPublic    bool      `json:"is_public"`
-	Amenities   []string  `json:"amenities,omitempty"` // Lights, Water, Restrooms, etc.
-	ContactInfo string    `json:"contact_info,omitempty"`
-	Website     string    `json:"website,omitempty"`
-	CheckIns    []CheckIn `json:"check_ins,omitempty"`
-	Popularity  int       `json:"popularity"` // Calculated based on check-ins
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID           `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Location    Location            `json:"location"`
+	ImageURL    string              `json:"image_url,omitempty"`
+	CourtType   string              `json:"court_type"` // Clay, Hard, Grass, etc.
+	IsPublic    bool                `json:"is_public"`
+	Amenities   []string            `json:"amenities,omitempty"` // Lights, Water, Restrooms, etc.
+	ContactInfo string              `json:"contact_info,omitempty"`
+	Website     string              `json:"website,omitempty"`
+	CheckIns    []CheckIn           `json:"check_ins,omitempty"`
+	Popularity  int                 `json:"popularity"` // Calculated based on check-ins
+	Photos      []string            `json:"photos,omitempty"`
+	Status      string              `json:"status"` // pending, approved, rejected, archived, merged
+	ReviewNote  string              `json:"review_note,omitempty"`
+	SubmittedBy *uuid.UUID          `json:"submitted_by,omitempty"`
+	MergedInto  *uuid.UUID          `json:"merged_into,omitempty"` // Court this one was merged into
+	ManagerIDs  []uuid.UUID         `json:"manager_ids,omitempty"`
+	Units       []CourtUnit         `json:"units,omitempty"`         // Individual bookable courts at this facility
+	Occupancy   *UnitOccupancy      `json:"occupancy,omitempty"`     // Populated in search results
+	Conditions  []CourtCondition    `json:"conditions,omitempty"`    // Current player-reported conditions, in court details
+	Rating      *CourtRatingSummary `json:"rating,omitempty"`        // From reviews and post-match ratings
+	IsFavorite  bool                `json:"is_favorite,omitempty"`   // Favorited by the player searching
+	IsHomeCourt bool                `json:"is_home_court,omitempty"` // The searching player's home court
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+
+	// Timezone is the IANA zone local times are shown in, from the override or derived from the location
+	Timezone         string `json:"timezone"`
+	TimezoneOverride string `json:"timezone_override,omitempty"` // Set by a manager when the derived zone is wrong
+}
+
+// CanManage returns true if the user manages the court
+func (c *Court) CanManage(userID uuid.UUID) bool {
+	for _, id := range c.ManagerIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsListed returns true if the court should appear in search results
+func (c *Court) IsListed() bool {
+	return c.Status == CourtStatusApproved
+}
+
+// Validate checks the fields every court needs
+func (c *Court) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if strings.TrimSpace(c.CourtType) == "" {
+		return fmt.Errorf("court type is required")
+	}
+	if c.Location.Latitude < -90 || c.Location.Latitude > 90 || c.Location.Longitude < -180 || c.Location.Longitude > 180 {
+		return fmt.Errorf("location is out of range")
+	}
+	if c.Location.Latitude == 0 && c.Location.Longitude == 0 {
+		return fmt.Errorf("location is required")
+	}
+	if c.TimezoneOverride != "" {
+		if _, err := time.LoadLocation(c.TimezoneOverride); err != nil {
+			return fmt.Errorf("unknown timezone %s", c.TimezoneOverride)
+		}
+	}
+	return nil
+}
+
+// TimeLocation returns the court's timezone, or UTC if it has none
+func (c *Court) TimeLocation() *time.Location {
+	if c.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// CourtUpdateRequest represents a partial update to a court; nil fields are left unchanged
+type CourtUpdateRequest struct {
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	Location    *Location `json:"location"`
+	ImageURL    *string   `json:"image_url"`
+	CourtType   *string   `json:"court_type"`
+	IsPublic    *bool     `json:"is_public"`
+	Amenities   *[]string `json:"amenities"`
+	Photos      *[]string `json:"photos"`
+	ContactInfo *string   `json:"contact_info"`
+	Website     *string   `json:"website"`
+
+	TimezoneOverride *string `json:"timezone_override"` // Empty string goes back to the derived zone
+}
+
+// ApplyUpdate copies the non-nil fields of the request onto the court
+func (c *Court) ApplyUpdate(req CourtUpdateRequest) {
+	if req.Name != nil {
+		c.Name = *req.Name
+	}
+	if req.Description != nil {
+		c.Description = *req.Description
+	}
+	if req.Location != nil {
+		c.Location = *req.Location
+	}
+	if req.ImageURL != nil {
+		c.ImageURL = *req.ImageURL
+	}
+	if req.CourtType != nil {
+		c.CourtType = *req.CourtType
+	}
+	if req.IsPublic != nil {
+		c.IsPublic = *req.IsPublic
+	}
+	if req.Amenities != nil {
+		c.Amenities = *req.Amenities
+	}
+	if req.Photos != nil {
+		c.Photos = *req.Photos
+	}
+	if req.ContactInfo != nil {
+		c.ContactInfo = *req.ContactInfo
+	}
+	if req.Website != nil {
+		c.Website = *req.Website
+	}
+	if req.TimezoneOverride != nil {
+		c.TimezoneOverride = *req.TimezoneOverride
+	}
+}
+
+// CourtFieldChange is the before and after value of one edited field
+type CourtFieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// CourtEdit is an entry in a court's edit history
+type CourtEdit struct {
+	ID        uuid.UUID                   `json:"id"`
+	CourtID   uuid.UUID                   `json:"court_id"`
+	UserID    uuid.UUID                   `json:"user_id"`
+	UserName  string                      `json:"user_name"`
+	Action    string                      `json:"action"` // created, updated, approved, rejected, archived, merged
+	Changes   map[string]CourtFieldChange `json:"changes,omitempty"`
+	Note      string                      `json:"note,omitempty"`
+	CreatedAt time.Time                   `json:"created_at"`
+}
+
+// DiffCourts returns the editable fields that differ between two versions of
+// a court, keyed by their JSON name. Amenities and photos are compared as sets.
+func DiffCourts(before, after *Court) map[string]CourtFieldChange {
+	changes := map[string]CourtFieldChange{}
+	compare := func(field string, from, to interface{}) {
+		if !reflect.DeepEqual(from, to) {
+			changes[field] = CourtFieldChange{From: from, To: to}
+		}
+	}
+	compare("name", before.Name, after.Name)
+	compare("description", before.Description, after.Description)
+	compare("location", before.Location, after.Location)
+	compare("image_url", before.ImageURL, after.ImageURL)
+	compare("court_type", before.CourtType, after.CourtType)
+	compare("is_public", before.IsPublic, after.IsPublic)
+	compare("amenities", sortedStrings(before.Amenities), sortedStrings(after.Amenities))
+	compare("photos", nonNilStrings(before.Photos), nonNilStrings(after.Photos))
+	compare("contact_info", before.ContactInfo, after.ContactInfo)
+	compare("website", before.Website, after.Website)
+	compare("timezone_override", before.TimezoneOverride, after.TimezoneOverride)
+	return changes
+}
+
+func sortedStrings(values []string) []string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// CourtReviewRequest represents a moderator approving or rejecting a submitted court
+type CourtReviewRequest struct {
+	Approve bool   `json:"approve"`
+	Note    string `json:"note"` // Shown to the submitter, e.g. why it was rejected
+}
+
+// CourtMergeRequest represents merging a duplicate court into another
+type CourtMergeRequest struct {
+	TargetID uuid.UUID `json:"target_id" binding:"required"`
 }
 
 // CheckIn represents a user checking in at a court
@@ -31,10 +238,12 @@ type CheckIn struct {
 	UserName   string     `json:"user_name"`
 	CheckedIn  time.Time  `json:"checked_in"`
 	CheckedOut *time.Time `json:"checked_out,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`        // When the player is checked out if they forget to
 	Message    string     `json:"message,omitempty"` // Optional message, e.g., "Looking for a hitting partner!"
 }
 
-// IsActive returns true if the check-in is still active (user hasn't checked out)
+// IsActive returns true if the check-in is still active (user hasn't checked
+// out and it hasn't expired)
 func (c *CheckIn) IsActive() bool {
-	return c.CheckedOut == nil
+	return c.CheckedOut == nil && (c.ExpiresAt.IsZero() || time.Now().Before(c.ExpiresAt))
 }
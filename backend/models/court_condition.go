@@ -0,0 +1,277 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Court conditions players can report
+const (
+	ConditionWet        = "wet"
+	ConditionLightsOut  = "lights_out"
+	ConditionNetDamaged = "net_damaged"
+	ConditionCrowded    = "crowded"
+	ConditionClosed     = "closed"
+)
+
+// How a manager resolved a condition. Fixed reports count towards their
+// reporter's reliability, dismissed ones against it.
+const (
+	ConditionResolutionFixed     = "fixed"
+	ConditionResolutionDismissed = "dismissed"
+)
+
+// Condition report scoring. A fresh report from a new player scores 1, and
+// a condition is shown once its reports add up to ConditionShowThreshold.
+const (
+	ConditionShowThreshold = 0.5
+	ConditionFlagThreshold = 3.0 // Managers are alerted above this
+	MaxReporterWeight      = 2.0 // Court managers and players whose reports keep proving right
+	MaxConditionComment    = 500
+)
+
+// conditionHalfLives is how quickly each kind of report fades: rain dries in
+// hours, a broken net stays broken until someone fixes it
+var conditionHalfLives = map[string]time.Duration{
+	ConditionWet:        2 * time.Hour,
+	ConditionCrowded:    time.Hour,
+	ConditionClosed:     6 * time.Hour,
+	ConditionLightsOut:  2 * 24 * time.Hour,
+	ConditionNetDamaged: 7 * 24 * time.Hour,
+}
+
+var conditionLabels = map[string]string{
+	ConditionWet:        "Wet",
+	ConditionLightsOut:  "Lights out",
+	ConditionNetDamaged: "Net damaged",
+	ConditionCrowded:    "Crowded",
+	ConditionClosed:     "Closed",
+}
+
+// ConditionHalfLife returns how long it takes a report of condition to lose
+// half its weight
+func ConditionHalfLife(condition string) time.Duration {
+	return conditionHalfLives[condition]
+}
+
+// ConditionReportMaxAge is the age past which reports of condition are ignored
+func ConditionReportMaxAge(condition string) time.Duration {
+	return 4 * ConditionHalfLife(condition)
+}
+
+// ConditionReportHistory is how far back any report can still count
+func ConditionReportHistory() time.Duration {
+	var longest time.Duration
+	for condition := range conditionHalfLives {
+		if age := ConditionReportMaxAge(condition); age > longest {
+			longest = age
+		}
+	}
+	return longest
+}
+
+// IsValidCondition returns true for the conditions players can report
+func IsValidCondition(condition string) bool {
+	_, ok := conditionHalfLives[condition]
+	return ok
+}
+
+// CourtConditionReport is one player's report of a court's condition
+type CourtConditionReport struct {
+	ID         uuid.UUID  `json:"id"`
+	CourtID    uuid.UUID  `json:"court_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	UserName   string     `json:"user_name,omitempty"`
+	Condition  string     `json:"condition"`
+	Comment    string     `json:"comment,omitempty"`
+	PhotoURL   string     `json:"photo_url,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	Resolution string     `json:"resolution,omitempty"` // fixed or dismissed
+}
+
+// CourtConditionReportRequest represents a request to report a court's condition
+type CourtConditionReportRequest struct {
+	Condition string `json:"condition" binding:"required"`
+	Comment   string `json:"comment"`
+	PhotoURL  string `json:"photo_url"`
+}
+
+// Validate checks the condition is known and the comment and photo are usable
+func (r *CourtConditionReportRequest) Validate() error {
+	if !IsValidCondition(r.Condition) {
+		return fmt.Errorf("unknown condition %s", r.Condition)
+	}
+	if len(r.Comment) > MaxConditionComment {
+		return fmt.Errorf("comment must be at most %d characters", MaxConditionComment)
+	}
+	if r.PhotoURL != "" {
+		u, err := url.Parse(r.PhotoURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("photo URL must be an http or https link")
+		}
+	}
+	return nil
+}
+
+// CourtConditionResolveRequest represents a manager clearing a condition,
+// either because it has been fixed or because the reports were wrong
+type CourtConditionResolveRequest struct {
+	Dismissed bool `json:"dismissed"`
+}
+
+// CourtCondition is the current state of one condition at a court, from the
+// reports that haven't faded or been resolved
+type CourtCondition struct {
+	Condition      string    `json:"condition"`
+	Label          string    `json:"label"`
+	Score          float64   `json:"score"` // Reports weighted by reliability and age
+	Reports        int       `json:"reports"`
+	LastReportedAt time.Time `json:"last_reported_at"`
+	Comment        string    `json:"comment,omitempty"`   // From the latest report with one
+	PhotoURL       string    `json:"photo_url,omitempty"` // From the latest report with one
+	Flagged        bool      `json:"flagged"`             // Managers have been alerted
+}
+
+// IsCurrent returns true if enough recent reports back the condition to show it
+func (c CourtCondition) IsCurrent() bool {
+	return c.Score >= ConditionShowThreshold
+}
+
+// ShouldFlag returns true once reports pass the threshold for alerting managers
+func (c CourtCondition) ShouldFlag() bool {
+	return c.Score >= ConditionFlagThreshold
+}
+
+// CourtConditionFlag alerts a court's managers that a condition has been
+// reported often enough to need looking at
+type CourtConditionFlag struct {
+	ID         uuid.UUID  `json:"id"`
+	CourtID    uuid.UUID  `json:"court_id"`
+	Condition  string     `json:"condition"`
+	Score      float64    `json:"score"` // When flagged
+	FlaggedAt  time.Time  `json:"flagged_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	ResolvedBy *uuid.UUID `json:"resolved_by,omitempty"`
+	Dismissed  bool       `json:"dismissed"`
+}
+
+// ReporterStats is a player's track record of condition reports
+type ReporterStats struct {
+	Fixed        int  // Reports a manager confirmed by fixing the problem
+	Dismissed    int  // Reports a manager dismissed as wrong
+	IsVerified   bool // Verified account
+	IsCourtAdmin bool // Manages the court being reported on
+}
+
+// ReporterWeight is how much a player's reports count, from 0 to
+// MaxReporterWeight. New players count 1; confirmed reports raise it and
+// dismissed ones lower it.
+func ReporterWeight(stats ReporterStats) float64 {
+	if stats.IsCourtAdmin {
+		return MaxReporterWeight
+	}
+	weight := 2 * float64(stats.Fixed+1) / float64(stats.Fixed+stats.Dismissed+2)
+	if stats.IsVerified {
+		weight *= 1.25
+	}
+	return math.Min(weight, MaxReporterWeight)
+}
+
+// ScoreConditions totals unresolved reports per condition, weighting each by
+// its reporter and halving it every half-life. Only a player's latest report
+// of each condition counts, so reporting again refreshes rather than stacks.
+// Conditions are returned highest score first, including ones too weak to show.
+func ScoreConditions(reports []CourtConditionReport, weights map[uuid.UUID]float64, now time.Time) []CourtCondition {
+	type key struct {
+		userID    uuid.UUID
+		condition string
+	}
+	latest := map[key]CourtConditionReport{}
+	for _, report := range reports {
+		if report.ResolvedAt != nil || !IsValidCondition(report.Condition) {
+			continue
+		}
+		k := key{report.UserID, report.Condition}
+		if existing, ok := latest[k]; !ok || report.CreatedAt.After(existing.CreatedAt) {
+			latest[k] = report
+		}
+	}
+
+	byCondition := map[string]*CourtCondition{}
+	commentAt := map[string]time.Time{}
+	photoAt := map[string]time.Time{}
+	for _, report := range latest {
+		age := now.Sub(report.CreatedAt)
+		if age < 0 {
+			age = 0
+		}
+		if age > ConditionReportMaxAge(report.Condition) {
+			continue
+		}
+		weight, ok := weights[report.UserID]
+		if !ok {
+			weight = ReporterWeight(ReporterStats{})
+		}
+
+		condition, ok := byCondition[report.Condition]
+		if !ok {
+			condition = &CourtCondition{Condition: report.Condition, Label: conditionLabels[report.Condition]}
+			byCondition[report.Condition] = condition
+		}
+		condition.Score += weight * math.Pow(0.5, float64(age)/float64(ConditionHalfLife(report.Condition)))
+		condition.Reports++
+		if report.CreatedAt.After(condition.LastReportedAt) {
+			condition.LastReportedAt = report.CreatedAt
+		}
+		if report.Comment != "" && report.CreatedAt.After(commentAt[report.Condition]) {
+			condition.Comment = report.Comment
+			commentAt[report.Condition] = report.CreatedAt
+		}
+		if report.PhotoURL != "" && report.CreatedAt.After(photoAt[report.Condition]) {
+			condition.PhotoURL = report.PhotoURL
+			photoAt[report.Condition] = report.CreatedAt
+		}
+	}
+
+	conditions := make([]CourtCondition, 0, len(byCondition))
+	for _, condition := range byCondition {
+		condition.Score = math.Round(condition.Score*100) / 100
+		conditions = append(conditions, *condition)
+	}
+	sort.Slice(conditions, func(i, j int) bool {
+		if conditions[i].Score != conditions[j].Score {
+			return conditions[i].Score > conditions[j].Score
+		}
+		return conditions[i].Condition < conditions[j].Condition
+	})
+	return conditions
+}
+
+// CurrentConditions keeps the conditions strong enough to show players
+func CurrentConditions(conditions []CourtCondition) []CourtCondition {
+	current := []CourtCondition{}
+	for _, condition := range conditions {
+		if condition.IsCurrent() {
+			current = append(current, condition)
+		}
+	}
+	return current
+}
+
+// ConditionFlagMessage is the notification sent to managers when a
+// condition is flagged
+func ConditionFlagMessage(courtName string, condition CourtCondition) string {
+	message := fmt.Sprintf("Players have reported %s at %s (%d reports).",
+		strings.ToLower(condition.Label), courtName, condition.Reports)
+	if condition.Comment != "" {
+		message += fmt.Sprintf(" Latest comment: %q", condition.Comment)
+	}
+	return message
+}
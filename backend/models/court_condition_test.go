@@ -0,0 +1,89 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCourtConditionReportRequest_Validate(t *testing.T) {
+	req := CourtConditionReportRequest{Condition: ConditionWet, PhotoURL: "https://example.com/puddle.jpg"}
+	assert.NoError(t, req.Validate())
+
+	req = CourtConditionReportRequest{Condition: "haunted"}
+	assert.EqualError(t, req.Validate(), "unknown condition haunted")
+
+	req = CourtConditionReportRequest{Condition: ConditionWet, PhotoURL: "javascript:alert(1)"}
+	assert.EqualError(t, req.Validate(), "photo URL must be an http or https link")
+
+	req = CourtConditionReportRequest{Condition: ConditionWet, Comment: string(make([]byte, MaxConditionComment+1))}
+	assert.Error(t, req.Validate())
+}
+
+func TestReporterWeight(t *testing.T) {
+	assert.Equal(t, 1.0, ReporterWeight(ReporterStats{}))
+	assert.Equal(t, 1.25, ReporterWeight(ReporterStats{IsVerified: true}))
+	assert.Equal(t, 1.6, ReporterWeight(ReporterStats{Fixed: 3}))
+	assert.Equal(t, 0.5, ReporterWeight(ReporterStats{Dismissed: 2}))
+	assert.Equal(t, MaxReporterWeight, ReporterWeight(ReporterStats{Fixed: 20, IsVerified: true}))
+	assert.Equal(t, MaxReporterWeight, ReporterWeight(ReporterStats{Dismissed: 5, IsCourtAdmin: true}))
+}
+
+func TestScoreConditions(t *testing.T) {
+	now := time.Date(2026, time.June, 6, 12, 0, 0, 0, time.UTC)
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	report := func(userID uuid.UUID, condition string, age time.Duration) CourtConditionReport {
+		return CourtConditionReport{ID: uuid.New(), UserID: userID, Condition: condition, CreatedAt: now.Add(-age)}
+	}
+
+	t.Run("reports fade with age", func(t *testing.T) {
+		conditions := ScoreConditions([]CourtConditionReport{
+			report(alice, ConditionWet, 0),
+			report(bob, ConditionWet, 2*time.Hour), // One half-life
+			report(carol, ConditionWet, 9*time.Hour),
+		}, nil, now)
+		require.Len(t, conditions, 1)
+		assert.Equal(t, 1.5, conditions[0].Score)
+		assert.Equal(t, 2, conditions[0].Reports)
+		assert.Equal(t, "Wet", conditions[0].Label)
+	})
+
+	t.Run("slow conditions outlast quick ones", func(t *testing.T) {
+		conditions := ScoreConditions([]CourtConditionReport{
+			report(alice, ConditionWet, 6*time.Hour),
+			report(alice, ConditionNetDamaged, 6*time.Hour),
+		}, nil, now)
+		require.Len(t, conditions, 2)
+		assert.Equal(t, ConditionNetDamaged, conditions[0].Condition)
+		current := CurrentConditions(conditions)
+		require.Len(t, current, 1)
+		assert.Equal(t, ConditionNetDamaged, current[0].Condition)
+	})
+
+	t.Run("repeat reports refresh instead of stacking", func(t *testing.T) {
+		latest := report(alice, ConditionCrowded, 0)
+		latest.Comment = "Queue for every court"
+		conditions := ScoreConditions([]CourtConditionReport{report(alice, ConditionCrowded, 30*time.Minute), latest}, nil, now)
+		require.Len(t, conditions, 1)
+		assert.Equal(t, 1.0, conditions[0].Score)
+		assert.Equal(t, 1, conditions[0].Reports)
+		assert.Equal(t, "Queue for every court", conditions[0].Comment)
+	})
+
+	t.Run("reliable reporters count more", func(t *testing.T) {
+		reports := []CourtConditionReport{report(alice, ConditionLightsOut, 0), report(bob, ConditionLightsOut, 0)}
+		assert.False(t, ScoreConditions(reports, nil, now)[0].ShouldFlag())
+
+		weights := map[uuid.UUID]float64{alice: MaxReporterWeight, bob: 1.6}
+		assert.True(t, ScoreConditions(reports, weights, now)[0].ShouldFlag())
+	})
+
+	t.Run("resolved reports are ignored", func(t *testing.T) {
+		resolved := report(alice, ConditionClosed, 0)
+		resolved.ResolvedAt = &now
+		assert.Empty(t, ScoreConditions([]CourtConditionReport{resolved}, nil, now))
+	})
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Activity at a favorite court players can subscribe to
+const (
+	CourtActivityBulletins  = "bulletins"  // New "looking to play" bulletins at the court
+	CourtActivityEvents     = "events"     // New events at the court
+	CourtActivityCheckIns   = "check_ins"  // Players checking in
+	CourtActivityConditions = "conditions" // Condition reports, e.g. wet or lights out
+)
+
+// CourtActivities lists every kind of court activity, in display order
+var CourtActivities = []string{CourtActivityBulletins, CourtActivityEvents, CourtActivityCheckIns, CourtActivityConditions}
+
+// MaxFavoriteCourts caps how many courts a player can favorite
+const MaxFavoriteCourts = 50
+
+// FavoriteCourt is a court a player has favorited, with the activity there
+// they are notified of
+type FavoriteCourt struct {
+	UserID        uuid.UUID `json:"user_id"`
+	CourtID       uuid.UUID `json:"court_id"`
+	Subscriptions []string  `json:"subscriptions"`
+	IsHomeCourt   bool      `json:"is_home_court"`
+	Court         *Court    `json:"court,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// IsSubscribed reports whether the player is notified of the activity
+func (f *FavoriteCourt) IsSubscribed(activity string) bool {
+	for _, subscription := range f.Subscriptions {
+		if subscription == activity {
+			return true
+		}
+	}
+	return false
+}
+
+// FavoriteCourtRequest favorites a court or changes what a player is
+// notified of there. Subscriptions may be "all".
+type FavoriteCourtRequest struct {
+	Subscriptions []string `json:"subscriptions"`
+}
+
+// NormalizeCourtActivities validates a list of court activities, returning
+// them without duplicates in display order. "all" subscribes to everything.
+func NormalizeCourtActivities(activities []string) ([]string, error) {
+	requested := map[string]bool{}
+	for _, activity := range activities {
+		activity = strings.ToLower(strings.TrimSpace(activity))
+		if activity == "all" {
+			return append([]string{}, CourtActivities...), nil
+		}
+		if !isCourtActivity(activity) {
+			return nil, fmt.Errorf("unknown court activity %s; use %s or all", activity, strings.Join(CourtActivities, ", "))
+		}
+		requested[activity] = true
+	}
+
+	normalized := []string{}
+	for _, activity := range CourtActivities {
+		if requested[activity] {
+			normalized = append(normalized, activity)
+		}
+	}
+	return normalized, nil
+}
+
+func isCourtActivity(activity string) bool {
+	for _, known := range CourtActivities {
+		if activity == known {
+			return true
+		}
+	}
+	return false
+}
+
+// HomeCourt is the court a player plays at most, shown on their profile
+type HomeCourt struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	City string    `json:"city,omitempty"`
+}
+
+// CourtActivityNotification returns the notification type and title telling
+// a subscriber about activity at one of their favorite courts
+func CourtActivityNotification(activity, courtName string) (string, string) {
+	switch activity {
+	case CourtActivityBulletins:
+		return NotificationTypeFavoriteCourtBulletin, "New bulletin at " + courtName
+	case CourtActivityEvents:
+		return NotificationTypeFavoriteCourtEvent, "New event at " + courtName
+	case CourtActivityCheckIns:
+		return NotificationTypeFavoriteCourtCheckIn, "Someone checked in at " + courtName
+	default:
+		return NotificationTypeFavoriteCourtCondition, "Condition reported at " + courtName
+	}
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCourtActivities(t *testing.T) {
+	activities, err := NormalizeCourtActivities(nil)
+	require.NoError(t, err)
+	assert.Empty(t, activities)
+	assert.NotNil(t, activities)
+
+	activities, err = NormalizeCourtActivities([]string{" Conditions", "bulletins", "conditions"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{CourtActivityBulletins, CourtActivityConditions}, activities)
+
+	activities, err = NormalizeCourtActivities([]string{"events", "all"})
+	require.NoError(t, err)
+	assert.Equal(t, CourtActivities, activities)
+	activities[0] = "changed"
+	assert.Equal(t, CourtActivityBulletins, CourtActivities[0], "all returns a copy")
+
+	_, err = NormalizeCourtActivities([]string{"reviews"})
+	assert.EqualError(t, err, "unknown court activity reviews; use bulletins, events, check_ins, conditions or all")
+}
+
+func TestFavoriteCourtIsSubscribed(t *testing.T) {
+	favorite := &FavoriteCourt{Subscriptions: []string{CourtActivityEvents}}
+	assert.True(t, favorite.IsSubscribed(CourtActivityEvents))
+	assert.False(t, favorite.IsSubscribed(CourtActivityCheckIns))
+}
+
+func TestCourtActivityNotification(t *testing.T) {
+	notificationType, title := CourtActivityNotification(CourtActivityCheckIns, "Dolores Park")
+	assert.Equal(t, NotificationTypeFavoriteCourtCheckIn, notificationType)
+	assert.Equal(t, "Someone checked in at Dolores Park", title)
+
+	notificationType, title = CourtActivityNotification(CourtActivityConditions, "Dolores Park")
+	assert.Equal(t, NotificationTypeFavoriteCourtCondition, notificationType)
+	assert.Equal(t, "Condition reported at Dolores Park", title)
+}
+
+func TestLocalityScore(t *testing.T) {
+	courtID, otherCourtID := uuid.New(), uuid.New()
+	local := &User{ID: uuid.New(), SkillLevel: 4.0, HomeCourtID: &courtID}
+	regular := &User{ID: uuid.New(), SkillLevel: 4.0}
+	visitor := &User{ID: uuid.New(), SkillLevel: 4.0, HomeCourtID: &otherCourtID}
+
+	criteria := DefaultMatchingCriteria()
+	assert.Equal(t, NeutralLocalityScore, LocalityScore(local, criteria), "no court, no locality")
+
+	criteria.CourtID = courtID
+	criteria.CourtFavoriters = map[uuid.UUID]bool{local.ID: true, regular.ID: true}
+	assert.Equal(t, HomeCourtLocalityScore, LocalityScore(local, criteria))
+	assert.Equal(t, FavoriteCourtLocalityScore, LocalityScore(regular, criteria))
+	assert.Equal(t, NeutralLocalityScore, LocalityScore(visitor, criteria))
+
+	pairing := &PlayerPairing{}
+	neighbours := pairing.CalculateCompatibilityScore(local, regular, criteria)
+	strangers := pairing.CalculateCompatibilityScore(visitor, &User{ID: uuid.New(), SkillLevel: 4.0}, criteria)
+	assert.Greater(t, neighbours, strangers)
+
+	// Players sharing a home court are local to each other wherever they play
+	criteria.CourtID = uuid.Nil
+	partner := &User{ID: uuid.New(), SkillLevel: 4.0, HomeCourtID: &otherCourtID}
+	assert.Greater(t, pairing.CalculateCompatibilityScore(visitor, partner, criteria),
+		pairing.CalculateCompatibilityScore(visitor, regular, criteria))
+}
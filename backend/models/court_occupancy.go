@@ -0,0 +1,176 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Check-in durations. Players say how long they expect to stay and are
+// checked out automatically once that time is up.
+const (
+	DefaultCheckInDuration = 2 * time.Hour
+	MinCheckInDuration     = 15 * time.Minute
+	MaxCheckInDuration     = 8 * time.Hour
+)
+
+// CourtBusynessHistory is how far back check-ins are used for typical
+// busyness and popularity
+const CourtBusynessHistory = 8 * 7 * 24 * time.Hour
+
+// PlayersPerCourt is how many checked-in players are assumed to share a court
+// when estimating how many are in use
+const PlayersPerCourt = 2
+
+// Busyness levels, relative to a court's busiest hour of the week
+const (
+	BusynessQuiet    = "quiet"
+	BusynessModerate = "moderate"
+	BusynessBusy     = "busy"
+)
+
+// CheckInDuration returns how long a check-in lasts, given the minutes the
+// player asked for. Zero means the default.
+func CheckInDuration(minutes int) (time.Duration, error) {
+	if minutes == 0 {
+		return DefaultCheckInDuration, nil
+	}
+	d := time.Duration(minutes) * time.Minute
+	if d < MinCheckInDuration || d > MaxCheckInDuration {
+		return 0, fmt.Errorf("duration must be between %d and %d minutes", int(MinCheckInDuration.Minutes()), int(MaxCheckInDuration.Minutes()))
+	}
+	return d, nil
+}
+
+// HourlyBusyness is how many players are typically at a court during one
+// hour of the week, in the court's local time
+type HourlyBusyness struct {
+	Weekday        time.Weekday `json:"weekday"` // 0 is Sunday
+	Hour           int          `json:"hour"`
+	AveragePlayers float64      `json:"average_players"`
+	Level          string       `json:"level"` // quiet, moderate or busy
+}
+
+// CourtOccupancy is the live view of who is at a court and how many of its
+// courts are taken, alongside how busy it usually is
+type CourtOccupancy struct {
+	CourtID        uuid.UUID        `json:"court_id"`
+	At             time.Time        `json:"at"`
+	Timezone       string           `json:"timezone"`
+	PlayersPresent int              `json:"players_present"`
+	Players        []CheckIn        `json:"players"`
+	CourtsInUse    int              `json:"courts_in_use"`
+	Courts         UnitOccupancy    `json:"courts"`
+	Typical        *HourlyBusyness  `json:"typical,omitempty"` // Usual busyness for the current hour
+	Busyness       []HourlyBusyness `json:"busyness"`          // All 168 hours of the week, starting Sunday midnight
+}
+
+// EstimateCourtsInUse works out how many of total courts are taken from the
+// number booked and the number of players checked in, since players on a
+// walk-up court don't book
+func EstimateCourtsInUse(total, booked, players int) int {
+	inUse := (players + PlayersPerCourt - 1) / PlayersPerCourt
+	if booked > inUse {
+		inUse = booked
+	}
+	if inUse > total {
+		inUse = total
+	}
+	return inUse
+}
+
+// NewCourtOccupancy builds the live occupancy view of a court
+func NewCourtOccupancy(courtID uuid.UUID, at time.Time, loc *time.Location, players []CheckIn, totalUnits, bookedUnits int, busyness []HourlyBusyness) *CourtOccupancy {
+	if players == nil {
+		players = []CheckIn{}
+	}
+	inUse := EstimateCourtsInUse(totalUnits, bookedUnits, len(players))
+	occupancy := &CourtOccupancy{
+		CourtID:        courtID,
+		At:             at,
+		Timezone:       loc.String(),
+		PlayersPresent: len(players),
+		Players:        players,
+		CourtsInUse:    inUse,
+		Courts:         NewUnitOccupancy(at.In(loc), totalUnits-inUse, totalUnits),
+		Busyness:       busyness,
+	}
+	local := at.In(loc)
+	for i := range busyness {
+		if busyness[i].Weekday == local.Weekday() && busyness[i].Hour == local.Hour() {
+			occupancy.Typical = &busyness[i]
+			break
+		}
+	}
+	return occupancy
+}
+
+// BuildBusyness spreads past check-ins over the hours of the week in loc and
+// averages them over the weeks in window. Each check-in counts for the part
+// of each hour it covered, so a half-hour visit adds half a player.
+func BuildBusyness(visits []TimeRange, window TimeRange, loc *time.Location) []HourlyBusyness {
+	var hours [7 * 24]float64
+	for _, visit := range visits {
+		start, end := visit.Start, visit.End
+		if start.Before(window.Start) {
+			start = window.Start
+		}
+		if end.After(window.End) {
+			end = window.End
+		}
+		for start.Before(end) {
+			local := start.In(loc)
+			next := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, loc).Add(time.Hour)
+			if !next.After(start) {
+				// Repeated hour at the end of daylight saving
+				next = start.Add(time.Hour)
+			}
+			if next.After(end) {
+				next = end
+			}
+			hours[int(local.Weekday())*24+local.Hour()] += next.Sub(start).Hours()
+			start = next
+		}
+	}
+
+	weeks := window.End.Sub(window.Start).Hours() / (7 * 24)
+	peak := 0.0
+	busyness := make([]HourlyBusyness, len(hours))
+	for i, total := range hours {
+		average := 0.0
+		if weeks > 0 {
+			average = math.Round(total/weeks*100) / 100
+		}
+		busyness[i] = HourlyBusyness{Weekday: time.Weekday(i / 24), Hour: i % 24, AveragePlayers: average}
+		if average > peak {
+			peak = average
+		}
+	}
+	for i := range busyness {
+		busyness[i].Level = busynessLevel(busyness[i].AveragePlayers, peak)
+	}
+	return busyness
+}
+
+func busynessLevel(average, peak float64) string {
+	switch {
+	case peak == 0 || average < peak/3:
+		return BusynessQuiet
+	case average < peak*2/3:
+		return BusynessModerate
+	default:
+		return BusynessBusy
+	}
+}
+
+// PopularityFromPlayerHours turns the player-hours spent at a court over a
+// window into its popularity: the average player-hours per week
+func PopularityFromPlayerHours(hours float64, window time.Duration) int {
+	weeks := window.Hours() / (7 * 24)
+	if weeks <= 0 || hours <= 0 {
+		return 0
+	}
+	return int(math.Round(hours / weeks))
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckInDuration(t *testing.T) {
+	d, err := CheckInDuration(0)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCheckInDuration, d)
+
+	d, err = CheckInDuration(90)
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Minute, d)
+
+	_, err = CheckInDuration(5)
+	assert.EqualError(t, err, "duration must be between 15 and 480 minutes")
+	_, err = CheckInDuration(600)
+	assert.Error(t, err)
+}
+
+func TestCheckIn_IsActive(t *testing.T) {
+	checkIn := CheckIn{CheckedIn: time.Now().Add(-time.Hour), ExpiresAt: time.Now().Add(time.Hour)}
+	assert.True(t, checkIn.IsActive())
+
+	checkIn.ExpiresAt = time.Now().Add(-time.Minute)
+	assert.False(t, checkIn.IsActive())
+
+	now := time.Now()
+	checkIn = CheckIn{CheckedOut: &now}
+	assert.False(t, checkIn.IsActive())
+}
+
+func TestEstimateCourtsInUse(t *testing.T) {
+	assert.Equal(t, 0, EstimateCourtsInUse(4, 0, 0))
+	assert.Equal(t, 2, EstimateCourtsInUse(4, 0, 3))  // Three players need two courts
+	assert.Equal(t, 3, EstimateCourtsInUse(4, 3, 2))  // Booked courts count even if nobody checked in
+	assert.Equal(t, 4, EstimateCourtsInUse(4, 1, 12)) // Never more than the facility has
+}
+
+func TestBuildBusyness(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	window := TimeRange{Start: time.Date(2026, time.May, 3, 0, 0, 0, 0, ny)}
+	window.End = window.Start.AddDate(0, 0, 14)
+
+	// Two Saturdays at 9:30-11:00am, plus one outside the window
+	visits := []TimeRange{
+		{Start: time.Date(2026, time.May, 9, 9, 30, 0, 0, ny), End: time.Date(2026, time.May, 9, 11, 0, 0, 0, ny)},
+		{Start: time.Date(2026, time.May, 16, 9, 30, 0, 0, ny), End: time.Date(2026, time.May, 16, 11, 0, 0, 0, ny)},
+		{Start: time.Date(2026, time.April, 4, 18, 0, 0, 0, ny), End: time.Date(2026, time.April, 4, 19, 0, 0, 0, ny)},
+	}
+	busyness := BuildBusyness(visits, window, ny)
+	require.Len(t, busyness, 168)
+
+	at := func(day time.Weekday, hour int) HourlyBusyness { return busyness[int(day)*24+hour] }
+	assert.Equal(t, 0.5, at(time.Saturday, 9).AveragePlayers)
+	assert.Equal(t, BusynessModerate, at(time.Saturday, 9).Level)
+	assert.Equal(t, 1.0, at(time.Saturday, 10).AveragePlayers)
+	assert.Equal(t, BusynessBusy, at(time.Saturday, 10).Level)
+	assert.Equal(t, 0.0, at(time.Saturday, 18).AveragePlayers)
+	assert.Equal(t, BusynessQuiet, at(time.Saturday, 18).Level)
+
+	// Two weeks, 3 player-hours in total
+	assert.Equal(t, 2, PopularityFromPlayerHours(3, window.End.Sub(window.Start)))
+	assert.Equal(t, 0, PopularityFromPlayerHours(3, 0))
+}
+
+func TestNewCourtOccupancy(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	at := time.Date(2026, time.May, 16, 10, 15, 0, 0, ny)
+	window := TimeRange{Start: at.AddDate(0, 0, -7), End: at}
+	busyness := BuildBusyness([]TimeRange{{Start: at.AddDate(0, 0, -7), End: at.AddDate(0, 0, -7).Add(30 * time.Minute)}}, window, ny)
+
+	players := []CheckIn{{UserID: uuid.New()}, {UserID: uuid.New()}, {UserID: uuid.New()}}
+	occupancy := NewCourtOccupancy(uuid.New(), at.UTC(), ny, players, 4, 1, busyness)
+
+	assert.Equal(t, 3, occupancy.PlayersPresent)
+	assert.Equal(t, 2, occupancy.CourtsInUse)
+	assert.Equal(t, "2 of 4 courts free at 10:15am", occupancy.Courts.Summary)
+	require.NotNil(t, occupancy.Typical)
+	assert.Equal(t, time.Saturday, occupancy.Typical.Weekday)
+	assert.Equal(t, 10, occupancy.Typical.Hour)
+	assert.Equal(t, 0.5, occupancy.Typical.AveragePlayers)
+}
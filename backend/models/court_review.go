@@ -0,0 +1,177 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Court review statuses. Reviews with a written comment wait for a moderator
+// like submitted courts do; star ratings on their own are published straight away.
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+)
+
+// Court sort orders for search results
+const (
+	CourtSortDistance = "distance"
+	CourtSortRating   = "rating"
+)
+
+// Rating limits and the prior used to rank courts with few ratings. A court
+// is treated as having RatingPriorWeight extra ratings of RatingPrior, so one
+// five-star rating doesn't put it above a court with fifty four-star ones.
+const (
+	MinRating         = 1
+	MaxRating         = 5
+	RatingPrior       = 3.0
+	RatingPriorWeight = 5
+	MaxReviewComment  = 2000
+)
+
+// CourtReview is a player's standalone review of a court. Each player has at
+// most one review per court, which they can edit.
+type CourtReview struct {
+	ID             uuid.UUID `json:"id"`
+	CourtID        uuid.UUID `json:"court_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	UserName       string    `json:"user_name,omitempty"`
+	Overall        int       `json:"overall"`                   // 1-5
+	SurfaceQuality *int      `json:"surface_quality,omitempty"` // 1-5, optional
+	Lighting       *int      `json:"lighting,omitempty"`        // 1-5, optional
+	Cleanliness    *int      `json:"cleanliness,omitempty"`     // 1-5, optional
+	Comment        string    `json:"comment,omitempty"`
+	Status         string    `json:"status"` // pending, approved or rejected
+	ReviewNote     string    `json:"review_note,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// SubmitCourtReviewRequest represents a player adding or replacing their review of a court
+type SubmitCourtReviewRequest struct {
+	Overall        int    `json:"overall" binding:"required"`
+	SurfaceQuality *int   `json:"surface_quality"`
+	Lighting       *int   `json:"lighting"`
+	Cleanliness    *int   `json:"cleanliness"`
+	Comment        string `json:"comment"`
+}
+
+// Validate checks every rating given is between 1 and 5 and the comment isn't too long
+func (r *SubmitCourtReviewRequest) Validate() error {
+	ratings := []struct {
+		name  string
+		value *int
+	}{
+		{"overall", &r.Overall},
+		{"surface quality", r.SurfaceQuality},
+		{"lighting", r.Lighting},
+		{"cleanliness", r.Cleanliness},
+	}
+	for _, rating := range ratings {
+		if rating.value != nil && (*rating.value < MinRating || *rating.value > MaxRating) {
+			return fmt.Errorf("%s rating must be between %d and %d", rating.name, MinRating, MaxRating)
+		}
+	}
+	if len(r.Comment) > MaxReviewComment {
+		return fmt.Errorf("comment must be at most %d characters", MaxReviewComment)
+	}
+	return nil
+}
+
+// ModerateCourtReviewRequest represents a moderator publishing or rejecting a review
+type ModerateCourtReviewRequest struct {
+	Approve bool   `json:"approve"`
+	Note    string `json:"note"` // Shown to the author, e.g. why it was rejected
+}
+
+// InitialReviewStatus returns the status a newly submitted or edited review
+// starts in. Moderators' reviews and reviews without a comment have nothing
+// to check.
+func InitialReviewStatus(comment string, isModerator bool) string {
+	if isModerator || comment == "" {
+		return ReviewStatusApproved
+	}
+	return ReviewStatusPending
+}
+
+// CourtRatingSummary aggregates a court's published reviews and the court
+// ratings players leave after matches
+type CourtRatingSummary struct {
+	Overall        float64 `json:"overall"`
+	RatingCount    int     `json:"rating_count"` // Players who rated the court, by review or after a match
+	ReviewCount    int     `json:"review_count"`
+	SurfaceQuality float64 `json:"surface_quality,omitempty"`
+	Lighting       float64 `json:"lighting,omitempty"`
+	Cleanliness    float64 `json:"cleanliness,omitempty"`
+}
+
+// AggregateCourtRatings combines published reviews with post-match court
+// ratings, keyed by the player who gave them. Every player counts once: a
+// player's review stands for them if they wrote one, otherwise their match
+// ratings are averaged. Only reviews rate surface, lighting and cleanliness.
+func AggregateCourtRatings(reviews []CourtReview, matchRatings map[uuid.UUID][]int) CourtRatingSummary {
+	var summary CourtRatingSummary
+	var overall float64
+	var surface, lighting, cleanliness []int
+	reviewed := map[uuid.UUID]bool{}
+
+	for _, review := range reviews {
+		if review.Status != ReviewStatusApproved || reviewed[review.UserID] {
+			continue
+		}
+		reviewed[review.UserID] = true
+		overall += float64(review.Overall)
+		summary.RatingCount++
+		summary.ReviewCount++
+		if review.SurfaceQuality != nil {
+			surface = append(surface, *review.SurfaceQuality)
+		}
+		if review.Lighting != nil {
+			lighting = append(lighting, *review.Lighting)
+		}
+		if review.Cleanliness != nil {
+			cleanliness = append(cleanliness, *review.Cleanliness)
+		}
+	}
+
+	for userID, ratings := range matchRatings {
+		if reviewed[userID] {
+			continue
+		}
+		if average := averageRating(ratings); average > 0 {
+			overall += average
+			summary.RatingCount++
+		}
+	}
+
+	if summary.RatingCount > 0 {
+		summary.Overall = roundRating(overall / float64(summary.RatingCount))
+	}
+	summary.SurfaceQuality = roundRating(averageRating(surface))
+	summary.Lighting = roundRating(averageRating(lighting))
+	summary.Cleanliness = roundRating(averageRating(cleanliness))
+	return summary
+}
+
+// averageRating averages the ratings between 1 and 5, ignoring anything else
+func averageRating(ratings []int) float64 {
+	var total, count int
+	for _, rating := range ratings {
+		if rating >= MinRating && rating <= MaxRating {
+			total += rating
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return float64(total) / float64(count)
+}
+
+func roundRating(rating float64) float64 {
+	return math.Round(rating*10) / 10
+}
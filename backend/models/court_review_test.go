@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestSubmitCourtReviewRequest_Validate(t *testing.T) {
+	req := SubmitCourtReviewRequest{Overall: 4, Lighting: intPtr(2), Comment: "Lights flicker after 8pm"}
+	assert.NoError(t, req.Validate())
+
+	req = SubmitCourtReviewRequest{Overall: 6}
+	assert.EqualError(t, req.Validate(), "overall rating must be between 1 and 5")
+
+	req = SubmitCourtReviewRequest{Overall: 3, Cleanliness: intPtr(0)}
+	assert.EqualError(t, req.Validate(), "cleanliness rating must be between 1 and 5")
+}
+
+func TestInitialReviewStatus(t *testing.T) {
+	assert.Equal(t, ReviewStatusApproved, InitialReviewStatus("", false))
+	assert.Equal(t, ReviewStatusPending, InitialReviewStatus("Great nets", false))
+	assert.Equal(t, ReviewStatusApproved, InitialReviewStatus("Great nets", true))
+}
+
+func TestAggregateCourtRatings(t *testing.T) {
+	alice, bob, carol, dave := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	reviews := []CourtReview{
+		{UserID: alice, Overall: 5, SurfaceQuality: intPtr(4), Lighting: intPtr(3), Status: ReviewStatusApproved},
+		{UserID: bob, Overall: 3, SurfaceQuality: intPtr(2), Status: ReviewStatusApproved},
+		{UserID: carol, Overall: 1, Comment: "Awful", Status: ReviewStatusPending},
+	}
+	matchRatings := map[uuid.UUID][]int{
+		alice: {1, 1},    // Alice's review stands for her
+		carol: {2, 3},    // Carol's review isn't published, so her match ratings count
+		dave:  {4, 5, 0}, // Out of range ratings are ignored
+	}
+
+	summary := AggregateCourtRatings(reviews, matchRatings)
+	assert.Equal(t, 4, summary.RatingCount)
+	assert.Equal(t, 2, summary.ReviewCount)
+	assert.Equal(t, 3.8, summary.Overall) // (5 + 3 + 2.5 + 4.5) / 4, rounded
+	assert.Equal(t, 3.0, summary.SurfaceQuality)
+	assert.Equal(t, 3.0, summary.Lighting)
+	assert.Equal(t, 0.0, summary.Cleanliness)
+
+	assert.Equal(t, CourtRatingSummary{}, AggregateCourtRatings(nil, nil))
+}
@@ -0,0 +1,270 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Opening hours used for courts that have not set their own
+const (
+	DefaultOpensAt  = "06:00"
+	DefaultClosesAt = "22:00"
+)
+
+// OperatingHours are a court's regular hours for one day of the week
+type OperatingHours struct {
+	DayOfWeek   time.Weekday `json:"day_of_week"`             // 0 = Sunday
+	OpensAt     string       `json:"opens_at"`                // HH:MM
+	ClosesAt    string       `json:"closes_at"`               // HH:MM
+	LightsOffAt string       `json:"lights_off_at,omitempty"` // Play must finish by then even if the venue stays open
+	IsClosed    bool         `json:"is_closed"`
+}
+
+// Validate checks the day and that the times are in order
+func (h OperatingHours) Validate() error {
+	if h.DayOfWeek < time.Sunday || h.DayOfWeek > time.Saturday {
+		return fmt.Errorf("day of week must be between 0 and 6")
+	}
+	if h.IsClosed {
+		return nil
+	}
+	opens, closes, err := parseOpeningTimes(h.OpensAt, h.ClosesAt)
+	if err != nil {
+		return err
+	}
+	if h.LightsOffAt != "" {
+		lightsOff, err := parseClock(h.LightsOffAt)
+		if err != nil {
+			return err
+		}
+		if lightsOff <= opens || lightsOff > closes {
+			return fmt.Errorf("lights off must be between opening and closing")
+		}
+	}
+	return nil
+}
+
+// CourtHoliday overrides a court's weekly hours on one date, either closing
+// it for the day or opening it with special hours
+type CourtHoliday struct {
+	ID        uuid.UUID `json:"id"`
+	CourtID   uuid.UUID `json:"court_id"`
+	Date      string    `json:"date" binding:"required"` // YYYY-MM-DD
+	Name      string    `json:"name" binding:"required"`
+	IsClosed  bool      `json:"is_closed"`
+	OpensAt   string    `json:"opens_at,omitempty"`  // HH:MM, when open with special hours
+	ClosesAt  string    `json:"closes_at,omitempty"` // HH:MM
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks the date and, for special hours, that the times are in order
+func (h CourtHoliday) Validate() error {
+	if _, err := time.Parse("2006-01-02", h.Date); err != nil {
+		return fmt.Errorf("invalid date format, use YYYY-MM-DD")
+	}
+	if h.IsClosed {
+		return nil
+	}
+	_, _, err := parseOpeningTimes(h.OpensAt, h.ClosesAt)
+	return err
+}
+
+// CourtClosure is a one-off maintenance or closure window, such as resurfacing
+// or a tournament. Without a CourtUnitID the whole facility is closed.
+type CourtClosure struct {
+	ID          uuid.UUID  `json:"id"`
+	CourtID     uuid.UUID  `json:"court_id"`
+	CourtUnitID *uuid.UUID `json:"court_unit_id,omitempty"`
+	StartTime   time.Time  `json:"start_time"`
+	EndTime     time.Time  `json:"end_time"`
+	Reason      string     `json:"reason"`
+	CreatedBy   uuid.UUID  `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Covers returns true if the closure takes the unit out of use at any point
+// between start and end. A nil unit only matches facility-wide closures.
+func (cl CourtClosure) Covers(unitID *uuid.UUID, start, end time.Time) bool {
+	if !cl.StartTime.Before(end) || !cl.EndTime.After(start) {
+		return false
+	}
+	return cl.CourtUnitID == nil || (unitID != nil && *cl.CourtUnitID == *unitID)
+}
+
+// CourtClosureRequest represents a request to close a court or one of its units
+type CourtClosureRequest struct {
+	CourtUnitID    *uuid.UUID `json:"court_unit_id"`
+	StartTime      time.Time  `json:"start_time" binding:"required"`
+	EndTime        time.Time  `json:"end_time" binding:"required"`
+	Reason         string     `json:"reason" binding:"required"`
+	CancelBookings bool       `json:"cancel_bookings"` // Cancel affected bookings rather than only warning their owners
+}
+
+// CourtSchedule holds everything that decides when a court can be used
+type CourtSchedule struct {
+	CourtID  uuid.UUID        `json:"court_id"`
+	Timezone string           `json:"timezone"` // Hours and holidays are in this zone
+	Hours    []OperatingHours `json:"hours"`    // Empty means the default hours every day
+	Holidays []CourtHoliday   `json:"holidays"`
+	Closures []CourtClosure   `json:"closures"`
+
+	Location *time.Location `json:"-"` // Loaded from Timezone; nil leaves times in their own zone
+}
+
+// Local returns t in the court's timezone
+func (s *CourtSchedule) Local(t time.Time) time.Time {
+	if s.Location == nil {
+		return t
+	}
+	return t.In(s.Location)
+}
+
+// HoursOn returns when the court opens and closes on the date's day, or false
+// if it is closed all day. Holidays take precedence over the weekly hours.
+// The date should already be in the court's timezone.
+func (s *CourtSchedule) HoursOn(date time.Time) (time.Time, time.Time, bool) {
+	day := date.Format("2006-01-02")
+	for _, holiday := range s.Holidays {
+		if holiday.Date == day {
+			if holiday.IsClosed {
+				return time.Time{}, time.Time{}, false
+			}
+			return clockOn(date, holiday.OpensAt), clockOn(date, holiday.ClosesAt), true
+		}
+	}
+
+	if len(s.Hours) == 0 {
+		return clockOn(date, DefaultOpensAt), clockOn(date, DefaultClosesAt), true
+	}
+	for _, hours := range s.Hours {
+		if hours.DayOfWeek != date.Weekday() {
+			continue
+		}
+		if hours.IsClosed {
+			break
+		}
+		closes := hours.ClosesAt
+		if hours.LightsOffAt != "" {
+			closes = hours.LightsOffAt
+		}
+		return clockOn(date, hours.OpensAt), clockOn(date, closes), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// ClosureFor returns the closure, if any, that stops the unit being used
+// between start and end. A nil unit only checks facility-wide closures.
+func (s *CourtSchedule) ClosureFor(unitID *uuid.UUID, start, end time.Time) *CourtClosure {
+	for i := range s.Closures {
+		if s.Closures[i].Covers(unitID, start, end) {
+			return &s.Closures[i]
+		}
+	}
+	return nil
+}
+
+// CheckOpen returns an error unless the court is open for the whole of
+// start-end. Opening hours are only enforced for spans within a single day,
+// so multi-day events are checked against closures alone.
+func (s *CourtSchedule) CheckOpen(start, end time.Time) error {
+	start, end = s.Local(start), s.Local(end)
+	if sameDay(start, end.Add(-time.Nanosecond)) {
+		opens, closes, ok := s.HoursOn(start)
+		if !ok {
+			return fmt.Errorf("court is closed on %s", start.Format("Mon Jan 2"))
+		}
+		if start.Before(opens) || end.After(closes) {
+			return fmt.Errorf("court is closed at the requested time, it is open %s-%s",
+				opens.Format("15:04"), closes.Format("15:04"))
+		}
+	}
+	if closure := s.ClosureFor(nil, start, end); closure != nil {
+		return fmt.Errorf("court is closed for %s until %s", closure.Reason, s.Local(closure.EndTime).Format("Mon Jan 2 15:04"))
+	}
+	return nil
+}
+
+// ClosedRanges returns when the court is closed between start and end:
+// outside its opening hours on each day, and during facility-wide closures
+func (s *CourtSchedule) ClosedRanges(start, end time.Time) []TimeRange {
+	var closed []TimeRange
+	local := s.Local(start)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	for day.Before(end) {
+		next := day.AddDate(0, 0, 1)
+		if opens, closes, ok := s.HoursOn(day); ok {
+			closed = append(closed, TimeRange{Start: day, End: opens}, TimeRange{Start: closes, End: next})
+		} else {
+			closed = append(closed, TimeRange{Start: day, End: next})
+		}
+		day = next
+	}
+	for _, closure := range s.Closures {
+		if closure.Covers(nil, start, end) {
+			closed = append(closed, TimeRange{Start: closure.StartTime, End: closure.EndTime})
+		}
+	}
+
+	var clipped []TimeRange
+	for _, r := range MergeTimeRanges(closed) {
+		if r.Start.Before(start) {
+			r.Start = start
+		}
+		if r.End.After(end) {
+			r.End = end
+		}
+		if r.End.After(r.Start) {
+			clipped = append(clipped, r)
+		}
+	}
+	return clipped
+}
+
+// OpenUnits returns the units not closed for maintenance between start and end
+func (s *CourtSchedule) OpenUnits(units []CourtUnit, start, end time.Time) []CourtUnit {
+	var open []CourtUnit
+	for _, unit := range units {
+		unitID := unit.ID
+		if s.ClosureFor(&unitID, start, end) == nil {
+			open = append(open, unit)
+		}
+	}
+	return open
+}
+
+// parseClock parses HH:MM into minutes after midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, use HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseOpeningTimes parses an opening and closing time and checks they are in order
+func parseOpeningTimes(opensAt, closesAt string) (int, int, error) {
+	opens, err := parseClock(opensAt)
+	if err != nil {
+		return 0, 0, err
+	}
+	closes, err := parseClock(closesAt)
+	if err != nil {
+		return 0, 0, err
+	}
+	if closes <= opens {
+		return 0, 0, fmt.Errorf("closing time must be after opening time")
+	}
+	return opens, closes, nil
+}
+
+// clockOn returns the HH:MM time on the date's day, in the date's location
+func clockOn(date time.Time, value string) time.Time {
+	minutes, _ := parseClock(value)
+	return time.Date(date.Year(), date.Month(), date.Day(), minutes/60, minutes%60, 0, 0, date.Location())
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperatingHours_Validate(t *testing.T) {
+	assert.NoError(t, OperatingHours{DayOfWeek: time.Monday, OpensAt: "07:00", ClosesAt: "21:00", LightsOffAt: "20:30"}.Validate())
+	assert.NoError(t, OperatingHours{DayOfWeek: time.Sunday, IsClosed: true}.Validate())
+
+	assert.EqualError(t, OperatingHours{DayOfWeek: 7, IsClosed: true}.Validate(), "day of week must be between 0 and 6")
+	assert.EqualError(t, OperatingHours{OpensAt: "21:00", ClosesAt: "07:00"}.Validate(), "closing time must be after opening time")
+	assert.EqualError(t, OperatingHours{OpensAt: "7am", ClosesAt: "21:00"}.Validate(), `invalid time "7am", use HH:MM`)
+	assert.EqualError(t, OperatingHours{OpensAt: "07:00", ClosesAt: "21:00", LightsOffAt: "22:00"}.Validate(),
+		"lights off must be between opening and closing")
+}
+
+func TestCourtSchedule_HoursOn(t *testing.T) {
+	monday := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+
+	t.Run("defaults", func(t *testing.T) {
+		opens, closes, ok := (&CourtSchedule{}).HoursOn(monday)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2025, 6, 2, 6, 0, 0, 0, time.UTC), opens)
+		assert.Equal(t, time.Date(2025, 6, 2, 22, 0, 0, 0, time.UTC), closes)
+	})
+
+	schedule := &CourtSchedule{
+		Hours: []OperatingHours{
+			{DayOfWeek: time.Monday, OpensAt: "08:00", ClosesAt: "22:00", LightsOffAt: "21:00"},
+		},
+		Holidays: []CourtHoliday{{Date: "2025-06-09", Name: "Holiday", IsClosed: true}},
+	}
+
+	t.Run("lights off ends play early", func(t *testing.T) {
+		opens, closes, ok := schedule.HoursOn(monday)
+		assert.True(t, ok)
+		assert.Equal(t, 8, opens.Hour())
+		assert.Equal(t, 21, closes.Hour())
+	})
+
+	t.Run("days without hours are closed", func(t *testing.T) {
+		_, _, ok := schedule.HoursOn(tuesday)
+		assert.False(t, ok)
+	})
+
+	t.Run("holidays override the week", func(t *testing.T) {
+		_, _, ok := schedule.HoursOn(monday.AddDate(0, 0, 7))
+		assert.False(t, ok)
+
+		schedule.Holidays[0] = CourtHoliday{Date: "2025-06-09", Name: "Holiday", OpensAt: "10:00", ClosesAt: "14:00"}
+		opens, closes, ok := schedule.HoursOn(monday.AddDate(0, 0, 7))
+		assert.True(t, ok)
+		assert.Equal(t, 10, opens.Hour())
+		assert.Equal(t, 14, closes.Hour())
+	})
+}
+
+func TestCourtSchedule_CheckOpen(t *testing.T) {
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	unitID := uuid.New()
+	schedule := &CourtSchedule{
+		Closures: []CourtClosure{
+			{CourtUnitID: &unitID, StartTime: day.Add(9 * time.Hour), EndTime: day.Add(12 * time.Hour), Reason: "resurfacing"},
+			{StartTime: day.Add(15 * time.Hour), EndTime: day.Add(17 * time.Hour), Reason: "club tournament"},
+		},
+	}
+
+	assert.NoError(t, schedule.CheckOpen(day.Add(9*time.Hour), day.Add(10*time.Hour)))
+	assert.NoError(t, schedule.CheckOpen(day.Add(21*time.Hour), day.Add(22*time.Hour)))
+	assert.EqualError(t, schedule.CheckOpen(day.Add(5*time.Hour), day.Add(7*time.Hour)),
+		"court is closed at the requested time, it is open 06:00-22:00")
+	assert.EqualError(t, schedule.CheckOpen(day.Add(16*time.Hour), day.Add(18*time.Hour)),
+		"court is closed for club tournament until Mon Jun 2 17:00")
+
+	// Multi-day spans skip opening hours but still respect closures
+	assert.NoError(t, schedule.CheckOpen(day.AddDate(0, 0, 1), day.AddDate(0, 0, 3)))
+
+	// Unit closures leave the rest of the facility open
+	units := newTestUnits(2)
+	units[0].ID = unitID
+	open := schedule.OpenUnits(units, day.Add(10*time.Hour), day.Add(11*time.Hour))
+	assert.Equal(t, []CourtUnit{units[1]}, open)
+}
+
+func TestCourtSchedule_ClosedRanges(t *testing.T) {
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	unitID := uuid.New()
+	schedule := &CourtSchedule{
+		Holidays: []CourtHoliday{{Date: "2025-06-03", IsClosed: true}},
+		Closures: []CourtClosure{
+			{CourtUnitID: &unitID, StartTime: day.Add(9 * time.Hour), EndTime: day.Add(12 * time.Hour), Reason: "resurfacing"},
+			{StartTime: day.Add(15 * time.Hour), EndTime: day.Add(17 * time.Hour), Reason: "club tournament"},
+		},
+	}
+
+	closed := schedule.ClosedRanges(day.Add(8*time.Hour), day.AddDate(0, 0, 2).Add(12*time.Hour))
+	assert.Equal(t, []TimeRange{
+		{Start: day.Add(15 * time.Hour), End: day.Add(17 * time.Hour)},
+		{Start: day.Add(22 * time.Hour), End: day.AddDate(0, 0, 2).Add(6 * time.Hour)},
+	}, closed, "unit closures leave the facility open; the holiday joins two nights")
+
+	assert.Empty(t, schedule.ClosedRanges(day.Add(9*time.Hour), day.Add(12*time.Hour)))
+}
+
+func TestCourtSchedule_CheckOpenInCourtTimezone(t *testing.T) {
+	taipei, _ := time.LoadLocation("Asia/Taipei")
+	schedule := &CourtSchedule{Timezone: "Asia/Taipei", Location: taipei}
+
+	// 23:00 UTC is 07:00 the next morning in Taipei, when the court is open
+	start := time.Date(2025, 6, 2, 23, 0, 0, 0, time.UTC)
+	assert.NoError(t, schedule.CheckOpen(start, start.Add(time.Hour)))
+
+	// 15:00 UTC is 23:00 in Taipei, after closing
+	start = time.Date(2025, 6, 2, 15, 0, 0, 0, time.UTC)
+	assert.EqualError(t, schedule.CheckOpen(start, start.Add(time.Hour)),
+		"court is closed at the requested time, it is open 06:00-22:00")
+}
+
+func TestLocalTimes_Localize(t *testing.T) {
+	taipei, _ := time.LoadLocation("Asia/Taipei")
+	start := time.Date(2025, 6, 3, 18, 0, 0, 0, taipei)
+	end := start.Add(time.Hour)
+
+	var booking Booking
+	booking.Localize(&start, &end, "Asia/Taipei")
+	assert.Equal(t, time.UTC, start.Location())
+	assert.Equal(t, 10, start.Hour())
+	assert.Equal(t, "Asia/Taipei", booking.Timezone)
+	assert.Equal(t, 18, booking.LocalStartTime.Hour())
+	assert.Equal(t, 19, booking.LocalEndTime.Hour())
+
+	var unknown Booking
+	unknown.Localize(&start, &end, "Pacific/Nowhere")
+	assert.Nil(t, unknown.LocalStartTime)
+}
+
+func TestBuildAvailability_Schedule(t *testing.T) {
+	day := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	units := newTestUnits(2)
+	schedule := &CourtSchedule{
+		Hours: []OperatingHours{{DayOfWeek: time.Monday, OpensAt: "08:00", ClosesAt: "12:00"}},
+		Closures: []CourtClosure{
+			{CourtUnitID: &units[0].ID, StartTime: day.Add(8 * time.Hour), EndTime: day.Add(10 * time.Hour), Reason: "nets"},
+		},
+	}
+
+	availability := BuildAvailability(uuid.New(), units, nil, schedule, day, time.Hour)
+	assert.False(t, availability.IsClosed)
+	assert.Len(t, availability.TimeSlots, 4)
+	assert.Equal(t, 1, availability.TimeSlots[0].FreeUnits)
+	assert.Equal(t, "nets", availability.Units[0].TimeSlots[0].ClosedReason)
+	assert.Equal(t, 2, availability.TimeSlots[2].FreeUnits)
+
+	availability = BuildAvailability(uuid.New(), units, nil, schedule, day.AddDate(0, 0, 1), time.Hour)
+	assert.True(t, availability.IsClosed)
+	assert.Empty(t, availability.TimeSlots)
+}
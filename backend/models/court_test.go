@@ -0,0 +1,88 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCourt() *Court {
+	return &Court{
+		ID:        uuid.New(),
+		Name:      "Golden Gate Park Tennis Courts",
+		Location:  Location{Latitude: 37.7694, Longitude: -122.4862, City: "San Francisco", State: "CA"},
+		CourtType: "Hard Court",
+		IsPublic:  true,
+		Amenities: []string{"Lights", "Restrooms"},
+		Status:    CourtStatusApproved,
+	}
+}
+
+func TestCourt_Validate(t *testing.T) {
+	assert.NoError(t, newTestCourt().Validate())
+
+	court := newTestCourt()
+	court.Name = "  "
+	assert.EqualError(t, court.Validate(), "name is required")
+
+	court = newTestCourt()
+	court.CourtType = ""
+	assert.EqualError(t, court.Validate(), "court type is required")
+
+	court = newTestCourt()
+	court.Location.Latitude = 95
+	assert.EqualError(t, court.Validate(), "location is out of range")
+
+	court = newTestCourt()
+	court.Location = Location{}
+	assert.EqualError(t, court.Validate(), "location is required")
+
+	court = newTestCourt()
+	court.TimezoneOverride = "Pacific/Nowhere"
+	assert.EqualError(t, court.Validate(), "unknown timezone Pacific/Nowhere")
+}
+
+func TestDiffCourts(t *testing.T) {
+	before := newTestCourt()
+
+	t.Run("no changes", func(t *testing.T) {
+		after := *before
+		after.Amenities = []string{"Restrooms", "Lights"} // Order does not matter
+		assert.Empty(t, DiffCourts(before, &after))
+	})
+
+	t.Run("changed fields only", func(t *testing.T) {
+		after := *before
+		name := "GGP Tennis Center"
+		photos := []string{"https://example.com/court.jpg"}
+		after.ApplyUpdate(CourtUpdateRequest{Name: &name, Photos: &photos})
+
+		changes := DiffCourts(before, &after)
+		assert.Len(t, changes, 2)
+		assert.Equal(t, CourtFieldChange{From: before.Name, To: name}, changes["name"])
+		assert.Equal(t, CourtFieldChange{From: []string{}, To: photos}, changes["photos"])
+	})
+
+	t.Run("amenities", func(t *testing.T) {
+		after := *before
+		amenities := []string{"Lights"}
+		after.ApplyUpdate(CourtUpdateRequest{Amenities: &amenities})
+
+		changes := DiffCourts(before, &after)
+		assert.Equal(t, CourtFieldChange{From: []string{"Lights", "Restrooms"}, To: []string{"Lights"}}, changes["amenities"])
+	})
+}
+
+func TestCourt_CanManageAndIsListed(t *testing.T) {
+	manager := uuid.New()
+	court := newTestCourt()
+	court.ManagerIDs = []uuid.UUID{manager}
+
+	assert.True(t, court.CanManage(manager))
+	assert.False(t, court.CanManage(uuid.New()))
+	assert.True(t, court.IsListed())
+
+	court.Status = CourtStatusPending
+	assert.False(t, court.IsListed())
+}
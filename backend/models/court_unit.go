@@ -0,0 +1,229 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CourtUnit is one physical, bookable court at a facility. A models.Court is
+// the facility (the venue on the map); its units are "Court 1", "Court 2"...
+type CourtUnit struct {
+	ID        uuid.UUID `json:"id"`
+	CourtID   uuid.UUID `json:"court_id"` // The facility this court belongs to
+	Name      string    `json:"name"`
+	Position  int       `json:"position"`          // Display order within the facility
+	Surface   string    `json:"surface,omitempty"` // Overrides the facility's court type, e.g. one clay court at a hard-court venue
+	HasLights bool      `json:"has_lights"`
+	IsActive  bool      `json:"is_active"` // Inactive units cannot be booked
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CourtUnitRequest represents a request to add or edit a court unit; nil fields are left unchanged
+type CourtUnitRequest struct {
+	Name      *string `json:"name"`
+	Position  *int    `json:"position"`
+	Surface   *string `json:"surface"`
+	HasLights *bool   `json:"has_lights"`
+	IsActive  *bool   `json:"is_active"`
+}
+
+// ApplyUpdate copies the non-nil fields of the request onto the unit
+func (u *CourtUnit) ApplyUpdate(req CourtUnitRequest) {
+	if req.Name != nil {
+		u.Name = *req.Name
+	}
+	if req.Position != nil {
+		u.Position = *req.Position
+	}
+	if req.Surface != nil {
+		u.Surface = *req.Surface
+	}
+	if req.HasLights != nil {
+		u.HasLights = *req.HasLights
+	}
+	if req.IsActive != nil {
+		u.IsActive = *req.IsActive
+	}
+}
+
+// DefaultCourtUnits names n units "Court 1" to "Court n"
+func DefaultCourtUnits(n int) []CourtUnit {
+	units := make([]CourtUnit, n)
+	for i := range units {
+		units[i] = CourtUnit{Name: fmt.Sprintf("Court %d", i+1), Position: i, IsActive: true}
+	}
+	return units
+}
+
+// FreeUnits returns the active units with no booking overlapping start-end,
+// in the order given
+func FreeUnits(units []CourtUnit, bookings []*Booking, start, end time.Time) []CourtUnit {
+	busy := map[uuid.UUID]bool{}
+	for _, booking := range bookings {
+		if booking.CourtUnitID != nil && booking.Overlaps(start, end) {
+			busy[*booking.CourtUnitID] = true
+		}
+	}
+
+	var free []CourtUnit
+	for _, unit := range units {
+		if unit.IsActive && !busy[unit.ID] {
+			free = append(free, unit)
+		}
+	}
+	return free
+}
+
+// BookedOutRanges returns the parts of start-end when no active unit is
+// free. A facility without active units is booked out whenever it has any
+// booking at all.
+func BookedOutRanges(units []CourtUnit, bookings []*Booking, start, end time.Time) []TimeRange {
+	boundaries := []time.Time{start, end}
+	for _, booking := range bookings {
+		for _, t := range []time.Time{booking.StartTime, booking.EndTime} {
+			if t.After(start) && t.Before(end) {
+				boundaries = append(boundaries, t)
+			}
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+
+	hasActive := len(FreeUnits(units, nil, start, end)) > 0
+	var booked []TimeRange
+	for i := 1; i < len(boundaries); i++ {
+		from, to := boundaries[i-1], boundaries[i]
+		if !to.After(from) {
+			continue
+		}
+		bookedOut := hasActive && len(FreeUnits(units, bookings, from, to)) == 0
+		if !hasActive {
+			for _, booking := range bookings {
+				bookedOut = bookedOut || booking.Overlaps(from, to)
+			}
+		}
+		if bookedOut {
+			booked = append(booked, TimeRange{Start: from, End: to})
+		}
+	}
+	return MergeTimeRanges(booked)
+}
+
+// UnitOccupancy summarises how many of a facility's courts are free at a time
+type UnitOccupancy struct {
+	At      time.Time `json:"at"`
+	Free    int       `json:"free"`
+	Total   int       `json:"total"`
+	Summary string    `json:"summary"` // e.g. "4 of 8 courts free at 6:00pm"
+}
+
+// NewUnitOccupancy builds an occupancy summary for search results
+func NewUnitOccupancy(at time.Time, free, total int) UnitOccupancy {
+	noun := "courts"
+	if total == 1 {
+		noun = "court"
+	}
+	return UnitOccupancy{
+		At:      at,
+		Free:    free,
+		Total:   total,
+		Summary: fmt.Sprintf("%d of %d %s free at %s", free, total, noun, at.Format("3:04pm")),
+	}
+}
+
+// UnitAvailability is the availability of a single court unit for a day
+type UnitAvailability struct {
+	UnitID    uuid.UUID              `json:"unit_id"`
+	Name      string                 `json:"name"`
+	TimeSlots []TimeSlotAvailability `json:"time_slots"`
+}
+
+// BuildAvailability splits the date's opening hours into slots of the given
+// length and works out which units are free in each. A facility slot is
+// available while at least one unit is free.
+func BuildAvailability(courtID uuid.UUID, units []CourtUnit, bookings []*Booking, schedule *CourtSchedule, date time.Time, slot time.Duration) *CourtAvailability {
+	availability := &CourtAvailability{
+		CourtID:   courtID,
+		Date:      date.Format("2006-01-02"),
+		TimeSlots: []TimeSlotAvailability{},
+		Units:     []UnitAvailability{},
+	}
+
+	active := 0
+	unitIndex := map[uuid.UUID]int{}
+	for _, unit := range units {
+		if !unit.IsActive {
+			continue
+		}
+		unitIndex[unit.ID] = len(availability.Units)
+		availability.Units = append(availability.Units, UnitAvailability{UnitID: unit.ID, Name: unit.Name, TimeSlots: []TimeSlotAvailability{}})
+		active++
+	}
+
+	start, end, ok := schedule.HoursOn(date)
+	if !ok {
+		availability.IsClosed = true
+		return availability
+	}
+	availability.OpensAt = &start
+	availability.ClosesAt = &end
+
+	for current := start; current.Before(end); current = current.Add(slot) {
+		slotEnd := current.Add(slot)
+		if slotEnd.After(end) {
+			break
+		}
+		free := FreeUnits(schedule.OpenUnits(units, current, slotEnd), bookings, current, slotEnd)
+		freeIDs := map[uuid.UUID]bool{}
+		for _, unit := range free {
+			freeIDs[unit.ID] = true
+		}
+
+		// Per-unit slots, noting which booking or closure holds each busy unit
+		var lastConflict *uuid.UUID
+		for _, unit := range units {
+			i, ok := unitIndex[unit.ID]
+			if !ok {
+				continue
+			}
+			unitSlot := TimeSlotAvailability{StartTime: current, EndTime: slotEnd, IsAvailable: freeIDs[unit.ID], TotalUnits: 1}
+			unitID := unit.ID
+			if unitSlot.IsAvailable {
+				unitSlot.FreeUnits = 1
+			} else if closure := schedule.ClosureFor(&unitID, current, slotEnd); closure != nil {
+				unitSlot.ClosedReason = closure.Reason
+			} else {
+				for _, booking := range bookings {
+					if booking.CourtUnitID != nil && *booking.CourtUnitID == unit.ID && booking.Overlaps(current, slotEnd) {
+						id := booking.ID
+						unitSlot.BookingID = &id
+						lastConflict = &id
+						break
+					}
+				}
+			}
+			availability.Units[i].TimeSlots = append(availability.Units[i].TimeSlots, unitSlot)
+		}
+
+		facilitySlot := TimeSlotAvailability{
+			StartTime:   current,
+			EndTime:     slotEnd,
+			IsAvailable: len(free) > 0,
+			FreeUnits:   len(free),
+			TotalUnits:  active,
+		}
+		if !facilitySlot.IsAvailable {
+			if closure := schedule.ClosureFor(nil, current, slotEnd); closure != nil {
+				facilitySlot.ClosedReason = closure.Reason
+			} else {
+				facilitySlot.BookingID = lastConflict
+			}
+		}
+		availability.TimeSlots = append(availability.TimeSlots, facilitySlot)
+	}
+
+	return availability
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestUnits(n int) []CourtUnit {
+	units := DefaultCourtUnits(n)
+	for i := range units {
+		units[i].ID = uuid.New()
+	}
+	return units
+}
+
+func newTestUnitBooking(unit CourtUnit, start time.Time, d time.Duration) *Booking {
+	return &Booking{ID: uuid.New(), CourtUnitID: &unit.ID, StartTime: start, EndTime: start.Add(d), Status: BookingStatusConfirmed}
+}
+
+func TestDefaultCourtUnits(t *testing.T) {
+	units := DefaultCourtUnits(3)
+	assert.Len(t, units, 3)
+	assert.Equal(t, "Court 1", units[0].Name)
+	assert.Equal(t, "Court 3", units[2].Name)
+	assert.Equal(t, 2, units[2].Position)
+	assert.True(t, units[1].IsActive)
+}
+
+func TestFreeUnits(t *testing.T) {
+	start := time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC)
+	units := newTestUnits(3)
+	units[2].IsActive = false
+	bookings := []*Booking{newTestUnitBooking(units[0], start, time.Hour)}
+
+	free := FreeUnits(units, bookings, start, start.Add(time.Hour))
+	assert.Equal(t, []CourtUnit{units[1]}, free)
+
+	// Back-to-back bookings do not overlap
+	free = FreeUnits(units, bookings, start.Add(time.Hour), start.Add(2*time.Hour))
+	assert.Equal(t, []CourtUnit{units[0], units[1]}, free)
+}
+
+func TestBookedOutRanges(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	end := start.Add(8 * time.Hour)
+	units := newTestUnits(2)
+	bookings := []*Booking{
+		newTestUnitBooking(units[0], start.Add(time.Hour), 3*time.Hour),
+		newTestUnitBooking(units[1], start.Add(2*time.Hour), 3*time.Hour),
+	}
+
+	// Only when both courts are taken
+	assert.Equal(t, []TimeRange{{Start: start.Add(2 * time.Hour), End: start.Add(4 * time.Hour)}},
+		BookedOutRanges(units, bookings, start, end))
+	assert.Empty(t, BookedOutRanges(units, bookings[:1], start, end), "one booked court leaves the other free")
+
+	// Without units any booking takes the facility
+	assert.Equal(t, []TimeRange{{Start: start.Add(time.Hour), End: start.Add(4 * time.Hour)}},
+		BookedOutRanges(nil, bookings[:1], start, end))
+}
+
+func TestBuildAvailability(t *testing.T) {
+	start := time.Date(2025, 6, 1, 6, 0, 0, 0, time.UTC)
+	courtID := uuid.New()
+	units := newTestUnits(2)
+	first := newTestUnitBooking(units[0], start.Add(2*time.Hour), 2*time.Hour)
+	second := newTestUnitBooking(units[1], start.Add(3*time.Hour), time.Hour)
+
+	availability := BuildAvailability(courtID, units, []*Booking{first, second}, &CourtSchedule{}, start, time.Hour)
+	assert.Equal(t, "2025-06-01", availability.Date)
+	assert.Len(t, availability.TimeSlots, 16)
+	assert.Len(t, availability.Units, 2)
+
+	// 8am: one of two courts free
+	slot := availability.TimeSlots[2]
+	assert.True(t, slot.IsAvailable)
+	assert.Equal(t, 1, slot.FreeUnits)
+	assert.Equal(t, 2, slot.TotalUnits)
+	assert.Nil(t, slot.BookingID)
+	assert.Equal(t, &first.ID, availability.Units[0].TimeSlots[2].BookingID)
+
+	// 9am: fully booked
+	slot = availability.TimeSlots[3]
+	assert.False(t, slot.IsAvailable)
+	assert.Equal(t, 0, slot.FreeUnits)
+	assert.NotNil(t, slot.BookingID)
+	assert.Equal(t, &second.ID, availability.Units[1].TimeSlots[3].BookingID)
+
+	// Inactive courts are left out entirely
+	units[1].IsActive = false
+	availability = BuildAvailability(courtID, units, nil, &CourtSchedule{}, start, time.Hour)
+	assert.Len(t, availability.Units, 1)
+	assert.Equal(t, 1, availability.TimeSlots[0].TotalUnits)
+}
+
+func TestNewUnitOccupancy(t *testing.T) {
+	at := time.Date(2025, 6, 1, 18, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "4 of 8 courts free at 6:00pm", NewUnitOccupancy(at, 4, 8).Summary)
+	assert.Equal(t, "0 of 1 court free at 6:00pm", NewUnitOccupancy(at, 0, 1).Summary)
+}
@@ -6,26 +6,51 @@ import (
 	"github.com/google/uuid"
 )
 
+// Event statuses
+const (
+	EventStatusScheduled = "Scheduled"
+	EventStatusCancelled = "Cancelled"
+)
+
 type Event struct {
-	ID                 uuid.UUID `json:"id"`
-	Title              string    `json:"title"`
-	Description        string    `json:"description"`
-	CourtID            uuid.UUID `json:"court_id"`
-	CourtName          string    `json:"court_name"`
-	Location           Location  `json:"location"`
-	StartTime          time.Time `json:"start_time"`
-	EndTime            time.Time `json:"end_time"`
-	HostID             uuid.UUID `json:"host_id"`
-	HostName           string    `json:"host_name"`
-	MaxPlayers         int       `json:"max_players"`
-	SkillLevel         string    `json:"skill_level,omitempty"` // Beginner, Intermediate, Advanced, or NTRP range
-	EventType          string    `json:"event_type"`            // Open Rally, Tournament, Clinic, etc.
-	IsRecurring        bool      `json:"is_recurring"`
-	RSVPs              []RSVP    `json:"rsvps,omitempty"`
-	Waitlist           []RSVP    `json:"waitlist,omitempty"`
-	IsNewcomerFriendly bool      `json:"is_newcomer_friendly"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ID                 uuid.UUID   `json:"id"`
+	Title              string      `json:"title"`
+	Description        string      `json:"description"`
+	CourtID            uuid.UUID   `json:"court_id"`
+	CourtName          string      `json:"court_name"`
+	Location           Location    `json:"location"`
+	StartTime          time.Time   `json:"start_time"`
+	EndTime            time.Time   `json:"end_time"`
+	HostID             uuid.UUID   `json:"host_id"`
+	HostName           string      `json:"host_name"`
+	MaxPlayers         int         `json:"max_players"`
+	SkillLevel         string      `json:"skill_level,omitempty"` // Beginner, Intermediate, Advanced, or NTRP range
+	EventType          string      `json:"event_type"`            // Open Rally, Tournament, Clinic, etc.
+	IsRecurring        bool        `json:"is_recurring"`
+	RSVPs              []RSVP      `json:"rsvps,omitempty"`
+	Waitlist           []RSVP      `json:"waitlist,omitempty"`
+	IsNewcomerFriendly bool        `json:"is_newcomer_friendly"`
+	Status             string      `json:"status"` // Scheduled, Cancelled
+	CancellationReason string      `json:"cancellation_reason,omitempty"`
+	CoHostIDs          []uuid.UUID `json:"co_host_ids,omitempty"`
+	MinReliability     float64     `json:"min_reliability"` // Reliability score needed to RSVP; 0 lets anyone join
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+	LocalTimes
+}
+
+// EventUpdateRequest represents a partial update to an event; nil fields are left unchanged
+type EventUpdateRequest struct {
+	Title              *string      `json:"title"`
+	Description        *string      `json:"description"`
+	StartTime          *time.Time   `json:"start_time"`
+	EndTime            *time.Time   `json:"end_time"`
+	MaxPlayers         *int         `json:"max_players"`
+	SkillLevel         *string      `json:"skill_level"`
+	EventType          *string      `json:"event_type"`
+	IsNewcomerFriendly *bool        `json:"is_newcomer_friendly"`
+	CoHostIDs          *[]uuid.UUID `json:"co_host_ids"` // Only the host may change co-hosts
+	MinReliability     *float64     `json:"min_reliability"`
 }
 
 // RSVP represents a user's RSVP to an event
@@ -50,6 +75,58 @@ func (e *Event) GetConfirmedRSVPs() []RSVP {
 	return confirmed
 }
 
+// CanManage returns true if the user is the host or one of the co-hosts
+func (e *Event) CanManage(userID uuid.UUID) bool {
+	if e.HostID == userID {
+		return true
+	}
+	for _, coHostID := range e.CoHostIDs {
+		if coHostID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCancelled returns true if the event has been cancelled by its host
+func (e *Event) IsCancelled() bool {
+	return e.Status == EventStatusCancelled
+}
+
+// ApplyUpdate copies the non-nil fields of the request onto the event
+func (e *Event) ApplyUpdate(req EventUpdateRequest) {
+	if req.Title != nil {
+		e.Title = *req.Title
+	}
+	if req.Description != nil {
+		e.Description = *req.Description
+	}
+	if req.StartTime != nil {
+		e.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		e.EndTime = *req.EndTime
+	}
+	if req.MaxPlayers != nil {
+		e.MaxPlayers = *req.MaxPlayers
+	}
+	if req.SkillLevel != nil {
+		e.SkillLevel = *req.SkillLevel
+	}
+	if req.EventType != nil {
+		e.EventType = *req.EventType
+	}
+	if req.IsNewcomerFriendly != nil {
+		e.IsNewcomerFriendly = *req.IsNewcomerFriendly
+	}
+	if req.CoHostIDs != nil {
+		e.CoHostIDs = *req.CoHostIDs
+	}
+	if req.MinReliability != nil {
+		e.MinReliability = *req.MinReliability
+	}
+}
+
 // HasAvailableSpots returns true if the event has spots available
 func (e *Event) HasAvailableSpots() bool {
 	return len(e.GetConfirmedRSVPs()) < e.MaxPlayers
@@ -77,6 +154,40 @@ func (e *Event) MoveFromWaitlist() {
 	}
 }
 
+// Rebalance moves RSVPs between the confirmed list and the waitlist so that the
+// number of confirmed players matches MaxPlayers. Both lists are expected to be
+// ordered by RSVP time. Waitlisted players are promoted first-come first-served;
+// when capacity shrinks, the most recent confirmations are moved back to the
+// front of the waitlist so they keep their place ahead of later sign-ups.
+func (e *Event) Rebalance() (promoted, demoted []RSVP) {
+	confirmed := e.GetConfirmedRSVPs()
+	waitlist := e.Waitlist
+
+	for len(confirmed) < e.MaxPlayers && len(waitlist) > 0 {
+		rsvp := waitlist[0]
+		rsvp.Status = "Confirmed"
+		confirmed = append(confirmed, rsvp)
+		promoted = append(promoted, rsvp)
+		waitlist = waitlist[1:]
+	}
+
+	if e.MaxPlayers >= 0 && len(confirmed) > e.MaxPlayers {
+		overflow := confirmed[e.MaxPlayers:]
+		confirmed = confirmed[:e.MaxPlayers]
+		moved := make([]RSVP, 0, len(overflow)+len(waitlist))
+		for _, rsvp := range overflow {
+			rsvp.Status = "Waitlisted"
+			moved = append(moved, rsvp)
+			demoted = append(demoted, rsvp)
+		}
+		waitlist = append(moved, waitlist...)
+	}
+
+	e.RSVPs = confirmed
+	e.Waitlist = waitlist
+	return promoted, demoted
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
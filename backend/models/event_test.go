@@ -0,0 +1,97 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRSVPs(status string, count int, start time.Time) []RSVP {
+	rsvps := make([]RSVP, count)
+	for i := range rsvps {
+		rsvps[i] = RSVP{
+			ID:        uuid.New(),
+			UserID:    uuid.New(),
+			Status:    status,
+			CreatedAt: start.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	return rsvps
+}
+
+func TestEvent_Rebalance(t *testing.T) {
+	base := time.Now().Add(-time.Hour)
+
+	t.Run("capacity increase promotes waitlist in order", func(t *testing.T) {
+		confirmed := newTestRSVPs("Confirmed", 2, base)
+		waitlist := newTestRSVPs("Waitlisted", 3, base.Add(10*time.Minute))
+		event := &Event{MaxPlayers: 4, RSVPs: confirmed, Waitlist: waitlist}
+
+		promoted, demoted := event.Rebalance()
+
+		assert.Empty(t, demoted)
+		assert.Len(t, promoted, 2)
+		assert.Equal(t, waitlist[0].ID, promoted[0].ID)
+		assert.Equal(t, waitlist[1].ID, promoted[1].ID)
+		assert.Equal(t, "Confirmed", promoted[0].Status)
+		assert.Len(t, event.RSVPs, 4)
+		assert.Len(t, event.Waitlist, 1)
+		assert.Equal(t, waitlist[2].ID, event.Waitlist[0].ID)
+	})
+
+	t.Run("capacity decrease demotes latest confirmations to front of waitlist", func(t *testing.T) {
+		confirmed := newTestRSVPs("Confirmed", 4, base)
+		waitlist := newTestRSVPs("Waitlisted", 1, base.Add(10*time.Minute))
+		event := &Event{MaxPlayers: 2, RSVPs: confirmed, Waitlist: waitlist}
+
+		promoted, demoted := event.Rebalance()
+
+		assert.Empty(t, promoted)
+		assert.Len(t, demoted, 2)
+		assert.Equal(t, confirmed[2].ID, demoted[0].ID)
+		assert.Equal(t, confirmed[3].ID, demoted[1].ID)
+		assert.Equal(t, "Waitlisted", demoted[0].Status)
+		assert.Len(t, event.RSVPs, 2)
+		assert.Equal(t, []uuid.UUID{confirmed[2].ID, confirmed[3].ID, waitlist[0].ID},
+			[]uuid.UUID{event.Waitlist[0].ID, event.Waitlist[1].ID, event.Waitlist[2].ID})
+	})
+
+	t.Run("no change when at capacity", func(t *testing.T) {
+		event := &Event{
+			MaxPlayers: 2,
+			RSVPs:      newTestRSVPs("Confirmed", 2, base),
+			Waitlist:   newTestRSVPs("Waitlisted", 2, base.Add(10*time.Minute)),
+		}
+
+		promoted, demoted := event.Rebalance()
+
+		assert.Empty(t, promoted)
+		assert.Empty(t, demoted)
+		assert.Len(t, event.RSVPs, 2)
+		assert.Len(t, event.Waitlist, 2)
+	})
+}
+
+func TestEvent_CanManage(t *testing.T) {
+	hostID := uuid.New()
+	coHostID := uuid.New()
+	event := &Event{HostID: hostID, CoHostIDs: []uuid.UUID{coHostID}}
+
+	assert.True(t, event.CanManage(hostID))
+	assert.True(t, event.CanManage(coHostID))
+	assert.False(t, event.CanManage(uuid.New()))
+}
+
+func TestEvent_ApplyUpdate(t *testing.T) {
+	event := &Event{Title: "Open Rally", MaxPlayers: 8, SkillLevel: "3.5"}
+	title := "Sunday Open Rally"
+	maxPlayers := 12
+
+	event.ApplyUpdate(EventUpdateRequest{Title: &title, MaxPlayers: &maxPlayers})
+
+	assert.Equal(t, "Sunday Open Rally", event.Title)
+	assert.Equal(t, 12, event.MaxPlayers)
+	assert.Equal(t, "3.5", event.SkillLevel)
+}
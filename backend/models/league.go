@@ -0,0 +1,474 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// League types
+const (
+	LeagueTypeFlex   = "flex"   // Scheduled round robin within each division
+	LeagueTypeLadder = "ladder" // Players challenge those ranked above them
+)
+
+// League match statuses
+const (
+	LeagueMatchStatusScheduled = "scheduled"
+	LeagueMatchStatusCompleted = "completed"
+)
+
+// League match outcomes
+const (
+	LeagueOutcomePlayed  = "played"
+	LeagueOutcomeDefault = "default" // Walkover; the loser failed to play
+)
+
+// Ladder challenge statuses
+const (
+	ChallengeStatusPending   = "pending"
+	ChallengeStatusAccepted  = "accepted"
+	ChallengeStatusDeclined  = "declined"
+	ChallengeStatusExpired   = "expired"
+	ChallengeStatusCompleted = "completed"
+)
+
+// League is a season of play owned by a community or an event
+type League struct {
+	ID                     uuid.UUID        `json:"id"`
+	Name                   string           `json:"name"`
+	Type                   string           `json:"type"` // flex, ladder
+	CommunityID            *uuid.UUID       `json:"community_id,omitempty"`
+	EventID                *uuid.UUID       `json:"event_id,omitempty"`
+	OwnerID                uuid.UUID        `json:"owner_id"`
+	SeasonStart            time.Time        `json:"season_start"`
+	SeasonEnd              time.Time        `json:"season_end"`
+	PointsWin              int              `json:"points_win"`
+	PointsLoss             int              `json:"points_loss"`
+	PointsDefault          int              `json:"points_default"`           // Awarded to the winner of a walkover
+	ChallengeRange         int              `json:"challenge_range"`          // Ladder: how many places up a player may challenge
+	ChallengeResponseHours int              `json:"challenge_response_hours"` // Ladder: time to accept before the challenger wins by default
+	ChallengePlayDays      int              `json:"challenge_play_days"`      // Ladder: days to play an accepted challenge before the defender wins by default
+	Divisions              []LeagueDivision `json:"divisions"`
+	CreatedAt              time.Time        `json:"created_at"`
+	UpdatedAt              time.Time        `json:"updated_at"`
+}
+
+// LeagueDivision groups league players by NTRP band
+type LeagueDivision struct {
+	ID       uuid.UUID      `json:"id"`
+	LeagueID uuid.UUID      `json:"league_id"`
+	Name     string         `json:"name"`
+	MinNTRP  float64        `json:"min_ntrp"`
+	MaxNTRP  float64        `json:"max_ntrp"`
+	Players  []LeaguePlayer `json:"players,omitempty"`
+}
+
+// LeaguePlayer is a player registered in a league division
+type LeaguePlayer struct {
+	ID         uuid.UUID `json:"id"`
+	LeagueID   uuid.UUID `json:"league_id"`
+	DivisionID uuid.UUID `json:"division_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	SkillLevel float64   `json:"skill_level"`
+	LadderRank int       `json:"ladder_rank,omitempty"` // 1 is the top of the ladder
+	JoinedAt   time.Time `json:"joined_at"`
+}
+
+// LeagueMatch is a scheduled or challenge match in a league
+type LeagueMatch struct {
+	ID          uuid.UUID  `json:"id"`
+	LeagueID    uuid.UUID  `json:"league_id"`
+	DivisionID  uuid.UUID  `json:"division_id"`
+	Round       int        `json:"round,omitempty"` // Flex schedule week; 0 for challenge matches
+	Player1ID   uuid.UUID  `json:"player1_id"`
+	Player2ID   uuid.UUID  `json:"player2_id"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	ChallengeID *uuid.UUID `json:"challenge_id,omitempty"`
+	WinnerID    *uuid.UUID `json:"winner_id,omitempty"`
+	Score       string     `json:"score,omitempty"` // From player 1's perspective
+	Outcome     string     `json:"outcome,omitempty"`
+	Status      string     `json:"status"`
+	ReportedBy  *uuid.UUID `json:"reported_by,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// LadderChallenge is a challenge from one ladder player to a higher-ranked one
+type LadderChallenge struct {
+	ID           uuid.UUID  `json:"id"`
+	LeagueID     uuid.UUID  `json:"league_id"`
+	DivisionID   uuid.UUID  `json:"division_id"`
+	ChallengerID uuid.UUID  `json:"challenger_id"`
+	DefenderID   uuid.UUID  `json:"defender_id"`
+	Status       string     `json:"status"`
+	RespondBy    time.Time  `json:"respond_by"`
+	MatchID      *uuid.UUID `json:"match_id,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty"`
+}
+
+// LeagueStanding is a player's record and points in a division
+type LeagueStanding struct {
+	Standing
+	Points        int `json:"points"`
+	DefaultWins   int `json:"default_wins"`
+	DefaultLosses int `json:"default_losses"`
+	LadderRank    int `json:"ladder_rank,omitempty"`
+}
+
+// LeagueRequest represents a request to create a league
+type LeagueRequest struct {
+	Name                   string                  `json:"name" binding:"required"`
+	Type                   string                  `json:"type" binding:"required"`
+	CommunityID            *uuid.UUID              `json:"community_id"`
+	EventID                *uuid.UUID              `json:"event_id"`
+	SeasonStart            time.Time               `json:"season_start" binding:"required"`
+	SeasonEnd              time.Time               `json:"season_end" binding:"required"`
+	PointsWin              *int                    `json:"points_win"`     // Defaults to 3
+	PointsLoss             *int                    `json:"points_loss"`    // Defaults to 1
+	PointsDefault          *int                    `json:"points_default"` // Defaults to points_win
+	ChallengeRange         int                     `json:"challenge_range"`
+	ChallengeResponseHours int                     `json:"challenge_response_hours"`
+	ChallengePlayDays      int                     `json:"challenge_play_days"`
+	Divisions              []LeagueDivisionRequest `json:"divisions" binding:"required,min=1"`
+}
+
+// LeagueDivisionRequest describes one division of a new league
+type LeagueDivisionRequest struct {
+	Name    string  `json:"name" binding:"required"`
+	MinNTRP float64 `json:"min_ntrp"`
+	MaxNTRP float64 `json:"max_ntrp"`
+}
+
+// LeagueResultRequest represents a reported league match result
+type LeagueResultRequest struct {
+	WinnerID uuid.UUID `json:"winner_id" binding:"required"`
+	Score    string    `json:"score"`   // From player 1's perspective
+	Default  bool      `json:"default"` // The loser did not play
+}
+
+// NewLeague builds a league from a request, applying defaults and validating the divisions
+func NewLeague(req LeagueRequest, ownerID uuid.UUID) (*League, error) {
+	if req.Type != LeagueTypeFlex && req.Type != LeagueTypeLadder {
+		return nil, fmt.Errorf("league type must be %s or %s", LeagueTypeFlex, LeagueTypeLadder)
+	}
+	if !req.SeasonEnd.After(req.SeasonStart) {
+		return nil, fmt.Errorf("season end must be after season start")
+	}
+
+	league := &League{
+		ID:                     uuid.New(),
+		Name:                   req.Name,
+		Type:                   req.Type,
+		CommunityID:            req.CommunityID,
+		EventID:                req.EventID,
+		OwnerID:                ownerID,
+		SeasonStart:            req.SeasonStart,
+		SeasonEnd:              req.SeasonEnd,
+		PointsWin:              3,
+		PointsLoss:             1,
+		ChallengeRange:         req.ChallengeRange,
+		ChallengeResponseHours: req.ChallengeResponseHours,
+		ChallengePlayDays:      req.ChallengePlayDays,
+	}
+	if req.PointsWin != nil {
+		league.PointsWin = *req.PointsWin
+	}
+	if req.PointsLoss != nil {
+		league.PointsLoss = *req.PointsLoss
+	}
+	league.PointsDefault = league.PointsWin
+	if req.PointsDefault != nil {
+		league.PointsDefault = *req.PointsDefault
+	}
+	if league.Type == LeagueTypeLadder {
+		if league.ChallengeRange <= 0 {
+			league.ChallengeRange = 3
+		}
+		if league.ChallengeResponseHours <= 0 {
+			league.ChallengeResponseHours = 48
+		}
+		if league.ChallengePlayDays <= 0 {
+			league.ChallengePlayDays = 7
+		}
+	}
+
+	for _, d := range req.Divisions {
+		if d.MaxNTRP != 0 && d.MaxNTRP < d.MinNTRP {
+			return nil, fmt.Errorf("division %s has an invalid NTRP band", d.Name)
+		}
+		league.Divisions = append(league.Divisions, LeagueDivision{
+			ID:       uuid.New(),
+			LeagueID: league.ID,
+			Name:     d.Name,
+			MinNTRP:  d.MinNTRP,
+			MaxNTRP:  d.MaxNTRP,
+		})
+	}
+	return league, nil
+}
+
+// CanManage returns true if the user may run the league
+func (l *League) CanManage(userID uuid.UUID) bool {
+	return l.OwnerID == userID
+}
+
+// FindPlayer returns the league player for a user, or nil if they have not joined
+func (l *League) FindPlayer(userID uuid.UUID) *LeaguePlayer {
+	for i := range l.Divisions {
+		for j := range l.Divisions[i].Players {
+			if l.Divisions[i].Players[j].UserID == userID {
+				return &l.Divisions[i].Players[j]
+			}
+		}
+	}
+	return nil
+}
+
+// FindDivision returns the division with the given ID, or nil
+func (l *League) FindDivision(divisionID uuid.UUID) *LeagueDivision {
+	for i := range l.Divisions {
+		if l.Divisions[i].ID == divisionID {
+			return &l.Divisions[i]
+		}
+	}
+	return nil
+}
+
+// DivisionFor returns the first division whose NTRP band contains the skill
+// level. A MaxNTRP of 0 means the band has no upper limit.
+func (l *League) DivisionFor(skillLevel float64) *LeagueDivision {
+	for i := range l.Divisions {
+		d := &l.Divisions[i]
+		if skillLevel >= d.MinNTRP && (d.MaxNTRP == 0 || skillLevel <= d.MaxNTRP) {
+			return d
+		}
+	}
+	return nil
+}
+
+// ScheduleDivision builds a flex league round robin for the division's
+// players, spreading the rounds evenly across the season
+func (l *League) ScheduleDivision(division *LeagueDivision) []LeagueMatch {
+	userIDs := make([]uuid.UUID, len(division.Players))
+	for i, p := range division.Players {
+		userIDs[i] = p.UserID
+	}
+	rounds := RoundRobinPairings(userIDs)
+	if len(rounds) == 0 {
+		return nil
+	}
+
+	roundLength := l.SeasonEnd.Sub(l.SeasonStart) / time.Duration(len(rounds))
+	var matches []LeagueMatch
+	for r, pairs := range rounds {
+		dueDate := l.SeasonStart.Add(roundLength * time.Duration(r+1))
+		for _, pair := range pairs {
+			due := dueDate
+			matches = append(matches, LeagueMatch{
+				ID:         uuid.New(),
+				LeagueID:   l.ID,
+				DivisionID: division.ID,
+				Round:      r + 1,
+				Player1ID:  pair[0],
+				Player2ID:  pair[1],
+				DueDate:    &due,
+				Status:     LeagueMatchStatusScheduled,
+			})
+		}
+	}
+	return matches
+}
+
+// ValidateResult checks a reported result against the match
+func (m *LeagueMatch) ValidateResult(req LeagueResultRequest) error {
+	if m.Status == LeagueMatchStatusCompleted {
+		return fmt.Errorf("match result has already been reported")
+	}
+	if req.WinnerID != m.Player1ID && req.WinnerID != m.Player2ID {
+		return fmt.Errorf("winner must be one of the match players")
+	}
+	if req.Default || req.Score == "" {
+		return nil
+	}
+	score, err := ParseScore(req.Score)
+	if err != nil {
+		return err
+	}
+	if score.Player1Won() != (req.WinnerID == m.Player1ID) {
+		return fmt.Errorf("score does not match the winner")
+	}
+	return nil
+}
+
+// IsPlayer returns true if the user plays in the match
+func (m *LeagueMatch) IsPlayer(userID uuid.UUID) bool {
+	return m.Player1ID == userID || m.Player2ID == userID
+}
+
+// CanChallenge checks the ladder rules for a challenge between two players
+func (l *League) CanChallenge(challenger, defender LeaguePlayer) error {
+	if l.Type != LeagueTypeLadder {
+		return fmt.Errorf("challenges are only available in ladder leagues")
+	}
+	if challenger.DivisionID != defender.DivisionID {
+		return fmt.Errorf("players must be in the same division")
+	}
+	if defender.LadderRank >= challenger.LadderRank {
+		return fmt.Errorf("you can only challenge players ranked above you")
+	}
+	if challenger.LadderRank-defender.LadderRank > l.ChallengeRange {
+		return fmt.Errorf("you can only challenge players up to %d places above you", l.ChallengeRange)
+	}
+	return nil
+}
+
+// NewChallenge creates a pending challenge with the league's acceptance deadline
+func (l *League) NewChallenge(challenger, defender LeaguePlayer, now time.Time) LadderChallenge {
+	return LadderChallenge{
+		ID:           uuid.New(),
+		LeagueID:     l.ID,
+		DivisionID:   challenger.DivisionID,
+		ChallengerID: challenger.UserID,
+		DefenderID:   defender.UserID,
+		Status:       ChallengeStatusPending,
+		RespondBy:    now.Add(time.Duration(l.ChallengeResponseHours) * time.Hour),
+		CreatedAt:    now,
+	}
+}
+
+// IsExpired returns true if a pending challenge has passed its acceptance deadline
+func (c *LadderChallenge) IsExpired(now time.Time) bool {
+	return c.Status == ChallengeStatusPending && now.After(c.RespondBy)
+}
+
+// IsOpen returns true if the challenge has not been resolved yet
+func (c *LadderChallenge) IsOpen() bool {
+	return c.Status == ChallengeStatusPending || c.Status == ChallengeStatusAccepted
+}
+
+// SwapLadderRanks applies a ladder result: if the lower-ranked player won,
+// the two players trade places. It returns true if ranks changed.
+func SwapLadderRanks(winner, loser *LeaguePlayer) bool {
+	if winner.LadderRank <= loser.LadderRank {
+		return false
+	}
+	winner.LadderRank, loser.LadderRank = loser.LadderRank, winner.LadderRank
+	return true
+}
+
+// ComputeLeagueStandings builds the division table from completed matches.
+// Players are ordered by points, then head-to-head wins among the tied
+// players, then set difference, then game difference. Ladders are ordered
+// by ladder rank instead.
+func (l *League) ComputeLeagueStandings(players []LeaguePlayer, matches []LeagueMatch) []LeagueStanding {
+	byUser := map[uuid.UUID]*LeagueStanding{}
+	standings := make([]*LeagueStanding, 0, len(players))
+	for _, p := range players {
+		s := &LeagueStanding{
+			Standing:   Standing{UserID: p.UserID, UserName: p.UserName},
+			LadderRank: p.LadderRank,
+		}
+		byUser[p.UserID] = s
+		standings = append(standings, s)
+	}
+
+	// Head-to-head wins, keyed by winner then loser
+	beat := map[uuid.UUID]map[uuid.UUID]int{}
+	for _, m := range matches {
+		if m.Status != LeagueMatchStatusCompleted || m.WinnerID == nil {
+			continue
+		}
+		p1, p2 := byUser[m.Player1ID], byUser[m.Player2ID]
+		if p1 == nil || p2 == nil {
+			continue
+		}
+		winner, loser := p1, p2
+		if *m.WinnerID == m.Player2ID {
+			winner, loser = p2, p1
+		}
+		winner.Played++
+		loser.Played++
+		winner.Wins++
+		loser.Losses++
+		if beat[winner.UserID] == nil {
+			beat[winner.UserID] = map[uuid.UUID]int{}
+		}
+		beat[winner.UserID][loser.UserID]++
+
+		if m.Outcome == LeagueOutcomeDefault {
+			winner.DefaultWins++
+			loser.DefaultLosses++
+			winner.Points += l.PointsDefault
+			continue
+		}
+		winner.Points += l.PointsWin
+		loser.Points += l.PointsLoss
+
+		if score, err := ParseScore(m.Score); err == nil {
+			s1, s2 := score.SetsWon()
+			g1, g2 := score.GamesWon()
+			p1.SetsWon += s1
+			p1.SetsLost += s2
+			p2.SetsWon += s2
+			p2.SetsLost += s1
+			p1.GamesWon += g1
+			p1.GamesLost += g2
+			p2.GamesWon += g2
+			p2.GamesLost += g1
+		}
+	}
+
+	if l.Type == LeagueTypeLadder {
+		sort.SliceStable(standings, func(i, j int) bool {
+			return standings[i].LadderRank < standings[j].LadderRank
+		})
+		result := make([]LeagueStanding, len(standings))
+		for i, s := range standings {
+			s.Rank = i + 1
+			result[i] = *s
+		}
+		return result
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].Points > standings[j].Points
+	})
+
+	// Break ties within each group of players on equal points
+	for start := 0; start < len(standings); {
+		end := start + 1
+		for end < len(standings) && standings[end].Points == standings[start].Points {
+			end++
+		}
+		group := standings[start:end]
+		headToHead := map[uuid.UUID]int{}
+		for _, a := range group {
+			for _, b := range group {
+				headToHead[a.UserID] += beat[a.UserID][b.UserID]
+			}
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			a, b := group[i], group[j]
+			if headToHead[a.UserID] != headToHead[b.UserID] {
+				return headToHead[a.UserID] > headToHead[b.UserID]
+			}
+			if a.SetsWon-a.SetsLost != b.SetsWon-b.SetsLost {
+				return a.SetsWon-a.SetsLost > b.SetsWon-b.SetsLost
+			}
+			return a.GamesWon-a.GamesLost > b.GamesWon-b.GamesLost
+		})
+		start = end
+	}
+
+	result := make([]LeagueStanding, len(standings))
+	for i, s := range standings {
+		s.Rank = i + 1
+		result[i] = *s
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLeague(leagueType string) *League {
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	league, _ := NewLeague(LeagueRequest{
+		Name:        "Spring League",
+		Type:        leagueType,
+		SeasonStart: start,
+		SeasonEnd:   start.AddDate(0, 0, 28),
+		Divisions: []LeagueDivisionRequest{
+			{Name: "3.0", MinNTRP: 2.5, MaxNTRP: 3.0},
+			{Name: "3.5+", MinNTRP: 3.5},
+		},
+	}, uuid.New())
+	return league
+}
+
+func completedMatch(p1, p2, winner uuid.UUID, score, outcome string) LeagueMatch {
+	return LeagueMatch{Player1ID: p1, Player2ID: p2, WinnerID: &winner, Score: score, Outcome: outcome, Status: LeagueMatchStatusCompleted}
+}
+
+func TestNewLeague(t *testing.T) {
+	league := newTestLeague(LeagueTypeLadder)
+	require.NotNil(t, league)
+	assert.Equal(t, 3, league.PointsWin)
+	assert.Equal(t, 3, league.PointsDefault)
+	assert.Equal(t, 48, league.ChallengeResponseHours)
+
+	_, err := NewLeague(LeagueRequest{Type: "swiss", SeasonStart: time.Now(), SeasonEnd: time.Now().Add(time.Hour)}, uuid.New())
+	assert.Error(t, err)
+}
+
+func TestLeague_DivisionFor(t *testing.T) {
+	league := newTestLeague(LeagueTypeFlex)
+
+	assert.Equal(t, "3.0", league.DivisionFor(3.0).Name)
+	assert.Equal(t, "3.5+", league.DivisionFor(5.0).Name)
+	assert.Nil(t, league.DivisionFor(2.0))
+}
+
+func TestLeague_ScheduleDivision(t *testing.T) {
+	league := newTestLeague(LeagueTypeFlex)
+	division := &league.Divisions[0]
+	for i := 0; i < 4; i++ {
+		division.Players = append(division.Players, LeaguePlayer{UserID: uuid.New()})
+	}
+
+	matches := league.ScheduleDivision(division)
+
+	// 4 players: 3 rounds of 2 matches, one round due each 28/3 days
+	assert.Len(t, matches, 6)
+	assert.Equal(t, 3, matches[len(matches)-1].Round)
+	assert.Equal(t, league.SeasonEnd, *matches[len(matches)-1].DueDate)
+}
+
+func TestLeagueMatch_ValidateResult(t *testing.T) {
+	p1, p2 := uuid.New(), uuid.New()
+	match := LeagueMatch{Player1ID: p1, Player2ID: p2, Status: LeagueMatchStatusScheduled}
+
+	assert.NoError(t, match.ValidateResult(LeagueResultRequest{WinnerID: p2, Score: "4-6 2-6"}))
+	assert.NoError(t, match.ValidateResult(LeagueResultRequest{WinnerID: p1, Default: true}))
+	assert.Error(t, match.ValidateResult(LeagueResultRequest{WinnerID: p1, Score: "4-6 2-6"}))
+	assert.Error(t, match.ValidateResult(LeagueResultRequest{WinnerID: uuid.New()}))
+
+	match.Status = LeagueMatchStatusCompleted
+	assert.Error(t, match.ValidateResult(LeagueResultRequest{WinnerID: p1}))
+}
+
+func TestLeague_ComputeLeagueStandings(t *testing.T) {
+	league := newTestLeague(LeagueTypeFlex)
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	players := []LeaguePlayer{{UserID: a, UserName: "A"}, {UserID: b, UserName: "B"}, {UserID: c, UserName: "C"}, {UserID: d, UserName: "D"}}
+
+	matches := []LeagueMatch{
+		completedMatch(a, b, b, "4-6 4-6", LeagueOutcomePlayed), // b beats a head-to-head
+		completedMatch(a, c, a, "6-0 6-0", LeagueOutcomePlayed),
+		completedMatch(b, d, d, "", LeagueOutcomeDefault), // b gave a walkover
+		completedMatch(c, d, c, "6-4 6-4", LeagueOutcomePlayed),
+		{Player1ID: a, Player2ID: d, Status: LeagueMatchStatusScheduled},
+	}
+
+	standings := league.ComputeLeagueStandings(players, matches)
+
+	// a: win + loss = 4, b: win + default loss = 3, c: win + loss = 4, d: default win + loss = 4
+	points := map[uuid.UUID]int{}
+	for _, s := range standings {
+		points[s.UserID] = s.Points
+	}
+	assert.Equal(t, map[uuid.UUID]int{a: 4, b: 3, c: 4, d: 4}, points)
+	assert.Equal(t, b, standings[3].UserID)
+	assert.Equal(t, 1, standings[3].DefaultLosses)
+
+	// a, c and d are tied on 4 points. a and c each beat one of the others
+	// (d's win came against b), and a has the better game difference.
+	assert.Equal(t, []uuid.UUID{a, c, d, b},
+		[]uuid.UUID{standings[0].UserID, standings[1].UserID, standings[2].UserID, standings[3].UserID})
+	assert.Equal(t, 1, standings[0].Rank)
+}
+
+func TestLeague_Ladder(t *testing.T) {
+	league := newTestLeague(LeagueTypeLadder)
+	division := league.Divisions[0].ID
+	top := LeaguePlayer{UserID: uuid.New(), DivisionID: division, LadderRank: 1}
+	mid := LeaguePlayer{UserID: uuid.New(), DivisionID: division, LadderRank: 3}
+	bottom := LeaguePlayer{UserID: uuid.New(), DivisionID: division, LadderRank: 5}
+
+	assert.NoError(t, league.CanChallenge(mid, top))
+	assert.Error(t, league.CanChallenge(top, mid), "cannot challenge down the ladder")
+	assert.Error(t, league.CanChallenge(bottom, top), "out of challenge range")
+
+	now := time.Now()
+	challenge := league.NewChallenge(mid, top, now)
+	assert.Equal(t, ChallengeStatusPending, challenge.Status)
+	assert.False(t, challenge.IsExpired(now.Add(47*time.Hour)))
+	assert.True(t, challenge.IsExpired(now.Add(49*time.Hour)))
+
+	assert.True(t, SwapLadderRanks(&mid, &top))
+	assert.Equal(t, 1, mid.LadderRank)
+	assert.Equal(t, 3, top.LadderRank)
+	assert.False(t, SwapLadderRanks(&mid, &top), "higher ranked winner keeps places")
+
+	standings := league.ComputeLeagueStandings([]LeaguePlayer{top, mid, bottom}, nil)
+	assert.Equal(t, []uuid.UUID{mid.UserID, top.UserID, bottom.UserID},
+		[]uuid.UUID{standings[0].UserID, standings[1].UserID, standings[2].UserID})
+}
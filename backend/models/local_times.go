@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// LocalTimes is embedded in anything that happens at a court so responses
+// carry start and end times in the court's timezone as well as in UTC
+type LocalTimes struct {
+	Timezone       string     `json:"timezone,omitempty"`
+	LocalStartTime *time.Time `json:"local_start_time,omitempty"`
+	LocalEndTime   *time.Time `json:"local_end_time,omitempty"`
+}
+
+// Localize converts start and end to UTC in place and records them in the
+// named timezone; an unknown zone leaves the local times unset
+func (l *LocalTimes) Localize(start, end *time.Time, timezone string) {
+	*start, *end = start.UTC(), end.UTC()
+	loc, err := time.LoadLocation(timezone)
+	if timezone == "" || err != nil {
+		return
+	}
+	localStart, localEnd := start.In(loc), end.In(loc)
+	l.Timezone = timezone
+	l.LocalStartTime = &localStart
+	l.LocalEndTime = &localEnd
+}
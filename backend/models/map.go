@@ -0,0 +1,177 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of things shown on the map
+const (
+	MapTypeCourts    = "courts"
+	MapTypeEvents    = "events"
+	MapTypeBulletins = "bulletins"
+	MapTypePlayers   = "players"
+)
+
+// MapTypes lists every kind of map marker, in the order they're reported
+var MapTypes = []string{MapTypeCourts, MapTypeEvents, MapTypeBulletins, MapTypePlayers}
+
+// Players are grouped by geohash cells no finer than MaxPlayerMapPrecision,
+// about 5 km across, and cells with fewer than MinPlayerMapCount players are
+// left off the map, so zooming in never narrows down where a player lives
+const (
+	MaxPlayerMapPrecision = 5
+	MinPlayerMapCount     = 3
+)
+
+// MapPrecision returns the geohash length a map type is grouped by when the
+// map is clustered by the given length
+func MapPrecision(mapType string, precision int) int {
+	if mapType == MapTypePlayers && precision > MaxPlayerMapPrecision {
+		return MaxPlayerMapPrecision
+	}
+	return precision
+}
+
+// ParseMapTypes parses a comma-separated list of map types. An empty list
+// means all of them.
+func ParseMapTypes(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return MapTypes, nil
+	}
+	requested := map[string]bool{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isMapType(name) {
+			return nil, fmt.Errorf("unknown map type %s", name)
+		}
+		requested[name] = true
+	}
+	var types []string
+	for _, name := range MapTypes {
+		if requested[name] {
+			types = append(types, name)
+		}
+	}
+	return types, nil
+}
+
+func isMapType(name string) bool {
+	for _, mapType := range MapTypes {
+		if name == mapType {
+			return true
+		}
+	}
+	return false
+}
+
+// MapBounds is the area a cluster's markers cover, so a client can zoom to it
+type MapBounds struct {
+	MinLat float64 `json:"min_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLng float64 `json:"max_lng"`
+}
+
+// MapMarker is one point on the map: either a single court, event or
+// bulletin, or a cluster of everything in one grid cell with a count for
+// each type. Players are only ever counted; a marker is never placed at a
+// player's location.
+type MapMarker struct {
+	Cell      string         `json:"cell"` // Geohash of the grid cell
+	Latitude  float64        `json:"latitude"`
+	Longitude float64        `json:"longitude"`
+	Count     int            `json:"count"`
+	Counts    map[string]int `json:"counts"`
+	Bounds    *MapBounds     `json:"bounds,omitempty"`
+	Type      string         `json:"type,omitempty"` // Set with ID and Label on a single item
+	ID        *uuid.UUID     `json:"id,omitempty"`
+	Label     string         `json:"label,omitempty"`
+}
+
+// IsCluster reports whether the marker stands for more than one thing
+func (m *MapMarker) IsCluster() bool {
+	return m.Count > 1
+}
+
+// MapGroup is what one type has in one grid cell. Latitude and Longitude are
+// the mean of the group's locations, except for players, whose group is
+// placed at the centre of the cell. ID and Label are one member's.
+type MapGroup struct {
+	Cell      string
+	Type      string
+	Count     int
+	Latitude  float64
+	Longitude float64
+	Bounds    MapBounds
+	ID        uuid.UUID
+	Label     string
+}
+
+// ClusterMapGroups combines the groups sharing a cell into one marker each,
+// ordered by cell. A marker sits at the mean location of its courts, events
+// and bulletins, or at the centre of the cell if it only has players.
+func ClusterMapGroups(groups []MapGroup) []*MapMarker {
+	byCell := map[string][]MapGroup{}
+	var cells []string
+	for _, group := range groups {
+		if group.Count == 0 {
+			continue
+		}
+		if _, ok := byCell[group.Cell]; !ok {
+			cells = append(cells, group.Cell)
+		}
+		byCell[group.Cell] = append(byCell[group.Cell], group)
+	}
+	sort.Strings(cells)
+
+	markers := make([]*MapMarker, 0, len(cells))
+	for _, cell := range cells {
+		marker := &MapMarker{Cell: cell, Counts: map[string]int{}}
+		located := 0
+		var single MapGroup
+		for _, group := range byCell[cell] {
+			marker.Count += group.Count
+			marker.Counts[group.Type] += group.Count
+			if group.Type == MapTypePlayers {
+				if located == 0 {
+					marker.Latitude, marker.Longitude = group.Latitude, group.Longitude
+				}
+				continue
+			}
+
+			// Running mean, weighted by how many each group has
+			total := located + group.Count
+			marker.Latitude += (group.Latitude - marker.Latitude) * float64(group.Count) / float64(total)
+			marker.Longitude += (group.Longitude - marker.Longitude) * float64(group.Count) / float64(total)
+			if marker.Bounds == nil {
+				bounds := group.Bounds
+				marker.Bounds = &bounds
+			} else {
+				marker.Bounds.extend(group.Bounds)
+			}
+			located = total
+			single = group
+		}
+
+		if marker.Count == 1 && single.Count == 1 {
+			id := single.ID
+			marker.Type, marker.ID, marker.Label = single.Type, &id, single.Label
+			marker.Bounds = nil
+		}
+		markers = append(markers, marker)
+	}
+	return markers
+}
+
+// extend grows the bounds to cover other as well
+func (b *MapBounds) extend(other MapBounds) {
+	b.MinLat = math.Min(b.MinLat, other.MinLat)
+	b.MinLng = math.Min(b.MinLng, other.MinLng)
+	b.MaxLat = math.Max(b.MaxLat, other.MaxLat)
+	b.MaxLng = math.Max(b.MaxLng, other.MaxLng)
+}
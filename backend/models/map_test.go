@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapPrecision(t *testing.T) {
+	assert.Equal(t, 7, MapPrecision(MapTypeCourts, 7))
+	assert.Equal(t, 3, MapPrecision(MapTypePlayers, 3))
+	assert.Equal(t, MaxPlayerMapPrecision, MapPrecision(MapTypePlayers, 9), "players are never grouped finely")
+}
+
+func TestParseMapTypes(t *testing.T) {
+	types, err := ParseMapTypes("")
+	require.NoError(t, err)
+	assert.Equal(t, MapTypes, types)
+
+	types, err = ParseMapTypes("Players, courts,courts")
+	require.NoError(t, err)
+	assert.Equal(t, []string{MapTypeCourts, MapTypePlayers}, types)
+
+	_, err = ParseMapTypes("courts,shops")
+	assert.EqualError(t, err, "unknown map type shops")
+}
+
+func TestClusterMapGroups(t *testing.T) {
+	courtID, eventID := uuid.New(), uuid.New()
+	markers := ClusterMapGroups([]MapGroup{
+		// A cell with two courts and an event
+		{Cell: "9q8yy", Type: MapTypeCourts, Count: 2, Latitude: 37.77, Longitude: -122.42,
+			Bounds: MapBounds{MinLat: 37.76, MinLng: -122.43, MaxLat: 37.78, MaxLng: -122.41}, ID: courtID, Label: "Dolores Park"},
+		{Cell: "9q8yy", Type: MapTypeEvents, Count: 1, Latitude: 37.79, Longitude: -122.40,
+			Bounds: MapBounds{MinLat: 37.79, MinLng: -122.40, MaxLat: 37.79, MaxLng: -122.40}, ID: eventID},
+		{Cell: "9q8yy", Type: MapTypePlayers, Count: 5, Latitude: 37.771, Longitude: -122.409},
+		// A cell with one event
+		{Cell: "9q8yv", Type: MapTypeEvents, Count: 1, Latitude: 37.75, Longitude: -122.45,
+			Bounds: MapBounds{MinLat: 37.75, MinLng: -122.45, MaxLat: 37.75, MaxLng: -122.45}, ID: eventID, Label: "Sunday Social"},
+		// A cell with only players, placed at its centre
+		{Cell: "9q8yw", Type: MapTypePlayers, Count: 1, Latitude: 37.73, Longitude: -122.47},
+		{Cell: "9q8yx", Type: MapTypeCourts},
+	})
+	require.Len(t, markers, 3)
+
+	single := markers[0]
+	assert.Equal(t, "9q8yv", single.Cell)
+	assert.False(t, single.IsCluster())
+	assert.Equal(t, MapTypeEvents, single.Type)
+	assert.Equal(t, eventID, *single.ID)
+	assert.Equal(t, "Sunday Social", single.Label)
+	assert.Nil(t, single.Bounds)
+
+	player := markers[1]
+	assert.Equal(t, 1, player.Count)
+	assert.Equal(t, map[string]int{MapTypePlayers: 1}, player.Counts)
+	assert.Equal(t, 37.73, player.Latitude)
+	assert.Nil(t, player.ID, "players are never identified")
+	assert.Nil(t, player.Bounds)
+
+	cluster := markers[2]
+	assert.True(t, cluster.IsCluster())
+	assert.Equal(t, 8, cluster.Count)
+	assert.Equal(t, map[string]int{MapTypeCourts: 2, MapTypeEvents: 1, MapTypePlayers: 5}, cluster.Counts)
+	assert.InDelta(t, (2*37.77+37.79)/3, cluster.Latitude, 1e-9, "players don't move the marker")
+	assert.InDelta(t, (2*-122.42+-122.40)/3, cluster.Longitude, 1e-9)
+	assert.Equal(t, &MapBounds{MinLat: 37.76, MinLng: -122.43, MaxLat: 37.79, MaxLng: -122.40}, cluster.Bounds)
+	assert.Nil(t, cluster.ID)
+	assert.Empty(t, cluster.Type)
+}
@@ -31,6 +31,7 @@ type MatchSession struct {
 	UpdatedAt  time.Time      `json:"updated_at"`
 
 	// Populated fields
+	LocalTimes
 	Court   *Court          `json:"court,omitempty"`
 	Players []MatchPlayer   `json:"players,omitempty"`
 	Matches []PlayerPairing `json:"matches,omitempty"`
@@ -104,6 +105,16 @@ type MatchingCriteria struct {
 	PreferenceWeight   float32 `json:"preference_weight"`   // Weight for historical preferences
 	AvailabilityWeight float32 `json:"availability_weight"` // Weight for player availability
 	SkillWeight        float32 `json:"skill_weight"`        // Weight for skill compatibility
+
+	// The session being matched and the busy time of players who imported a
+	// calendar, for scoring availability
+	Slot      TimeRange                 `json:"-"`
+	BusyTimes map[uuid.UUID][]TimeRange `json:"-"`
+
+	// The session's court and the players who have favorited it, for
+	// scoring locality
+	CourtID         uuid.UUID          `json:"-"`
+	CourtFavoriters map[uuid.UUID]bool `json:"-"`
 }
 
 // DefaultMatchingCriteria returns default matching criteria
@@ -123,11 +134,16 @@ func (p *PlayerPairing) CalculateCompatibilityScore(player1, player2 *User, crit
 	skillDiff := abs(player1.SkillLevel - player2.SkillLevel)
 	skillScore := max(0, 1.0-(skillDiff/criteria.SkillLevelRange))
 
-	// Time preference compatibility (simplified)
-	timeScore := float32(0.8) // Default good compatibility
+	// Availability for the session, from imported calendars
+	timeScore := (AvailabilityScore(criteria.BusyTimes[player1.ID], criteria.Slot) +
+		AvailabilityScore(criteria.BusyTimes[player2.ID], criteria.Slot)) / 2
 
-	// Historical preference score (would be calculated from feedback)
-	preferenceScore := float32(0.7) // Default neutral preference
+	// Locality: players who call the session's court home, or share a home
+	// court, are preferred
+	preferenceScore := (LocalityScore(player1, criteria) + LocalityScore(player2, criteria)) / 2
+	if player1.HomeCourtID != nil && player2.HomeCourtID != nil && *player1.HomeCourtID == *player2.HomeCourtID {
+		preferenceScore = HomeCourtLocalityScore
+	}
 
 	// Weighted total
 	totalScore := (skillScore * criteria.SkillWeight) +
@@ -137,6 +153,28 @@ func (p *PlayerPairing) CalculateCompatibilityScore(player1, player2 *User, crit
 	return totalScore
 }
 
+// Locality scores for how a player is tied to the court being played at
+const (
+	HomeCourtLocalityScore     = float32(1.0)
+	FavoriteCourtLocalityScore = float32(0.9)
+	NeutralLocalityScore       = float32(0.7) // Neither, or the court isn't known
+)
+
+// LocalityScore scores a player for the session's court: their home court,
+// one of their favorites, or neither
+func LocalityScore(player *User, criteria MatchingCriteria) float32 {
+	switch {
+	case criteria.CourtID == uuid.Nil:
+		return NeutralLocalityScore
+	case player.HomeCourtID != nil && *player.HomeCourtID == criteria.CourtID:
+		return HomeCourtLocalityScore
+	case criteria.CourtFavoriters[player.ID]:
+		return FavoriteCourtLocalityScore
+	default:
+		return NeutralLocalityScore
+	}
+}
+
 // Helper functions
 func abs(x float32) float32 {
 	if x < 0 {
@@ -0,0 +1,334 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Weights used to score a rotation; lower is better
+const (
+	mixerRepeatPartnerCost  = 10.0
+	mixerRepeatOpponentCost = 3.0
+	mixerSkillGapCost       = 4.0 // Per NTRP point of difference between team totals
+)
+
+// MixerPlayer is a player available for a social mixer
+type MixerPlayer struct {
+	UserID     uuid.UUID `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	SkillLevel float64   `json:"skill_level"`
+	Gender     string    `json:"gender,omitempty"`
+}
+
+// MixerCourt is one doubles match within a mixer round
+type MixerCourt struct {
+	Court    int          `json:"court"`
+	TeamA    [2]uuid.UUID `json:"team_a"`
+	TeamB    [2]uuid.UUID `json:"team_b"`
+	SkillGap float64      `json:"skill_gap"` // Difference between the teams' combined NTRP
+}
+
+// MixerRound is one rotation of a mixer
+type MixerRound struct {
+	Round      int          `json:"round"`
+	Courts     []MixerCourt `json:"courts"`
+	SittingOut []uuid.UUID  `json:"sitting_out"`
+}
+
+// Mixer is a rotating doubles schedule for an event
+type Mixer struct {
+	EventID      uuid.UUID     `json:"event_id"`
+	Courts       int           `json:"courts"`
+	Rounds       int           `json:"rounds"`
+	MixedDoubles bool          `json:"mixed_doubles"`
+	Players      []MixerPlayer `json:"players"`
+	Schedule     []MixerRound  `json:"schedule"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// MixerRequest represents a request to generate or regenerate a mixer
+type MixerRequest struct {
+	Courts       int         `json:"courts" binding:"required,min=1"`
+	Rounds       int         `json:"rounds" binding:"required,min=1"`
+	MixedDoubles bool        `json:"mixed_doubles"`
+	FromRound    int         `json:"from_round"` // Keep earlier rounds and regenerate from here; 0 regenerates everything
+	PlayerIDs    []uuid.UUID `json:"player_ids"` // Players present; defaults to confirmed RSVPs and checked-in players
+}
+
+// mixerHistory counts how often pairs of players have partnered or opposed
+type mixerHistory struct {
+	partners  map[[2]uuid.UUID]int
+	opponents map[[2]uuid.UUID]int
+	sitOuts   map[uuid.UUID]int
+}
+
+func newMixerHistory(rounds []MixerRound) *mixerHistory {
+	h := &mixerHistory{
+		partners:  map[[2]uuid.UUID]int{},
+		opponents: map[[2]uuid.UUID]int{},
+		sitOuts:   map[uuid.UUID]int{},
+	}
+	for _, round := range rounds {
+		h.add(round)
+	}
+	return h
+}
+
+func (h *mixerHistory) add(round MixerRound) {
+	for _, court := range round.Courts {
+		h.partners[pairKey(court.TeamA[0], court.TeamA[1])]++
+		h.partners[pairKey(court.TeamB[0], court.TeamB[1])]++
+		for _, a := range court.TeamA {
+			for _, b := range court.TeamB {
+				h.opponents[pairKey(a, b)]++
+			}
+		}
+	}
+	for _, id := range round.SittingOut {
+		h.sitOuts[id]++
+	}
+}
+
+func pairKey(a, b uuid.UUID) [2]uuid.UUID {
+	if strings.Compare(a.String(), b.String()) > 0 {
+		a, b = b, a
+	}
+	return [2]uuid.UUID{a, b}
+}
+
+// GenerateRounds builds rounds numbered from len(previous)+1 up to
+// m.Rounds for m.Players, taking the partners and opponents of previous
+// rounds into account. Players who have sat out least sit out first.
+func (m *Mixer) GenerateRounds(previous []MixerRound) ([]MixerRound, error) {
+	if m.Courts < 1 {
+		return nil, fmt.Errorf("a mixer needs at least one court")
+	}
+	if len(m.Players) < 4 {
+		return nil, fmt.Errorf("a mixer needs at least 4 players")
+	}
+
+	players := map[uuid.UUID]MixerPlayer{}
+	for _, p := range m.Players {
+		players[p.UserID] = p
+	}
+
+	history := newMixerHistory(previous)
+	var rounds []MixerRound
+	for r := len(previous) + 1; r <= m.Rounds; r++ {
+		active, sittingOut, err := m.chooseActive(history, r)
+		if err != nil {
+			return nil, err
+		}
+		round := MixerRound{Round: r, SittingOut: sittingOut}
+		for i, group := range m.groupCourts(active, players, history) {
+			court := bestSplit(group, players, history, m.MixedDoubles)
+			court.Court = i + 1
+			round.Courts = append(round.Courts, court)
+		}
+		history.add(round)
+		rounds = append(rounds, round)
+	}
+	return rounds, nil
+}
+
+// chooseActive decides who plays this round. For mixed doubles each court
+// needs two men and two women.
+func (m *Mixer) chooseActive(history *mixerHistory, round int) (active []uuid.UUID, sittingOut []uuid.UUID, err error) {
+	// Rotate the starting point each round so ties do not always favour the same players
+	ordered := make([]MixerPlayer, len(m.Players))
+	for i := range m.Players {
+		ordered[i] = m.Players[(i+round)%len(m.Players)]
+	}
+	// Those who have sat out most play first
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return history.sitOuts[ordered[i].UserID] > history.sitOuts[ordered[j].UserID]
+	})
+
+	if !m.MixedDoubles {
+		courts := m.Courts
+		if len(ordered)/4 < courts {
+			courts = len(ordered) / 4
+		}
+		for i, p := range ordered {
+			if i < courts*4 {
+				active = append(active, p.UserID)
+			} else {
+				sittingOut = append(sittingOut, p.UserID)
+			}
+		}
+		return active, sittingOut, nil
+	}
+
+	var men, women, others []MixerPlayer
+	for _, p := range ordered {
+		switch {
+		case isMale(p.Gender):
+			men = append(men, p)
+		case isFemale(p.Gender):
+			women = append(women, p)
+		default:
+			others = append(others, p)
+		}
+	}
+	courts := m.Courts
+	if len(men)/2 < courts {
+		courts = len(men) / 2
+	}
+	if len(women)/2 < courts {
+		courts = len(women) / 2
+	}
+	if courts == 0 {
+		return nil, nil, fmt.Errorf("mixed doubles needs at least 2 men and 2 women")
+	}
+	for i, p := range men {
+		if i < courts*2 {
+			active = append(active, p.UserID)
+		} else {
+			sittingOut = append(sittingOut, p.UserID)
+		}
+	}
+	for i, p := range women {
+		if i < courts*2 {
+			active = append(active, p.UserID)
+		} else {
+			sittingOut = append(sittingOut, p.UserID)
+		}
+	}
+	for _, p := range others {
+		sittingOut = append(sittingOut, p.UserID)
+	}
+	return active, sittingOut, nil
+}
+
+// groupCourts splits the active players into groups of four. Players start
+// grouped by skill so each court is competitive, then pairs of players are
+// swapped between courts while that lowers the total cost.
+func (m *Mixer) groupCourts(active []uuid.UUID, players map[uuid.UUID]MixerPlayer, history *mixerHistory) [][]uuid.UUID {
+	sorted := append([]uuid.UUID(nil), active...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return players[sorted[i]].SkillLevel > players[sorted[j]].SkillLevel
+	})
+
+	courts := len(sorted) / 4
+	groups := make([][]uuid.UUID, courts)
+	if m.MixedDoubles {
+		// Fill each court with the next two men and next two women by skill
+		var men, women []uuid.UUID
+		for _, id := range sorted {
+			if isMale(players[id].Gender) {
+				men = append(men, id)
+			} else {
+				women = append(women, id)
+			}
+		}
+		for c := range groups {
+			groups[c] = []uuid.UUID{men[2*c], men[2*c+1], women[2*c], women[2*c+1]}
+		}
+	} else {
+		for c := range groups {
+			groups[c] = sorted[4*c : 4*c+4]
+		}
+	}
+
+	courtCost := func(group []uuid.UUID) float64 {
+		return bestSplit(group, players, history, m.MixedDoubles).cost(history, players)
+	}
+
+	for improved := true; improved; {
+		improved = false
+		for a := 0; a < courts; a++ {
+			for b := a + 1; b < courts; b++ {
+				for i := 0; i < 4; i++ {
+					for j := 0; j < 4; j++ {
+						if m.MixedDoubles && isMale(players[groups[a][i]].Gender) != isMale(players[groups[b][j]].Gender) {
+							continue
+						}
+						before := courtCost(groups[a]) + courtCost(groups[b])
+						groups[a][i], groups[b][j] = groups[b][j], groups[a][i]
+						if after := courtCost(groups[a]) + courtCost(groups[b]); after < before-1e-9 {
+							improved = true
+						} else {
+							groups[a][i], groups[b][j] = groups[b][j], groups[a][i]
+						}
+					}
+				}
+			}
+		}
+	}
+	return groups
+}
+
+// bestSplit picks the cheapest of the three ways to split four players into
+// two teams. In mixed doubles each team must have one man and one woman.
+func bestSplit(group []uuid.UUID, players map[uuid.UUID]MixerPlayer, history *mixerHistory, mixed bool) MixerCourt {
+	splits := [3][4]int{{0, 1, 2, 3}, {0, 2, 1, 3}, {0, 3, 1, 2}}
+	var best MixerCourt
+	bestCost := math.Inf(1)
+	for _, s := range splits {
+		court := MixerCourt{
+			TeamA: [2]uuid.UUID{group[s[0]], group[s[1]]},
+			TeamB: [2]uuid.UUID{group[s[2]], group[s[3]]},
+		}
+		if mixed && (isMale(players[court.TeamA[0]].Gender) == isMale(players[court.TeamA[1]].Gender)) {
+			continue
+		}
+		if cost := court.cost(history, players); cost < bestCost {
+			best, bestCost = court, cost
+		}
+	}
+	best.SkillGap = math.Abs(teamSkill(best.TeamA, players) - teamSkill(best.TeamB, players))
+	return best
+}
+
+// cost scores a court by repeated partners, repeated opponents and the skill gap between teams
+func (c MixerCourt) cost(history *mixerHistory, players map[uuid.UUID]MixerPlayer) float64 {
+	cost := mixerRepeatPartnerCost * float64(history.partners[pairKey(c.TeamA[0], c.TeamA[1])]+history.partners[pairKey(c.TeamB[0], c.TeamB[1])])
+	for _, a := range c.TeamA {
+		for _, b := range c.TeamB {
+			cost += mixerRepeatOpponentCost * float64(history.opponents[pairKey(a, b)])
+		}
+	}
+	return cost + mixerSkillGapCost*math.Abs(teamSkill(c.TeamA, players)-teamSkill(c.TeamB, players))
+}
+
+func teamSkill(team [2]uuid.UUID, players map[uuid.UUID]MixerPlayer) float64 {
+	return players[team[0]].SkillLevel + players[team[1]].SkillLevel
+}
+
+func isMale(gender string) bool {
+	switch strings.ToLower(gender) {
+	case "male", "m", "man":
+		return true
+	}
+	return false
+}
+
+func isFemale(gender string) bool {
+	switch strings.ToLower(gender) {
+	case "female", "f", "woman":
+		return true
+	}
+	return false
+}
+
+// RepeatCounts returns how many times any pair partnered or opposed more than once across the schedule
+func (m *Mixer) RepeatCounts() (partnerRepeats, opponentRepeats int) {
+	history := newMixerHistory(m.Schedule)
+	for _, n := range history.partners {
+		if n > 1 {
+			partnerRepeats += n - 1
+		}
+	}
+	for _, n := range history.opponents {
+		if n > 1 {
+			opponentRepeats += n - 1
+		}
+	}
+	return partnerRepeats, opponentRepeats
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMixerPlayers(count int, gender func(i int) string) []MixerPlayer {
+	players := make([]MixerPlayer, count)
+	for i := range players {
+		players[i] = MixerPlayer{UserID: uuid.New(), SkillLevel: 3.0 + float64(i%4)*0.5, Gender: gender(i)}
+	}
+	return players
+}
+
+func TestMixer_GenerateRounds(t *testing.T) {
+	mixer := &Mixer{Courts: 2, Rounds: 3, Players: newTestMixerPlayers(8, func(int) string { return "" })}
+
+	rounds, err := mixer.GenerateRounds(nil)
+	require.NoError(t, err)
+	require.Len(t, rounds, 3)
+	mixer.Schedule = rounds
+
+	for _, round := range rounds {
+		assert.Len(t, round.Courts, 2)
+		assert.Empty(t, round.SittingOut)
+		seen := map[uuid.UUID]bool{}
+		for _, court := range round.Courts {
+			for _, id := range append(court.TeamA[:], court.TeamB[:]...) {
+				assert.False(t, seen[id], "player on two courts in one round")
+				seen[id] = true
+			}
+		}
+	}
+
+	// 8 players over 3 rounds can always avoid repeating a partner
+	partnerRepeats, _ := mixer.RepeatCounts()
+	assert.Equal(t, 0, partnerRepeats)
+}
+
+func TestMixer_SitOutsRotate(t *testing.T) {
+	mixer := &Mixer{Courts: 2, Rounds: 5, Players: newTestMixerPlayers(10, func(int) string { return "" })}
+
+	rounds, err := mixer.GenerateRounds(nil)
+	require.NoError(t, err)
+
+	sitOuts := map[uuid.UUID]int{}
+	for _, round := range rounds {
+		assert.Len(t, round.SittingOut, 2)
+		for _, id := range round.SittingOut {
+			sitOuts[id]++
+		}
+	}
+	// 10 sit-outs shared by 10 players
+	for _, p := range mixer.Players {
+		assert.Equal(t, 1, sitOuts[p.UserID])
+	}
+}
+
+func TestMixer_MixedDoubles(t *testing.T) {
+	gender := func(i int) string {
+		if i%2 == 0 {
+			return "Male"
+		}
+		return "Female"
+	}
+	players := newTestMixerPlayers(8, gender)
+	genders := map[uuid.UUID]string{}
+	for _, p := range players {
+		genders[p.UserID] = p.Gender
+	}
+	mixer := &Mixer{Courts: 2, Rounds: 3, MixedDoubles: true, Players: players}
+
+	rounds, err := mixer.GenerateRounds(nil)
+	require.NoError(t, err)
+	for _, round := range rounds {
+		for _, court := range round.Courts {
+			assert.NotEqual(t, genders[court.TeamA[0]], genders[court.TeamA[1]])
+			assert.NotEqual(t, genders[court.TeamB[0]], genders[court.TeamB[1]])
+		}
+	}
+
+	mixer.Players = newTestMixerPlayers(4, func(int) string { return "Female" })
+	_, err = mixer.GenerateRounds(nil)
+	assert.Error(t, err)
+}
+
+func TestMixer_RegenerateKeepsHistory(t *testing.T) {
+	mixer := &Mixer{Courts: 1, Rounds: 3, Players: newTestMixerPlayers(4, func(int) string { return "" })}
+	first, err := mixer.GenerateRounds(nil)
+	require.NoError(t, err)
+
+	// A late arrival joins after round 1
+	mixer.Players = append(mixer.Players, MixerPlayer{UserID: uuid.New(), SkillLevel: 3.5})
+	rest, err := mixer.GenerateRounds(first[:1])
+	require.NoError(t, err)
+	require.Len(t, rest, 2)
+	assert.Equal(t, 2, rest[0].Round)
+	assert.Len(t, rest[0].SittingOut, 1)
+
+	// Round 1 partnerships are not repeated in round 2
+	played := first[0].Courts[0]
+	next := rest[0].Courts[0]
+	assert.NotEqual(t, pairKey(played.TeamA[0], played.TeamA[1]), pairKey(next.TeamA[0], next.TeamA[1]))
+	assert.NotEqual(t, pairKey(played.TeamA[0], played.TeamA[1]), pairKey(next.TeamB[0], next.TeamB[1]))
+}
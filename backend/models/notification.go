@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification types
+const (
+	NotificationTypeEventUpdated           = "event_updated"
+	NotificationTypeEventCancelled         = "event_cancelled"
+	NotificationTypeWaitlistPromoted       = "waitlist_promoted"
+	NotificationTypeWaitlistDemoted        = "waitlist_demoted"
+	NotificationTypeTournamentDraw         = "tournament_draw"
+	NotificationTypeTournamentMatchReady   = "tournament_match_ready"
+	NotificationTypeLeagueScheduled        = "league_scheduled"
+	NotificationTypeLeagueResult           = "league_result"
+	NotificationTypeLadderChallenge        = "ladder_challenge"
+	NotificationTypeNoShow                 = "no_show"
+	NotificationTypeCourtApproved          = "court_approved"
+	NotificationTypeCourtRejected          = "court_rejected"
+	NotificationTypeCourtClosure           = "court_closure"
+	NotificationTypeConditionFlagged       = "court_condition_flagged"
+	NotificationTypeReviewApproved         = "court_review_approved"
+	NotificationTypeReviewRejected         = "court_review_rejected"
+	NotificationTypeBookingInvite          = "booking_invite"
+	NotificationTypeBookingRoster          = "booking_roster"
+	NotificationTypeBookingWaitlistOffer   = "booking_waitlist_offer"
+	NotificationTypeBookingRequest         = "booking_request"
+	NotificationTypeBookingApproved        = "booking_approved"
+	NotificationTypeBookingRejected        = "booking_rejected"
+	NotificationTypeBookingExpired         = "booking_expired"
+	NotificationTypeFavoriteCourtBulletin  = "favorite_court_bulletin"
+	NotificationTypeFavoriteCourtEvent     = "favorite_court_event"
+	NotificationTypeFavoriteCourtCheckIn   = "favorite_court_check_in"
+	NotificationTypeFavoriteCourtCondition = "favorite_court_condition"
+)
+
+// Notification represents an in-app message delivered to a user
+type Notification struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Type        string     `json:"type"`
+	Title       string     `json:"title"`
+	Message     string     `json:"message,omitempty"`
+	ReferenceID *uuid.UUID `json:"reference_id,omitempty"` // ID of the event, booking, etc. the notification is about
+	IsRead      bool       `json:"is_read"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
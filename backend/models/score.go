@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SetScore represents the games won by each side in one set
+type SetScore struct {
+	Player1Games int `json:"player1_games"`
+	Player2Games int `json:"player2_games"`
+}
+
+// MatchScore is a parsed match score, always from player 1's perspective
+type MatchScore []SetScore
+
+// ParseScore parses a score such as "6-4 3-6 10-8" or "7-6(5), 6-2".
+// Tiebreak points in parentheses are ignored.
+func ParseScore(score string) (MatchScore, error) {
+	fields := strings.FieldsFunc(score, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("score is empty")
+	}
+
+	sets := make(MatchScore, 0, len(fields))
+	for _, field := range fields {
+		if i := strings.Index(field, "("); i >= 0 {
+			field = field[:i]
+		}
+		parts := strings.Split(field, "-")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid set score %q", field)
+		}
+		p1, err := strconv.Atoi(parts[0])
+		if err != nil || p1 < 0 {
+			return nil, fmt.Errorf("invalid set score %q", field)
+		}
+		p2, err := strconv.Atoi(parts[1])
+		if err != nil || p2 < 0 {
+			return nil, fmt.Errorf("invalid set score %q", field)
+		}
+		if p1 == p2 {
+			return nil, fmt.Errorf("set score %q has no winner", field)
+		}
+		sets = append(sets, SetScore{Player1Games: p1, Player2Games: p2})
+	}
+	return sets, nil
+}
+
+// SetsWon returns the number of sets won by each side
+func (s MatchScore) SetsWon() (player1, player2 int) {
+	for _, set := range s {
+		if set.Player1Games > set.Player2Games {
+			player1++
+		} else {
+			player2++
+		}
+	}
+	return player1, player2
+}
+
+// GamesWon returns the number of games won by each side
+func (s MatchScore) GamesWon() (player1, player2 int) {
+	for _, set := range s {
+		player1 += set.Player1Games
+		player2 += set.Player2Games
+	}
+	return player1, player2
+}
+
+// Player1Won returns true if player 1 won more sets than player 2
+func (s MatchScore) Player1Won() bool {
+	p1, p2 := s.SetsWon()
+	return p1 > p2
+}
+
+// Reversed returns the score from player 2's perspective
+func (s MatchScore) Reversed() MatchScore {
+	reversed := make(MatchScore, len(s))
+	for i, set := range s {
+		reversed[i] = SetScore{Player1Games: set.Player2Games, Player2Games: set.Player1Games}
+	}
+	return reversed
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScore(t *testing.T) {
+	score, err := ParseScore("7-6(5), 3-6 10-8")
+	require.NoError(t, err)
+	assert.Equal(t, MatchScore{{7, 6}, {3, 6}, {10, 8}}, score)
+
+	p1Sets, p2Sets := score.SetsWon()
+	assert.Equal(t, 2, p1Sets)
+	assert.Equal(t, 1, p2Sets)
+	p1Games, p2Games := score.GamesWon()
+	assert.Equal(t, 20, p1Games)
+	assert.Equal(t, 20, p2Games)
+	assert.True(t, score.Player1Won())
+	assert.False(t, score.Reversed().Player1Won())
+
+	for _, invalid := range []string{"", "6-4 6", "6-x", "6-6", "-1-6"} {
+		_, err := ParseScore(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TimeRange represents a half-open interval [Start, End)
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Overlaps returns true if the two ranges share any time
+func (r TimeRange) Overlaps(other TimeRange) bool {
+	return r.Start.Before(other.End) && other.Start.Before(r.End)
+}
+
+// Contains returns true if the other range lies entirely within this one
+func (r TimeRange) Contains(other TimeRange) bool {
+	return !other.Start.Before(r.Start) && !other.End.After(r.End)
+}
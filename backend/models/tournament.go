@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tournament formats
+const (
+	TournamentFormatSingleElimination = "single_elimination"
+	TournamentFormatDoubleElimination = "double_elimination"
+	TournamentFormatRoundRobin        = "round_robin"
+	TournamentFormatCompass           = "compass"
+)
+
+// Seeding methods
+const (
+	SeedingMethodNTRP   = "ntrp"   // Seed by the player's NTRP skill level
+	SeedingMethodRating = "rating" // Seed by a rating supplied by the host (UTR, club ladder, etc.)
+	SeedingMethodManual = "manual" // Seeds supplied by the host
+	SeedingMethodRandom = "random" // Random draw
+)
+
+// Tournament statuses
+const (
+	TournamentStatusInProgress = "in_progress"
+	TournamentStatusCompleted  = "completed"
+)
+
+// Tournament match statuses
+const (
+	MatchStatusPending   = "pending"   // Waiting for one or both players
+	MatchStatusReady     = "ready"     // Both players known, waiting for a result
+	MatchStatusCompleted = "completed" // Result recorded
+	MatchStatusBye       = "bye"       // Decided without play because a slot is empty
+	MatchStatusSkipped   = "skipped"   // Not needed, e.g. an unused grand final reset
+)
+
+// Bracket names
+const (
+	BracketMain       = "Main"
+	BracketWinners    = "Winners"
+	BracketLosers     = "Losers"
+	BracketFinal      = "Final"
+	BracketRoundRobin = "Round Robin"
+	BracketEast       = "East"
+)
+
+// Tournament is a draw played as part of an event
+type Tournament struct {
+	ID            uuid.UUID           `json:"id"`
+	EventID       uuid.UUID           `json:"event_id"`
+	Format        string              `json:"format"`
+	SeedingMethod string              `json:"seeding_method"`
+	Status        string              `json:"status"`
+	MatchDuration int                 `json:"match_duration"` // Minutes reserved per match
+	CourtCount    int                 `json:"court_count"`    // Courts played in parallel
+	ChampionID    *uuid.UUID          `json:"champion_id,omitempty"`
+	Entrants      []TournamentEntrant `json:"entrants"`
+	Matches       []TournamentMatch   `json:"matches"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+// TournamentEntrant is a seeded player in a tournament
+type TournamentEntrant struct {
+	ID           uuid.UUID `json:"id"`
+	TournamentID uuid.UUID `json:"tournament_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	UserName     string    `json:"user_name"`
+	Seed         int       `json:"seed"`
+	Rating       float64   `json:"rating"` // Value used for seeding
+}
+
+// TournamentMatch is a single match in a draw. Winners (and, in double
+// elimination and compass draws, losers) are routed to later matches through
+// the Next/LoserNext links.
+type TournamentMatch struct {
+	ID                 uuid.UUID  `json:"id"`
+	TournamentID       uuid.UUID  `json:"tournament_id"`
+	Bracket            string     `json:"bracket"`
+	Round              int        `json:"round"`
+	Position           int        `json:"position"`
+	Player1ID          *uuid.UUID `json:"player1_id,omitempty"`
+	Player2ID          *uuid.UUID `json:"player2_id,omitempty"`
+	Player1Bye         bool       `json:"player1_bye"` // Slot will never be filled
+	Player2Bye         bool       `json:"player2_bye"`
+	WinnerID           *uuid.UUID `json:"winner_id,omitempty"`
+	Score              string     `json:"score,omitempty"`
+	Status             string     `json:"status"`
+	ScheduledAt        *time.Time `json:"scheduled_at,omitempty"`
+	CourtNumber        int        `json:"court_number,omitempty"`
+	NextMatchID        *uuid.UUID `json:"next_match_id,omitempty"`
+	NextMatchSlot      int        `json:"next_match_slot,omitempty"`
+	LoserNextMatchID   *uuid.UUID `json:"loser_next_match_id,omitempty"`
+	LoserNextMatchSlot int        `json:"loser_next_match_slot,omitempty"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+}
+
+// IsDecided returns true if the match needs no further play
+func (m *TournamentMatch) IsDecided() bool {
+	return m.Status == MatchStatusCompleted || m.Status == MatchStatusBye || m.Status == MatchStatusSkipped
+}
+
+// HasPlayer returns true if the user is one of the two players
+func (m *TournamentMatch) HasPlayer(userID uuid.UUID) bool {
+	return (m.Player1ID != nil && *m.Player1ID == userID) || (m.Player2ID != nil && *m.Player2ID == userID)
+}
+
+// TournamentRequest represents a request to create a tournament for an event
+type TournamentRequest struct {
+	Format        string                `json:"format" binding:"required"`
+	SeedingMethod string                `json:"seeding_method"`    // Defaults to ntrp
+	MatchDuration int                   `json:"match_duration"`    // Defaults to 90 minutes
+	CourtCount    int                   `json:"court_count"`       // Defaults to 1
+	Ratings       map[uuid.UUID]float64 `json:"ratings,omitempty"` // For rating seeding
+	Seeds         map[uuid.UUID]int     `json:"seeds,omitempty"`   // For manual seeding
+}
+
+// MatchResultRequest represents a reported match result
+type MatchResultRequest struct {
+	WinnerID uuid.UUID `json:"winner_id" binding:"required"`
+	Score    string    `json:"score"` // From player 1's perspective, e.g. "6-4 3-6 10-8"
+}
+
+// BracketRound groups the matches of one round for display
+type BracketRound struct {
+	Round   int               `json:"round"`
+	Matches []TournamentMatch `json:"matches"`
+}
+
+// BracketView groups the matches of one bracket for display
+type BracketView struct {
+	Name   string         `json:"name"`
+	Rounds []BracketRound `json:"rounds"`
+}
+
+// Standing is a player's record in a round robin
+type Standing struct {
+	UserID    uuid.UUID `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	Seed      int       `json:"seed"`
+	Played    int       `json:"played"`
+	Wins      int       `json:"wins"`
+	Losses    int       `json:"losses"`
+	SetsWon   int       `json:"sets_won"`
+	SetsLost  int       `json:"sets_lost"`
+	GamesWon  int       `json:"games_won"`
+	GamesLost int       `json:"games_lost"`
+	Rank      int       `json:"rank"`
+}
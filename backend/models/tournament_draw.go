@@ -0,0 +1,689 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// compassNames names the consolation brackets of a compass draw. Losers of
+// round N of a bracket move to the bracket listed under N.
+var compassNames = map[string]map[int]string{
+	"East":  {1: "West", 2: "North", 3: "Northeast"},
+	"West":  {1: "South", 2: "Southeast"},
+	"North": {1: "Northwest"},
+	"South": {1: "Southwest"},
+}
+
+// SeedEntrants orders the entrants according to the seeding method and
+// assigns seeds starting at 1. For NTRP and rating seeding the Rating field
+// must already be populated; ties keep the original (RSVP) order.
+func SeedEntrants(entrants []TournamentEntrant, method string) error {
+	switch method {
+	case SeedingMethodNTRP, SeedingMethodRating:
+		sort.SliceStable(entrants, func(i, j int) bool {
+			return entrants[i].Rating > entrants[j].Rating
+		})
+	case SeedingMethodManual:
+		// Unseeded entrants go to the back
+		sort.SliceStable(entrants, func(i, j int) bool {
+			if entrants[i].Seed == 0 || entrants[j].Seed == 0 {
+				return entrants[j].Seed == 0 && entrants[i].Seed != 0
+			}
+			return entrants[i].Seed < entrants[j].Seed
+		})
+	case SeedingMethodRandom:
+		rand.Shuffle(len(entrants), func(i, j int) {
+			entrants[i], entrants[j] = entrants[j], entrants[i]
+		})
+	default:
+		return fmt.Errorf("unknown seeding method: %s", method)
+	}
+
+	for i := range entrants {
+		entrants[i].Seed = i + 1
+	}
+	return nil
+}
+
+// GenerateDraw builds the matches for the tournament's format from its seeded
+// entrants. Byes are given to the top seeds and resolved immediately.
+func (t *Tournament) GenerateDraw() error {
+	if len(t.Entrants) < 2 {
+		return fmt.Errorf("a tournament needs at least 2 entrants")
+	}
+
+	b := &drawBuilder{tournament: t}
+	switch t.Format {
+	case TournamentFormatSingleElimination:
+		size := nextPowerOfTwo(len(t.Entrants))
+		b.placeSeeds(b.knockout(BracketMain, size)[0], size)
+	case TournamentFormatDoubleElimination:
+		if len(t.Entrants) < 3 {
+			return fmt.Errorf("double elimination needs at least 3 entrants")
+		}
+		b.doubleElimination(nextPowerOfTwo(len(t.Entrants)))
+	case TournamentFormatCompass:
+		size := nextPowerOfTwo(len(t.Entrants))
+		b.placeSeeds(b.compass(BracketEast, size)[0], size)
+	case TournamentFormatRoundRobin:
+		b.roundRobin()
+	default:
+		return fmt.Errorf("unknown tournament format: %s", t.Format)
+	}
+
+	t.Matches = make([]TournamentMatch, len(b.matches))
+	for i, m := range b.matches {
+		t.Matches[i] = *m
+	}
+
+	// Resolve first-round byes now that the matches live in t.Matches
+	index := t.matchIndex()
+	for i := range t.Matches {
+		t.resolve(index, &t.Matches[i])
+	}
+	t.Status = TournamentStatusInProgress
+	t.updateStatus()
+	return nil
+}
+
+// RecordResult stores the result of a ready match and routes the winner (and
+// loser, where the format has consolation play) to their next matches.
+func (t *Tournament) RecordResult(matchID, winnerID uuid.UUID, score string) error {
+	index := t.matchIndex()
+	m, ok := index[matchID]
+	if !ok {
+		return fmt.Errorf("match not found")
+	}
+	if m.Status != MatchStatusReady {
+		return fmt.Errorf("match is not ready for a result (status: %s)", m.Status)
+	}
+	if !m.HasPlayer(winnerID) {
+		return fmt.Errorf("winner must be one of the match players")
+	}
+	if score != "" {
+		parsed, err := ParseScore(score)
+		if err != nil {
+			return err
+		}
+		if parsed.Player1Won() != (*m.Player1ID == winnerID) {
+			return fmt.Errorf("score does not match the winner")
+		}
+	}
+
+	now := time.Now()
+	winner := winnerID
+	loser := *m.Player1ID
+	if loser == winnerID {
+		loser = *m.Player2ID
+	}
+	m.WinnerID = &winner
+	m.Score = score
+	m.Status = MatchStatusCompleted
+	m.CompletedAt = &now
+
+	if isGrandFinal(m) {
+		reset := index[*m.NextMatchID]
+		if winnerID == *m.Player1ID {
+			// The winners-bracket champion is undefeated, so no reset is needed
+			reset.Status = MatchStatusSkipped
+		} else {
+			t.place(index, reset, 1, m.Player1ID)
+			t.place(index, reset, 2, m.Player2ID)
+		}
+	} else {
+		t.advance(index, m, &winner, &loser)
+	}
+
+	t.updateStatus()
+	return nil
+}
+
+// Standings returns the round robin table ordered by wins, then set
+// difference, then game difference, then seed
+func (t *Tournament) Standings() []Standing {
+	byUser := map[uuid.UUID]*Standing{}
+	standings := make([]*Standing, 0, len(t.Entrants))
+	for _, e := range t.Entrants {
+		s := &Standing{UserID: e.UserID, UserName: e.UserName, Seed: e.Seed}
+		byUser[e.UserID] = s
+		standings = append(standings, s)
+	}
+
+	for _, m := range t.Matches {
+		if m.Status != MatchStatusCompleted || m.Player1ID == nil || m.Player2ID == nil {
+			continue
+		}
+		p1, p2 := byUser[*m.Player1ID], byUser[*m.Player2ID]
+		if p1 == nil || p2 == nil {
+			continue
+		}
+		p1.Played++
+		p2.Played++
+		if *m.WinnerID == p1.UserID {
+			p1.Wins++
+			p2.Losses++
+		} else {
+			p2.Wins++
+			p1.Losses++
+		}
+		if parsed, err := ParseScore(m.Score); err == nil {
+			s1, s2 := parsed.SetsWon()
+			g1, g2 := parsed.GamesWon()
+			p1.SetsWon += s1
+			p1.SetsLost += s2
+			p2.SetsWon += s2
+			p2.SetsLost += s1
+			p1.GamesWon += g1
+			p1.GamesLost += g2
+			p2.GamesWon += g2
+			p2.GamesLost += g1
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.SetsWon-a.SetsLost != b.SetsWon-b.SetsLost {
+			return a.SetsWon-a.SetsLost > b.SetsWon-b.SetsLost
+		}
+		if a.GamesWon-a.GamesLost != b.GamesWon-b.GamesLost {
+			return a.GamesWon-a.GamesLost > b.GamesWon-b.GamesLost
+		}
+		return a.Seed < b.Seed
+	})
+
+	result := make([]Standing, len(standings))
+	for i, s := range standings {
+		s.Rank = i + 1
+		result[i] = *s
+	}
+	return result
+}
+
+// Brackets groups the matches by bracket and round for display
+func (t *Tournament) Brackets() []BracketView {
+	var views []BracketView
+	viewIndex := map[string]int{}
+	for _, m := range t.Matches {
+		i, ok := viewIndex[m.Bracket]
+		if !ok {
+			i = len(views)
+			viewIndex[m.Bracket] = i
+			views = append(views, BracketView{Name: m.Bracket})
+		}
+		view := &views[i]
+		for len(view.Rounds) < m.Round {
+			view.Rounds = append(view.Rounds, BracketRound{Round: len(view.Rounds) + 1})
+		}
+		view.Rounds[m.Round-1].Matches = append(view.Rounds[m.Round-1].Matches, m)
+	}
+
+	for _, view := range views {
+		for _, round := range view.Rounds {
+			sort.Slice(round.Matches, func(i, j int) bool {
+				return round.Matches[i].Position < round.Matches[j].Position
+			})
+		}
+	}
+	return views
+}
+
+// Schedule assigns start times and court numbers to undecided matches between
+// start and end, avoiding the blocked ranges. Matches are placed in round
+// order, never before the matches that feed them and never while one of the
+// players is still on court. It returns the number of matches that did not fit.
+func (t *Tournament) Schedule(start, end time.Time, blocked []TimeRange) int {
+	duration := time.Duration(t.MatchDuration) * time.Minute
+	courts := t.CourtCount
+	if courts < 1 {
+		courts = 1
+	}
+
+	feeders := map[uuid.UUID][]uuid.UUID{}
+	for _, m := range t.Matches {
+		if m.NextMatchID != nil {
+			feeders[*m.NextMatchID] = append(feeders[*m.NextMatchID], m.ID)
+		}
+		if m.LoserNextMatchID != nil {
+			feeders[*m.LoserNextMatchID] = append(feeders[*m.LoserNextMatchID], m.ID)
+		}
+	}
+
+	levels := map[uuid.UUID]int{}
+	var level func(id uuid.UUID) int
+	level = func(id uuid.UUID) int {
+		if l, ok := levels[id]; ok {
+			return l
+		}
+		l := 0
+		for _, f := range feeders[id] {
+			if fl := level(f) + 1; fl > l {
+				l = fl
+			}
+		}
+		levels[id] = l
+		return l
+	}
+
+	bracketOrder := map[string]int{}
+	order := make([]*TournamentMatch, len(t.Matches))
+	for i := range t.Matches {
+		m := &t.Matches[i]
+		if _, ok := bracketOrder[m.Bracket]; !ok {
+			bracketOrder[m.Bracket] = len(bracketOrder)
+		}
+		order[i] = m
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if la, lb := level(a.ID)+a.Round, level(b.ID)+b.Round; la != lb {
+			return la < lb
+		}
+		if bracketOrder[a.Bracket] != bracketOrder[b.Bracket] {
+			return bracketOrder[a.Bracket] < bracketOrder[b.Bracket]
+		}
+		return a.Position < b.Position
+	})
+
+	courtFree := make([]time.Time, courts)
+	for i := range courtFree {
+		courtFree[i] = start
+	}
+	playerFree := map[uuid.UUID]time.Time{}
+	finish := map[uuid.UUID]time.Time{}
+	unscheduled := 0
+
+	for _, m := range order {
+		earliest := start
+		for _, f := range feeders[m.ID] {
+			if finish[f].After(earliest) {
+				earliest = finish[f]
+			}
+		}
+
+		if m.IsDecided() {
+			if m.Status == MatchStatusCompleted && m.ScheduledAt != nil {
+				earliest = m.ScheduledAt.Add(duration)
+			}
+			finish[m.ID] = earliest
+			continue
+		}
+
+		for _, p := range []*uuid.UUID{m.Player1ID, m.Player2ID} {
+			if p != nil && playerFree[*p].After(earliest) {
+				earliest = playerFree[*p]
+			}
+		}
+
+		court := 0
+		for i := 1; i < courts; i++ {
+			if courtFree[i].Before(courtFree[court]) {
+				court = i
+			}
+		}
+		slotStart := earliest
+		if courtFree[court].After(slotStart) {
+			slotStart = courtFree[court]
+		}
+		slotStart = nextFreeStart(slotStart, duration, blocked)
+		slotEnd := slotStart.Add(duration)
+
+		if slotEnd.After(end) {
+			m.ScheduledAt = nil
+			m.CourtNumber = 0
+			finish[m.ID] = end
+			unscheduled++
+			continue
+		}
+
+		scheduledAt := slotStart
+		m.ScheduledAt = &scheduledAt
+		m.CourtNumber = court + 1
+		courtFree[court] = slotEnd
+		finish[m.ID] = slotEnd
+		for _, p := range []*uuid.UUID{m.Player1ID, m.Player2ID} {
+			if p != nil {
+				playerFree[*p] = slotEnd
+			}
+		}
+	}
+
+	return unscheduled
+}
+
+// nextFreeStart moves start forward until [start, start+duration) avoids every blocked range
+func nextFreeStart(start time.Time, duration time.Duration, blocked []TimeRange) time.Time {
+	for moved := true; moved; {
+		moved = false
+		for _, b := range blocked {
+			if (TimeRange{Start: start, End: start.Add(duration)}).Overlaps(b) {
+				start = b.End
+				moved = true
+			}
+		}
+	}
+	return start
+}
+
+// matchIndex returns pointers into t.Matches keyed by match ID
+func (t *Tournament) matchIndex() map[uuid.UUID]*TournamentMatch {
+	index := make(map[uuid.UUID]*TournamentMatch, len(t.Matches))
+	for i := range t.Matches {
+		index[t.Matches[i].ID] = &t.Matches[i]
+	}
+	return index
+}
+
+// place puts a player (or a bye, if player is nil) into a match slot and
+// resolves the match if both slots are now known
+func (t *Tournament) place(index map[uuid.UUID]*TournamentMatch, m *TournamentMatch, slot int, player *uuid.UUID) {
+	if slot == 1 {
+		m.Player1ID = player
+		m.Player1Bye = player == nil
+	} else {
+		m.Player2ID = player
+		m.Player2Bye = player == nil
+	}
+	t.resolve(index, m)
+}
+
+// resolve marks a match ready once both slots are known, or decides it
+// straight away if one or both slots are byes
+func (t *Tournament) resolve(index map[uuid.UUID]*TournamentMatch, m *TournamentMatch) {
+	if m.Status != MatchStatusPending {
+		return
+	}
+	if (m.Player1ID == nil && !m.Player1Bye) || (m.Player2ID == nil && !m.Player2Bye) {
+		return
+	}
+
+	switch {
+	case m.Player1ID != nil && m.Player2ID != nil:
+		m.Status = MatchStatusReady
+		return
+	case m.Player1ID != nil:
+		m.WinnerID = m.Player1ID
+	case m.Player2ID != nil:
+		m.WinnerID = m.Player2ID
+	}
+	m.Status = MatchStatusBye
+
+	if isGrandFinal(m) {
+		index[*m.NextMatchID].Status = MatchStatusSkipped
+		return
+	}
+	t.advance(index, m, m.WinnerID, nil)
+}
+
+// advance routes the winner and loser of a decided match
+func (t *Tournament) advance(index map[uuid.UUID]*TournamentMatch, m *TournamentMatch, winner, loser *uuid.UUID) {
+	if m.NextMatchID != nil {
+		t.place(index, index[*m.NextMatchID], m.NextMatchSlot, winner)
+	}
+	if m.LoserNextMatchID != nil {
+		t.place(index, index[*m.LoserNextMatchID], m.LoserNextMatchSlot, loser)
+	}
+}
+
+// updateStatus completes the tournament and records the champion once every match is decided
+func (t *Tournament) updateStatus() {
+	for _, m := range t.Matches {
+		if !m.IsDecided() {
+			return
+		}
+	}
+
+	t.Status = TournamentStatusCompleted
+	t.ChampionID = nil
+	if t.Format == TournamentFormatRoundRobin {
+		if standings := t.Standings(); len(standings) > 0 {
+			champion := standings[0].UserID
+			t.ChampionID = &champion
+		}
+		return
+	}
+
+	// The champion won the last match played in the top bracket
+	topBracket := BracketMain
+	switch t.Format {
+	case TournamentFormatDoubleElimination:
+		topBracket = BracketFinal
+	case TournamentFormatCompass:
+		topBracket = BracketEast
+	}
+	var final *TournamentMatch
+	for i := range t.Matches {
+		m := &t.Matches[i]
+		if m.Bracket == topBracket && m.Status != MatchStatusSkipped && (final == nil || m.Round > final.Round) {
+			final = m
+		}
+	}
+	if final != nil {
+		t.ChampionID = final.WinnerID
+	}
+}
+
+// isGrandFinal returns true for the first double elimination final, whose
+// next match is the bracket reset
+func isGrandFinal(m *TournamentMatch) bool {
+	return m.Bracket == BracketFinal && m.Round == 1 && m.NextMatchID != nil
+}
+
+// drawBuilder accumulates matches while a draw is generated
+type drawBuilder struct {
+	tournament *Tournament
+	matches    []*TournamentMatch
+}
+
+func (b *drawBuilder) newMatch(bracket string, round, position int) *TournamentMatch {
+	m := &TournamentMatch{
+		ID:           uuid.New(),
+		TournamentID: b.tournament.ID,
+		Bracket:      bracket,
+		Round:        round,
+		Position:     position,
+		Status:       MatchStatusPending,
+	}
+	b.matches = append(b.matches, m)
+	return m
+}
+
+// knockout creates a single elimination bracket for size slots (a power of
+// two) with winners linked forward, and returns its matches grouped by round
+func (b *drawBuilder) knockout(bracket string, size int) [][]*TournamentMatch {
+	var rounds [][]*TournamentMatch
+	for round, count := 1, size/2; count >= 1; round, count = round+1, count/2 {
+		matches := make([]*TournamentMatch, count)
+		for i := range matches {
+			matches[i] = b.newMatch(bracket, round, i+1)
+		}
+		if round > 1 {
+			for i, prev := range rounds[round-2] {
+				linkWinner(prev, matches[i/2], i%2+1)
+			}
+		}
+		rounds = append(rounds, matches)
+	}
+	return rounds
+}
+
+// placeSeeds fills a first round with entrants in standard seed positions so
+// that the top seeds meet as late as possible and receive any byes
+func (b *drawBuilder) placeSeeds(firstRound []*TournamentMatch, size int) {
+	entrants := b.tournament.Entrants
+	positions := seedPositions(size)
+	for i, m := range firstRound {
+		for slot, seed := range []int{positions[2*i], positions[2*i+1]} {
+			if seed > len(entrants) {
+				if slot == 0 {
+					m.Player1Bye = true
+				} else {
+					m.Player2Bye = true
+				}
+				continue
+			}
+			userID := entrants[seed-1].UserID
+			if slot == 0 {
+				m.Player1ID = &userID
+			} else {
+				m.Player2ID = &userID
+			}
+		}
+	}
+}
+
+// doubleElimination creates winners and losers brackets plus a grand final
+// with a possible bracket reset
+func (b *drawBuilder) doubleElimination(size int) {
+	winners := b.knockout(BracketWinners, size)
+	b.placeSeeds(winners[0], size)
+
+	// Losers round 2j-1 pairs up survivors; round 2j adds the losers of winners round j+1
+	k := len(winners)
+	losers := make([][]*TournamentMatch, 2*(k-1))
+	for j := 1; j < k; j++ {
+		oddCount := size >> (j + 1)
+		if j == 1 {
+			oddCount = size / 4
+		}
+		losers[2*j-2] = make([]*TournamentMatch, oddCount)
+		for i := range losers[2*j-2] {
+			losers[2*j-2][i] = b.newMatch(BracketLosers, 2*j-1, i+1)
+		}
+		evenCount := size >> (j + 1)
+		losers[2*j-1] = make([]*TournamentMatch, evenCount)
+		for i := range losers[2*j-1] {
+			losers[2*j-1][i] = b.newMatch(BracketLosers, 2*j, i+1)
+		}
+	}
+
+	for i, m := range winners[0] {
+		linkLoser(m, losers[0][i/2], i%2+1)
+	}
+	for j := 1; j < k; j++ {
+		odd, even := losers[2*j-2], losers[2*j-1]
+		for i, m := range odd {
+			linkWinner(m, even[i], 1)
+		}
+		// Cross the incoming losers over to delay rematches
+		for i, m := range winners[j] {
+			linkLoser(m, even[len(even)-1-i], 2)
+		}
+		if j < k-1 {
+			for i, m := range even {
+				linkWinner(m, losers[2*j][i/2], i%2+1)
+			}
+		}
+	}
+
+	final := b.newMatch(BracketFinal, 1, 1)
+	reset := b.newMatch(BracketFinal, 2, 1)
+	linkWinner(winners[k-1][0], final, 1)
+	linkWinner(losers[len(losers)-1][0], final, 2)
+	linkWinner(final, reset, 1)
+}
+
+// compass creates a bracket whose losers in each round except the final
+// drop into their own consolation bracket, recursively
+func (b *drawBuilder) compass(name string, size int) [][]*TournamentMatch {
+	rounds := b.knockout(name, size)
+	for r := 1; r < len(rounds); r++ {
+		consolationName, ok := compassNames[name][r]
+		if !ok {
+			consolationName = fmt.Sprintf("%s Consolation %d", name, r)
+		}
+		consolation := b.compass(consolationName, size>>r)
+		for i, m := range rounds[r-1] {
+			linkLoser(m, consolation[0][i/2], i%2+1)
+		}
+	}
+	return rounds
+}
+
+// roundRobin pairs every entrant with every other using the circle method
+func (b *drawBuilder) roundRobin() {
+	userIDs := make([]uuid.UUID, len(b.tournament.Entrants))
+	for i, e := range b.tournament.Entrants {
+		userIDs[i] = e.UserID
+	}
+
+	for round, pairs := range RoundRobinPairings(userIDs) {
+		for position, pair := range pairs {
+			m := b.newMatch(BracketRoundRobin, round+1, position+1)
+			player1, player2 := pair[0], pair[1]
+			m.Player1ID = &player1
+			m.Player2ID = &player2
+		}
+	}
+}
+
+// RoundRobinPairings schedules every player against every other using the
+// circle method. Each element is one round; with an odd number of players
+// one player sits out each round.
+func RoundRobinPairings(userIDs []uuid.UUID) [][][2]uuid.UUID {
+	players := make([]*uuid.UUID, 0, len(userIDs)+1)
+	for i := range userIDs {
+		players = append(players, &userIDs[i])
+	}
+	if len(players)%2 == 1 {
+		players = append(players, nil) // Whoever meets nil sits out the round
+	}
+
+	n := len(players)
+	var rounds [][][2]uuid.UUID
+	for round := 1; round < n; round++ {
+		var pairs [][2]uuid.UUID
+		for i := 0; i < n/2; i++ {
+			p1, p2 := players[i], players[n-1-i]
+			if p1 == nil || p2 == nil {
+				continue
+			}
+			pairs = append(pairs, [2]uuid.UUID{*p1, *p2})
+		}
+		rounds = append(rounds, pairs)
+
+		// Keep the first player fixed and rotate the rest
+		rotated := append([]*uuid.UUID{players[0], players[n-1]}, players[1:n-1]...)
+		players = rotated
+	}
+	return rounds
+}
+
+func linkWinner(from, to *TournamentMatch, slot int) {
+	from.NextMatchID = &to.ID
+	from.NextMatchSlot = slot
+}
+
+func linkLoser(from, to *TournamentMatch, slot int) {
+	from.LoserNextMatchID = &to.ID
+	from.LoserNextMatchSlot = slot
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
+}
+
+// seedPositions returns the seed occupying each slot of a bracket, e.g.
+// 1, 8, 4, 5, 2, 7, 3, 6 for eight slots
+func seedPositions(size int) []int {
+	positions := []int{1}
+	for len(positions) < size {
+		n := len(positions) * 2
+		next := make([]int, 0, n)
+		for _, seed := range positions {
+			next = append(next, seed, n+1-seed)
+		}
+		positions = next
+	}
+	return positions
+}
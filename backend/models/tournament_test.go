@@ -0,0 +1,297 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTournament(format string, players int) *Tournament {
+	t := &Tournament{ID: uuid.New(), Format: format, MatchDuration: 60, CourtCount: 2}
+	for i := 0; i < players; i++ {
+		t.Entrants = append(t.Entrants, TournamentEntrant{
+			UserID: uuid.New(),
+			Rating: float64(players - i), // Entrant i ends up seed i+1
+		})
+	}
+	SeedEntrants(t.Entrants, SeedingMethodRating)
+	return t
+}
+
+// playOut records results until the tournament completes, with the lower
+// seed always winning
+func playOut(t *testing.T, tournament *Tournament) {
+	seeds := map[uuid.UUID]int{}
+	for _, e := range tournament.Entrants {
+		seeds[e.UserID] = e.Seed
+	}
+	for tournament.Status != TournamentStatusCompleted {
+		var ready *TournamentMatch
+		for i := range tournament.Matches {
+			if tournament.Matches[i].Status == MatchStatusReady {
+				ready = &tournament.Matches[i]
+				break
+			}
+		}
+		require.NotNil(t, ready, "tournament stalled with no ready matches")
+		winner := *ready.Player1ID
+		if seeds[*ready.Player2ID] < seeds[winner] {
+			winner = *ready.Player2ID
+		}
+		require.NoError(t, tournament.RecordResult(ready.ID, winner, ""))
+	}
+}
+
+func TestSeedEntrants(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("rating orders highest first", func(t *testing.T) {
+		entrants := []TournamentEntrant{{UserID: a, Rating: 3.0}, {UserID: b, Rating: 4.5}, {UserID: c, Rating: 3.5}}
+		require.NoError(t, SeedEntrants(entrants, SeedingMethodNTRP))
+		assert.Equal(t, []uuid.UUID{b, c, a}, []uuid.UUID{entrants[0].UserID, entrants[1].UserID, entrants[2].UserID})
+		assert.Equal(t, 1, entrants[0].Seed)
+		assert.Equal(t, 3, entrants[2].Seed)
+	})
+
+	t.Run("manual puts unseeded entrants last", func(t *testing.T) {
+		entrants := []TournamentEntrant{{UserID: a}, {UserID: b, Seed: 2}, {UserID: c, Seed: 1}}
+		require.NoError(t, SeedEntrants(entrants, SeedingMethodManual))
+		assert.Equal(t, []uuid.UUID{c, b, a}, []uuid.UUID{entrants[0].UserID, entrants[1].UserID, entrants[2].UserID})
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		assert.Error(t, SeedEntrants(nil, "coin_toss"))
+	})
+}
+
+func TestSeedPositions(t *testing.T) {
+	assert.Equal(t, []int{1, 8, 4, 5, 2, 7, 3, 6}, seedPositions(8))
+}
+
+func TestTournament_SingleElimination(t *testing.T) {
+	tournament := newTestTournament(TournamentFormatSingleElimination, 6)
+	require.NoError(t, tournament.GenerateDraw())
+
+	// 6 players in an 8-draw: seeds 1 and 2 get byes into round 2
+	assert.Len(t, tournament.Matches, 7)
+	byes := 0
+	for _, m := range tournament.Matches {
+		if m.Status == MatchStatusBye {
+			byes++
+			assert.Contains(t, []uuid.UUID{tournament.Entrants[0].UserID, tournament.Entrants[1].UserID}, *m.WinnerID)
+		}
+	}
+	assert.Equal(t, 2, byes)
+
+	playOut(t, tournament)
+	require.NotNil(t, tournament.ChampionID)
+	assert.Equal(t, tournament.Entrants[0].UserID, *tournament.ChampionID)
+}
+
+func TestTournament_RecordResultValidation(t *testing.T) {
+	tournament := newTestTournament(TournamentFormatSingleElimination, 4)
+	require.NoError(t, tournament.GenerateDraw())
+
+	var ready, pending TournamentMatch
+	for _, m := range tournament.Matches {
+		switch m.Status {
+		case MatchStatusReady:
+			ready = m
+		case MatchStatusPending:
+			pending = m
+		}
+	}
+
+	assert.Error(t, tournament.RecordResult(pending.ID, tournament.Entrants[0].UserID, ""))
+	assert.Error(t, tournament.RecordResult(ready.ID, uuid.New(), ""))
+	// Player 1 wins but the score says player 2 took both sets
+	assert.Error(t, tournament.RecordResult(ready.ID, *ready.Player1ID, "3-6 4-6"))
+	assert.NoError(t, tournament.RecordResult(ready.ID, *ready.Player1ID, "6-3 6-4"))
+	assert.Error(t, tournament.RecordResult(ready.ID, *ready.Player1ID, "6-3 6-4"))
+}
+
+func TestTournament_DoubleElimination(t *testing.T) {
+	t.Run("winners bracket champion wins without reset", func(t *testing.T) {
+		tournament := newTestTournament(TournamentFormatDoubleElimination, 8)
+		require.NoError(t, tournament.GenerateDraw())
+
+		playOut(t, tournament)
+		assert.Equal(t, tournament.Entrants[0].UserID, *tournament.ChampionID)
+		for _, m := range tournament.Matches {
+			if m.Bracket == BracketFinal && m.Round == 2 {
+				assert.Equal(t, MatchStatusSkipped, m.Status)
+			}
+		}
+	})
+
+	t.Run("losers bracket winner forces reset", func(t *testing.T) {
+		tournament := newTestTournament(TournamentFormatDoubleElimination, 3)
+		require.NoError(t, tournament.GenerateDraw())
+		top, second, third := tournament.Entrants[0].UserID, tournament.Entrants[1].UserID, tournament.Entrants[2].UserID
+
+		// Seed 2 beats seed 3, then seed 1 beats seed 2 in the winners final
+		for _, winner := range []uuid.UUID{second, top} {
+			m := findReady(tournament, BracketWinners)
+			require.NotNil(t, m)
+			require.NoError(t, tournament.RecordResult(m.ID, winner, ""))
+		}
+		// Seed 2 drops into the losers bracket to face seed 3 again
+		m := findReady(tournament, BracketLosers)
+		require.NotNil(t, m)
+		assert.True(t, m.HasPlayer(second))
+		assert.True(t, m.HasPlayer(third))
+		require.NoError(t, tournament.RecordResult(m.ID, second, ""))
+
+		final := findReady(tournament, BracketFinal)
+		require.NotNil(t, final)
+		require.NoError(t, tournament.RecordResult(final.ID, second, "4-6 4-6"))
+		assert.Equal(t, TournamentStatusInProgress, tournament.Status)
+
+		reset := findReady(tournament, BracketFinal)
+		require.NotNil(t, reset)
+		assert.Equal(t, 2, reset.Round)
+		require.NoError(t, tournament.RecordResult(reset.ID, second, ""))
+		assert.Equal(t, TournamentStatusCompleted, tournament.Status)
+		assert.Equal(t, second, *tournament.ChampionID)
+	})
+
+	t.Run("needs three entrants", func(t *testing.T) {
+		assert.Error(t, newTestTournament(TournamentFormatDoubleElimination, 2).GenerateDraw())
+	})
+}
+
+func findReady(tournament *Tournament, bracket string) *TournamentMatch {
+	for i := range tournament.Matches {
+		if tournament.Matches[i].Bracket == bracket && tournament.Matches[i].Status == MatchStatusReady {
+			return &tournament.Matches[i]
+		}
+	}
+	return nil
+}
+
+func TestTournament_Compass(t *testing.T) {
+	tournament := newTestTournament(TournamentFormatCompass, 8)
+	require.NoError(t, tournament.GenerateDraw())
+
+	brackets := map[string]int{}
+	for _, m := range tournament.Matches {
+		brackets[m.Bracket]++
+	}
+	// Every player gets three matches in an 8-player compass draw
+	assert.Equal(t, map[string]int{"East": 7, "West": 3, "North": 1, "South": 1}, brackets)
+
+	playOut(t, tournament)
+	assert.Equal(t, tournament.Entrants[0].UserID, *tournament.ChampionID)
+
+	played := map[uuid.UUID]int{}
+	for _, m := range tournament.Matches {
+		played[*m.Player1ID]++
+		played[*m.Player2ID]++
+	}
+	for _, e := range tournament.Entrants {
+		assert.Equal(t, 3, played[e.UserID])
+	}
+}
+
+func TestTournament_RoundRobin(t *testing.T) {
+	tournament := newTestTournament(TournamentFormatRoundRobin, 5)
+	require.NoError(t, tournament.GenerateDraw())
+
+	// Everyone plays everyone once
+	assert.Len(t, tournament.Matches, 10)
+	pairs := map[[2]uuid.UUID]bool{}
+	for _, m := range tournament.Matches {
+		assert.Equal(t, MatchStatusReady, m.Status)
+		a, b := *m.Player1ID, *m.Player2ID
+		if b.String() < a.String() {
+			a, b = b, a
+		}
+		assert.False(t, pairs[[2]uuid.UUID{a, b}], "duplicate pairing")
+		pairs[[2]uuid.UUID{a, b}] = true
+	}
+
+	playOut(t, tournament)
+	standings := tournament.Standings()
+	assert.Equal(t, tournament.Entrants[0].UserID, standings[0].UserID)
+	assert.Equal(t, 4, standings[0].Wins)
+	assert.Equal(t, 0, standings[4].Wins)
+	assert.Equal(t, 5, standings[4].Rank)
+	assert.Equal(t, tournament.Entrants[0].UserID, *tournament.ChampionID)
+}
+
+func TestTournament_StandingsTiebreaks(t *testing.T) {
+	tournament := newTestTournament(TournamentFormatRoundRobin, 3)
+	require.NoError(t, tournament.GenerateDraw())
+	a, b, c := tournament.Entrants[0].UserID, tournament.Entrants[1].UserID, tournament.Entrants[2].UserID
+
+	// Everyone wins once and sets are level, so game difference decides:
+	// a +8, c 0, b -8
+	record := func(winner, loser uuid.UUID, score string) {
+		for _, m := range tournament.Matches {
+			if m.HasPlayer(winner) && m.HasPlayer(loser) {
+				if *m.Player1ID != winner {
+					parsed, err := ParseScore(score)
+					require.NoError(t, err)
+					score = formatTestScore(parsed.Reversed())
+				}
+				require.NoError(t, tournament.RecordResult(m.ID, winner, score))
+				return
+			}
+		}
+		t.Fatalf("no match between players")
+	}
+	record(a, b, "6-0 6-0")
+	record(b, c, "6-4 6-4")
+	record(c, a, "7-5 7-5")
+
+	standings := tournament.Standings()
+	assert.Equal(t, []uuid.UUID{a, c, b}, []uuid.UUID{standings[0].UserID, standings[1].UserID, standings[2].UserID})
+	assert.Equal(t, 22, standings[0].GamesWon)
+	assert.Equal(t, 14, standings[0].GamesLost)
+	assert.Equal(t, 2, standings[2].SetsWon)
+	assert.Equal(t, 2, standings[2].SetsLost)
+}
+
+func formatTestScore(score MatchScore) string {
+	s := ""
+	for i, set := range score {
+		if i > 0 {
+			s += " "
+		}
+		s += fmt.Sprintf("%d-%d", set.Player1Games, set.Player2Games)
+	}
+	return s
+}
+
+func TestTournament_Schedule(t *testing.T) {
+	tournament := newTestTournament(TournamentFormatSingleElimination, 8)
+	tournament.CourtCount = 2
+	require.NoError(t, tournament.GenerateDraw())
+
+	start := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	blocked := []TimeRange{{Start: start.Add(2 * time.Hour), End: start.Add(3 * time.Hour)}}
+	unscheduled := tournament.Schedule(start, start.Add(8*time.Hour), blocked)
+	assert.Equal(t, 0, unscheduled)
+
+	index := tournament.matchIndex()
+	for _, m := range tournament.Matches {
+		require.NotNil(t, m.ScheduledAt)
+		slot := TimeRange{Start: *m.ScheduledAt, End: m.ScheduledAt.Add(time.Hour)}
+		assert.False(t, slot.Overlaps(blocked[0]), "match scheduled in blocked window")
+		if m.NextMatchID != nil {
+			next := index[*m.NextMatchID]
+			assert.False(t, next.ScheduledAt.Before(slot.End), "match scheduled before its feeder finished")
+		}
+	}
+
+	// Quarterfinals fill both courts for two slots, semis resume after the block
+	assert.Equal(t, start.Add(3*time.Hour), *tournament.Matches[4].ScheduledAt)
+
+	t.Run("matches that do not fit are left unscheduled", func(t *testing.T) {
+		assert.Equal(t, 3, tournament.Schedule(start, start.Add(2*time.Hour), nil))
+	})
+}
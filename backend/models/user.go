@@ -20,7 +20,9 @@ type User struct {
 	IsVerified     bool       `json:"is_verified"`
 	IsNewToArea    bool       `json:"is_new_to_area"`
 	Gender         string     `json:"gender,omitempty"` // For safety filters
-	Distance       float64    `json:"distance,omitempty"` // Distance in miles (calculated field, not stored in DB)
+	HomeCourtID    *uuid.UUID `json:"home_court_id,omitempty"`
+	HomeCourt      *HomeCourt `json:"home_court,omitempty"` // Populated from HomeCourtID
+	Distance       float64    `json:"distance,omitempty"` // Distance in the units searched with (calculated field, not stored in DB)
 	CreatedAt      time.Time  `json:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at"`
 }
@@ -0,0 +1,120 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Payment methods understood by FakeProvider. Any other method is accepted.
+const (
+	FakePaymentMethodOK       = "pm_fake_ok"
+	FakePaymentMethodDeclined = "pm_fake_declined"
+)
+
+// FakeProvider is an in-process provider that keeps intents in memory, so
+// the payment flow can be run and tested without a real provider
+type FakeProvider struct {
+	mu      sync.Mutex
+	intents map[string]*Intent
+}
+
+// NewFakeProvider creates an empty FakeProvider
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{intents: make(map[string]*Intent)}
+}
+
+// Name implements Provider
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+// CreateIntent implements Provider
+func (p *FakeProvider) CreateIntent(ctx context.Context, amountCents int, currency, reference string) (*Intent, error) {
+	if amountCents <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := "pi_fake_" + uuid.New().String()
+	intent := &Intent{
+		ID:           id,
+		ClientSecret: id + "_secret_" + reference,
+		AmountCents:  amountCents,
+		Currency:     currency,
+		Status:       IntentRequiresPayment,
+	}
+	p.intents[id] = intent
+	result := *intent
+	return &result, nil
+}
+
+// Confirm implements Provider
+func (p *FakeProvider) Confirm(ctx context.Context, intentID, paymentMethod string) (*Intent, error) {
+	return p.update(intentID, func(intent *Intent) error {
+		if intent.Status != IntentRequiresPayment {
+			return fmt.Errorf("intent cannot be confirmed in status %s", intent.Status)
+		}
+		if paymentMethod == FakePaymentMethodDeclined {
+			return ErrDeclined
+		}
+		intent.Status = IntentAuthorized
+		return nil
+	})
+}
+
+// Capture implements Provider
+func (p *FakeProvider) Capture(ctx context.Context, intentID string) (*Intent, error) {
+	return p.update(intentID, func(intent *Intent) error {
+		if intent.Status != IntentAuthorized {
+			return fmt.Errorf("intent cannot be captured in status %s", intent.Status)
+		}
+		intent.Status = IntentSucceeded
+		return nil
+	})
+}
+
+// Cancel implements Provider
+func (p *FakeProvider) Cancel(ctx context.Context, intentID string) (*Intent, error) {
+	return p.update(intentID, func(intent *Intent) error {
+		if intent.Status == IntentSucceeded {
+			return fmt.Errorf("captured intents must be refunded")
+		}
+		intent.Status = IntentCancelled
+		return nil
+	})
+}
+
+// Refund implements Provider
+func (p *FakeProvider) Refund(ctx context.Context, intentID string, amountCents int) (*Intent, error) {
+	return p.update(intentID, func(intent *Intent) error {
+		if intent.Status != IntentSucceeded {
+			return fmt.Errorf("only captured intents can be refunded")
+		}
+		if amountCents <= 0 || intent.RefundedCents+amountCents > intent.AmountCents {
+			return fmt.Errorf("refund exceeds the amount captured")
+		}
+		intent.RefundedCents += amountCents
+		return nil
+	})
+}
+
+// update applies fn to an intent under the lock, returning a copy of the result
+func (p *FakeProvider) update(intentID string, fn func(*Intent) error) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, fmt.Errorf("intent not found")
+	}
+	if err := fn(intent); err != nil {
+		return nil, err
+	}
+	result := *intent
+	return &result, nil
+}
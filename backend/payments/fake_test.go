@@ -0,0 +1,71 @@
+package payments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProvider_PaymentFlow(t *testing.T) {
+	ctx := context.Background()
+	provider := NewFakeProvider()
+
+	intent, err := provider.CreateIntent(ctx, 3000, "USD", "booking-1")
+	require.NoError(t, err)
+	assert.Equal(t, IntentRequiresPayment, intent.Status)
+	assert.NotEmpty(t, intent.ClientSecret)
+
+	// A declined card leaves the intent open for another attempt
+	_, err = provider.Confirm(ctx, intent.ID, FakePaymentMethodDeclined)
+	assert.ErrorIs(t, err, ErrDeclined)
+
+	_, err = provider.Capture(ctx, intent.ID)
+	assert.Error(t, err, "cannot capture before the payment is authorized")
+
+	intent, err = provider.Confirm(ctx, intent.ID, FakePaymentMethodOK)
+	require.NoError(t, err)
+	assert.Equal(t, IntentAuthorized, intent.Status)
+
+	intent, err = provider.Capture(ctx, intent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, IntentSucceeded, intent.Status)
+
+	intent, err = provider.Refund(ctx, intent.ID, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, intent.RefundedCents)
+
+	_, err = provider.Refund(ctx, intent.ID, 2500)
+	assert.EqualError(t, err, "refund exceeds the amount captured")
+
+	_, err = provider.Cancel(ctx, intent.ID)
+	assert.EqualError(t, err, "captured intents must be refunded")
+}
+
+func TestFakeProvider_Cancel(t *testing.T) {
+	ctx := context.Background()
+	provider := NewFakeProvider()
+
+	intent, err := provider.CreateIntent(ctx, 1500, "USD", "booking-2")
+	require.NoError(t, err)
+
+	intent, err = provider.Cancel(ctx, intent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, IntentCancelled, intent.Status)
+
+	_, err = provider.Confirm(ctx, intent.ID, FakePaymentMethodOK)
+	assert.Error(t, err)
+
+	_, err = provider.Capture(ctx, "pi_missing")
+	assert.EqualError(t, err, "intent not found")
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider("fake")
+	require.NoError(t, err)
+	assert.Equal(t, "fake", provider.Name())
+
+	_, err = NewProvider("acme")
+	assert.EqualError(t, err, `unknown payment provider "acme"`)
+}
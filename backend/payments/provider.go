@@ -0,0 +1,56 @@
+// Package payments talks to the payment provider that charges for court bookings
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Payment intent statuses
+const (
+	IntentRequiresPayment = "requires_payment"
+	IntentAuthorized      = "authorized" // The payment method was accepted and the funds are held
+	IntentSucceeded       = "succeeded"  // The held funds were captured
+	IntentCancelled       = "cancelled"
+)
+
+// ErrDeclined is returned when the provider declines a payment method
+var ErrDeclined = errors.New("payment declined")
+
+// Intent is a provider's record of a single charge
+type Intent struct {
+	ID            string `json:"id"`
+	ClientSecret  string `json:"client_secret"` // Lets the client confirm the intent with the provider directly
+	AmountCents   int    `json:"amount_cents"`
+	Currency      string `json:"currency"`
+	Status        string `json:"status"`
+	RefundedCents int    `json:"refunded_cents"`
+}
+
+// Provider is a payment provider. Charges are made in two steps: Confirm
+// authorizes the player's payment method and Capture collects the funds.
+type Provider interface {
+	// Name identifies the provider in stored payments
+	Name() string
+	// CreateIntent starts a charge; reference ties it to our own records
+	CreateIntent(ctx context.Context, amountCents int, currency, reference string) (*Intent, error)
+	// Confirm authorizes a payment method against an intent, returning ErrDeclined if it is refused
+	Confirm(ctx context.Context, intentID, paymentMethod string) (*Intent, error)
+	// Capture collects the funds of an authorized intent
+	Capture(ctx context.Context, intentID string) (*Intent, error)
+	// Cancel abandons an intent that has not been captured
+	Cancel(ctx context.Context, intentID string) (*Intent, error)
+	// Refund returns part or all of a captured intent
+	Refund(ctx context.Context, intentID string, amountCents int) (*Intent, error)
+}
+
+// NewProvider returns the provider with the given name
+func NewProvider(name string) (Provider, error) {
+	switch name {
+	case "", "fake":
+		return NewFakeProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", name)
+	}
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/database"
+	"github.com/user/tennis-connect/models"
+)
+
+// AttendanceRepository handles database operations related to check-ins and no-shows
+type AttendanceRepository struct {
+	db *database.DB
+}
+
+// NewAttendanceRepository creates a new AttendanceRepository
+func NewAttendanceRepository(db *database.DB) *AttendanceRepository {
+	return &AttendanceRepository{db: db}
+}
+
+// GetSubject loads the event, booking or match session attendance is taken for
+func (r *AttendanceRepository) GetSubject(ctx context.Context, subjectType string, subjectID uuid.UUID) (*models.AttendanceSubject, error) {
+	switch subjectType {
+	case models.AttendanceSubjectEvent:
+		return r.getEventSubject(ctx, subjectID)
+	case models.AttendanceSubjectBooking:
+		return r.getBookingSubject(ctx, subjectID)
+	case models.AttendanceSubjectMatchSession:
+		return r.getMatchSessionSubject(ctx, subjectID)
+	}
+	return nil, fmt.Errorf("invalid subject type: %s", subjectType)
+}
+
+func (r *AttendanceRepository) getEventSubject(ctx context.Context, eventID uuid.UUID) (*models.AttendanceSubject, error) {
+	subject := &models.AttendanceSubject{Type: models.AttendanceSubjectEvent, ID: eventID}
+	var hostID uuid.UUID
+	var status string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT title, latitude, longitude, start_time, end_time, host_id, status
+		FROM events WHERE id = $1
+	`, eventID).Scan(&subject.Title, &subject.Location.Latitude, &subject.Location.Longitude,
+		&subject.StartTime, &subject.EndTime, &hostID, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("event not found")
+		}
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if status == models.EventStatusCancelled {
+		return nil, fmt.Errorf("event has been cancelled")
+	}
+
+	subject.ManagerIDs, err = r.queryUserIDs(ctx, "SELECT user_id FROM event_cohosts WHERE event_id = $1", eventID)
+	if err != nil {
+		return nil, err
+	}
+	subject.ManagerIDs = append([]uuid.UUID{hostID}, subject.ManagerIDs...)
+
+	subject.ParticipantIDs, err = r.queryUserIDs(ctx, `
+		SELECT user_id FROM event_rsvps WHERE event_id = $1 AND status = 'Confirmed' ORDER BY created_at
+	`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return subject, nil
+}
+
+func (r *AttendanceRepository) getBookingSubject(ctx context.Context, bookingID uuid.UUID) (*models.AttendanceSubject, error) {
+	subject := &models.AttendanceSubject{Type: models.AttendanceSubjectBooking, ID: bookingID}
+	var userID uuid.UUID
+	var status models.BookingStatus
+	err := r.db.QueryRowContext(ctx, `
+		SELECT c.name, c.latitude, c.longitude, b.start_time, b.end_time, b.user_id, b.status
+		FROM bookings b
+		JOIN courts c ON b.court_id = c.id
+		WHERE b.id = $1
+	`, bookingID).Scan(&subject.Title, &subject.Location.Latitude, &subject.Location.Longitude,
+		&subject.StartTime, &subject.EndTime, &userID, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("booking not found")
+		}
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if status == models.BookingStatusCancelled {
+		return nil, fmt.Errorf("booking has been cancelled")
+	}
+
+	subject.ManagerIDs = []uuid.UUID{userID}
+	subject.ParticipantIDs = []uuid.UUID{userID}
+	return subject, nil
+}
+
+func (r *AttendanceRepository) getMatchSessionSubject(ctx context.Context, sessionID uuid.UUID) (*models.AttendanceSubject, error) {
+	subject := &models.AttendanceSubject{Type: models.AttendanceSubjectMatchSession, ID: sessionID}
+	var status models.MatchingStatus
+	err := r.db.QueryRowContext(ctx, `
+		SELECT c.name, c.latitude, c.longitude, ms.start_time, ms.end_time, ms.status
+		FROM match_sessions ms
+		JOIN courts c ON ms.court_id = c.id
+		WHERE ms.id = $1
+	`, sessionID).Scan(&subject.Title, &subject.Location.Latitude, &subject.Location.Longitude,
+		&subject.StartTime, &subject.EndTime, &status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("match session not found")
+		}
+		return nil, fmt.Errorf("failed to get match session: %w", err)
+	}
+	if status == models.MatchingStatusCancelled {
+		return nil, fmt.Errorf("match session has been cancelled")
+	}
+
+	// Match sessions have no host, so players can only check themselves in
+	subject.ParticipantIDs, err = r.queryUserIDs(ctx, `
+		SELECT user_id FROM match_players WHERE match_session_id = $1 ORDER BY joined_at
+	`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return subject, nil
+}
+
+func (r *AttendanceRepository) queryUserIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
+
+// GetCheckInCode returns the code players scan to check in, creating one the
+// first time it is requested
+func (r *AttendanceRepository) GetCheckInCode(ctx context.Context, subjectType string, subjectID uuid.UUID) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate check-in code: %w", err)
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO check_in_codes (subject_type, subject_id, code)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (subject_type, subject_id) DO NOTHING
+	`, subjectType, subjectID, hex.EncodeToString(buf))
+	if err != nil {
+		return "", fmt.Errorf("failed to create check-in code: %w", err)
+	}
+
+	var code string
+	err = r.db.QueryRowContext(ctx, `
+		SELECT code FROM check_in_codes WHERE subject_type = $1 AND subject_id = $2
+	`, subjectType, subjectID).Scan(&code)
+	if err != nil {
+		return "", fmt.Errorf("failed to get check-in code: %w", err)
+	}
+	return code, nil
+}
+
+// Record saves a player's attendance, replacing anything recorded earlier
+func (r *AttendanceRepository) Record(ctx context.Context, attendance *models.Attendance) error {
+	now := time.Now()
+	if attendance.ID == uuid.Nil {
+		attendance.ID = uuid.New()
+	}
+	attendance.CreatedAt = now
+	attendance.UpdatedAt = now
+	if attendance.Status == models.AttendanceStatusCheckedIn {
+		attendance.CheckedInAt = &now
+	} else {
+		attendance.CheckedInAt = nil
+		attendance.Method = ""
+	}
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO attendance (
+			id, subject_type, subject_id, user_id, status, method, recorded_by, checked_in_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10)
+		ON CONFLICT (subject_type, subject_id, user_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			method = EXCLUDED.method,
+			recorded_by = EXCLUDED.recorded_by,
+			checked_in_at = EXCLUDED.checked_in_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`,
+		attendance.ID, attendance.SubjectType, attendance.SubjectID, attendance.UserID, attendance.Status,
+		attendance.Method, attendance.RecordedBy, attendance.CheckedInAt, attendance.CreatedAt, attendance.UpdatedAt,
+	).Scan(&attendance.ID, &attendance.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record attendance: %w", err)
+	}
+	return nil
+}
+
+// GetAttendance lists the attendance recorded for an event, booking or match session
+func (r *AttendanceRepository) GetAttendance(ctx context.Context, subjectType string, subjectID uuid.UUID) ([]models.Attendance, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, a.user_id, u.name, a.status, COALESCE(a.method, ''), a.recorded_by,
+			a.checked_in_at, a.created_at, a.updated_at
+		FROM attendance a
+		JOIN users u ON a.user_id = u.id
+		WHERE a.subject_type = $1 AND a.subject_id = $2
+		ORDER BY a.checked_in_at NULLS LAST, u.name
+	`, subjectType, subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query attendance: %w", err)
+	}
+	defer rows.Close()
+
+	records := []models.Attendance{}
+	for rows.Next() {
+		record := models.Attendance{SubjectType: subjectType, SubjectID: subjectID}
+		var recordedBy uuid.NullUUID
+		var checkedInAt sql.NullTime
+		err := rows.Scan(&record.ID, &record.UserID, &record.UserName, &record.Status, &record.Method, &recordedBy,
+			&checkedInAt, &record.CreatedAt, &record.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attendance: %w", err)
+		}
+		if recordedBy.Valid {
+			record.RecordedBy = &recordedBy.UUID
+		}
+		if checkedInAt.Valid {
+			record.CheckedInAt = &checkedInAt.Time
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// GetReliability counts a player's check-ins and no-shows across events,
+// bookings and match sessions
+func (r *AttendanceRepository) GetReliability(ctx context.Context, userID uuid.UUID) (models.Reliability, error) {
+	var attended, noShows int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'checked_in'),
+			COUNT(*) FILTER (WHERE status = 'no_show')
+		FROM attendance WHERE user_id = $1
+	`, userID).Scan(&attended, &noShows)
+	if err != nil {
+		return models.Reliability{}, fmt.Errorf("failed to get reliability: %w", err)
+	}
+	return models.NewReliability(userID, attended, noShows), nil
+}
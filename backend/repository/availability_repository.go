@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/utils"
+)
+
+// ReplaceBusyTimes stores the busy time read from a player's calendar in
+// place of anything imported before
+func (r *CalendarRepository) ReplaceBusyTimes(ctx context.Context, userID uuid.UUID, busy []models.TimeRange, coversUntil time.Time) (*models.Availability, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM user_busy_times WHERE user_id = $1", userID); err != nil {
+		return nil, fmt.Errorf("failed to clear busy times: %w", err)
+	}
+	for _, block := range busy {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_busy_times (user_id, start_time, end_time) VALUES ($1, $2, $3)
+		`, userID, block.Start, block.End)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save busy time: %w", err)
+		}
+	}
+
+	availability := &models.Availability{UserID: userID, Busy: busy}
+	now := time.Now()
+	availability.ImportedAt = &now
+	availability.CoversUntil = &coversUntil
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_calendar_imports (user_id, imported_at, covers_until) VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET imported_at = EXCLUDED.imported_at, covers_until = EXCLUDED.covers_until
+	`, userID, now, coversUntil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record calendar import: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return availability, nil
+}
+
+// GetAvailability retrieves a player's busy time between from and to
+func (r *CalendarRepository) GetAvailability(ctx context.Context, userID uuid.UUID, from, to time.Time) (*models.Availability, error) {
+	availability := &models.Availability{UserID: userID, Busy: []models.TimeRange{}}
+	var importedAt, coversUntil time.Time
+	err := r.db.QueryRowContext(ctx, `
+		SELECT imported_at, covers_until FROM user_calendar_imports WHERE user_id = $1
+	`, userID).Scan(&importedAt, &coversUntil)
+	if err == sql.ErrNoRows {
+		return availability, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar import: %w", err)
+	}
+	availability.ImportedAt = &importedAt
+	availability.CoversUntil = &coversUntil
+
+	busy, err := getBusyTimes(ctx, r.db, []uuid.UUID{userID}, models.TimeRange{Start: from, End: to})
+	if err != nil {
+		return nil, err
+	}
+	availability.Busy = append(availability.Busy, busy[userID]...)
+	return availability, nil
+}
+
+// ClearAvailability deletes a player's imported busy time
+func (r *CalendarRepository) ClearAvailability(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM user_busy_times WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to clear busy times: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM user_calendar_imports WHERE user_id = $1", userID); err != nil {
+		return fmt.Errorf("failed to clear calendar import: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetUserTimezone returns the timezone of a player's home location
+func (r *CalendarRepository) GetUserTimezone(ctx context.Context, userID uuid.UUID) (string, error) {
+	var latitude, longitude float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(latitude, 0), COALESCE(longitude, 0) FROM users WHERE id = $1
+	`, userID).Scan(&latitude, &longitude)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("user not found")
+		}
+		return "", fmt.Errorf("failed to get user location: %w", err)
+	}
+	return utils.TimezoneForCoordinates(latitude, longitude), nil
+}
+
+// getBusyTimes loads the busy time of each player within window. Players who
+// have imported a calendar get a list, even if it's empty; players who
+// haven't are left out, as we can't tell when they are busy.
+func getBusyTimes(ctx context.Context, q queryer, userIDs []uuid.UUID, window models.TimeRange) (map[uuid.UUID][]models.TimeRange, error) {
+	busy := make(map[uuid.UUID][]models.TimeRange)
+	if len(userIDs) == 0 {
+		return busy, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT i.user_id, b.start_time, b.end_time
+		FROM user_calendar_imports i
+		LEFT JOIN user_busy_times b ON b.user_id = i.user_id AND b.start_time < $3 AND b.end_time > $2
+		WHERE i.user_id = ANY($1)
+		ORDER BY i.user_id, b.start_time
+	`, pq.Array(userIDs), window.Start, window.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query busy times: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		var start, end sql.NullTime
+		if err := rows.Scan(&userID, &start, &end); err != nil {
+			return nil, fmt.Errorf("failed to scan busy time: %w", err)
+		}
+		if _, ok := busy[userID]; !ok {
+			busy[userID] = []models.TimeRange{}
+		}
+		if start.Valid {
+			busy[userID] = append(busy[userID], models.TimeRange{Start: start.Time, End: end.Time})
+		}
+	}
+	return busy, rows.Err()
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+// GetApprovalQueue retrieves a court's booking requests that are waiting for
+// a manager, soonest deadline first
+func (r *BookingRepository) GetApprovalQueue(ctx context.Context, courtID uuid.UUID) ([]*models.Booking, error) {
+	bookings, err := queryBookings(ctx, r.db, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.court_id = $1 AND b.status = 'pending' AND b.approval_status = 'awaiting_approval'
+		AND b.approval_deadline > $2
+		ORDER BY b.approval_deadline ASC, b.created_at ASC
+	`, courtID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query booking requests: %w", err)
+	}
+	if err = attachParticipants(ctx, r.db, bookings); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// ReviewBooking approves or rejects a booking request at a court. Approved
+// bookings stay pending until confirmed or paid for; rejected ones are
+// cancelled and their court offered to the waitlist, whose new offer is returned.
+func (r *BookingRepository) ReviewBooking(ctx context.Context, courtID, bookingID, reviewerID uuid.UUID, approve bool, note string) (*models.Booking, *models.BookingWaitlistEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	booking, err := lockBooking(ctx, tx, "b.id = $1", bookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if booking.CourtID != courtID {
+		return nil, nil, fmt.Errorf("booking not found")
+	}
+	now := time.Now()
+	if err = booking.CheckReview(now); err != nil {
+		return nil, nil, err
+	}
+
+	booking.ApprovalStatus = models.ApprovalStatusApproved
+	if !approve {
+		booking.ApprovalStatus = models.ApprovalStatusRejected
+		booking.Status = models.BookingStatusCancelled
+	}
+	booking.ReviewedBy = &reviewerID
+	booking.ReviewedAt = &now
+	booking.ReviewNote = note
+	booking.UpdatedAt = now
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE bookings
+		SET status = $2, approval_status = $3, reviewed_by = $4, reviewed_at = $5, review_note = NULLIF($6, ''), updated_at = $7
+		WHERE id = $1
+	`, booking.ID, booking.Status, booking.ApprovalStatus, booking.ReviewedBy, booking.ReviewedAt, booking.ReviewNote, booking.UpdatedAt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to review booking: %w", err)
+	}
+	if err = syncOpenSpotsBulletin(ctx, tx, booking); err != nil {
+		return nil, nil, err
+	}
+
+	var offer *models.BookingWaitlistEntry
+	if !approve {
+		offer, err = offerFreedCourt(ctx, tx, booking.CourtID, booking.CourtUnitID, booking.StartTime, booking.EndTime)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return booking, offer, nil
+}
+
+// ExpireApprovalRequests cancels booking requests no manager answered before
+// their deadline, offering their courts to the waitlist. It returns the
+// expired bookings and any new waitlist offers.
+func (r *BookingRepository) ExpireApprovalRequests(ctx context.Context) ([]*models.Booking, []*models.BookingWaitlistEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	bookings, err := queryBookings(ctx, tx, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.status = 'pending' AND b.approval_status = 'awaiting_approval' AND b.approval_deadline <= $1
+		FOR UPDATE OF b SKIP LOCKED
+	`, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query expired booking requests: %w", err)
+	}
+
+	var offers []*models.BookingWaitlistEntry
+	for _, booking := range bookings {
+		booking.Status = models.BookingStatusCancelled
+		booking.ApprovalStatus = models.ApprovalStatusExpired
+		booking.UpdatedAt = now
+		_, err = tx.ExecContext(ctx, `
+			UPDATE bookings SET status = $2, approval_status = $3, updated_at = $4 WHERE id = $1
+		`, booking.ID, booking.Status, booking.ApprovalStatus, booking.UpdatedAt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to expire booking request: %w", err)
+		}
+		if err = syncOpenSpotsBulletin(ctx, tx, booking); err != nil {
+			return nil, nil, err
+		}
+
+		offer, err := offerFreedCourt(ctx, tx, booking.CourtID, booking.CourtUnitID, booking.StartTime, booking.EndTime)
+		if err != nil {
+			return nil, nil, err
+		}
+		if offer != nil {
+			offers = append(offers, offer)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return bookings, offers, nil
+}
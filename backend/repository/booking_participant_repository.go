@@ -0,0 +1,374 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/user/tennis-connect/models"
+)
+
+// attachParticipants loads the roster of each booking and counts its open spots
+func attachParticipants(ctx context.Context, q queryer, bookings []*models.Booking) error {
+	if len(bookings) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(bookings))
+	for i, booking := range bookings {
+		ids[i] = booking.ID
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT p.id, p.booking_id, p.user_id, u.name, p.status, p.is_owner, p.invited_by, p.responded_at, p.created_at
+		FROM booking_participants p
+		JOIN users u ON p.user_id = u.id
+		WHERE p.booking_id = ANY($1)
+		ORDER BY p.is_owner DESC, p.created_at ASC
+	`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query booking participants: %w", err)
+	}
+	defer rows.Close()
+
+	rosters := make(map[uuid.UUID][]models.BookingParticipant)
+	for rows.Next() {
+		var p models.BookingParticipant
+		var invitedBy uuid.NullUUID
+		var respondedAt sql.NullTime
+		if err := rows.Scan(&p.ID, &p.BookingID, &p.UserID, &p.UserName, &p.Status, &p.IsOwner, &invitedBy, &respondedAt, &p.CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan booking participant: %w", err)
+		}
+		if invitedBy.Valid {
+			p.InvitedBy = &invitedBy.UUID
+		}
+		if respondedAt.Valid {
+			p.RespondedAt = &respondedAt.Time
+		}
+		rosters[p.BookingID] = append(rosters[p.BookingID], p)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating booking participants: %w", err)
+	}
+
+	for _, booking := range bookings {
+		booking.Participants = rosters[booking.ID]
+		booking.OpenSpots = booking.SpotsOpen()
+	}
+	return nil
+}
+
+// lockBooking loads a booking and its roster, locking the booking row so
+// concurrent joins cannot overfill it
+func lockBooking(ctx context.Context, tx *sql.Tx, where string, arg interface{}) (*models.Booking, error) {
+	booking, err := scanBooking(tx.QueryRowContext(ctx, `SELECT `+bookingColumns+bookingFrom+` WHERE `+where+` FOR UPDATE OF b`, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("booking not found")
+		}
+		return nil, fmt.Errorf("failed to get booking: %w", err)
+	}
+	if err = attachParticipants(ctx, tx, []*models.Booking{booking}); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// syncOpenSpotsBulletin keeps the bulletin advertising a booking's open spots
+// in step with its roster, posting it the first time it is needed and
+// deactivating it once the booking is full, cancelled or no longer published
+func syncOpenSpotsBulletin(ctx context.Context, tx *sql.Tx, booking *models.Booking) error {
+	active := booking.PublishOpenSpots && booking.SpotsOpen() > 0 && booking.StartTime.After(time.Now()) &&
+		(booking.Status == models.BookingStatusPending || booking.Status == models.BookingStatusConfirmed)
+	if booking.BulletinID == nil && !active {
+		return nil
+	}
+
+	var courtName string
+	if err := tx.QueryRowContext(ctx, "SELECT name FROM courts WHERE id = $1", booking.CourtID).Scan(&courtName); err != nil {
+		return fmt.Errorf("failed to get court name: %w", err)
+	}
+	title := booking.OpenSpotsTitle(courtName)
+
+	if booking.BulletinID != nil {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE bulletins SET title = $2, is_active = $3, updated_at = $4 WHERE id = $1
+		`, booking.BulletinID, title, active, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to update open spots bulletin: %w", err)
+		}
+		return nil
+	}
+
+	token, err := ensureShareToken(ctx, tx, booking.ID)
+	if err != nil {
+		return err
+	}
+	booking.ShareToken = token
+
+	bulletinID := uuid.New()
+	description := fmt.Sprintf("Join with the booking link: %s", models.NewBookingShareLink(token).Path)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO bulletins (
+			id, user_id, title, description, latitude, longitude, zip_code, city, state,
+			court_id, start_time, end_time, game_type, is_active, created_at, updated_at
+		)
+		SELECT $1, $2, $3, $4, c.latitude, c.longitude, c.zip_code, c.city, c.state,
+			c.id, $5, $6, $7, TRUE, NOW(), NOW()
+		FROM courts c WHERE c.id = $8
+	`, bulletinID, booking.UserID, title, description, booking.StartTime, booking.EndTime, booking.GameType, booking.CourtID)
+	if err != nil {
+		return fmt.Errorf("failed to post open spots bulletin: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, "UPDATE bookings SET bulletin_id = $2 WHERE id = $1", booking.ID, bulletinID); err != nil {
+		return fmt.Errorf("failed to link open spots bulletin: %w", err)
+	}
+	booking.BulletinID = &bulletinID
+	return nil
+}
+
+// ensureShareToken returns the booking's share token, creating one the first time
+func ensureShareToken(ctx context.Context, q queryer, bookingID uuid.UUID) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	var token string
+	err := q.QueryRowContext(ctx, `
+		UPDATE bookings SET share_token = COALESCE(share_token, $2)
+		WHERE id = $1
+		RETURNING share_token
+	`, bookingID, hex.EncodeToString(buf)).Scan(&token)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("booking not found")
+		}
+		return "", fmt.Errorf("failed to create share token: %w", err)
+	}
+	return token, nil
+}
+
+// GetShareToken returns the token players use to join a booking by link,
+// creating one the first time it is requested
+func (r *BookingRepository) GetShareToken(ctx context.Context, bookingID uuid.UUID) (string, error) {
+	return ensureShareToken(ctx, r.db, bookingID)
+}
+
+// InviteParticipants invites players to a booking. Players already invited
+// or on the roster are left alone; players who declined are invited again.
+// It returns the IDs of the players who were invited.
+func (r *BookingRepository) InviteParticipants(ctx context.Context, bookingID, ownerID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	booking, err := lockBooking(ctx, tx, "b.id = $1", bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != ownerID {
+		return nil, fmt.Errorf("booking does not belong to user")
+	}
+	if booking.Status != models.BookingStatusPending && booking.Status != models.BookingStatusConfirmed {
+		return nil, fmt.Errorf("booking is no longer active")
+	}
+
+	var invited []uuid.UUID
+	for _, userID := range userIDs {
+		if userID == ownerID {
+			continue
+		}
+		var id uuid.UUID
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO booking_participants (id, booking_id, user_id, status, invited_by, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (booking_id, user_id) DO UPDATE
+			SET status = EXCLUDED.status, invited_by = EXCLUDED.invited_by, responded_at = NULL
+			WHERE booking_participants.status = 'declined'
+			RETURNING user_id
+		`, uuid.New(), bookingID, userID, models.ParticipantStatusInvited, ownerID, time.Now()).Scan(&id)
+		if err == sql.ErrNoRows {
+			continue // Already invited or on the roster
+		}
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				return nil, fmt.Errorf("user not found: %s", userID)
+			}
+			return nil, fmt.Errorf("failed to invite player: %w", err)
+		}
+		invited = append(invited, id)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return invited, nil
+}
+
+// RespondToInvite records an invitee accepting or declining. Players on the
+// roster can also decline to give up their place. Accepting fails once the
+// booking is full.
+func (r *BookingRepository) RespondToInvite(ctx context.Context, bookingID, userID uuid.UUID, status string) (*models.Booking, error) {
+	if status != models.ParticipantStatusAccepted && status != models.ParticipantStatusDeclined {
+		return nil, fmt.Errorf("status must be accepted or declined")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	booking, err := lockBooking(ctx, tx, "b.id = $1", bookingID)
+	if err != nil {
+		return nil, err
+	}
+	participant := booking.Participant(userID)
+	if participant == nil || participant.Status == models.ParticipantStatusDeclined {
+		return nil, fmt.Errorf("no invitation to this booking")
+	}
+	if participant.IsOwner {
+		return nil, fmt.Errorf("the owner cannot leave their own booking")
+	}
+	if status == models.ParticipantStatusAccepted {
+		if err := booking.CheckJoin(userID, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE booking_participants SET status = $3, responded_at = $4
+		WHERE booking_id = $1 AND user_id = $2
+	`, bookingID, userID, status, now); err != nil {
+		return nil, fmt.Errorf("failed to update invitation: %w", err)
+	}
+	participant.Status = status
+	participant.RespondedAt = &now
+	booking.OpenSpots = booking.SpotsOpen()
+
+	if err = syncOpenSpotsBulletin(ctx, tx, booking); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return booking, nil
+}
+
+// JoinByToken adds a player to the roster of the booking with the share token
+func (r *BookingRepository) JoinByToken(ctx context.Context, token string, userID uuid.UUID) (*models.Booking, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	booking, err := lockBooking(ctx, tx, "b.share_token = $1", token)
+	if err != nil {
+		return nil, err
+	}
+	if err := booking.CheckJoin(userID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO booking_participants (id, booking_id, user_id, status, responded_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (booking_id, user_id) DO UPDATE SET status = EXCLUDED.status, responded_at = EXCLUDED.responded_at
+	`, uuid.New(), booking.ID, userID, models.ParticipantStatusAccepted, now); err != nil {
+		return nil, fmt.Errorf("failed to join booking: %w", err)
+	}
+	if err = attachParticipants(ctx, tx, []*models.Booking{booking}); err != nil {
+		return nil, err
+	}
+
+	if err = syncOpenSpotsBulletin(ctx, tx, booking); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return booking, nil
+}
+
+// RemoveParticipant takes a player off a booking's roster. The owner can
+// remove anyone else; other players can only remove themselves.
+func (r *BookingRepository) RemoveParticipant(ctx context.Context, bookingID, actorID, userID uuid.UUID) (*models.Booking, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	booking, err := lockBooking(ctx, tx, "b.id = $1", bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if actorID != booking.UserID && actorID != userID {
+		return nil, fmt.Errorf("booking does not belong to user")
+	}
+	if userID == booking.UserID {
+		return nil, fmt.Errorf("the owner cannot leave their own booking")
+	}
+	if booking.Participant(userID) == nil {
+		return nil, fmt.Errorf("participant not found")
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		DELETE FROM booking_participants WHERE booking_id = $1 AND user_id = $2
+	`, bookingID, userID); err != nil {
+		return nil, fmt.Errorf("failed to remove participant: %w", err)
+	}
+	if err = attachParticipants(ctx, tx, []*models.Booking{booking}); err != nil {
+		return nil, err
+	}
+
+	if err = syncOpenSpotsBulletin(ctx, tx, booking); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return booking, nil
+}
+
+// SetPublishOpenSpots turns advertising a booking's open spots as a bulletin on or off
+func (r *BookingRepository) SetPublishOpenSpots(ctx context.Context, bookingID, ownerID uuid.UUID, publish bool) (*models.Booking, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	booking, err := lockBooking(ctx, tx, "b.id = $1", bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.UserID != ownerID {
+		return nil, fmt.Errorf("booking does not belong to user")
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE bookings SET publish_open_spots = $2, updated_at = $3 WHERE id = $1
+	`, bookingID, publish, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to update booking: %w", err)
+	}
+	booking.PublishOpenSpots = publish
+
+	if err = syncOpenSpotsBulletin(ctx, tx, booking); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return booking, nil
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/utils"
+)
+
+// getCourtPricing loads a court's prices and members, falling back to the defaults
+func getCourtPricing(ctx context.Context, q queryer, courtID uuid.UUID) (*models.CourtPricing, error) {
+	pricing := &models.CourtPricing{CourtID: courtID}
+	err := q.QueryRowContext(ctx, `
+		SELECT currency, off_peak_rate_cents, peak_rate_cents, member_off_peak_rate_cents, member_peak_rate_cents,
+			COALESCE(to_char(peak_start, 'HH24:MI'), ''), COALESCE(to_char(peak_end, 'HH24:MI'), ''),
+			full_refund_hours, late_refund_percent, updated_at
+		FROM court_pricing WHERE court_id = $1
+	`, courtID).Scan(
+		&pricing.Currency, &pricing.OffPeakRateCents, &pricing.PeakRateCents, &pricing.MemberOffPeakRateCents, &pricing.MemberPeakRateCents,
+		&pricing.PeakStart, &pricing.PeakEnd,
+		&pricing.FullRefundHours, &pricing.LateRefundPercent, &pricing.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		pricing = models.DefaultCourtPricing(courtID)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get court pricing: %w", err)
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT user_id FROM court_members WHERE court_id = $1 ORDER BY created_at`, courtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan court member: %w", err)
+		}
+		pricing.MemberIDs = append(pricing.MemberIDs, userID)
+	}
+	return pricing, rows.Err()
+}
+
+// GetPricing returns a court's prices, or the defaults if it has none
+func (r *CourtRepository) GetPricing(ctx context.Context, courtID uuid.UUID) (*models.CourtPricing, error) {
+	return getCourtPricing(ctx, r.db, courtID)
+}
+
+// SetPricing saves a court's prices and replaces its member list
+func (r *CourtRepository) SetPricing(ctx context.Context, pricing *models.CourtPricing) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	pricing.UpdatedAt = time.Now()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO court_pricing (
+			court_id, currency, off_peak_rate_cents, peak_rate_cents, member_off_peak_rate_cents, member_peak_rate_cents,
+			peak_start, peak_end, full_refund_hours, late_refund_percent, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::time, NULLIF($8, '')::time, $9, $10, $11)
+		ON CONFLICT (court_id) DO UPDATE SET
+			currency = EXCLUDED.currency,
+			off_peak_rate_cents = EXCLUDED.off_peak_rate_cents,
+			peak_rate_cents = EXCLUDED.peak_rate_cents,
+			member_off_peak_rate_cents = EXCLUDED.member_off_peak_rate_cents,
+			member_peak_rate_cents = EXCLUDED.member_peak_rate_cents,
+			peak_start = EXCLUDED.peak_start,
+			peak_end = EXCLUDED.peak_end,
+			full_refund_hours = EXCLUDED.full_refund_hours,
+			late_refund_percent = EXCLUDED.late_refund_percent,
+			updated_at = EXCLUDED.updated_at
+	`,
+		pricing.CourtID, pricing.Currency, pricing.OffPeakRateCents, pricing.PeakRateCents, pricing.MemberOffPeakRateCents, pricing.MemberPeakRateCents,
+		pricing.PeakStart, pricing.PeakEnd, pricing.FullRefundHours, pricing.LateRefundPercent, pricing.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save court pricing: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM court_members WHERE court_id = $1`, pricing.CourtID); err != nil {
+		return fmt.Errorf("failed to clear court members: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO court_members (court_id, user_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+	`, pricing.CourtID, pq.Array(pricing.MemberIDs))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to save court members: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Quote prices a booking for a player. Public courts are always free.
+func (r *BookingRepository) Quote(ctx context.Context, courtID, userID uuid.UUID, start, end time.Time) (*models.BookingQuote, error) {
+	var isPublic bool
+	var timezone string
+	var latitude, longitude float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(is_public, TRUE), COALESCE(timezone, ''), latitude, longitude FROM courts WHERE id = $1
+	`, courtID).Scan(&isPublic, &timezone, &latitude, &longitude)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("court not found")
+		}
+		return nil, fmt.Errorf("failed to get court: %w", err)
+	}
+
+	pricing := models.DefaultCourtPricing(courtID)
+	if !isPublic {
+		if pricing, err = getCourtPricing(ctx, r.db, courtID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Peak hours are in the court's local time
+	loc := utils.LoadTimezone(courtTimezone(timezone, latitude, longitude))
+	return pricing.Quote(userID, start.In(loc), end.In(loc)), nil
+}
+
+// paymentColumns is the column list scanned by scanPayment
+const paymentColumns = `
+	id, booking_id, user_id, provider, intent_id, amount_cents, currency, status, refunded_cents,
+	COALESCE(failure_reason, ''), created_at, updated_at
+`
+
+func scanPayment(row rowScanner) (*models.BookingPayment, error) {
+	payment := &models.BookingPayment{}
+	err := row.Scan(
+		&payment.ID, &payment.BookingID, &payment.UserID, &payment.Provider, &payment.IntentID, &payment.AmountCents,
+		&payment.Currency, &payment.Status, &payment.RefundedCents,
+		&payment.FailureReason, &payment.CreatedAt, &payment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// CreatePayment records the payment intent for a pending booking
+func (r *BookingRepository) CreatePayment(ctx context.Context, payment *models.BookingPayment) error {
+	if payment.ID == uuid.Nil {
+		payment.ID = uuid.New()
+	}
+	payment.CreatedAt = time.Now()
+	payment.UpdatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO booking_payments (
+			id, booking_id, user_id, provider, intent_id, amount_cents, currency, status, refunded_cents,
+			failure_reason, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $12)
+	`,
+		payment.ID, payment.BookingID, payment.UserID, payment.Provider, payment.IntentID, payment.AmountCents,
+		payment.Currency, payment.Status, payment.RefundedCents,
+		payment.FailureReason, payment.CreatedAt, payment.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create payment: %w", err)
+	}
+	return nil
+}
+
+// GetPayment retrieves the payment for a booking
+func (r *BookingRepository) GetPayment(ctx context.Context, bookingID uuid.UUID) (*models.BookingPayment, error) {
+	payment, err := scanPayment(r.db.QueryRowContext(ctx, `SELECT `+paymentColumns+` FROM booking_payments WHERE booking_id = $1`, bookingID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("payment not found")
+		}
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	return payment, nil
+}
+
+// UpdatePayment saves a payment's status, refunds and failure reason
+func (r *BookingRepository) UpdatePayment(ctx context.Context, payment *models.BookingPayment) error {
+	payment.UpdatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE booking_payments
+		SET status = $2, refunded_cents = $3, failure_reason = NULLIF($4, ''), updated_at = $5
+		WHERE id = $1
+	`, payment.ID, payment.Status, payment.RefundedCents, payment.FailureReason, payment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+	return nil
+}
+
+// MarkPaid records a captured payment and confirms its booking. It fails with
+// "no longer open" if the booking expired or was cancelled in the meantime.
+func (r *BookingRepository) MarkPaid(ctx context.Context, payment *models.BookingPayment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE booking_payments SET status = $2, failure_reason = NULL, updated_at = $3
+		WHERE id = $1 AND status IN ('requires_payment', 'failed')
+	`, payment.ID, models.PaymentStatusSucceeded, now)
+	if err != nil {
+		return fmt.Errorf("failed to update payment: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("payment is no longer open")
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		UPDATE bookings SET status = $2, updated_at = $3 WHERE id = $1 AND status = 'pending'
+	`, payment.BookingID, models.BookingStatusConfirmed, now)
+	if err != nil {
+		return fmt.Errorf("failed to confirm booking: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("booking is no longer open for payment")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	payment.Status = models.PaymentStatusSucceeded
+	payment.FailureReason = ""
+	payment.UpdatedAt = now
+	return nil
+}
+
+// RefundDue returns how much of a captured payment the court's cancellation
+// policy refunds if its booking is cancelled at now
+func (r *BookingRepository) RefundDue(ctx context.Context, payment *models.BookingPayment, now time.Time) (int, error) {
+	var courtID uuid.UUID
+	var start time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT court_id, start_time FROM bookings WHERE id = $1`, payment.BookingID).Scan(&courtID, &start)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("booking not found")
+		}
+		return 0, fmt.Errorf("failed to get booking: %w", err)
+	}
+
+	pricing, err := getCourtPricing(ctx, r.db, courtID)
+	if err != nil {
+		return 0, err
+	}
+	return pricing.RefundCents(payment.AmountCents-payment.RefundedCents, start, now), nil
+}
+
+// ExpireUnpaidBookings cancels bookings that were not paid within the
+// payment window, offering their courts to the waitlist. It returns the
+// abandoned payments, whose intents should be cancelled with the provider,
+// and any new waitlist offers.
+func (r *BookingRepository) ExpireUnpaidBookings(ctx context.Context) ([]*models.BookingPayment, []*models.BookingWaitlistEntry, error) {
+	bookings, err := queryBookings(ctx, r.db, `SELECT `+bookingColumns+bookingFrom+`
+		JOIN booking_payments p ON p.booking_id = b.id
+		WHERE b.status = 'pending' AND p.status IN ('requires_payment', 'failed') AND p.created_at <= $1
+	`, time.Now().Add(-models.PaymentWindow))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query unpaid bookings: %w", err)
+	}
+
+	var expired []*models.BookingPayment
+	var offers []*models.BookingWaitlistEntry
+	for _, booking := range bookings {
+		// Claim the payment first so a payment landing now cannot also confirm the booking
+		payment, err := scanPayment(r.db.QueryRowContext(ctx, `
+			UPDATE booking_payments SET status = $2, updated_at = $3
+			WHERE booking_id = $1 AND status IN ('requires_payment', 'failed')
+			RETURNING `+paymentColumns,
+			booking.ID, models.PaymentStatusCancelled, time.Now()))
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to expire payment: %w", err)
+		}
+		expired = append(expired, payment)
+
+		if err = r.UpdateStatus(ctx, booking.ID, models.BookingStatusCancelled); err != nil {
+			return nil, nil, err
+		}
+		offer, err := r.OfferFreedBooking(ctx, booking)
+		if err != nil {
+			return nil, nil, err
+		}
+		if offer != nil {
+			offers = append(offers, offer)
+		}
+	}
+	return expired, offers, nil
+}
@@ -21,7 +21,83 @@ func NewBookingRepository(db *database.DB) *BookingRepository {
 	return &BookingRepository{db: db}
 }
 
-// Create creates a new booking
+// bookingColumns is the column list scanned by scanBooking
+const bookingColumns = `
+	b.id, b.court_id, b.court_unit_id, COALESCE(u.name, ''), b.user_id, b.start_time, b.end_time, b.status,
+	b.player_count, b.game_type, b.notes, b.series_id, b.created_at, b.updated_at,
+	COALESCE(b.share_token, ''), COALESCE(b.publish_open_spots, FALSE), b.bulletin_id,
+	COALESCE(b.approval_status, ''), b.approval_deadline, b.reviewed_by, b.reviewed_at, COALESCE(b.review_note, ''),
+	COALESCE(c.timezone, ''), c.latitude, c.longitude
+`
+
+// bookingFrom joins bookings to their court unit and court for bookingColumns
+const bookingFrom = `
+	FROM bookings b
+	LEFT JOIN court_units u ON b.court_unit_id = u.id
+	JOIN courts c ON b.court_id = c.id
+`
+
+func scanBooking(row rowScanner) (*models.Booking, error) {
+	booking := &models.Booking{}
+	var unitID, seriesID, bulletinID, reviewedBy uuid.NullUUID
+	var approvalDeadline, reviewedAt sql.NullTime
+	var notes sql.NullString
+	var timezone string
+	var latitude, longitude float64
+	err := row.Scan(
+		&booking.ID, &booking.CourtID, &unitID, &booking.CourtUnitName, &booking.UserID, &booking.StartTime, &booking.EndTime,
+		&booking.Status, &booking.PlayerCount, &booking.GameType, &notes, &seriesID,
+		&booking.CreatedAt, &booking.UpdatedAt,
+		&booking.ShareToken, &booking.PublishOpenSpots, &bulletinID,
+		&booking.ApprovalStatus, &approvalDeadline, &reviewedBy, &reviewedAt, &booking.ReviewNote,
+		&timezone, &latitude, &longitude,
+	)
+	if err != nil {
+		return nil, err
+	}
+	booking.Localize(&booking.StartTime, &booking.EndTime, courtTimezone(timezone, latitude, longitude))
+	if unitID.Valid {
+		booking.CourtUnitID = &unitID.UUID
+	}
+	if seriesID.Valid {
+		booking.SeriesID = &seriesID.UUID
+	}
+	if bulletinID.Valid {
+		booking.BulletinID = &bulletinID.UUID
+	}
+	if approvalDeadline.Valid {
+		booking.ApprovalDeadline = &approvalDeadline.Time
+	}
+	if reviewedBy.Valid {
+		booking.ReviewedBy = &reviewedBy.UUID
+	}
+	if reviewedAt.Valid {
+		booking.ReviewedAt = &reviewedAt.Time
+	}
+	booking.Notes = notes.String
+	return booking, nil
+}
+
+func queryBookings(ctx context.Context, q queryer, query string, args ...interface{}) ([]*models.Booking, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []*models.Booking
+	for rows.Next() {
+		booking, err := scanBooking(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking: %w", err)
+		}
+		bookings = append(bookings, booking)
+	}
+	return bookings, rows.Err()
+}
+
+// Create creates a new booking. With CourtUnitID set that court is booked;
+// otherwise the first free court at the facility is assigned.
 func (r *BookingRepository) Create(ctx context.Context, booking *models.Booking) error {
 	if booking.ID == uuid.Nil {
 		booking.ID = uuid.New()
@@ -37,217 +113,289 @@ func (r *BookingRepository) Create(ctx context.Context, booking *models.Booking)
 		return fmt.Errorf("end time must be after start time")
 	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the facility's units so concurrent bookings cannot take the same court
+	units, err := getCourtUnits(ctx, tx, booking.CourtID, true)
+	if err != nil {
+		return err
+	}
+	if len(units) == 0 {
+		return fmt.Errorf("court has no bookable units")
+	}
+
+	// Check opening hours, holidays and closures
+	schedule, err := getCourtSchedule(ctx, tx, booking.CourtID, booking.StartTime, booking.EndTime)
+	if err != nil {
+		return err
+	}
+	if err = schedule.CheckOpen(booking.StartTime, booking.EndTime); err != nil {
+		return err
+	}
+	units = schedule.OpenUnits(units, booking.StartTime, booking.EndTime)
+
+	// Check the court's booking rules against the player's other bookings here,
+	// using the court's local time for slots, prime time and weeks
+	rules, err := getBookingRules(ctx, tx, booking.CourtID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	start, end := schedule.Local(booking.StartTime), schedule.Local(booking.EndTime)
+	opens, _, _ := schedule.HoursOn(start)
+	if err = rules.CheckTimes(start, end, opens, now); err != nil {
+		return fmt.Errorf("booking not allowed: %w", err)
+	}
+	earliest := models.StartOfWeek(start)
+	if now.Before(earliest) {
+		earliest = now
+	}
+	userBookings, err := queryBookings(ctx, tx, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.court_id = $1 AND b.user_id = $2 AND b.status IN ('pending', 'confirmed')
+		AND b.end_time > $3
+	`, booking.CourtID, booking.UserID, earliest)
+	if err != nil {
+		return fmt.Errorf("failed to query user bookings: %w", err)
+	}
+	for _, existing := range userBookings {
+		existing.StartTime, existing.EndTime = schedule.Local(existing.StartTime), schedule.Local(existing.EndTime)
+	}
+	if err = rules.CheckLimits(userBookings, start, end, now); err != nil {
+		return fmt.Errorf("booking not allowed: %w", err)
+	}
+
+	// Requests from players who do not manage the court wait for a manager
+	approvalNeeded, err := needsApproval(ctx, tx, rules, booking.CourtID, booking.UserID)
+	if err != nil {
+		return err
+	}
+	if approvalNeeded {
+		awaitApproval(booking, rules, now)
+	}
+
 	// Check for conflicts
-	conflicts, err := r.CheckConflicts(ctx, booking.CourtID, booking.StartTime, booking.EndTime, uuid.Nil)
+	existing, err := queryBookings(ctx, tx, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.court_id = $1 AND b.status IN ('pending', 'confirmed')
+		AND b.start_time < $3 AND b.end_time > $2
+	`, booking.CourtID, booking.StartTime, booking.EndTime)
 	if err != nil {
 		return fmt.Errorf("failed to check conflicts: %w", err)
 	}
-	if len(conflicts) > 0 {
+	free := models.FreeUnits(units, existing, booking.StartTime, booking.EndTime)
+
+	// Courts held for waitlisted players can only be claimed by them
+	held, err := heldUnits(ctx, tx, booking.CourtID, booking.UserID, booking.StartTime, booking.EndTime)
+	if err != nil {
+		return err
+	}
+
+	var unit *models.CourtUnit
+	for i := range free {
+		if held[free[i].ID] {
+			continue
+		}
+		if booking.CourtUnitID == nil || free[i].ID == *booking.CourtUnitID {
+			unit = &free[i]
+			break
+		}
+	}
+	if unit == nil {
 		return fmt.Errorf("court is not available during the requested time")
 	}
+	booking.CourtUnitID = &unit.ID
+	booking.CourtUnitName = unit.Name
+
+	if err = insertBooking(ctx, tx, booking); err != nil {
+		return err
+	}
+	if err = syncOpenSpotsBulletin(ctx, tx, booking); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	booking.Localize(&booking.StartTime, &booking.EndTime, schedule.Timezone)
+	return nil
+}
+
+// needsApproval reports whether a player's bookings at a court wait for a
+// manager: the court requires approval and the player doesn't manage it
+func needsApproval(ctx context.Context, tx *sql.Tx, rules *models.BookingRules, courtID, userID uuid.UUID) (bool, error) {
+	if !rules.RequireApproval {
+		return false, nil
+	}
+	var isManager bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM court_managers WHERE court_id = $1 AND user_id = $2)
+	`, courtID, userID).Scan(&isManager)
+	if err != nil {
+		return false, fmt.Errorf("failed to check court managers: %w", err)
+	}
+	return !isManager, nil
+}
 
-	_, err = r.db.ExecContext(ctx, `
+// awaitApproval holds a new booking as a request for the managers to answer
+// before its approval deadline
+func awaitApproval(booking *models.Booking, rules *models.BookingRules, now time.Time) {
+	deadline := rules.ApprovalDeadline(booking.StartTime, now)
+	booking.Status = models.BookingStatusPending
+	booking.ApprovalStatus = models.ApprovalStatusAwaiting
+	booking.ApprovalDeadline = &deadline
+}
+
+// insertBooking inserts a booking with its owner as the first player on the roster
+func insertBooking(ctx context.Context, tx *sql.Tx, booking *models.Booking) error {
+	_, err := tx.ExecContext(ctx, `
 		INSERT INTO bookings (
-			id, court_id, user_id, start_time, end_time, status, 
-			player_count, game_type, notes, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			id, court_id, court_unit_id, user_id, start_time, end_time, status, 
+			player_count, game_type, notes, series_id, publish_open_spots, created_at, updated_at,
+			approval_status, approval_deadline
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, ''), $16)
 	`,
-		booking.ID, booking.CourtID, booking.UserID, booking.StartTime, booking.EndTime,
-		booking.Status, booking.PlayerCount, booking.GameType, booking.Notes,
+		booking.ID, booking.CourtID, booking.CourtUnitID, booking.UserID, booking.StartTime, booking.EndTime,
+		booking.Status, booking.PlayerCount, booking.GameType, booking.Notes, booking.SeriesID, booking.PublishOpenSpots,
 		booking.CreatedAt, booking.UpdatedAt,
+		booking.ApprovalStatus, booking.ApprovalDeadline,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create booking: %w", err)
 	}
 
+	owner := models.BookingParticipant{
+		ID:          uuid.New(),
+		BookingID:   booking.ID,
+		UserID:      booking.UserID,
+		Status:      models.ParticipantStatusAccepted,
+		IsOwner:     true,
+		RespondedAt: &booking.CreatedAt,
+		CreatedAt:   booking.CreatedAt,
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO booking_participants (id, booking_id, user_id, status, is_owner, responded_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, owner.ID, owner.BookingID, owner.UserID, owner.Status, owner.IsOwner, owner.RespondedAt, owner.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add booking owner to roster: %w", err)
+	}
+	booking.Participants = []models.BookingParticipant{owner}
+	booking.OpenSpots = booking.SpotsOpen()
 	return nil
 }
 
 // GetByID retrieves a booking by its ID
 func (r *BookingRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Booking, error) {
-	booking := &models.Booking{}
-	err := r.db.QueryRowContext(ctx, `
-		SELECT 
-			id, court_id, user_id, start_time, end_time, status,
-			player_count, game_type, notes, created_at, updated_at
-		FROM bookings WHERE id = $1
-	`, id).Scan(
-		&booking.ID, &booking.CourtID, &booking.UserID, &booking.StartTime, &booking.EndTime,
-		&booking.Status, &booking.PlayerCount, &booking.GameType, &booking.Notes,
-		&booking.CreatedAt, &booking.UpdatedAt,
-	)
+	booking, err := scanBooking(r.db.QueryRowContext(ctx, `SELECT `+bookingColumns+bookingFrom+` WHERE b.id = $1`, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("booking not found")
 		}
 		return nil, fmt.Errorf("failed to get booking: %w", err)
 	}
+	if err = attachParticipants(ctx, r.db, []*models.Booking{booking}); err != nil {
+		return nil, err
+	}
 
 	return booking, nil
 }
 
-// GetByUserID retrieves bookings for a specific user
+// GetByUserID retrieves the bookings a user owns or is on the roster of, with their rosters
 func (r *BookingRepository) GetByUserID(ctx context.Context, userID uuid.UUID, includeCompleted bool) ([]*models.Booking, error) {
-	query := `
-		SELECT 
-			id, court_id, user_id, start_time, end_time, status,
-			player_count, game_type, notes, created_at, updated_at
-		FROM bookings 
-		WHERE user_id = $1
-	`
-	args := []interface{}{userID}
+	query := `SELECT ` + bookingColumns + bookingFrom + ` WHERE (b.user_id = $1 OR EXISTS (
+		SELECT 1 FROM booking_participants p
+		WHERE p.booking_id = b.id AND p.user_id = $1 AND p.status IN ('invited', 'accepted')
+	))`
 
 	if !includeCompleted {
-		query += " AND status != 'completed' AND status != 'cancelled'"
+		query += " AND b.status != 'completed' AND b.status != 'cancelled'"
 	}
 
-	query += " ORDER BY start_time ASC"
+	query += " ORDER BY b.start_time ASC"
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	bookings, err := queryBookings(ctx, r.db, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query user bookings: %w", err)
 	}
-	defer rows.Close()
-
-	var bookings []*models.Booking
-	for rows.Next() {
-		booking := &models.Booking{}
-		err := rows.Scan(
-			&booking.ID, &booking.CourtID, &booking.UserID, &booking.StartTime, &booking.EndTime,
-			&booking.Status, &booking.PlayerCount, &booking.GameType, &booking.Notes,
-			&booking.CreatedAt, &booking.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan booking: %w", err)
-		}
-		bookings = append(bookings, booking)
+	if err = attachParticipants(ctx, r.db, bookings); err != nil {
+		return nil, err
 	}
-
 	return bookings, nil
 }
 
-// GetByCourtID retrieves bookings for a specific court
+// GetByCourtID retrieves bookings at any of a facility's courts
 func (r *BookingRepository) GetByCourtID(ctx context.Context, courtID uuid.UUID, startDate, endDate time.Time) ([]*models.Booking, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT 
-			id, court_id, user_id, start_time, end_time, status,
-			player_count, game_type, notes, created_at, updated_at
-		FROM bookings 
-		WHERE court_id = $1 
-		AND start_time >= $2 
-		AND end_time <= $3
-		AND status IN ('pending', 'confirmed')
-		ORDER BY start_time ASC
+	bookings, err := queryBookings(ctx, r.db, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.court_id = $1 
+		AND b.start_time >= $2 
+		AND b.end_time <= $3
+		AND b.status IN ('pending', 'confirmed')
+		ORDER BY b.start_time ASC
 	`, courtID, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query court bookings: %w", err)
 	}
-	defer rows.Close()
-
-	var bookings []*models.Booking
-	for rows.Next() {
-		booking := &models.Booking{}
-		err := rows.Scan(
-			&booking.ID, &booking.CourtID, &booking.UserID, &booking.StartTime, &booking.EndTime,
-			&booking.Status, &booking.PlayerCount, &booking.GameType, &booking.Notes,
-			&booking.CreatedAt, &booking.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan booking: %w", err)
-		}
-		bookings = append(bookings, booking)
-	}
-
 	return bookings, nil
 }
 
-// CheckConflicts checks for booking conflicts
-func (r *BookingRepository) CheckConflicts(ctx context.Context, courtID uuid.UUID, startTime, endTime time.Time, excludeBookingID uuid.UUID) ([]*models.Booking, error) {
-	query := `
-		SELECT 
-			id, court_id, user_id, start_time, end_time, status,
-			player_count, game_type, notes, created_at, updated_at
-		FROM bookings 
-		WHERE court_id = $1 
-		AND status IN ('pending', 'confirmed')
-		AND (
-			(start_time <= $2 AND end_time > $2) OR
-			(start_time < $3 AND end_time >= $3) OR
-			(start_time >= $2 AND end_time <= $3)
-		)
+// CheckConflicts returns the bookings holding a court unit between startTime and endTime
+func (r *BookingRepository) CheckConflicts(ctx context.Context, courtUnitID uuid.UUID, startTime, endTime time.Time, excludeBookingID uuid.UUID) ([]*models.Booking, error) {
+	return checkConflicts(ctx, r.db, courtUnitID, startTime, endTime, excludeBookingID)
+}
+
+func checkConflicts(ctx context.Context, q queryer, courtUnitID uuid.UUID, startTime, endTime time.Time, excludeBookingID uuid.UUID) ([]*models.Booking, error) {
+	query := `SELECT ` + bookingColumns + bookingFrom + `
+		WHERE b.court_unit_id = $1 
+		AND b.status IN ('pending', 'confirmed')
+		AND b.start_time < $3 AND b.end_time > $2
 	`
-	args := []interface{}{courtID, startTime, endTime}
+	args := []interface{}{courtUnitID, startTime, endTime}
 
 	if excludeBookingID != uuid.Nil {
-		query += " AND id != $4"
+		query += " AND b.id != $4"
 		args = append(args, excludeBookingID)
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	conflicts, err := queryBookings(ctx, q, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check conflicts: %w", err)
 	}
-	defer rows.Close()
-
-	var conflicts []*models.Booking
-	for rows.Next() {
-		booking := &models.Booking{}
-		err := rows.Scan(
-			&booking.ID, &booking.CourtID, &booking.UserID, &booking.StartTime, &booking.EndTime,
-			&booking.Status, &booking.PlayerCount, &booking.GameType, &booking.Notes,
-			&booking.CreatedAt, &booking.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan conflict: %w", err)
-		}
-		conflicts = append(conflicts, booking)
-	}
-
 	return conflicts, nil
 }
 
-// GetAvailability gets court availability for a specific date
+// GetAvailability gets availability for each court at a facility on a
+// specific date, within the court's opening hours for that day and in the
+// court's slot length. The date's location should be the court's timezone.
 func (r *BookingRepository) GetAvailability(ctx context.Context, courtID uuid.UUID, date time.Time) (*models.CourtAvailability, error) {
-	// Define operating hours (6 AM to 10 PM)
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 6, 0, 0, 0, date.Location())
-	endOfDay := time.Date(date.Year(), date.Month(), date.Day(), 22, 0, 0, 0, date.Location())
+	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	endOfDay := startOfDay.AddDate(0, 0, 1)
 
-	// Get existing bookings for the day
-	bookings, err := r.GetByCourtID(ctx, courtID, startOfDay, endOfDay)
+	units, err := getCourtUnits(ctx, r.db, courtID, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get court bookings: %w", err)
+		return nil, err
 	}
-
-	// Generate time slots (1-hour intervals)
-	availability := &models.CourtAvailability{
-		CourtID:   courtID,
-		Date:      date.Format("2006-01-02"),
-		TimeSlots: []models.TimeSlotAvailability{},
+	schedule, err := getCourtSchedule(ctx, r.db, courtID, startOfDay, endOfDay)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := getBookingRules(ctx, r.db, courtID)
+	if err != nil {
+		return nil, err
 	}
 
-	for current := startOfDay; current.Before(endOfDay); current = current.Add(time.Hour) {
-		slotEnd := current.Add(time.Hour)
-		isAvailable := true
-		var conflictingBookingID *uuid.UUID
-
-		// Check if this slot conflicts with any booking
-		for _, booking := range bookings {
-			if current.Before(booking.EndTime) && slotEnd.After(booking.StartTime) {
-				isAvailable = false
-				conflictingBookingID = &booking.ID
-				break
-			}
-		}
-
-		slot := models.TimeSlotAvailability{
-			StartTime:   current,
-			EndTime:     slotEnd,
-			IsAvailable: isAvailable,
-			BookingID:   conflictingBookingID,
-		}
-		availability.TimeSlots = append(availability.TimeSlots, slot)
+	// Get existing bookings for the day
+	bookings, err := r.GetByCourtID(ctx, courtID, startOfDay, endOfDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get court bookings: %w", err)
 	}
 
+	// Generate time slots of the court's slot length
+	availability := models.BuildAvailability(courtID, units, bookings, schedule, startOfDay, rules.SlotDuration())
+	availability.Timezone = schedule.Timezone
+	rules.ApplyTo(availability, time.Now())
 	return availability, nil
 }
 
@@ -270,59 +418,63 @@ func (r *BookingRepository) UpdateStatus(ctx context.Context, bookingID uuid.UUI
 		return fmt.Errorf("booking not found")
 	}
 
+	// Stop advertising open spots on bookings that will not be played
+	if status == models.BookingStatusCancelled || status == models.BookingStatusCompleted {
+		_, err = r.db.ExecContext(ctx, `
+			UPDATE bulletins SET is_active = FALSE, updated_at = $2
+			WHERE id = (SELECT bulletin_id FROM bookings WHERE id = $1)
+		`, bookingID, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to close open spots bulletin: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// Cancel cancels a booking
-func (r *BookingRepository) Cancel(ctx context.Context, bookingID uuid.UUID, userID uuid.UUID) error {
+// Cancel cancels a booking and offers the freed court to the first
+// waitlisted player it suits, who is returned
+func (r *BookingRepository) Cancel(ctx context.Context, bookingID uuid.UUID, userID uuid.UUID) (*models.BookingWaitlistEntry, error) {
 	// First check if the booking exists and belongs to the user
 	booking, err := r.GetByID(ctx, bookingID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if booking.UserID != userID {
-		return fmt.Errorf("booking does not belong to user")
+		return nil, fmt.Errorf("booking does not belong to user")
+	}
+
+	rules, err := getBookingRules(ctx, r.db, booking.CourtID)
+	if err != nil {
+		return nil, err
+	}
+	if !rules.CanCancel(booking, time.Now()) {
+		return nil, fmt.Errorf("booking cannot be cancelled")
 	}
 
-	if !booking.CanBeCancelled() {
-		return fmt.Errorf("booking cannot be cancelled")
+	if err = r.UpdateStatus(ctx, bookingID, models.BookingStatusCancelled); err != nil {
+		return nil, err
 	}
 
-	return r.UpdateStatus(ctx, bookingID, models.BookingStatusCancelled)
+	offer, err := r.OfferFreedBooking(ctx, booking)
+	if err != nil {
+		return nil, fmt.Errorf("booking cancelled but waitlist offer failed: %w", err)
+	}
+	return offer, nil
 }
 
 // GetUpcomingBookings gets upcoming bookings for a user
 func (r *BookingRepository) GetUpcomingBookings(ctx context.Context, userID uuid.UUID, limit int) ([]*models.Booking, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT 
-			id, court_id, user_id, start_time, end_time, status,
-			player_count, game_type, notes, created_at, updated_at
-		FROM bookings 
-		WHERE user_id = $1 
-		AND start_time > $2
-		AND status IN ('pending', 'confirmed')
-		ORDER BY start_time ASC
+	bookings, err := queryBookings(ctx, r.db, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.user_id = $1 
+		AND b.start_time > $2
+		AND b.status IN ('pending', 'confirmed')
+		ORDER BY b.start_time ASC
 		LIMIT $3
 	`, userID, time.Now(), limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query upcoming bookings: %w", err)
 	}
-	defer rows.Close()
-
-	var bookings []*models.Booking
-	for rows.Next() {
-		booking := &models.Booking{}
-		err := rows.Scan(
-			&booking.ID, &booking.CourtID, &booking.UserID, &booking.StartTime, &booking.EndTime,
-			&booking.Status, &booking.PlayerCount, &booking.GameType, &booking.Notes,
-			&booking.CreatedAt, &booking.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan booking: %w", err)
-		}
-		bookings = append(bookings, booking)
-	}
-
 	return bookings, nil
 }
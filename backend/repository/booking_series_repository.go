@@ -0,0 +1,484 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/utils"
+)
+
+// bookingSeriesColumns is the column list scanned by scanBookingSeries
+const bookingSeriesColumns = `
+	s.id, s.court_id, s.court_unit_id, s.user_id, s.frequency, s.repeat_interval, to_char(s.until_date, 'YYYY-MM-DD'),
+	s.first_start_time, s.duration_minutes, s.status, s.player_count, s.game_type, s.notes,
+	s.created_at, s.updated_at, COALESCE(c.timezone, ''), c.latitude, c.longitude
+	FROM booking_series s
+	JOIN courts c ON s.court_id = c.id
+`
+
+func scanBookingSeries(row rowScanner) (*models.BookingSeries, error) {
+	series := &models.BookingSeries{}
+	var unitID uuid.NullUUID
+	var gameType, notes sql.NullString
+	var timezone string
+	var latitude, longitude float64
+	err := row.Scan(
+		&series.ID, &series.CourtID, &unitID, &series.UserID, &series.Frequency, &series.Interval, &series.Until,
+		&series.FirstStartTime, &series.DurationMinutes, &series.Status, &series.PlayerCount, &gameType, &notes,
+		&series.CreatedAt, &series.UpdatedAt, &timezone, &latitude, &longitude,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if unitID.Valid {
+		series.CourtUnitID = &unitID.UUID
+	}
+	series.GameType = gameType.String
+	series.Notes = notes.String
+	series.Timezone = courtTimezone(timezone, latitude, longitude)
+	return series, nil
+}
+
+// CreateSeries books every occurrence of a recurring booking. Each
+// occurrence is checked against the court's schedule, booking rules and
+// CheckConflicts before anything is written, and the outcome for each is
+// reported in series.Occurrences. Unless skipConflicts is set, any occurrence
+// that cannot be booked rejects the whole series with "series has conflicts";
+// otherwise those occurrences are recorded as skipped. The bookings are
+// created pending, like single bookings, waiting for a manager's approval
+// where the court requires it, and returned in series.Bookings.
+func (r *BookingRepository) CreateSeries(ctx context.Context, series *models.BookingSeries, skipConflicts bool) error {
+	if series.ID == uuid.Nil {
+		series.ID = uuid.New()
+	}
+	if series.Interval == 0 {
+		series.Interval = 1
+	}
+	series.Status = models.BookingSeriesStatusActive
+	series.CreatedAt = time.Now()
+	series.UpdatedAt = time.Now()
+
+	if series.FirstStartTime.Before(time.Now()) {
+		return fmt.Errorf("cannot book court in the past")
+	}
+	starts := series.RecurrenceRule.Starts(series.FirstStartTime)
+	if len(starts) == 0 {
+		return fmt.Errorf("series has no occurrences before its end date")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the facility's units so concurrent bookings cannot take the same court
+	units, err := getCourtUnits(ctx, tx, series.CourtID, true)
+	if err != nil {
+		return err
+	}
+	if len(units) == 0 {
+		return fmt.Errorf("court has no bookable units")
+	}
+	if series.CourtUnitID != nil {
+		var requested []models.CourtUnit
+		for _, unit := range units {
+			if unit.ID == *series.CourtUnitID {
+				requested = append(requested, unit)
+			}
+		}
+		if len(requested) == 0 {
+			return fmt.Errorf("court unit not found")
+		}
+		units = requested
+	}
+
+	schedule, err := getCourtSchedule(ctx, tx, series.CourtID, starts[0], starts[len(starts)-1].Add(series.Duration()))
+	if err != nil {
+		return err
+	}
+	series.Timezone = schedule.Timezone
+	starts = series.RecurrenceRule.Starts(schedule.Local(series.FirstStartTime))
+
+	rules, err := getBookingRules(ctx, tx, series.CourtID)
+	if err != nil {
+		return err
+	}
+	approvalNeeded, err := needsApproval(ctx, tx, rules, series.CourtID, series.UserID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	held, err := queryBookings(ctx, tx, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.court_id = $1 AND b.user_id = $2 AND b.status IN ('pending', 'confirmed')
+		AND b.end_time > $3
+	`, series.CourtID, series.UserID, models.StartOfWeek(schedule.Local(now)))
+	if err != nil {
+		return fmt.Errorf("failed to query user bookings: %w", err)
+	}
+	for _, existing := range held {
+		existing.StartTime, existing.EndTime = schedule.Local(existing.StartTime), schedule.Local(existing.EndTime)
+	}
+
+	var bookings []*models.Booking
+	var preferred *uuid.UUID
+	series.Occurrences = nil
+	for _, start := range starts {
+		end := start.Add(series.Duration())
+		occurrence := models.SeriesOccurrence{Date: start.Format("2006-01-02"), StartTime: start, EndTime: end}
+
+		opens, _, _ := schedule.HoursOn(start)
+		if err := schedule.CheckOpen(start, end); err != nil {
+			occurrence.Status, occurrence.Reason = models.OccurrenceClosed, err.Error()
+		} else if err := rules.CheckTimes(start, end, opens, now); err != nil {
+			occurrence.Status, occurrence.Reason = models.OccurrenceNotAllowed, err.Error()
+		} else if err := rules.CheckLimits(held, start, end, now); err != nil {
+			occurrence.Status, occurrence.Reason = models.OccurrenceNotAllowed, err.Error()
+		} else {
+			// Keep the series on one court where possible
+			open := schedule.OpenUnits(units, start, end)
+			for i := range open {
+				if preferred != nil && open[i].ID == *preferred {
+					open[0], open[i] = open[i], open[0]
+				}
+			}
+
+			// Courts held for waitlisted players can only be claimed by them
+			holds, err := heldUnits(ctx, tx, series.CourtID, series.UserID, start, end)
+			if err != nil {
+				return err
+			}
+
+			var unit *models.CourtUnit
+			for i := range open {
+				if holds[open[i].ID] {
+					continue
+				}
+				conflicts, err := checkConflicts(ctx, tx, open[i].ID, start, end, uuid.Nil)
+				if err != nil {
+					return err
+				}
+				if len(conflicts) == 0 {
+					unit = &open[i]
+					break
+				}
+				for _, conflict := range conflicts {
+					occurrence.Conflicts = append(occurrence.Conflicts, conflict.ID)
+				}
+			}
+
+			switch {
+			case unit != nil:
+				booking := &models.Booking{
+					ID:            uuid.New(),
+					CourtID:       series.CourtID,
+					CourtUnitID:   &unit.ID,
+					CourtUnitName: unit.Name,
+					UserID:        series.UserID,
+					StartTime:     start,
+					EndTime:       end,
+					Status:        models.BookingStatusPending,
+					PlayerCount:   series.PlayerCount,
+					GameType:      series.GameType,
+					Notes:         series.Notes,
+					SeriesID:      &series.ID,
+					CreatedAt:     series.CreatedAt,
+					UpdatedAt:     series.UpdatedAt,
+				}
+				// Each occurrence is a request of its own at courts that need approval
+				if approvalNeeded {
+					awaitApproval(booking, rules, now)
+				}
+				bookings = append(bookings, booking)
+				held = append(held, booking)
+				preferred = &unit.ID
+				occurrence.Status, occurrence.BookingID = models.OccurrenceBooked, &booking.ID
+				occurrence.Conflicts = nil
+			case len(open) == 0:
+				occurrence.Status, occurrence.Reason = models.OccurrenceClosed, "court is closed for maintenance"
+			default:
+				occurrence.Status, occurrence.Reason = models.OccurrenceConflict, "court is not available during the requested time"
+			}
+		}
+		series.Occurrences = append(series.Occurrences, occurrence)
+	}
+
+	if len(bookings) == 0 {
+		return fmt.Errorf("series has conflicts: no occurrences are available")
+	}
+	if len(bookings) < len(starts) && !skipConflicts {
+		return fmt.Errorf("series has conflicts: %d of %d occurrences are unavailable", len(starts)-len(bookings), len(starts))
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO booking_series (
+			id, court_id, court_unit_id, user_id, frequency, repeat_interval, until_date,
+			first_start_time, duration_minutes, status, player_count, game_type, notes, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`,
+		series.ID, series.CourtID, series.CourtUnitID, series.UserID, series.Frequency, series.Interval, series.Until,
+		series.FirstStartTime, series.DurationMinutes, series.Status, series.PlayerCount, series.GameType, series.Notes,
+		series.CreatedAt, series.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create booking series: %w", err)
+	}
+
+	for _, booking := range bookings {
+		if err = insertBooking(ctx, tx, booking); err != nil {
+			return err
+		}
+	}
+	for _, occurrence := range series.Occurrences {
+		if occurrence.Status == models.OccurrenceBooked {
+			continue
+		}
+		if err = insertSeriesSkip(ctx, tx, series.ID, occurrence.Date, occurrence.Reason); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	series.FirstStartTime = series.FirstStartTime.UTC()
+	for _, booking := range bookings {
+		booking.Localize(&booking.StartTime, &booking.EndTime, schedule.Timezone)
+	}
+	series.Bookings = bookings
+	return nil
+}
+
+// AbandonSeries cancels a series that could not be set up, along with all of
+// its bookings
+func (r *BookingRepository) AbandonSeries(ctx context.Context, seriesID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE bookings SET status = $1, updated_at = $2 WHERE series_id = $3 AND status IN ('pending', 'confirmed')
+	`, models.BookingStatusCancelled, now, seriesID); err != nil {
+		return fmt.Errorf("failed to cancel series bookings: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE booking_series SET status = $1, updated_at = $2 WHERE id = $3
+	`, models.BookingSeriesStatusCancelled, now, seriesID); err != nil {
+		return fmt.Errorf("failed to cancel booking series: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func insertSeriesSkip(ctx context.Context, tx *sql.Tx, seriesID uuid.UUID, date, reason string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO booking_series_skips (series_id, occurrence_date, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (series_id, occurrence_date) DO NOTHING
+	`, seriesID, date, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record skipped occurrence: %w", err)
+	}
+	return nil
+}
+
+// GetSeries retrieves a booking series with a report on each of its occurrences
+func (r *BookingRepository) GetSeries(ctx context.Context, id uuid.UUID) (*models.BookingSeries, error) {
+	series, err := scanBookingSeries(r.db.QueryRowContext(ctx, `SELECT `+bookingSeriesColumns+` WHERE s.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("booking series not found")
+		}
+		return nil, fmt.Errorf("failed to get booking series: %w", err)
+	}
+
+	bookings, err := queryBookings(ctx, r.db, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.series_id = $1
+		ORDER BY b.start_time ASC
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query series bookings: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT to_char(occurrence_date, 'YYYY-MM-DD'), reason
+		FROM booking_series_skips
+		WHERE series_id = $1
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query skipped occurrences: %w", err)
+	}
+	defer rows.Close()
+
+	skipped := make(map[string]string)
+	for rows.Next() {
+		var date, reason string
+		if err := rows.Scan(&date, &reason); err != nil {
+			return nil, fmt.Errorf("failed to scan skipped occurrence: %w", err)
+		}
+		skipped[date] = reason
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query skipped occurrences: %w", err)
+	}
+
+	starts := series.RecurrenceRule.Starts(series.FirstStartTime.In(utils.LoadTimezone(series.Timezone)))
+	series.Occurrences = models.BuildSeriesOccurrences(series, starts, bookings, skipped)
+	return series, nil
+}
+
+// GetSeriesByUserID retrieves a user's booking series, without occurrence reports
+func (r *BookingRepository) GetSeriesByUserID(ctx context.Context, userID uuid.UUID) ([]*models.BookingSeries, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+bookingSeriesColumns+`
+		WHERE s.user_id = $1
+		ORDER BY s.first_start_time ASC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query booking series: %w", err)
+	}
+	defer rows.Close()
+
+	var series []*models.BookingSeries
+	for rows.Next() {
+		s, err := scanBookingSeries(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan booking series: %w", err)
+		}
+		series = append(series, s)
+	}
+	return series, rows.Err()
+}
+
+// SkipOccurrence skips the occurrence of a series on a date in the court's
+// timezone, cancelling its booking if it has one. The cancelled booking is
+// returned so its payment can be settled.
+func (r *BookingRepository) SkipOccurrence(ctx context.Context, seriesID, userID uuid.UUID, date string) (*models.Booking, error) {
+	series, err := r.GetSeries(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if series.UserID != userID {
+		return nil, fmt.Errorf("booking series does not belong to user")
+	}
+
+	var occurrence *models.SeriesOccurrence
+	for i := range series.Occurrences {
+		if series.Occurrences[i].Date == date {
+			occurrence = &series.Occurrences[i]
+		}
+	}
+	if occurrence == nil {
+		return nil, fmt.Errorf("series has no occurrence on %s", date)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var booking *models.Booking
+	if occurrence.Status == models.OccurrenceBooked {
+		booking, err = r.GetByID(ctx, *occurrence.BookingID)
+		if err != nil {
+			return nil, err
+		}
+		rules, err := getBookingRules(ctx, tx, series.CourtID)
+		if err != nil {
+			return nil, err
+		}
+		if !rules.CanCancel(booking, time.Now()) {
+			return nil, fmt.Errorf("booking cannot be cancelled")
+		}
+		if _, err = tx.ExecContext(ctx, `
+			UPDATE bookings SET status = $1, updated_at = $2 WHERE id = $3
+		`, models.BookingStatusCancelled, time.Now(), booking.ID); err != nil {
+			return nil, fmt.Errorf("failed to cancel booking: %w", err)
+		}
+		booking.Status = models.BookingStatusCancelled
+		if err = syncOpenSpotsBulletin(ctx, tx, booking); err != nil {
+			return nil, err
+		}
+	}
+	if err = insertSeriesSkip(ctx, tx, seriesID, date, "skipped by player"); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return booking, nil
+}
+
+// CancelSeries cancels a series and each of its upcoming bookings that is
+// still outside the court's cancellation cutoff. The cancelled bookings are
+// returned along with those inside the cutoff, which are kept.
+func (r *BookingRepository) CancelSeries(ctx context.Context, seriesID, userID uuid.UUID) ([]*models.Booking, []*models.Booking, error) {
+	series, err := r.GetSeries(ctx, seriesID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if series.UserID != userID {
+		return nil, nil, fmt.Errorf("booking series does not belong to user")
+	}
+	if series.Status == models.BookingSeriesStatusCancelled {
+		return nil, nil, fmt.Errorf("booking series is already cancelled")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rules, err := getBookingRules(ctx, tx, series.CourtID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bookings, err := queryBookings(ctx, tx, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.series_id = $1 AND b.status IN ('pending', 'confirmed') AND b.start_time > $2
+	`, seriesID, time.Now())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query series bookings: %w", err)
+	}
+
+	now := time.Now()
+	var cancelled, kept []*models.Booking
+	for _, booking := range bookings {
+		if !rules.CanCancel(booking, now) {
+			kept = append(kept, booking)
+			continue
+		}
+		if _, err = tx.ExecContext(ctx, `
+			UPDATE bookings SET status = $1, updated_at = $2 WHERE id = $3
+		`, models.BookingStatusCancelled, now, booking.ID); err != nil {
+			return nil, nil, fmt.Errorf("failed to cancel booking: %w", err)
+		}
+		booking.Status = models.BookingStatusCancelled
+		if err = syncOpenSpotsBulletin(ctx, tx, booking); err != nil {
+			return nil, nil, err
+		}
+		cancelled = append(cancelled, booking)
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE booking_series SET status = $1, updated_at = $2 WHERE id = $3
+	`, models.BookingSeriesStatusCancelled, now, seriesID); err != nil {
+		return nil, nil, fmt.Errorf("failed to cancel booking series: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return cancelled, kept, nil
+}
@@ -0,0 +1,397 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+// waitlistColumns is the column list scanned by scanWaitlistEntry
+const waitlistColumns = `
+	w.id, w.court_id, w.court_unit_id, w.user_id, w.start_time, w.end_time, w.player_count,
+	COALESCE(w.game_type, ''), COALESCE(w.notes, ''), w.status, w.hold_unit_id, COALESCE(w.hold_token, ''),
+	w.hold_expires_at, w.booking_id, w.created_at, w.updated_at,
+	COALESCE(c.timezone, ''), c.latitude, c.longitude
+	FROM booking_waitlist w
+	JOIN courts c ON w.court_id = c.id
+`
+
+func scanWaitlistEntry(row rowScanner) (*models.BookingWaitlistEntry, error) {
+	entry := &models.BookingWaitlistEntry{}
+	var unitID, holdUnitID, bookingID uuid.NullUUID
+	var holdExpiresAt sql.NullTime
+	var timezone string
+	var latitude, longitude float64
+	err := row.Scan(
+		&entry.ID, &entry.CourtID, &unitID, &entry.UserID, &entry.StartTime, &entry.EndTime, &entry.PlayerCount,
+		&entry.GameType, &entry.Notes, &entry.Status, &holdUnitID, &entry.HoldToken,
+		&holdExpiresAt, &bookingID, &entry.CreatedAt, &entry.UpdatedAt,
+		&timezone, &latitude, &longitude,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if unitID.Valid {
+		entry.CourtUnitID = &unitID.UUID
+	}
+	if holdUnitID.Valid {
+		entry.HoldUnitID = &holdUnitID.UUID
+	}
+	if holdExpiresAt.Valid {
+		entry.HoldExpiresAt = &holdExpiresAt.Time
+	}
+	if bookingID.Valid {
+		entry.BookingID = &bookingID.UUID
+	}
+	entry.Localize(&entry.StartTime, &entry.EndTime, courtTimezone(timezone, latitude, longitude))
+	return entry, nil
+}
+
+func queryWaitlist(ctx context.Context, q queryer, query string, args ...interface{}) ([]*models.BookingWaitlistEntry, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.BookingWaitlistEntry
+	for rows.Next() {
+		entry, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan waitlist entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// heldUnits returns the courts at a facility held for other waitlisted
+// players between start and end
+func heldUnits(ctx context.Context, q queryer, courtID, userID uuid.UUID, start, end time.Time) (map[uuid.UUID]bool, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT hold_unit_id FROM booking_waitlist
+		WHERE court_id = $1 AND user_id != $2 AND status = 'offered' AND hold_expires_at > $5
+		AND hold_unit_id IS NOT NULL AND start_time < $4 AND end_time > $3
+	`, courtID, userID, start, end, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waitlist holds: %w", err)
+	}
+	defer rows.Close()
+
+	held := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var unitID uuid.UUID
+		if err := rows.Scan(&unitID); err != nil {
+			return nil, fmt.Errorf("failed to scan waitlist hold: %w", err)
+		}
+		held[unitID] = true
+	}
+	return held, rows.Err()
+}
+
+// JoinWaitlist puts a player in line for a court that is taken for the
+// entry's window. It fails with "court is available" if it can be booked now.
+func (r *BookingRepository) JoinWaitlist(ctx context.Context, entry *models.BookingWaitlistEntry) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	entry.Status = models.WaitlistStatusWaiting
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = time.Now()
+
+	if entry.StartTime.Before(time.Now()) {
+		return fmt.Errorf("cannot join the waitlist for a time in the past")
+	}
+	if !entry.EndTime.After(entry.StartTime) {
+		return fmt.Errorf("end time must be after start time")
+	}
+
+	units, err := getCourtUnits(ctx, r.db, entry.CourtID, false)
+	if err != nil {
+		return err
+	}
+	if entry.CourtUnitID != nil {
+		var requested []models.CourtUnit
+		for _, unit := range units {
+			if unit.ID == *entry.CourtUnitID {
+				requested = append(requested, unit)
+			}
+		}
+		if len(requested) == 0 {
+			return fmt.Errorf("court unit not found")
+		}
+		units = requested
+	}
+
+	existing, err := queryBookings(ctx, r.db, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.court_id = $1 AND b.status IN ('pending', 'confirmed')
+		AND b.start_time < $3 AND b.end_time > $2
+	`, entry.CourtID, entry.StartTime, entry.EndTime)
+	if err != nil {
+		return fmt.Errorf("failed to check conflicts: %w", err)
+	}
+	held, err := heldUnits(ctx, r.db, entry.CourtID, entry.UserID, entry.StartTime, entry.EndTime)
+	if err != nil {
+		return err
+	}
+	for _, unit := range models.FreeUnits(units, existing, entry.StartTime, entry.EndTime) {
+		if !held[unit.ID] {
+			return fmt.Errorf("court is available, book it directly")
+		}
+	}
+
+	var waiting bool
+	err = r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM booking_waitlist
+			WHERE court_id = $1 AND user_id = $2 AND status IN ('waiting', 'offered')
+			AND start_time < $4 AND end_time > $3
+		)
+	`, entry.CourtID, entry.UserID, entry.StartTime, entry.EndTime).Scan(&waiting)
+	if err != nil {
+		return fmt.Errorf("failed to check waitlist: %w", err)
+	}
+	if waiting {
+		return fmt.Errorf("already on the waitlist for this time")
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO booking_waitlist (
+			id, court_id, court_unit_id, user_id, start_time, end_time, player_count,
+			game_type, notes, status, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`,
+		entry.ID, entry.CourtID, entry.CourtUnitID, entry.UserID, entry.StartTime, entry.EndTime, entry.PlayerCount,
+		entry.GameType, entry.Notes, entry.Status, entry.CreatedAt, entry.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to join waitlist: %w", err)
+	}
+	return nil
+}
+
+// offerFreedCourt holds a court freed between start and end for the first
+// player in line whose window it covers. It returns nil if nobody fits.
+func offerFreedCourt(ctx context.Context, tx *sql.Tx, courtID uuid.UUID, unitID *uuid.UUID, start, end time.Time) (*models.BookingWaitlistEntry, error) {
+	now := time.Now()
+	if !start.After(now) {
+		return nil, nil
+	}
+
+	entries, err := queryWaitlist(ctx, tx, `SELECT `+waitlistColumns+`
+		WHERE w.court_id = $1 AND w.status = 'waiting'
+		AND w.start_time >= $2 AND w.end_time <= $3
+		ORDER BY w.created_at ASC
+		FOR UPDATE OF w SKIP LOCKED
+	`, courtID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waitlist: %w", err)
+	}
+	entry := models.NextInLine(entries, unitID, start, end)
+	if entry == nil {
+		return nil, nil
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate hold token: %w", err)
+	}
+	expiresAt := now.Add(models.WaitlistHoldDuration)
+	if entry.StartTime.Before(expiresAt) {
+		expiresAt = entry.StartTime // A hold cannot outlast the slot
+	}
+	entry.Status = models.WaitlistStatusOffered
+	entry.HoldUnitID = unitID
+	entry.HoldToken = hex.EncodeToString(buf)
+	entry.HoldExpiresAt = &expiresAt
+	entry.UpdatedAt = now
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE booking_waitlist
+		SET status = $2, hold_unit_id = $3, hold_token = $4, hold_expires_at = $5, updated_at = $6
+		WHERE id = $1
+	`, entry.ID, entry.Status, entry.HoldUnitID, entry.HoldToken, entry.HoldExpiresAt, entry.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to offer court to waitlist: %w", err)
+	}
+	entry.ClaimLink = models.NewWaitlistClaimLink(entry.HoldToken)
+	return entry, nil
+}
+
+// OfferFreedBooking offers the court a cancelled booking held to the waitlist
+func (r *BookingRepository) OfferFreedBooking(ctx context.Context, booking *models.Booking) (*models.BookingWaitlistEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	offer, err := offerFreedCourt(ctx, tx, booking.CourtID, booking.CourtUnitID, booking.StartTime, booking.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return offer, nil
+}
+
+// ExpireHolds expires holds that were not claimed in time and offers each
+// court to the next player in line, returning the new offers
+func (r *BookingRepository) ExpireHolds(ctx context.Context) ([]*models.BookingWaitlistEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	expired, err := queryWaitlist(ctx, tx, `SELECT `+waitlistColumns+`
+		WHERE w.status = 'offered' AND w.hold_expires_at <= $1
+		ORDER BY w.hold_expires_at ASC
+		FOR UPDATE OF w SKIP LOCKED
+	`, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired holds: %w", err)
+	}
+
+	var offers []*models.BookingWaitlistEntry
+	for _, entry := range expired {
+		if _, err = tx.ExecContext(ctx, `
+			UPDATE booking_waitlist SET status = $2, updated_at = $3 WHERE id = $1
+		`, entry.ID, models.WaitlistStatusExpired, time.Now()); err != nil {
+			return nil, fmt.Errorf("failed to expire hold: %w", err)
+		}
+		offer, err := offerFreedCourt(ctx, tx, entry.CourtID, entry.HoldUnitID, entry.StartTime, entry.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		if offer != nil {
+			offers = append(offers, offer)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return offers, nil
+}
+
+// ClaimHold turns a player's hold into a booking on the held court
+func (r *BookingRepository) ClaimHold(ctx context.Context, token string, userID uuid.UUID) (*models.Booking, error) {
+	entry, err := scanWaitlistEntry(r.db.QueryRowContext(ctx, `SELECT `+waitlistColumns+` WHERE w.hold_token = $1`, token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("hold not found")
+		}
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+	if entry.UserID != userID {
+		return nil, fmt.Errorf("hold does not belong to user")
+	}
+	if entry.Status == models.WaitlistStatusClaimed {
+		return nil, fmt.Errorf("hold has already been claimed")
+	}
+	if !entry.HoldActive(time.Now()) {
+		return nil, fmt.Errorf("hold has expired")
+	}
+
+	booking := &models.Booking{
+		CourtID:     entry.CourtID,
+		CourtUnitID: entry.HoldUnitID,
+		UserID:      entry.UserID,
+		StartTime:   entry.StartTime,
+		EndTime:     entry.EndTime,
+		Status:      models.BookingStatusPending,
+		PlayerCount: entry.PlayerCount,
+		GameType:    entry.GameType,
+		Notes:       entry.Notes,
+	}
+	if err = r.Create(ctx, booking); err != nil {
+		return nil, err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE booking_waitlist SET status = $2, booking_id = $3, updated_at = $4 WHERE id = $1
+	`, entry.ID, models.WaitlistStatusClaimed, booking.ID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim hold: %w", err)
+	}
+	return booking, nil
+}
+
+// GetWaitlistByUserID retrieves a player's open waitlist entries with their
+// place in line, plus claim links for courts being held for them
+func (r *BookingRepository) GetWaitlistByUserID(ctx context.Context, userID uuid.UUID) ([]*models.BookingWaitlistEntry, error) {
+	entries, err := queryWaitlist(ctx, r.db, `SELECT `+waitlistColumns+`
+		WHERE w.user_id = $1 AND w.status IN ('waiting', 'offered') AND w.end_time > $2
+		ORDER BY w.start_time ASC
+	`, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waitlist: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.Status == models.WaitlistStatusOffered {
+			entry.ClaimLink = models.NewWaitlistClaimLink(entry.HoldToken)
+			continue
+		}
+		err := r.db.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM booking_waitlist
+			WHERE court_id = $1 AND status = 'waiting' AND created_at <= $2
+			AND start_time < $4 AND end_time > $3
+		`, entry.CourtID, entry.CreatedAt, entry.StartTime, entry.EndTime).Scan(&entry.Position)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get waitlist position: %w", err)
+		}
+	}
+	return entries, nil
+}
+
+// LeaveWaitlist takes a player out of line. A court being held for them is
+// offered to the next player, who is returned.
+func (r *BookingRepository) LeaveWaitlist(ctx context.Context, entryID, userID uuid.UUID) (*models.BookingWaitlistEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	entry, err := scanWaitlistEntry(tx.QueryRowContext(ctx, `SELECT `+waitlistColumns+` WHERE w.id = $1 FOR UPDATE OF w`, entryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("waitlist entry not found")
+		}
+		return nil, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+	if entry.UserID != userID {
+		return nil, fmt.Errorf("waitlist entry does not belong to user")
+	}
+	if entry.Status != models.WaitlistStatusWaiting && entry.Status != models.WaitlistStatusOffered {
+		return nil, fmt.Errorf("waitlist entry is no longer open")
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE booking_waitlist SET status = $2, updated_at = $3 WHERE id = $1
+	`, entry.ID, models.WaitlistStatusCancelled, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to leave waitlist: %w", err)
+	}
+
+	var offer *models.BookingWaitlistEntry
+	if entry.HoldActive(time.Now()) {
+		offer, err = offerFreedCourt(ctx, tx, entry.CourtID, entry.HoldUnitID, entry.StartTime, entry.EndTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return offer, nil
+}
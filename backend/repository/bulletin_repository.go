@@ -145,7 +145,7 @@ func (r *BulletinRepository) GetBulletins(ctx context.Context, latitude, longitu
 				id, user_id, title, description, latitude, longitude, zip_code, city, state,
 				court_id, start_time, end_time, skill_level, game_type, is_active,
 				created_at, updated_at,
-				( 6371 * acos( cos( radians($1) ) * cos( radians( latitude ) ) * cos( radians( longitude ) - radians($2) ) + sin( radians($1) ) * sin( radians( latitude ) ) ) ) AS distance
+				` + geoDistanceSQL(1, 2) + ` AS distance
 			FROM bulletins
 		`
 	} else {
@@ -196,26 +196,20 @@ func (r *BulletinRepository) GetBulletins(ctx context.Context, latitude, longitu
 		whereClauses = append(whereClauses, "is_active = TRUE")
 	}
 
+	// Add distance filter only if radius is specified (radius >= 0, in km)
+	if radius >= 0 {
+		distanceFilter, distanceArgs := geoRadiusFilter(latitude, longitude, radius, 1, 2, argCount)
+		whereClauses = append(whereClauses, distanceFilter)
+		args = append(args, distanceArgs...)
+		argCount += len(distanceArgs)
+	}
+
 	// Combine WHERE clauses
 	if len(whereClauses) > 0 {
 		baseQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
 		countQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
 	}
 
-	// Add distance filter only if radius is specified (radius >= 0)
-	if radius >= 0 {
-		distanceFilter := fmt.Sprintf("( 6371 * acos( cos( radians($1) ) * cos( radians( latitude ) ) * cos( radians( longitude ) - radians($2) ) + sin( radians($1) ) * sin( radians( latitude ) ) ) ) < $%d", argCount)
-		if len(whereClauses) == 0 {
-			baseQuery += " WHERE " + distanceFilter
-			countQuery += " WHERE " + distanceFilter
-		} else {
-			baseQuery += " AND " + distanceFilter
-			countQuery += " AND " + distanceFilter
-		}
-		args = append(args, radius) // radius in km
-		argCount++
-	}
-
 	// Get total count for pagination
 	var totalBulletins int
 	// Use all current args for count (pagination args haven't been added yet)
@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/database"
+	"github.com/user/tennis-connect/models"
+)
+
+// CalendarRepository handles database operations for players' calendar feeds
+type CalendarRepository struct {
+	db *database.DB
+}
+
+// NewCalendarRepository creates a new CalendarRepository
+func NewCalendarRepository(db *database.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// GetFeedToken returns the secret token in the user's calendar feed URL,
+// creating one the first time it is requested
+func (r *CalendarRepository) GetFeedToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	token, err := newFeedToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO calendar_feeds (user_id, token) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID, token)
+	if err != nil {
+		return "", fmt.Errorf("failed to create calendar feed: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, "SELECT token FROM calendar_feeds WHERE user_id = $1", userID).Scan(&token)
+	if err != nil {
+		return "", fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+	return token, nil
+}
+
+// ResetFeedToken replaces the user's feed token, so the old URL stops working
+func (r *CalendarRepository) ResetFeedToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	token, err := newFeedToken()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO calendar_feeds (user_id, token) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = NOW()
+	`, userID, token)
+	if err != nil {
+		return "", fmt.Errorf("failed to reset calendar feed: %w", err)
+	}
+	return token, nil
+}
+
+// GetUserIDByFeedToken finds whose calendar a feed token belongs to
+func (r *CalendarRepository) GetUserIDByFeedToken(ctx context.Context, token string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.QueryRowContext(ctx, "SELECT user_id FROM calendar_feeds WHERE token = $1", token).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, fmt.Errorf("calendar feed not found")
+		}
+		return uuid.Nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+	return userID, nil
+}
+
+func newFeedToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate calendar feed token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// GetEntries retrieves everything in the user's calendar that ends after
+// since: their bookings, events they RSVP'd to and their matches. Cancelled
+// plans are included so subscribed calendars drop them.
+func (r *CalendarRepository) GetEntries(ctx context.Context, userID uuid.UUID, since time.Time) ([]*models.CalendarEntry, error) {
+	bookings, err := r.queryBookingEntries(ctx, " AND b.end_time > $2", userID, since)
+	if err != nil {
+		return nil, err
+	}
+	events, err := r.queryEventEntries(ctx, "r.status IN ('Confirmed', 'Cancelled') AND e.end_time > $2", userID, since)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := r.queryMatchEntries(ctx, " AND pp.status IN ('confirmed', 'completed', 'cancelled') AND s.end_time > $2", userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := append(append(bookings, events...), matches...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartTime.Before(entries[j].StartTime)
+	})
+	return entries, nil
+}
+
+// GetBookingEntry retrieves a booking the user owns or is on the roster of
+func (r *CalendarRepository) GetBookingEntry(ctx context.Context, bookingID, userID uuid.UUID) (*models.CalendarEntry, error) {
+	entries, err := r.queryBookingEntries(ctx, " AND b.id = $2", userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("booking not found")
+	}
+	return entries[0], nil
+}
+
+// GetEventEntry retrieves an event as it appears in the user's calendar
+func (r *CalendarRepository) GetEventEntry(ctx context.Context, eventID, userID uuid.UUID) (*models.CalendarEntry, error) {
+	entries, err := r.queryEventEntries(ctx, "e.id = $2", userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("event not found")
+	}
+	return entries[0], nil
+}
+
+// GetMatchEntry retrieves a pairing the user plays in
+func (r *CalendarRepository) GetMatchEntry(ctx context.Context, pairingID, userID uuid.UUID) (*models.CalendarEntry, error) {
+	entries, err := r.queryMatchEntries(ctx, " AND pp.id = $2", userID, pairingID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("match not found")
+	}
+	return entries[0], nil
+}
+
+// queryBookingEntries reads the user's bookings, as owner or as a player who
+// answered an invitation, narrowed by filter
+func (r *CalendarRepository) queryBookingEntries(ctx context.Context, filter string, userID uuid.UUID, arg interface{}) ([]*models.CalendarEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT b.id, c.name, COALESCE(u.name, ''), COALESCE(c.city, ''), COALESCE(c.state, ''),
+			b.start_time, b.end_time, b.status, CASE WHEN b.user_id = $1 THEN '' ELSE p.status END,
+			COALESCE(b.game_type, ''), COALESCE(b.player_count, 0), COALESCE(b.notes, ''),
+			COALESCE(c.timezone, ''), c.latitude, c.longitude, b.created_at, GREATEST(b.updated_at, p.responded_at)
+		FROM bookings b
+		JOIN courts c ON b.court_id = c.id
+		LEFT JOIN court_units u ON b.court_unit_id = u.id
+		LEFT JOIN booking_participants p ON p.booking_id = b.id AND p.user_id = $1
+		WHERE (b.user_id = $1 OR p.status IN ('accepted', 'declined'))`+filter+`
+		ORDER BY b.start_time
+	`, userID, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query calendar bookings: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.CalendarEntry
+	for rows.Next() {
+		entry := &models.CalendarEntry{Type: models.CalendarEntryBooking}
+		var courtName, unitName, city, state, gameType, notes, override string
+		var status models.BookingStatus
+		var participantStatus sql.NullString
+		var playerCount int
+		var latitude, longitude float64
+		err := rows.Scan(&entry.ID, &courtName, &unitName, &city, &state,
+			&entry.StartTime, &entry.EndTime, &status, &participantStatus,
+			&gameType, &playerCount, &notes,
+			&override, &latitude, &longitude, &entry.CreatedAt, &entry.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar booking: %w", err)
+		}
+
+		entry.Summary = "Tennis at " + courtName
+		if unitName != "" {
+			entry.Summary += " (" + unitName + ")"
+		}
+		var details []string
+		if gameType != "" {
+			details = append(details, gameType)
+		}
+		if playerCount > 0 {
+			details = append(details, fmt.Sprintf("%d players", playerCount))
+		}
+		if status == models.BookingStatusPending {
+			details = append(details, "Not confirmed yet")
+		}
+		if notes != "" {
+			details = append(details, notes)
+		}
+		entry.Description = strings.Join(details, "\n")
+		entry.Location = models.CalendarLocation(courtName, city, state)
+		entry.Status = models.BookingCalendarStatus(status, participantStatus.String)
+		entry.Timezone = courtTimezone(override, latitude, longitude)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// queryEventEntries reads events matching where, with the user's RSVP
+func (r *CalendarRepository) queryEventEntries(ctx context.Context, where string, userID uuid.UUID, arg interface{}) ([]*models.CalendarEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT e.id, e.title, COALESCE(e.description, ''), COALESCE(c.name, ''), COALESCE(e.city, ''), COALESCE(e.state, ''),
+			e.start_time, e.end_time, e.status, CASE WHEN r.status = 'Cancelled' THEN 'Cancelled' ELSE 'Confirmed' END,
+			COALESCE(c.timezone, ''), COALESCE(c.latitude, e.latitude, 0), COALESCE(c.longitude, e.longitude, 0),
+			e.created_at, GREATEST(e.updated_at, r.updated_at)
+		FROM events e
+		LEFT JOIN event_rsvps r ON r.event_id = e.id AND r.user_id = $1
+		LEFT JOIN courts c ON e.court_id = c.id
+		WHERE `+where+`
+		ORDER BY e.start_time
+	`, userID, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query calendar events: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.CalendarEntry
+	for rows.Next() {
+		entry := &models.CalendarEntry{Type: models.CalendarEntryEvent}
+		var courtName, city, state, eventStatus, rsvpStatus, override string
+		var latitude, longitude float64
+		err := rows.Scan(&entry.ID, &entry.Summary, &entry.Description, &courtName, &city, &state,
+			&entry.StartTime, &entry.EndTime, &eventStatus, &rsvpStatus,
+			&override, &latitude, &longitude, &entry.CreatedAt, &entry.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar event: %w", err)
+		}
+
+		entry.Location = models.CalendarLocation(courtName, city, state)
+		entry.Status = models.EventCalendarStatus(eventStatus, rsvpStatus)
+		entry.Timezone = courtTimezone(override, latitude, longitude)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// queryMatchEntries reads pairings the user plays in, narrowed by filter
+func (r *CalendarRepository) queryMatchEntries(ctx context.Context, filter string, userID uuid.UUID, arg interface{}) ([]*models.CalendarEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT pp.id, s.game_type, c.name, COALESCE(c.city, ''), COALESCE(c.state, ''),
+			s.start_time, s.end_time, pp.status, s.status,
+			COALESCE(u1.name, ''), COALESCE(u2.name, ''), COALESCE(u3.name, ''), COALESCE(u4.name, ''),
+			COALESCE(c.timezone, ''), c.latitude, c.longitude, pp.created_at, GREATEST(pp.updated_at, s.updated_at)
+		FROM player_pairings pp
+		JOIN match_sessions s ON pp.match_session_id = s.id
+		JOIN courts c ON s.court_id = c.id
+		LEFT JOIN users u1 ON pp.player1_id = u1.id
+		LEFT JOIN users u2 ON pp.player2_id = u2.id
+		LEFT JOIN users u3 ON pp.player3_id = u3.id
+		LEFT JOIN users u4 ON pp.player4_id = u4.id
+		WHERE $1 IN (pp.player1_id, pp.player2_id, pp.player3_id, pp.player4_id)`+filter+`
+		ORDER BY s.start_time
+	`, userID, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query calendar matches: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.CalendarEntry
+	for rows.Next() {
+		entry := &models.CalendarEntry{Type: models.CalendarEntryMatch}
+		var gameType, courtName, city, state, override string
+		var pairingStatus, sessionStatus models.MatchingStatus
+		players := make([]string, 4)
+		var latitude, longitude float64
+		err := rows.Scan(&entry.ID, &gameType, &courtName, &city, &state,
+			&entry.StartTime, &entry.EndTime, &pairingStatus, &sessionStatus,
+			&players[0], &players[1], &players[2], &players[3],
+			&override, &latitude, &longitude, &entry.CreatedAt, &entry.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan calendar match: %w", err)
+		}
+
+		entry.Summary = fmt.Sprintf("%s match at %s", gameType, courtName)
+		var names []string
+		for _, name := range players {
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+		entry.Description = "Players: " + strings.Join(names, ", ")
+		entry.Location = models.CalendarLocation(courtName, city, state)
+		entry.Status = models.MatchCalendarStatus(pairingStatus, sessionStatus)
+		entry.Timezone = courtTimezone(override, latitude, longitude)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
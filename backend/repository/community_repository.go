@@ -153,7 +153,7 @@ func (r *CommunityRepository) GetCommunities(ctx context.Context, latitude, long
 		SELECT 
 			id, name, description, latitude, longitude, zip_code, city, state,
 			image_url, type, created_by, created_at, updated_at,
-			( 6371 * acos( cos( radians($1) ) * cos( radians( latitude ) ) * cos( radians( longitude ) - radians($2) ) + sin( radians($1) ) * sin( radians( latitude ) ) ) ) AS distance
+			` + geoDistanceSQL(1, 2) + ` AS distance
 		FROM communities
 	`
 	countQuery := `SELECT COUNT(*) FROM communities`
@@ -169,27 +169,19 @@ func (r *CommunityRepository) GetCommunities(ctx context.Context, latitude, long
 		argCount++
 	}
 
-	// Combine WHERE clauses
-	if len(whereClauses) > 0 {
-		baseQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
-		countQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
-	}
+	// Add distance filter (radius in km)
+	distanceFilter, distanceArgs := geoRadiusFilter(latitude, longitude, radius, 1, 2, argCount)
+	whereClauses = append(whereClauses, distanceFilter)
+	args = append(args, distanceArgs...)
+	argCount += len(distanceArgs)
 
-	// Add distance filter
-	distanceFilter := fmt.Sprintf("( 6371 * acos( cos( radians($1) ) * cos( radians( latitude ) ) * cos( radians( longitude ) - radians($2) ) + sin( radians($1) ) * sin( radians( latitude ) ) ) ) < $%d", argCount)
-	if len(whereClauses) == 0 {
-		baseQuery += " WHERE " + distanceFilter
-		countQuery += " WHERE " + distanceFilter
-	} else {
-		baseQuery += " AND " + distanceFilter
-		countQuery += " AND " + distanceFilter
-	}
-	args = append(args, radius) // radius in km
-	argCount++
+	// Combine WHERE clauses
+	baseQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
+	countQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
 
 	// Get total count for pagination
 	var totalCommunities int
-	err := r.db.QueryRowContext(ctx, countQuery, args[:argCount-1]...).Scan(&totalCommunities)
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCommunities)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count communities: %w", err)
 	}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/user/tennis-connect/models"
+)
+
+// ReportCondition saves a player's report of a court's condition and
+// rescores it. Once the score passes the flag threshold the condition is
+// flagged for the court's managers; the new flag is returned so they can be
+// told, or nil if there is none or it was already flagged.
+func (r *CourtRepository) ReportCondition(ctx context.Context, report *models.CourtConditionReport, now time.Time) (*models.CourtCondition, *models.CourtConditionFlag, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	report.ID = uuid.New()
+	report.CreatedAt = now
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO court_condition_reports (id, court_id, user_id, condition, comment, photo_url, created_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7)
+	`, report.ID, report.CourtID, report.UserID, report.Condition, report.Comment, report.PhotoURL, report.CreatedAt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save condition report: %w", err)
+	}
+
+	conditions, err := scoreCourtConditions(ctx, tx, report.CourtID, report.Condition, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	condition := &models.CourtCondition{Condition: report.Condition}
+	if len(conditions) > 0 {
+		condition = &conditions[0]
+	}
+
+	var flag *models.CourtConditionFlag
+	if condition.ShouldFlag() && !condition.Flagged {
+		flag = &models.CourtConditionFlag{
+			ID:        uuid.New(),
+			CourtID:   report.CourtID,
+			Condition: report.Condition,
+			Score:     condition.Score,
+			FlaggedAt: now,
+		}
+		// Another report may have flagged it first
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO court_condition_flags (id, court_id, condition, score, flagged_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (court_id, condition) WHERE resolved_at IS NULL DO NOTHING
+		`, flag.ID, flag.CourtID, flag.Condition, flag.Score, flag.FlaggedAt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to flag court condition: %w", err)
+		}
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			flag = nil
+		}
+		condition.Flagged = true
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return condition, flag, nil
+}
+
+// GetConditions returns the conditions currently reported at a court,
+// strongest first
+func (r *CourtRepository) GetConditions(ctx context.Context, courtID uuid.UUID, now time.Time) ([]models.CourtCondition, error) {
+	conditions, err := scoreCourtConditions(ctx, r.db, courtID, "", now)
+	if err != nil {
+		return nil, err
+	}
+	return models.CurrentConditions(conditions), nil
+}
+
+// GetConditionFlags returns a court's open condition flags, newest first
+func (r *CourtRepository) GetConditionFlags(ctx context.Context, courtID uuid.UUID) ([]models.CourtConditionFlag, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, court_id, condition, score, flagged_at
+		FROM court_condition_flags
+		WHERE court_id = $1 AND resolved_at IS NULL
+		ORDER BY flagged_at DESC
+	`, courtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query condition flags: %w", err)
+	}
+	defer rows.Close()
+
+	flags := []models.CourtConditionFlag{}
+	for rows.Next() {
+		var flag models.CourtConditionFlag
+		if err := rows.Scan(&flag.ID, &flag.CourtID, &flag.Condition, &flag.Score, &flag.FlaggedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan condition flag: %w", err)
+		}
+		flags = append(flags, flag)
+	}
+	return flags, rows.Err()
+}
+
+// ResolveCondition clears a condition at a court along with its open flag.
+// Fixed conditions count towards their reporters' reliability and dismissed
+// ones against it. Returns the number of reports resolved.
+func (r *CourtRepository) ResolveCondition(ctx context.Context, courtID uuid.UUID, condition string, resolvedBy uuid.UUID, dismissed bool, now time.Time) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	resolution := models.ConditionResolutionFixed
+	if dismissed {
+		resolution = models.ConditionResolutionDismissed
+	}
+	result, err := tx.ExecContext(ctx, `
+		UPDATE court_condition_reports SET resolved_at = $1, resolution = $2
+		WHERE court_id = $3 AND condition = $4 AND resolved_at IS NULL AND created_at > $5
+	`, now, resolution, courtID, condition, now.Add(-models.ConditionReportMaxAge(condition)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve condition reports: %w", err)
+	}
+	resolved, _ := result.RowsAffected()
+
+	result, err = tx.ExecContext(ctx, `
+		UPDATE court_condition_flags SET resolved_at = $1, resolved_by = $2, dismissed = $3
+		WHERE court_id = $4 AND condition = $5 AND resolved_at IS NULL
+	`, now, resolvedBy, dismissed, courtID, condition)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve condition flag: %w", err)
+	}
+	if flags, _ := result.RowsAffected(); resolved == 0 && flags == 0 {
+		return 0, fmt.Errorf("no open reports of this condition")
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return resolved, nil
+}
+
+// scoreCourtConditions scores the unresolved reports at a court, optionally
+// for a single condition, and marks the conditions that are flagged
+func scoreCourtConditions(ctx context.Context, q queryer, courtID uuid.UUID, condition string, now time.Time) ([]models.CourtCondition, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, user_id, condition, COALESCE(comment, ''), COALESCE(photo_url, ''), created_at
+		FROM court_condition_reports
+		WHERE court_id = $1 AND resolved_at IS NULL AND created_at > $2 AND ($3 = '' OR condition = $3)
+	`, courtID, now.Add(-models.ConditionReportHistory()), condition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query condition reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []models.CourtConditionReport
+	var userIDs []uuid.UUID
+	seen := map[uuid.UUID]bool{}
+	for rows.Next() {
+		report := models.CourtConditionReport{CourtID: courtID}
+		if err := rows.Scan(&report.ID, &report.UserID, &report.Condition, &report.Comment, &report.PhotoURL, &report.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan condition report: %w", err)
+		}
+		reports = append(reports, report)
+		if !seen[report.UserID] {
+			seen[report.UserID] = true
+			userIDs = append(userIDs, report.UserID)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating condition reports: %w", err)
+	}
+	rows.Close()
+
+	weights, err := getReporterWeights(ctx, q, courtID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	conditions := models.ScoreConditions(reports, weights, now)
+	if len(conditions) == 0 {
+		return conditions, nil
+	}
+
+	flagRows, err := q.QueryContext(ctx, `
+		SELECT condition FROM court_condition_flags WHERE court_id = $1 AND resolved_at IS NULL
+	`, courtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query condition flags: %w", err)
+	}
+	defer flagRows.Close()
+
+	flagged := map[string]bool{}
+	for flagRows.Next() {
+		var flaggedCondition string
+		if err := flagRows.Scan(&flaggedCondition); err != nil {
+			return nil, fmt.Errorf("failed to scan condition flag: %w", err)
+		}
+		flagged[flaggedCondition] = true
+	}
+	if err = flagRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating condition flags: %w", err)
+	}
+	for i := range conditions {
+		conditions[i].Flagged = flagged[conditions[i].Condition]
+	}
+	return conditions, nil
+}
+
+// getReporterWeights works out how much each player's condition reports
+// count at a court, from their track record and whether they manage it
+func getReporterWeights(ctx context.Context, q queryer, courtID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	weights := map[uuid.UUID]float64{}
+	if len(userIDs) == 0 {
+		return weights, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT u.id, COALESCE(u.is_verified, FALSE),
+			EXISTS (SELECT 1 FROM court_managers m WHERE m.court_id = $2 AND m.user_id = u.id),
+			COUNT(r.id) FILTER (WHERE r.resolution = 'fixed'),
+			COUNT(r.id) FILTER (WHERE r.resolution = 'dismissed')
+		FROM users u
+		LEFT JOIN court_condition_reports r ON r.user_id = u.id AND r.resolution IS NOT NULL
+		WHERE u.id = ANY($1)
+		GROUP BY u.id, u.is_verified
+	`, pq.Array(userIDs), courtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reporter history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		var stats models.ReporterStats
+		if err := rows.Scan(&userID, &stats.IsVerified, &stats.IsCourtAdmin, &stats.Fixed, &stats.Dismissed); err != nil {
+			return nil, fmt.Errorf("failed to scan reporter history: %w", err)
+		}
+		weights[userID] = models.ReporterWeight(stats)
+	}
+	return weights, rows.Err()
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/user/tennis-connect/models"
+)
+
+// GetFavorites returns the courts a player has favorited, home court first
+// then most recently favorited
+func (r *CourtRepository) GetFavorites(ctx context.Context, userID uuid.UUID) ([]models.FavoriteCourt, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT fc.court_id, fc.subscriptions, fc.created_at, u.home_court_id IS NOT DISTINCT FROM fc.court_id,
+			c.name, c.latitude, c.longitude, c.zip_code, c.city, c.state,
+			c.court_type, c.is_public, c.status, COALESCE(c.timezone, '')
+		FROM favorite_courts fc
+		JOIN courts c ON c.id = fc.court_id
+		JOIN users u ON u.id = fc.user_id
+		WHERE fc.user_id = $1
+		ORDER BY 4 DESC, fc.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query favorite courts: %w", err)
+	}
+	defer rows.Close()
+
+	favorites := []models.FavoriteCourt{}
+	for rows.Next() {
+		favorite := models.FavoriteCourt{UserID: userID, Court: &models.Court{}}
+		court := favorite.Court
+		err := rows.Scan(&favorite.CourtID, pq.Array(&favorite.Subscriptions), &favorite.CreatedAt, &favorite.IsHomeCourt,
+			&court.Name, &court.Location.Latitude, &court.Location.Longitude, &court.Location.ZipCode, &court.Location.City, &court.Location.State,
+			&court.CourtType, &court.IsPublic, &court.Status, &court.TimezoneOverride)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan favorite court: %w", err)
+		}
+		court.ID = favorite.CourtID
+		court.Timezone = courtTimezone(court.TimezoneOverride, court.Location.Latitude, court.Location.Longitude)
+		favorites = append(favorites, favorite)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating favorite courts: %w", err)
+	}
+	return favorites, nil
+}
+
+// SaveFavorite favorites a court for a player, or replaces what they're
+// subscribed to there if it's already a favorite
+func (r *CourtRepository) SaveFavorite(ctx context.Context, favorite *models.FavoriteCourt) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = checkFavoriteLimit(ctx, tx, favorite.UserID, favorite.CourtID); err != nil {
+		return err
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO favorite_courts (user_id, court_id, subscriptions, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, court_id) DO UPDATE SET subscriptions = EXCLUDED.subscriptions
+		RETURNING created_at, (SELECT home_court_id IS NOT DISTINCT FROM $2 FROM users WHERE id = $1)
+	`, favorite.UserID, favorite.CourtID, pq.Array(favorite.Subscriptions), time.Now()).Scan(&favorite.CreatedAt, &favorite.IsHomeCourt)
+	if err != nil {
+		return fmt.Errorf("failed to save favorite court: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// RemoveFavorite unfavorites a court. A player's home court is always a
+// favorite, so removing it also clears their home court.
+func (r *CourtRepository) RemoveFavorite(ctx context.Context, userID, courtID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM favorite_courts WHERE user_id = $1 AND court_id = $2", userID, courtID)
+	if err != nil {
+		return fmt.Errorf("failed to remove favorite court: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("favorite court not found")
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE users SET home_court_id = NULL WHERE id = $1 AND home_court_id = $2", userID, courtID)
+	if err != nil {
+		return fmt.Errorf("failed to clear home court: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetHomeCourt makes a court a player's home court, favoriting it with every
+// subscription if it isn't already a favorite. A nil court clears their home
+// court.
+func (r *CourtRepository) SetHomeCourt(ctx context.Context, userID uuid.UUID, courtID *uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if courtID != nil {
+		if err = checkFavoriteLimit(ctx, tx, userID, *courtID); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO favorite_courts (user_id, court_id, subscriptions, created_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, court_id) DO NOTHING
+		`, userID, *courtID, pq.Array(models.CourtActivities), time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to favorite home court: %w", err)
+		}
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE users SET home_court_id = $1, updated_at = $2 WHERE id = $3", courtID, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to set home court: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// checkFavoriteLimit returns an error if favoriting the court would take the
+// player over MaxFavoriteCourts
+func checkFavoriteLimit(ctx context.Context, tx *sql.Tx, userID, courtID uuid.UUID) error {
+	var count int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM favorite_courts WHERE user_id = $1 AND court_id != $2
+	`, userID, courtID).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to count favorite courts: %w", err)
+	}
+	if count >= models.MaxFavoriteCourts {
+		return fmt.Errorf("favorite court limit of %d reached", models.MaxFavoriteCourts)
+	}
+	return nil
+}
+
+// GetCourtSubscribers returns the players subscribed to an activity at a
+// court, leaving out the player behind it
+func (r *CourtRepository) GetCourtSubscribers(ctx context.Context, courtID uuid.UUID, activity string, excludeUserID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id FROM favorite_courts
+		WHERE court_id = $1 AND $2 = ANY(subscriptions) AND user_id != $3
+	`, courtID, activity, excludeUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan court subscriber: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating court subscribers: %w", err)
+	}
+	return userIDs, nil
+}
+
+// getCourtFavoriters returns which of the players have favorited the court,
+// for scoring matches by locality
+func getCourtFavoriters(ctx context.Context, q queryer, courtID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	favoriters := make(map[uuid.UUID]bool)
+	if courtID == uuid.Nil || len(userIDs) == 0 {
+		return favoriters, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT user_id FROM favorite_courts WHERE court_id = $1 AND user_id = ANY($2)
+	`, courtID, pq.Array(userIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court favorites: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan court favorite: %w", err)
+		}
+		favoriters[userID] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating court favorites: %w", err)
+	}
+	return favoriters, nil
+}
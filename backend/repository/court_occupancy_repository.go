@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+// GetOccupancy builds the live occupancy view of a court: who is checked in,
+// how many of its courts are taken and how busy it usually is
+func (r *CourtRepository) GetOccupancy(ctx context.Context, courtID uuid.UUID, now time.Time) (*models.CourtOccupancy, error) {
+	var status, override string
+	var latitude, longitude float64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT status, COALESCE(timezone, ''), latitude, longitude FROM courts WHERE id = $1
+	`, courtID).Scan(&status, &override, &latitude, &longitude)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("court not found")
+		}
+		return nil, fmt.Errorf("failed to get court: %w", err)
+	}
+	if status != models.CourtStatusApproved {
+		return nil, fmt.Errorf("court not found")
+	}
+	court := models.Court{Timezone: courtTimezone(override, latitude, longitude)}
+	loc := court.TimeLocation()
+
+	players, err := getActiveCheckIns(ctx, r.db, courtID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var total, booked int
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE EXISTS (
+				SELECT 1 FROM bookings b
+				WHERE b.court_unit_id = u.id AND b.status IN ('pending', 'confirmed')
+				AND b.start_time <= $2 AND b.end_time > $2
+			))
+		FROM court_units u
+		WHERE u.court_id = $1 AND u.is_active
+	`, courtID, now).Scan(&total, &booked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court occupancy: %w", err)
+	}
+
+	window := models.TimeRange{Start: now.Add(-models.CourtBusynessHistory), End: now}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT checked_in, LEAST(COALESCE(checked_out, expires_at), $3)
+		FROM check_ins
+		WHERE court_id = $1 AND checked_in < $3 AND COALESCE(checked_out, expires_at) > $2
+	`, courtID, window.Start, window.End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query check-in history: %w", err)
+	}
+	defer rows.Close()
+
+	var visits []models.TimeRange
+	for rows.Next() {
+		var visit models.TimeRange
+		if err := rows.Scan(&visit.Start, &visit.End); err != nil {
+			return nil, fmt.Errorf("failed to scan check-in history: %w", err)
+		}
+		visits = append(visits, visit)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating check-in history: %w", err)
+	}
+
+	busyness := models.BuildBusyness(visits, window, loc)
+	return models.NewCourtOccupancy(courtID, now, loc, players, total, booked, busyness), nil
+}
+
+// ExpireCheckIns checks out everyone whose check-in has run past its
+// expected duration, as of the time it expired
+func (r *CourtRepository) ExpireCheckIns(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE check_ins SET checked_out = expires_at
+		WHERE checked_out IS NULL AND expires_at <= $1
+	`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire check-ins: %w", err)
+	}
+	expired, _ := result.RowsAffected()
+	return expired, nil
+}
+
+// RecomputePopularity sets each listed court's popularity from the
+// player-hours checked in there over the busyness history
+func (r *CourtRepository) RecomputePopularity(ctx context.Context, now time.Time) (int64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.popularity, COALESCE(SUM(
+			EXTRACT(EPOCH FROM LEAST(COALESCE(ci.checked_out, ci.expires_at), $2) - GREATEST(ci.checked_in, $1)) / 3600
+		), 0)
+		FROM courts c
+		LEFT JOIN check_ins ci ON ci.court_id = c.id
+			AND ci.checked_in < $2 AND COALESCE(ci.checked_out, ci.expires_at) > $1
+		WHERE c.status = 'approved'
+		GROUP BY c.id, c.popularity
+	`, now.Add(-models.CourtBusynessHistory), now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query court check-in hours: %w", err)
+	}
+
+	changed := map[uuid.UUID]int{}
+	for rows.Next() {
+		var courtID uuid.UUID
+		var current int
+		var hours float64
+		if err := rows.Scan(&courtID, &current, &hours); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan court check-in hours: %w", err)
+		}
+		if popularity := models.PopularityFromPlayerHours(hours, models.CourtBusynessHistory); popularity != current {
+			changed[courtID] = popularity
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating court check-in hours: %w", err)
+	}
+
+	// updated_at is left alone; popularity isn't an edit to the court
+	for courtID, popularity := range changed {
+		if _, err := r.db.ExecContext(ctx, "UPDATE courts SET popularity = $1 WHERE id = $2", popularity, courtID); err != nil {
+			return 0, fmt.Errorf("failed to update court popularity: %w", err)
+		}
+	}
+	return int64(len(changed)), nil
+}
+
+// StartOccupancyMaintenance starts a background goroutine that expires
+// forgotten check-ins and keeps court popularity up to date
+func (r *CourtRepository) StartOccupancyMaintenance(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			now := time.Now()
+			if expired, err := r.ExpireCheckIns(ctx, now); err != nil {
+				log.Printf("Check-in expiry failed: %v", err)
+			} else if expired > 0 {
+				log.Printf("Expired %d check-ins", expired)
+			}
+			if _, err := r.RecomputePopularity(ctx, now); err != nil {
+				log.Printf("Court popularity update failed: %v", err)
+			}
+			cancel()
+
+			<-ticker.C
+		}
+	}()
+}
+
+// getActiveCheckIns loads the players checked in at a court who haven't
+// checked out or expired, most recent first
+func getActiveCheckIns(ctx context.Context, q queryer, courtID uuid.UUID, now time.Time) ([]models.CheckIn, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT ci.id, ci.court_id, ci.user_id, COALESCE(u.name, ''), COALESCE(ci.message, ''), ci.checked_in, ci.expires_at
+		FROM check_ins ci
+		LEFT JOIN users u ON u.id = ci.user_id
+		WHERE ci.court_id = $1 AND ci.checked_out IS NULL AND ci.expires_at > $2
+		ORDER BY ci.checked_in DESC
+	`, courtID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court check-ins: %w", err)
+	}
+	defer rows.Close()
+
+	var checkIns []models.CheckIn
+	for rows.Next() {
+		var checkIn models.CheckIn
+		if err := rows.Scan(&checkIn.ID, &checkIn.CourtID, &checkIn.UserID, &checkIn.UserName, &checkIn.Message, &checkIn.CheckedIn, &checkIn.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan check-in: %w", err)
+		}
+		checkIns = append(checkIns, checkIn)
+	}
+	return checkIns, rows.Err()
+}
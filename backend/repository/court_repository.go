@@ -3,10 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/user/tennis-connect/database"
 	"github.com/user/tennis-connect/models"
 	"github.com/user/tennis-connect/utils"
@@ -22,8 +25,23 @@ func NewCourtRepository(db *database.DB) *CourtRepository {
 	return &CourtRepository{db: db}
 }
 
-// Create inserts a new court into the database
+// Create inserts a new court into the database. The submitter, if any,
+// becomes the court's first manager.
 func (r *CourtRepository) Create(ctx context.Context, court *models.Court) error {
+	var edit *models.CourtEdit
+	if court.SubmittedBy != nil {
+		edit = &models.CourtEdit{UserID: *court.SubmittedBy}
+	}
+	return r.create(ctx, court, edit)
+}
+
+// CreateImported inserts a court from a bulk import, recording who imported
+// it and from where in its history. Importers don't become managers.
+func (r *CourtRepository) CreateImported(ctx context.Context, court *models.Court, edit *models.CourtEdit) error {
+	return r.create(ctx, court, edit)
+}
+
+func (r *CourtRepository) create(ctx context.Context, court *models.Court, edit *models.CourtEdit) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -33,6 +51,9 @@ func (r *CourtRepository) Create(ctx context.Context, court *models.Court) error
 	if court.ID == uuid.Nil {
 		court.ID = uuid.New()
 	}
+	if court.Status == "" {
+		court.Status = models.CourtStatusApproved
+	}
 	court.CreatedAt = time.Now()
 	court.UpdatedAt = time.Now()
 
@@ -40,20 +61,66 @@ func (r *CourtRepository) Create(ctx context.Context, court *models.Court) error
 		INSERT INTO courts (
 			id, name, description, latitude, longitude, zip_code, city, state, 
 			image_url, court_type, is_public, contact_info, website, popularity, 
-			created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			status, submitted_by, timezone, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NULLIF($17, ''), $18, $19)
 	`,
 		court.ID, court.Name, court.Description,
 		court.Location.Latitude, court.Location.Longitude, court.Location.ZipCode, court.Location.City, court.Location.State,
 		court.ImageURL, court.CourtType, court.IsPublic, court.ContactInfo, court.Website, court.Popularity,
-		court.CreatedAt, court.UpdatedAt,
+		court.Status, court.SubmittedBy, court.TimezoneOverride, court.CreatedAt, court.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert court: %w", err)
 	}
+	court.Timezone = courtTimezone(court.TimezoneOverride, court.Location.Latitude, court.Location.Longitude)
+
+	if err = replaceCourtAmenities(ctx, tx, court.ID, court.Amenities); err != nil {
+		return err
+	}
+	if err = replaceCourtPhotos(ctx, tx, court.ID, court.Photos); err != nil {
+		return err
+	}
+
+	// Every facility has at least one bookable court
+	if len(court.Units) == 0 {
+		court.Units = models.DefaultCourtUnits(1)
+	}
+	for i := range court.Units {
+		court.Units[i].CourtID = court.ID
+		if err = insertCourtUnit(ctx, tx, &court.Units[i]); err != nil {
+			return err
+		}
+	}
+
+	if court.SubmittedBy != nil {
+		_, err = tx.ExecContext(ctx, "INSERT INTO court_managers (court_id, user_id) VALUES ($1, $2)", court.ID, *court.SubmittedBy)
+		if err != nil {
+			return fmt.Errorf("failed to add court manager: %w", err)
+		}
+		court.ManagerIDs = []uuid.UUID{*court.SubmittedBy}
+	}
+	if edit != nil {
+		edit.CourtID = court.ID
+		edit.Action = models.CourtEditCreated
+		if err = insertCourtEdit(ctx, tx, edit); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// replaceCourtAmenities sets a court's amenities, creating any that are new
+func replaceCourtAmenities(ctx context.Context, tx *sql.Tx, courtID uuid.UUID, amenities []string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM court_amenities WHERE court_id = $1", courtID)
+	if err != nil {
+		return fmt.Errorf("failed to clear court amenities: %w", err)
+	}
 
-	// Insert amenities
-	for _, amenityName := range court.Amenities {
+	for _, amenityName := range amenities {
 		var amenityID uuid.UUID
 		// Get or create amenity ID
 		err = tx.QueryRowContext(ctx, "SELECT id FROM amenities WHERE name = $1", amenityName).Scan(&amenityID)
@@ -69,14 +136,52 @@ func (r *CourtRepository) Create(ctx context.Context, court *models.Court) error
 
 		_, err = tx.ExecContext(ctx, `
 			INSERT INTO court_amenities (court_id, amenity_id) VALUES ($1, $2)
-		`, court.ID, amenityID)
+			ON CONFLICT DO NOTHING
+		`, courtID, amenityID)
 		if err != nil {
 			return fmt.Errorf("failed to insert court amenity: %w", err)
 		}
 	}
+	return nil
+}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+// replaceCourtPhotos sets a court's photos in display order
+func replaceCourtPhotos(ctx context.Context, tx *sql.Tx, courtID uuid.UUID, photos []string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM court_photos WHERE court_id = $1", courtID)
+	if err != nil {
+		return fmt.Errorf("failed to clear court photos: %w", err)
+	}
+	for i, url := range photos {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO court_photos (id, court_id, url, position) VALUES ($1, $2, $3, $4)
+		`, uuid.New(), courtID, url, i)
+		if err != nil {
+			return fmt.Errorf("failed to insert court photo: %w", err)
+		}
+	}
+	return nil
+}
+
+// insertCourtEdit appends an entry to a court's edit history
+func insertCourtEdit(ctx context.Context, tx *sql.Tx, edit *models.CourtEdit) error {
+	if edit.ID == uuid.Nil {
+		edit.ID = uuid.New()
+	}
+	edit.CreatedAt = time.Now()
+	changes, err := json.Marshal(edit.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to encode court changes: %w", err)
+	}
+	if edit.Changes == nil {
+		changes = []byte("{}")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO court_edits (id, court_id, user_id, action, changes, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+	`, edit.ID, edit.CourtID, edit.UserID, edit.Action, changes, edit.Note, edit.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record court edit: %w", err)
 	}
 	return nil
 }
@@ -88,13 +193,13 @@ func (r *CourtRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Co
 		SELECT 
 			name, description, latitude, longitude, zip_code, city, state, 
 			image_url, court_type, is_public, contact_info, website, popularity, 
-			created_at, updated_at
+			status, COALESCE(review_note, ''), submitted_by, merged_into, COALESCE(timezone, ''), created_at, updated_at
 		FROM courts WHERE id = $1
 	`, id).Scan(
 		&court.Name, &court.Description,
 		&court.Location.Latitude, &court.Location.Longitude, &court.Location.ZipCode, &court.Location.City, &court.Location.State,
 		&court.ImageURL, &court.CourtType, &court.IsPublic, &court.ContactInfo, &court.Website, &court.Popularity,
-		&court.CreatedAt, &court.UpdatedAt,
+		&court.Status, &court.ReviewNote, &court.SubmittedBy, &court.MergedInto, &court.TimezoneOverride, &court.CreatedAt, &court.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -102,6 +207,21 @@ func (r *CourtRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Co
 		}
 		return nil, fmt.Errorf("failed to get court: %w", err)
 	}
+	court.Timezone = courtTimezone(court.TimezoneOverride, court.Location.Latitude, court.Location.Longitude)
+
+	// Query photos and managers
+	court.Photos, err = r.getCourtPhotos(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	court.ManagerIDs, err = r.getCourtManagerIDs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	court.Units, err = getCourtUnits(ctx, r.db, id, false)
+	if err != nil {
+		return nil, err
+	}
 
 	// Query amenities
 	amenityRows, err := r.db.QueryContext(ctx, `
@@ -122,52 +242,22 @@ func (r *CourtRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Co
 		court.Amenities = append(court.Amenities, amenityName)
 	}
 
-	// Query check-ins
-	checkInRows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, message, checked_in, checked_out 
-		FROM check_ins 
-		WHERE court_id = $1 AND checked_out IS NULL 
-		ORDER BY checked_in DESC
-	`, id)
+	// Query active check-ins
+	court.CheckIns, err = getActiveCheckIns(ctx, r.db, id, time.Now())
 	if err != nil {
-		return nil, fmt.Errorf("failed to query court check-ins: %w", err)
-	}
-	defer checkInRows.Close()
-	for checkInRows.Next() {
-		var checkIn models.CheckIn
-		// In a real app, you'd also fetch UserName based on user_id
-		// For now, UserName will be empty in this mock-up part of the repository.
-		if err := checkInRows.Scan(&checkIn.ID, &checkIn.UserID, &checkIn.Message, &checkIn.CheckedIn, &checkIn.CheckedOut); err != nil {
-			// If checked_out is NULL, Scan will error. Need to handle this.
-			// A simpler scan for now if CheckedOut can be null:
-			if err := r.db.QueryRowContext(ctx, `SELECT id, user_id, message, checked_in FROM check_ins WHERE id = $1`, checkIn.ID).Scan(&checkIn.ID, &checkIn.UserID, &checkIn.Message, &checkIn.CheckedIn); err != nil {
-				return nil, fmt.Errorf("failed to scan check-in: %w", err)
-			}
-		}
-		court.CheckIns = append(court.CheckIns, checkIn)
+		return nil, err
 	}
 
 	return court, nil
 }
 
 // GetCourts retrieves a list of courts with filtering and pagination
-func (r *CourtRepository) GetCourts(ctx context.Context, latitude, longitude, radius float64, courtType string, amenities []string, isPublicOnly bool, hasActivePlayers bool, page, limit int) ([]*models.Court, int, error) {
-	// Base query
-	// For production, use PostGIS for geospatial queries.
-	// This is a simplified distance calculation.
-	baseQuery := `
-		SELECT 
-			id, name, description, latitude, longitude, zip_code, city, state, 
-			image_url, court_type, is_public, contact_info, website, popularity, 
-			created_at, updated_at,
-			( 6371 * acos( cos( radians($1) ) * cos( radians( latitude ) ) * cos( radians( longitude ) - radians($2) ) + sin( radians($1) ) * sin( radians( latitude ) ) ) ) AS distance
-		FROM courts
-	`
-	countQuery := `SELECT COUNT(*) FROM courts`
-
-	whereClauses := []string{}
+func (r *CourtRepository) GetCourts(ctx context.Context, viewerID uuid.UUID, latitude, longitude, radius float64, courtType string, amenities []string, isPublicOnly bool, hasActivePlayers bool, favoritesOnly bool, minRating float64, sortBy string, page, limit int) ([]*models.Court, int, error) {
+	// Pending, archived and merged courts are not listed. Radius is in kilometres.
+	whereClauses := []string{"status = 'approved'"}
 	args := []interface{}{latitude, longitude}
 	argCount := 3
+	viewerArg := 0
 
 	if courtType != "" {
 		whereClauses = append(whereClauses, fmt.Sprintf("court_type = $%d", argCount))
@@ -177,6 +267,23 @@ func (r *CourtRepository) GetCourts(ctx context.Context, latitude, longitude, ra
 	if isPublicOnly {
 		whereClauses = append(whereClauses, "is_public = TRUE")
 	}
+	if hasActivePlayers {
+		whereClauses = append(whereClauses, `EXISTS (
+			SELECT 1 FROM check_ins ci
+			WHERE ci.court_id = courts.id AND ci.checked_out IS NULL AND ci.expires_at > NOW()
+		)`)
+	}
+	if favoritesOnly {
+		viewerArg = argCount
+		whereClauses = append(whereClauses, fmt.Sprintf("EXISTS (SELECT 1 FROM favorite_courts fc WHERE fc.user_id = $%d AND fc.court_id = courts.id)", viewerArg))
+		args = append(args, viewerID)
+		argCount++
+	}
+	if minRating > 0 {
+		whereClauses = append(whereClauses, fmt.Sprintf("COALESCE((SELECT overall_rating FROM court_ratings WHERE court_id = courts.id), 0) >= $%d", argCount))
+		args = append(args, minRating)
+		argCount++
+	}
 
 	if len(amenities) > 0 {
 		// This part is complex and requires a subquery or JOIN for multiple amenities
@@ -184,33 +291,54 @@ func (r *CourtRepository) GetCourts(ctx context.Context, latitude, longitude, ra
 		// In a real app: JOIN court_amenities and amenities and use WHERE amenity.name IN (...)
 	}
 
-	// Combine WHERE clauses
-	if len(whereClauses) > 0 {
-		baseQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
-		countQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
-	}
-
 	// Add distance filter
-	distanceFilter := fmt.Sprintf("( 6371 * acos( cos( radians($1) ) * cos( radians( latitude ) ) * cos( radians( longitude ) - radians($2) ) + sin( radians($1) ) * sin( radians( latitude ) ) ) ) < $%d", argCount)
-	if len(whereClauses) == 0 {
-		baseQuery += " WHERE " + distanceFilter
-		countQuery += " WHERE " + distanceFilter
-	} else {
-		baseQuery += " AND " + distanceFilter
-		countQuery += " AND " + distanceFilter
-	}
-	args = append(args, radius) // radius in km for this formula
-	argCount++
+	distanceFilter, distanceArgs := geoRadiusFilter(latitude, longitude, radius, 1, 2, argCount)
+	whereClauses = append(whereClauses, distanceFilter)
+	args = append(args, distanceArgs...)
+	argCount += len(distanceArgs)
+
+	where := " WHERE " + utils.JoinStrings(whereClauses, " AND ")
 
-	// Get total count for pagination
+	// Get total count for pagination, with only the arguments the filters use
 	var totalCourts int
-	err := r.db.QueryRowContext(ctx, countQuery, args[:argCount-1]...).Scan(&totalCourts) // countQuery doesn't need limit/offset
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM courts`+where, args...).Scan(&totalCourts)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count courts: %w", err)
 	}
 
-	// Add ordering and pagination
-	baseQuery += fmt.Sprintf(" ORDER BY distance ASC LIMIT $%d OFFSET $%d", argCount, argCount+1)
+	// The viewer's home court and favorites are flagged so they can be
+	// listed first
+	if viewerArg == 0 {
+		viewerArg = argCount
+		args = append(args, viewerID)
+		argCount++
+	}
+	baseQuery := fmt.Sprintf(`
+		SELECT 
+			id, name, description, latitude, longitude, zip_code, city, state, 
+			image_url, court_type, is_public, contact_info, website, popularity, 
+			COALESCE(timezone, ''), created_at, updated_at,
+			cr.overall_rating, cr.rating_count, cr.review_count, cr.surface_rating, cr.lighting_rating, cr.cleanliness_rating,
+			%s AS distance,
+			EXISTS (SELECT 1 FROM favorite_courts fc WHERE fc.user_id = $%d AND fc.court_id = courts.id) AS is_favorite,
+			EXISTS (SELECT 1 FROM users hu WHERE hu.id = $%d AND hu.home_court_id = courts.id) AS is_home_court
+		FROM courts
+		LEFT JOIN court_ratings cr ON cr.court_id = courts.id
+	`, geoDistanceSQL(1, 2), viewerArg, viewerArg) + where
+
+	// Add ordering and pagination. The home court and favorites come first.
+	// Rating order pulls courts with few ratings towards the prior so a
+	// single five-star rating doesn't come first.
+	baseQuery += " ORDER BY is_home_court DESC, is_favorite DESC, "
+	if sortBy == models.CourtSortRating {
+		baseQuery += fmt.Sprintf(`(COALESCE(cr.overall_rating, 0) * COALESCE(cr.rating_count, 0) + $%d::float * $%d::int)
+			/ (COALESCE(cr.rating_count, 0) + $%d::int) DESC, distance ASC`, argCount, argCount+1, argCount+1)
+		args = append(args, models.RatingPrior, models.RatingPriorWeight)
+		argCount += 2
+	} else {
+		baseQuery += "distance ASC"
+	}
+	baseQuery += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argCount, argCount+1)
 	args = append(args, limit, (page-1)*limit)
 
 	rows, err := r.db.QueryContext(ctx, baseQuery, args...)
@@ -223,15 +351,31 @@ func (r *CourtRepository) GetCourts(ctx context.Context, latitude, longitude, ra
 	for rows.Next() {
 		court := &models.Court{}
 		var distance float64 // to scan the calculated distance
+		var overall, surface, lighting, cleanliness sql.NullFloat64
+		var ratingCount, reviewCount sql.NullInt64
 		err := rows.Scan(
 			&court.ID, &court.Name, &court.Description,
 			&court.Location.Latitude, &court.Location.Longitude, &court.Location.ZipCode, &court.Location.City, &court.Location.State,
 			&court.ImageURL, &court.CourtType, &court.IsPublic, &court.ContactInfo, &court.Website, &court.Popularity,
-			&court.CreatedAt, &court.UpdatedAt, &distance,
+			&court.TimezoneOverride, &court.CreatedAt, &court.UpdatedAt,
+			&overall, &ratingCount, &reviewCount, &surface, &lighting, &cleanliness, &distance,
+			&court.IsFavorite, &court.IsHomeCourt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan court: %w", err)
 		}
+		if overall.Valid {
+			court.Rating = &models.CourtRatingSummary{
+				Overall:        overall.Float64,
+				RatingCount:    int(ratingCount.Int64),
+				ReviewCount:    int(reviewCount.Int64),
+				SurfaceQuality: surface.Float64,
+				Lighting:       lighting.Float64,
+				Cleanliness:    cleanliness.Float64,
+			}
+		}
+		court.Status = models.CourtStatusApproved
+		court.Timezone = courtTimezone(court.TimezoneOverride, court.Location.Latitude, court.Location.Longitude)
 		// Potentially fetch amenities and active check-ins for each court here if hasActivePlayers is true
 		// This can lead to N+1 queries, so consider optimizing
 		courts = append(courts, court)
@@ -243,22 +387,41 @@ func (r *CourtRepository) GetCourts(ctx context.Context, latitude, longitude, ra
 	return courts, totalCourts, nil
 }
 
-// CheckInUser checks a user into a court
-func (r *CourtRepository) CheckInUser(ctx context.Context, checkIn *models.CheckIn) error {
+// CheckInUser checks a user into a court for the given duration, after which
+// they are checked out automatically. Any check-in they still have open
+// elsewhere is closed, since a player can only be at one court.
+func (r *CourtRepository) CheckInUser(ctx context.Context, checkIn *models.CheckIn, duration time.Duration) error {
 	if checkIn.ID == uuid.Nil {
 		checkIn.ID = uuid.New()
 	}
 	checkIn.CheckedIn = time.Now()
+	checkIn.ExpiresAt = checkIn.CheckedIn.Add(duration)
 
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO check_ins (id, court_id, user_id, message, checked_in)
-		VALUES ($1, $2, $3, $4, $5)
-	`, checkIn.ID, checkIn.CourtID, checkIn.UserID, checkIn.Message, checkIn.CheckedIn)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE check_ins SET checked_out = LEAST($1, expires_at)
+		WHERE user_id = $2 AND checked_out IS NULL
+	`, checkIn.CheckedIn, checkIn.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to close previous check-in: %w", err)
+	}
 
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO check_ins (id, court_id, user_id, message, checked_in, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, checkIn.ID, checkIn.CourtID, checkIn.UserID, checkIn.Message, checkIn.CheckedIn, checkIn.ExpiresAt)
 	if err != nil {
 		return fmt.Errorf("failed to check in user: %w", err)
 	}
-	// Could update court popularity here
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
@@ -268,7 +431,7 @@ func (r *CourtRepository) CheckOutUser(ctx context.Context, courtID, userID uuid
 	result, err := r.db.ExecContext(ctx, `
 		UPDATE check_ins 
 		SET checked_out = $1
-		WHERE court_id = $2 AND user_id = $3 AND checked_out IS NULL
+		WHERE court_id = $2 AND user_id = $3 AND checked_out IS NULL AND expires_at > $1
 	`, now, courtID, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check out user: %w", err)
@@ -286,13 +449,13 @@ func (r *CourtRepository) CheckOutUser(ctx context.Context, courtID, userID uuid
 	var checkedOutCheckIn models.CheckIn
 	// Assuming user_name is not directly in check_ins table, this will be limited
 	err = r.db.QueryRowContext(ctx, `
-	    SELECT id, court_id, user_id, message, checked_in, checked_out 
+	    SELECT id, court_id, user_id, COALESCE(message, ''), checked_in, checked_out, expires_at 
 	    FROM check_ins 
 	    WHERE court_id = $1 AND user_id = $2 AND checked_out = $3 
 	    ORDER BY checked_in DESC LIMIT 1
 	`, courtID, userID, now).Scan(
 		&checkedOutCheckIn.ID, &checkedOutCheckIn.CourtID, &checkedOutCheckIn.UserID,
-		&checkedOutCheckIn.Message, &checkedOutCheckIn.CheckedIn, &checkedOutCheckIn.CheckedOut,
+		&checkedOutCheckIn.Message, &checkedOutCheckIn.CheckedIn, &checkedOutCheckIn.CheckedOut, &checkedOutCheckIn.ExpiresAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve updated check-in: %w", err)
@@ -300,3 +463,979 @@ func (r *CourtRepository) CheckOutUser(ctx context.Context, courtID, userID uuid
 
 	return &checkedOutCheckIn, nil
 }
+
+func (r *CourtRepository) getCourtPhotos(ctx context.Context, courtID uuid.UUID) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT url FROM court_photos WHERE court_id = $1 ORDER BY position", courtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court photos: %w", err)
+	}
+	defer rows.Close()
+
+	var photos []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan court photo: %w", err)
+		}
+		photos = append(photos, url)
+	}
+	return photos, rows.Err()
+}
+
+func (r *CourtRepository) getCourtManagerIDs(ctx context.Context, courtID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id FROM court_managers WHERE court_id = $1 ORDER BY created_at", courtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court managers: %w", err)
+	}
+	defer rows.Close()
+
+	var managerIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan court manager: %w", err)
+		}
+		managerIDs = append(managerIDs, userID)
+	}
+	return managerIDs, rows.Err()
+}
+
+// IsModerator returns true if the user may review submitted courts and manage any court
+func (r *CourtRepository) IsModerator(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var isModerator bool
+	err := r.db.QueryRowContext(ctx, "SELECT COALESCE(is_moderator, FALSE) FROM users WHERE id = $1", userID).Scan(&isModerator)
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to check moderator: %w", err)
+	}
+	return isModerator, nil
+}
+
+// lockCourtStatus locks a court row for the rest of the transaction and returns its status
+func lockCourtStatus(ctx context.Context, tx *sql.Tx, courtID uuid.UUID) (string, error) {
+	var status string
+	err := tx.QueryRowContext(ctx, "SELECT status FROM courts WHERE id = $1 FOR UPDATE", courtID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("court not found")
+		}
+		return "", fmt.Errorf("failed to lock court: %w", err)
+	}
+	return status, nil
+}
+
+// Update saves changes to a court's details and records them in its edit history
+func (r *CourtRepository) Update(ctx context.Context, court *models.Court, edit *models.CourtEdit) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	status, err := lockCourtStatus(ctx, tx, court.ID)
+	if err != nil {
+		return err
+	}
+	if status == models.CourtStatusMerged {
+		return fmt.Errorf("court has been merged")
+	}
+
+	court.UpdatedAt = time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE courts SET
+			name = $1, description = $2, latitude = $3, longitude = $4, zip_code = $5, city = $6, state = $7,
+			image_url = $8, court_type = $9, is_public = $10, contact_info = $11, website = $12,
+			timezone = NULLIF($13, ''), updated_at = $14
+		WHERE id = $15
+	`,
+		court.Name, court.Description,
+		court.Location.Latitude, court.Location.Longitude, court.Location.ZipCode, court.Location.City, court.Location.State,
+		court.ImageURL, court.CourtType, court.IsPublic, court.ContactInfo, court.Website,
+		court.TimezoneOverride, court.UpdatedAt, court.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update court: %w", err)
+	}
+	court.Timezone = courtTimezone(court.TimezoneOverride, court.Location.Latitude, court.Location.Longitude)
+
+	if _, ok := edit.Changes["amenities"]; ok {
+		if err = replaceCourtAmenities(ctx, tx, court.ID, court.Amenities); err != nil {
+			return err
+		}
+	}
+	if _, ok := edit.Changes["photos"]; ok {
+		if err = replaceCourtPhotos(ctx, tx, court.ID, court.Photos); err != nil {
+			return err
+		}
+	}
+
+	edit.CourtID = court.ID
+	edit.Action = models.CourtEditUpdated
+	if err = insertCourtEdit(ctx, tx, edit); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetStatus moves a court through the approval queue or archives it. Only
+// pending courts can be approved or rejected.
+func (r *CourtRepository) SetStatus(ctx context.Context, courtID uuid.UUID, status string, edit *models.CourtEdit) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := lockCourtStatus(ctx, tx, courtID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case current == models.CourtStatusMerged:
+		return fmt.Errorf("court has been merged")
+	case current == status:
+		return fmt.Errorf("court is already %s", status)
+	case (status == models.CourtStatusApproved || status == models.CourtStatusRejected) && current != models.CourtStatusPending:
+		return fmt.Errorf("court is not awaiting review")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE courts SET status = $1, review_note = NULLIF($2, ''), updated_at = $3 WHERE id = $4
+	`, status, edit.Note, time.Now(), courtID)
+	if err != nil {
+		return fmt.Errorf("failed to update court status: %w", err)
+	}
+
+	edit.CourtID = courtID
+	edit.Changes = map[string]models.CourtFieldChange{"status": {From: current, To: status}}
+	if err = insertCourtEdit(ctx, tx, edit); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Merge folds a duplicate court into the target. Check-ins, events, bulletins, bookings,
+// match sessions, court units, amenities, photos, managers, favorites and home courts move
+// to the target and the duplicate is kept, marked as merged, so old links can be redirected.
+func (r *CourtRepository) Merge(ctx context.Context, sourceID, targetID, userID uuid.UUID) error {
+	if sourceID == targetID {
+		return fmt.Errorf("cannot merge a court into itself")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock both courts in a consistent order to avoid deadlocks
+	first, second := sourceID, targetID
+	if first.String() > second.String() {
+		first, second = second, first
+	}
+	statuses := map[uuid.UUID]string{}
+	for _, id := range []uuid.UUID{first, second} {
+		if statuses[id], err = lockCourtStatus(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+	if statuses[sourceID] == models.CourtStatusMerged || statuses[targetID] == models.CourtStatusMerged {
+		return fmt.Errorf("court has been merged")
+	}
+
+	if err = mergeCourtUnits(ctx, tx, sourceID, targetID); err != nil {
+		return err
+	}
+
+	for _, table := range []string{"check_ins", "events", "bulletins", "bookings", "booking_series", "booking_waitlist", "match_sessions", "court_closures", "court_condition_reports"} {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET court_id = $1 WHERE court_id = $2", table), targetID, sourceID)
+		if err != nil {
+			return fmt.Errorf("failed to move %s: %w", table, err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO court_amenities (court_id, amenity_id)
+		SELECT $1, amenity_id FROM court_amenities WHERE court_id = $2
+		ON CONFLICT DO NOTHING
+	`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to merge court amenities: %w", err)
+	}
+
+	// Photos from the duplicate go after the target's own
+	_, err = tx.ExecContext(ctx, `
+		UPDATE court_photos SET
+			court_id = $1,
+			position = position + (SELECT COALESCE(MAX(position) + 1, 0) FROM court_photos WHERE court_id = $1)
+		WHERE court_id = $2
+	`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to merge court photos: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO court_managers (court_id, user_id)
+		SELECT $1, user_id FROM court_managers WHERE court_id = $2
+		ON CONFLICT DO NOTHING
+	`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to merge court managers: %w", err)
+	}
+
+	// Players who reviewed both courts keep their review of the target
+	_, err = tx.ExecContext(ctx, `
+		UPDATE court_reviews s SET court_id = $1
+		WHERE s.court_id = $2 AND NOT EXISTS (
+			SELECT 1 FROM court_reviews t WHERE t.court_id = $1 AND t.user_id = s.user_id
+		)
+	`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to merge court reviews: %w", err)
+	}
+	for _, id := range []uuid.UUID{sourceID, targetID} {
+		if err = refreshCourtRating(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	// Players who favourited both keep the union of their subscriptions
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO favorite_courts (user_id, court_id, subscriptions, created_at)
+		SELECT user_id, $1, subscriptions, created_at FROM favorite_courts WHERE court_id = $2
+		ON CONFLICT (user_id, court_id) DO UPDATE SET subscriptions = ARRAY(
+			SELECT DISTINCT unnest(favorite_courts.subscriptions || EXCLUDED.subscriptions) ORDER BY 1
+		)
+	`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to merge favorite courts: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM favorite_courts WHERE court_id = $1", sourceID); err != nil {
+		return fmt.Errorf("failed to merge favorite courts: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, "UPDATE users SET home_court_id = $1 WHERE home_court_id = $2", targetID, sourceID); err != nil {
+		return fmt.Errorf("failed to move home courts: %w", err)
+	}
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE courts SET popularity = popularity + (SELECT popularity FROM courts WHERE id = $1), updated_at = $2
+		WHERE id = $3
+	`, sourceID, now, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to update merged court: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE courts SET status = $1, merged_into = $2, updated_at = $3 WHERE id = $4
+	`, models.CourtStatusMerged, targetID, now, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to mark court as merged: %w", err)
+	}
+
+	edits := []*models.CourtEdit{
+		{CourtID: sourceID, UserID: userID, Action: models.CourtEditMerged, Changes: map[string]models.CourtFieldChange{
+			"merged_into": {From: nil, To: targetID},
+		}},
+		{CourtID: targetID, UserID: userID, Action: models.CourtEditMerged, Changes: map[string]models.CourtFieldChange{
+			"merged_from": {From: nil, To: sourceID},
+		}},
+	}
+	for _, edit := range edits {
+		if err = insertCourtEdit(ctx, tx, edit); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetHistory returns a court's edit history, newest first
+func (r *CourtRepository) GetHistory(ctx context.Context, courtID uuid.UUID) ([]models.CourtEdit, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT e.id, e.user_id, COALESCE(u.name, ''), e.action, e.changes, COALESCE(e.note, ''), e.created_at
+		FROM court_edits e
+		LEFT JOIN users u ON e.user_id = u.id
+		WHERE e.court_id = $1
+		ORDER BY e.created_at DESC
+	`, courtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court history: %w", err)
+	}
+	defer rows.Close()
+
+	edits := []models.CourtEdit{}
+	for rows.Next() {
+		edit := models.CourtEdit{CourtID: courtID}
+		var userID uuid.NullUUID
+		var changes []byte
+		if err := rows.Scan(&edit.ID, &userID, &edit.UserName, &edit.Action, &changes, &edit.Note, &edit.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan court edit: %w", err)
+		}
+		edit.UserID = userID.UUID
+		if err := json.Unmarshal(changes, &edit.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode court changes: %w", err)
+		}
+		edits = append(edits, edit)
+	}
+	return edits, rows.Err()
+}
+
+// GetPending returns courts waiting for review, oldest first
+func (r *CourtRepository) GetPending(ctx context.Context, page, limit int) ([]*models.Court, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM courts WHERE status = $1", models.CourtStatusPending).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count pending courts: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id FROM courts WHERE status = $1 ORDER BY created_at LIMIT $2 OFFSET $3
+	`, models.CourtStatusPending, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query pending courts: %w", err)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, 0, fmt.Errorf("failed to scan pending court: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	courts := []*models.Court{}
+	for _, id := range ids {
+		court, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, 0, err
+		}
+		courts = append(courts, court)
+	}
+	return courts, total, nil
+}
+
+// FindNear returns the courts within radiusKm of a point whatever their
+// status, except those merged away, for matching bulk imports against
+func (r *CourtRepository) FindNear(ctx context.Context, latitude, longitude, radiusKm float64) ([]*models.Court, error) {
+	distanceFilter, distanceArgs := geoRadiusFilter(latitude, longitude, radiusKm, 1, 2, 4)
+	args := append([]interface{}{latitude, longitude, models.CourtStatusMerged}, distanceArgs...)
+	return r.getCourtsByID(ctx, "SELECT id FROM courts WHERE status <> $3 AND "+distanceFilter, args...)
+}
+
+// GetCatalogue returns every court with the given status, by name, for export
+func (r *CourtRepository) GetCatalogue(ctx context.Context, status string) ([]*models.Court, error) {
+	return r.getCourtsByID(ctx, "SELECT id FROM courts WHERE status = $1 ORDER BY name, id", status)
+}
+
+// getCourtsByID loads the courts whose IDs a query selects
+func (r *CourtRepository) getCourtsByID(ctx context.Context, query string, args ...interface{}) ([]*models.Court, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query courts: %w", err)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan court: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query courts: %w", err)
+	}
+
+	courts := []*models.Court{}
+	for _, id := range ids {
+		court, err := r.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		courts = append(courts, court)
+	}
+	return courts, nil
+}
+
+func insertCourtUnit(ctx context.Context, tx *sql.Tx, unit *models.CourtUnit) error {
+	if unit.ID == uuid.Nil {
+		unit.ID = uuid.New()
+	}
+	unit.CreatedAt = time.Now()
+	unit.UpdatedAt = time.Now()
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO court_units (id, court_id, name, position, surface, has_lights, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9)
+	`, unit.ID, unit.CourtID, unit.Name, unit.Position, unit.Surface, unit.HasLights, unit.IsActive, unit.CreatedAt, unit.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert court unit: %w", err)
+	}
+	return nil
+}
+
+// getCourtUnits lists a facility's courts in display order. With lock set the
+// rows stay locked for the rest of the transaction.
+func getCourtUnits(ctx context.Context, q queryer, courtID uuid.UUID, lock bool) ([]models.CourtUnit, error) {
+	query := `
+		SELECT id, name, position, COALESCE(surface, ''), has_lights, is_active, created_at, updated_at
+		FROM court_units WHERE court_id = $1
+		ORDER BY position, name
+	`
+	if lock {
+		query += " FOR UPDATE"
+	}
+	rows, err := q.QueryContext(ctx, query, courtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court units: %w", err)
+	}
+	defer rows.Close()
+
+	units := []models.CourtUnit{}
+	for rows.Next() {
+		unit := models.CourtUnit{CourtID: courtID}
+		err := rows.Scan(&unit.ID, &unit.Name, &unit.Position, &unit.Surface, &unit.HasLights, &unit.IsActive,
+			&unit.CreatedAt, &unit.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan court unit: %w", err)
+		}
+		units = append(units, unit)
+	}
+	return units, rows.Err()
+}
+
+// mergeCourtUnits moves a duplicate facility's courts to the target. Bookings,
+// booking series and waitlist entries on a court the target already has by
+// name are moved onto the target's court before the duplicate is deleted.
+func mergeCourtUnits(ctx context.Context, tx *sql.Tx, sourceID, targetID uuid.UUID) error {
+	for _, ref := range []struct{ table, column string }{
+		{"bookings", "court_unit_id"},
+		{"booking_series", "court_unit_id"},
+		{"booking_waitlist", "court_unit_id"},
+		{"booking_waitlist", "hold_unit_id"},
+	} {
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+			UPDATE %[1]s x SET %[2]s = t.id
+			FROM court_units s
+			JOIN court_units t ON t.court_id = $1 AND t.name = s.name
+			WHERE s.court_id = $2 AND x.%[2]s = s.id
+		`, ref.table, ref.column), targetID, sourceID)
+		if err != nil {
+			return fmt.Errorf("failed to move %s between court units: %w", ref.table, err)
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM court_units s
+		USING court_units t
+		WHERE s.court_id = $2 AND t.court_id = $1 AND t.name = s.name
+	`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to remove duplicate court units: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE court_units SET
+			court_id = $1,
+			position = position + (SELECT COALESCE(MAX(position) + 1, 0) FROM court_units WHERE court_id = $1),
+			updated_at = NOW()
+		WHERE court_id = $2
+	`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to merge court units: %w", err)
+	}
+	return nil
+}
+
+// GetUnits lists the individual courts at a facility
+func (r *CourtRepository) GetUnits(ctx context.Context, courtID uuid.UUID) ([]models.CourtUnit, error) {
+	return getCourtUnits(ctx, r.db, courtID, false)
+}
+
+// GetUnit retrieves one court unit belonging to a facility
+func (r *CourtRepository) GetUnit(ctx context.Context, courtID, unitID uuid.UUID) (*models.CourtUnit, error) {
+	unit := &models.CourtUnit{ID: unitID, CourtID: courtID}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT name, position, COALESCE(surface, ''), has_lights, is_active, created_at, updated_at
+		FROM court_units WHERE id = $1 AND court_id = $2
+	`, unitID, courtID).Scan(&unit.Name, &unit.Position, &unit.Surface, &unit.HasLights, &unit.IsActive,
+		&unit.CreatedAt, &unit.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("court unit not found")
+		}
+		return nil, fmt.Errorf("failed to get court unit: %w", err)
+	}
+	return unit, nil
+}
+
+// CreateUnit adds a court to a facility, after its existing courts unless a position is given
+func (r *CourtRepository) CreateUnit(ctx context.Context, unit *models.CourtUnit) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = lockCourtStatus(ctx, tx, unit.CourtID); err != nil {
+		return err
+	}
+	units, err := getCourtUnits(ctx, tx, unit.CourtID, false)
+	if err != nil {
+		return err
+	}
+	for _, existing := range units {
+		if existing.Name == unit.Name {
+			return fmt.Errorf("court unit %s already exists", unit.Name)
+		}
+		if existing.Position >= unit.Position {
+			unit.Position = existing.Position + 1
+		}
+	}
+
+	if err = insertCourtUnit(ctx, tx, unit); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpdateUnit saves changes to a court unit
+func (r *CourtRepository) UpdateUnit(ctx context.Context, unit *models.CourtUnit) error {
+	unit.UpdatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE court_units SET name = $1, position = $2, surface = NULLIF($3, ''), has_lights = $4, is_active = $5, updated_at = $6
+		WHERE id = $7 AND court_id = $8
+	`, unit.Name, unit.Position, unit.Surface, unit.HasLights, unit.IsActive, unit.UpdatedAt, unit.ID, unit.CourtID)
+	if err != nil {
+		if strings.Contains(err.Error(), "court_units_court_id_name_key") {
+			return fmt.Errorf("court unit %s already exists", unit.Name)
+		}
+		return fmt.Errorf("failed to update court unit: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("court unit not found")
+	}
+	return nil
+}
+
+// AttachOccupancy sets how many of each facility's courts are free between start
+// and end, e.g. "4 of 8 courts free at 6:00pm" for search results
+func (r *CourtRepository) AttachOccupancy(ctx context.Context, courts []*models.Court, start, end time.Time) error {
+	if len(courts) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(courts))
+	for i, court := range courts {
+		ids[i] = court.ID
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.court_id,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE NOT EXISTS (
+				SELECT 1 FROM bookings b
+				WHERE b.court_unit_id = u.id AND b.status IN ('pending', 'confirmed')
+				AND b.start_time < $3 AND b.end_time > $2
+			))
+		FROM court_units u
+		WHERE u.court_id = ANY($1) AND u.is_active
+		GROUP BY u.court_id
+	`, pq.Array(ids), start, end)
+	if err != nil {
+		return fmt.Errorf("failed to query court occupancy: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[uuid.UUID][2]int{}
+	for rows.Next() {
+		var courtID uuid.UUID
+		var total, free int
+		if err := rows.Scan(&courtID, &total, &free); err != nil {
+			return fmt.Errorf("failed to scan court occupancy: %w", err)
+		}
+		counts[courtID] = [2]int{free, total}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating court occupancy: %w", err)
+	}
+
+	for _, court := range courts {
+		count := counts[court.ID]
+		occupancy := models.NewUnitOccupancy(start, count[0], count[1])
+		court.Occupancy = &occupancy
+	}
+	return nil
+}
+
+// getCourtSchedule loads a court's timezone and weekly hours plus the
+// holidays and closures falling between from and to
+func getCourtSchedule(ctx context.Context, q queryer, courtID uuid.UUID, from, to time.Time) (*models.CourtSchedule, error) {
+	schedule := &models.CourtSchedule{
+		CourtID:  courtID,
+		Hours:    []models.OperatingHours{},
+		Holidays: []models.CourtHoliday{},
+		Closures: []models.CourtClosure{},
+	}
+
+	var override string
+	var latitude, longitude float64
+	err := q.QueryRowContext(ctx, `
+		SELECT COALESCE(timezone, ''), latitude, longitude FROM courts WHERE id = $1
+	`, courtID).Scan(&override, &latitude, &longitude)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("court not found")
+		}
+		return nil, fmt.Errorf("failed to get court timezone: %w", err)
+	}
+	schedule.Timezone = courtTimezone(override, latitude, longitude)
+	schedule.Location = utils.LoadTimezone(schedule.Timezone)
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT day_of_week, COALESCE(to_char(opens_at, 'HH24:MI'), ''), COALESCE(to_char(closes_at, 'HH24:MI'), ''),
+			COALESCE(to_char(lights_off_at, 'HH24:MI'), ''), is_closed
+		FROM court_hours WHERE court_id = $1
+		ORDER BY day_of_week
+	`, courtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court hours: %w", err)
+	}
+	for rows.Next() {
+		var hours models.OperatingHours
+		if err := rows.Scan(&hours.DayOfWeek, &hours.OpensAt, &hours.ClosesAt, &hours.LightsOffAt, &hours.IsClosed); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan court hours: %w", err)
+		}
+		schedule.Hours = append(schedule.Hours, hours)
+	}
+	rows.Close()
+
+	rows, err = q.QueryContext(ctx, `
+		SELECT id, to_char(date, 'YYYY-MM-DD'), name, is_closed,
+			COALESCE(to_char(opens_at, 'HH24:MI'), ''), COALESCE(to_char(closes_at, 'HH24:MI'), ''), created_at
+		FROM court_holidays
+		WHERE court_id = $1 AND date BETWEEN $2::date AND $3::date
+		ORDER BY date
+	`, courtID, schedule.Local(from).Format("2006-01-02"), schedule.Local(to).Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court holidays: %w", err)
+	}
+	for rows.Next() {
+		holiday := models.CourtHoliday{CourtID: courtID}
+		err := rows.Scan(&holiday.ID, &holiday.Date, &holiday.Name, &holiday.IsClosed,
+			&holiday.OpensAt, &holiday.ClosesAt, &holiday.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan court holiday: %w", err)
+		}
+		schedule.Holidays = append(schedule.Holidays, holiday)
+	}
+	rows.Close()
+
+	rows, err = q.QueryContext(ctx, `
+		SELECT id, court_unit_id, start_time, end_time, reason, created_by, created_at
+		FROM court_closures
+		WHERE court_id = $1 AND start_time < $3 AND end_time > $2
+		ORDER BY start_time
+	`, courtID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court closures: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		closure, err := scanCourtClosure(rows)
+		if err != nil {
+			return nil, err
+		}
+		closure.CourtID = courtID
+		schedule.Closures = append(schedule.Closures, *closure)
+	}
+	return schedule, rows.Err()
+}
+
+func scanCourtClosure(row rowScanner) (*models.CourtClosure, error) {
+	closure := &models.CourtClosure{}
+	var unitID, createdBy uuid.NullUUID
+	err := row.Scan(&closure.ID, &unitID, &closure.StartTime, &closure.EndTime, &closure.Reason, &createdBy, &closure.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan court closure: %w", err)
+	}
+	if unitID.Valid {
+		closure.CourtUnitID = &unitID.UUID
+	}
+	closure.CreatedBy = createdBy.UUID
+	return closure, nil
+}
+
+// GetSchedule returns a court's weekly hours plus the holidays and closures between from and to
+func (r *CourtRepository) GetSchedule(ctx context.Context, courtID uuid.UUID, from, to time.Time) (*models.CourtSchedule, error) {
+	return getCourtSchedule(ctx, r.db, courtID, from, to)
+}
+
+// SetHours replaces a court's weekly operating hours. Days left out are closed;
+// an empty list restores the default hours.
+func (r *CourtRepository) SetHours(ctx context.Context, courtID uuid.UUID, hours []models.OperatingHours) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = lockCourtStatus(ctx, tx, courtID); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM court_hours WHERE court_id = $1", courtID); err != nil {
+		return fmt.Errorf("failed to clear court hours: %w", err)
+	}
+	for _, h := range hours {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO court_hours (court_id, day_of_week, opens_at, closes_at, lights_off_at, is_closed)
+			VALUES ($1, $2, NULLIF($3, '')::time, NULLIF($4, '')::time, NULLIF($5, '')::time, $6)
+		`, courtID, int(h.DayOfWeek), h.OpensAt, h.ClosesAt, h.LightsOffAt, h.IsClosed)
+		if err != nil {
+			return fmt.Errorf("failed to insert court hours: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SaveHoliday adds a holiday override, replacing any existing one for the same date
+func (r *CourtRepository) SaveHoliday(ctx context.Context, holiday *models.CourtHoliday) error {
+	if holiday.ID == uuid.Nil {
+		holiday.ID = uuid.New()
+	}
+	holiday.CreatedAt = time.Now()
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO court_holidays (id, court_id, date, name, is_closed, opens_at, closes_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, '')::time, NULLIF($7, '')::time, $8)
+		ON CONFLICT (court_id, date) DO UPDATE SET
+			name = EXCLUDED.name,
+			is_closed = EXCLUDED.is_closed,
+			opens_at = EXCLUDED.opens_at,
+			closes_at = EXCLUDED.closes_at
+		RETURNING id, created_at
+	`, holiday.ID, holiday.CourtID, holiday.Date, holiday.Name, holiday.IsClosed, holiday.OpensAt, holiday.ClosesAt,
+		holiday.CreatedAt).Scan(&holiday.ID, &holiday.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save court holiday: %w", err)
+	}
+	return nil
+}
+
+// DeleteHoliday removes a holiday override
+func (r *CourtRepository) DeleteHoliday(ctx context.Context, courtID, holidayID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM court_holidays WHERE id = $1 AND court_id = $2", holidayID, courtID)
+	if err != nil {
+		return fmt.Errorf("failed to delete court holiday: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("holiday not found")
+	}
+	return nil
+}
+
+// CreateClosure closes a court, or one of its units, for a period and returns
+// the bookings it affects. With cancelBookings set those bookings are
+// cancelled, and their payments are returned to be refunded.
+func (r *CourtRepository) CreateClosure(ctx context.Context, closure *models.CourtClosure, cancelBookings bool) ([]*models.Booking, []*models.BookingPayment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the units so no booking can slip in while the closure is created
+	units, err := getCourtUnits(ctx, tx, closure.CourtID, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if closure.CourtUnitID != nil {
+		found := false
+		for _, unit := range units {
+			found = found || unit.ID == *closure.CourtUnitID
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("court unit not found")
+		}
+	}
+
+	if closure.ID == uuid.Nil {
+		closure.ID = uuid.New()
+	}
+	closure.CreatedAt = time.Now()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO court_closures (id, court_id, court_unit_id, start_time, end_time, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, closure.ID, closure.CourtID, closure.CourtUnitID, closure.StartTime, closure.EndTime, closure.Reason,
+		closure.CreatedBy, closure.CreatedAt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create court closure: %w", err)
+	}
+
+	query := `SELECT ` + bookingColumns + bookingFrom + `
+		WHERE b.court_id = $1 AND b.status IN ('pending', 'confirmed')
+		AND b.start_time < $3 AND b.end_time > $2
+	`
+	args := []interface{}{closure.CourtID, closure.StartTime, closure.EndTime}
+	if closure.CourtUnitID != nil {
+		query += " AND b.court_unit_id = $4"
+		args = append(args, *closure.CourtUnitID)
+	}
+	affected, err := queryBookings(ctx, tx, query+" ORDER BY b.start_time", args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query affected bookings: %w", err)
+	}
+	if err = attachParticipants(ctx, tx, affected); err != nil {
+		return nil, nil, err
+	}
+
+	if cancelBookings {
+		for _, booking := range affected {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE bookings SET status = $1, updated_at = $2 WHERE id = $3
+			`, models.BookingStatusCancelled, closure.CreatedAt, booking.ID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to cancel booking: %w", err)
+			}
+			booking.Status = models.BookingStatusCancelled
+			if err = syncOpenSpotsBulletin(ctx, tx, booking); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	var payments []*models.BookingPayment
+	if cancelBookings && len(affected) > 0 {
+		bookingIDs := make([]uuid.UUID, len(affected))
+		for i, booking := range affected {
+			bookingIDs[i] = booking.ID
+		}
+		rows, err := tx.QueryContext(ctx, `SELECT `+paymentColumns+` FROM booking_payments WHERE booking_id = ANY($1)`, pq.Array(bookingIDs))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to query affected payments: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			payment, err := scanPayment(rows)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to scan payment: %w", err)
+			}
+			payments = append(payments, payment)
+		}
+		if err = rows.Err(); err != nil {
+			return nil, nil, fmt.Errorf("error iterating affected payments: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return affected, payments, nil
+}
+
+// DeleteClosure removes a closure, reopening the court for that period
+func (r *CourtRepository) DeleteClosure(ctx context.Context, courtID, closureID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM court_closures WHERE id = $1 AND court_id = $2", closureID, courtID)
+	if err != nil {
+		return fmt.Errorf("failed to delete court closure: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("closure not found")
+	}
+	return nil
+}
+
+// getBookingRules loads a court's booking rules, falling back to the defaults
+func getBookingRules(ctx context.Context, q queryer, courtID uuid.UUID) (*models.BookingRules, error) {
+	rules := &models.BookingRules{CourtID: courtID}
+	err := q.QueryRowContext(ctx, `
+		SELECT slot_minutes, align_to_slots, min_duration_minutes, max_duration_minutes, advance_days,
+			max_active_bookings, cancel_cutoff_minutes,
+			COALESCE(to_char(prime_time_start, 'HH24:MI'), ''), COALESCE(to_char(prime_time_end, 'HH24:MI'), ''),
+			prime_time_max_minutes, prime_time_max_per_week, updated_at,
+			COALESCE(require_approval, FALSE), approval_hours
+		FROM court_booking_rules WHERE court_id = $1
+	`, courtID).Scan(
+		&rules.SlotMinutes, &rules.AlignToSlots, &rules.MinDurationMinutes, &rules.MaxDurationMinutes, &rules.AdvanceDays,
+		&rules.MaxActiveBookings, &rules.CancelCutoffMinutes,
+		&rules.PrimeTimeStart, &rules.PrimeTimeEnd,
+		&rules.PrimeTimeMaxMinutes, &rules.PrimeTimeMaxPerWeek, &rules.UpdatedAt,
+		&rules.RequireApproval, &rules.ApprovalHours,
+	)
+	if err == sql.ErrNoRows {
+		return models.DefaultBookingRules(courtID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booking rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetBookingRules returns a court's booking rules, or the defaults if it has none
+func (r *CourtRepository) GetBookingRules(ctx context.Context, courtID uuid.UUID) (*models.BookingRules, error) {
+	return getBookingRules(ctx, r.db, courtID)
+}
+
+// SetBookingRules saves a court's booking rules
+func (r *CourtRepository) SetBookingRules(ctx context.Context, rules *models.BookingRules) error {
+	rules.UpdatedAt = time.Now()
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO court_booking_rules (
+			court_id, slot_minutes, align_to_slots, min_duration_minutes, max_duration_minutes, advance_days,
+			max_active_bookings, cancel_cutoff_minutes, prime_time_start, prime_time_end,
+			prime_time_max_minutes, prime_time_max_per_week, updated_at, require_approval, approval_hours
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, '')::time, NULLIF($10, '')::time, $11, $12, $13, $14, $15)
+		ON CONFLICT (court_id) DO UPDATE SET
+			slot_minutes = EXCLUDED.slot_minutes,
+			align_to_slots = EXCLUDED.align_to_slots,
+			min_duration_minutes = EXCLUDED.min_duration_minutes,
+			max_duration_minutes = EXCLUDED.max_duration_minutes,
+			advance_days = EXCLUDED.advance_days,
+			max_active_bookings = EXCLUDED.max_active_bookings,
+			cancel_cutoff_minutes = EXCLUDED.cancel_cutoff_minutes,
+			prime_time_start = EXCLUDED.prime_time_start,
+			prime_time_end = EXCLUDED.prime_time_end,
+			prime_time_max_minutes = EXCLUDED.prime_time_max_minutes,
+			prime_time_max_per_week = EXCLUDED.prime_time_max_per_week,
+			updated_at = EXCLUDED.updated_at,
+			require_approval = EXCLUDED.require_approval,
+			approval_hours = EXCLUDED.approval_hours
+	`,
+		rules.CourtID, rules.SlotMinutes, rules.AlignToSlots, rules.MinDurationMinutes, rules.MaxDurationMinutes, rules.AdvanceDays,
+		rules.MaxActiveBookings, rules.CancelCutoffMinutes, rules.PrimeTimeStart, rules.PrimeTimeEnd,
+		rules.PrimeTimeMaxMinutes, rules.PrimeTimeMaxPerWeek, rules.UpdatedAt, rules.RequireApproval, rules.ApprovalHours,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save booking rules: %w", err)
+	}
+	return nil
+}
+
+// courtTimezone returns a court's override timezone, or derives one from its coordinates
+func courtTimezone(override string, latitude, longitude float64) string {
+	if override != "" {
+		return override
+	}
+	return utils.TimezoneForCoordinates(latitude, longitude)
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/tennis-connect/models"
+)
+
+func TestCourtRepository_GetCourts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	courtRepo := NewCourtRepository(db)
+	userRepo := NewUserRepository(db)
+	ctx := context.Background()
+
+	location := models.Location{Latitude: 37.7749, Longitude: -122.4194, ZipCode: "94105", City: "San Francisco", State: "CA"}
+	user := &models.User{
+		Email:        "courts@example.com",
+		PasswordHash: "password123",
+		Name:         "Court Viewer",
+		SkillLevel:   4.0,
+		Location:     location,
+	}
+	require.NoError(t, userRepo.Create(ctx, user))
+
+	near := &models.Court{Name: "Near Court", Location: location, CourtType: "Hard", IsPublic: true}
+	require.NoError(t, courtRepo.Create(ctx, near))
+	favorite := &models.Court{Name: "Favorite Court", Location: location, CourtType: "Clay", IsPublic: true}
+	favorite.Location.Latitude += 0.01
+	require.NoError(t, courtRepo.Create(ctx, favorite))
+	require.NoError(t, courtRepo.SaveFavorite(ctx, &models.FavoriteCourt{UserID: user.ID, CourtID: favorite.ID}))
+
+	t.Run("All courts", func(t *testing.T) {
+		courts, total, err := courtRepo.GetCourts(ctx, user.ID, location.Latitude, location.Longitude, 10, "", nil, false, false, false, 0, "", 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, courts, 2)
+		assert.Equal(t, favorite.ID, courts[0].ID, "favorites are listed first")
+		assert.True(t, courts[0].IsFavorite)
+		assert.False(t, courts[1].IsFavorite)
+	})
+
+	t.Run("Filtered", func(t *testing.T) {
+		courts, total, err := courtRepo.GetCourts(ctx, user.ID, location.Latitude, location.Longitude, 10, "Hard", nil, true, false, false, 0, models.CourtSortRating, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, courts, 1)
+		assert.Equal(t, near.ID, courts[0].ID)
+	})
+
+	t.Run("Favorites only", func(t *testing.T) {
+		courts, total, err := courtRepo.GetCourts(ctx, user.ID, location.Latitude, location.Longitude, 10, "", nil, false, false, true, 0, "", 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, courts, 1)
+		assert.Equal(t, favorite.ID, courts[0].ID)
+	})
+}
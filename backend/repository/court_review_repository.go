@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/models"
+)
+
+const courtReviewColumns = `
+	cr.id, cr.court_id, cr.user_id, COALESCE(u.name, ''), cr.overall, cr.surface_quality, cr.lighting, cr.cleanliness,
+	COALESCE(cr.comment, ''), cr.status, COALESCE(cr.review_note, ''), cr.created_at, cr.updated_at
+`
+
+// SubmitReview adds a player's review of a court or replaces their existing
+// one, then updates the court's rating
+func (r *CourtRepository) SubmitReview(ctx context.Context, review *models.CourtReview) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	review.UpdatedAt = time.Now()
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO court_reviews (id, court_id, user_id, overall, surface_quality, lighting, cleanliness, comment, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $10)
+		ON CONFLICT (court_id, user_id) DO UPDATE SET
+			overall = EXCLUDED.overall,
+			surface_quality = EXCLUDED.surface_quality,
+			lighting = EXCLUDED.lighting,
+			cleanliness = EXCLUDED.cleanliness,
+			comment = EXCLUDED.comment,
+			status = EXCLUDED.status,
+			review_note = NULL,
+			reviewed_by = NULL,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`, uuid.New(), review.CourtID, review.UserID, review.Overall, review.SurfaceQuality, review.Lighting, review.Cleanliness,
+		review.Comment, review.Status, review.UpdatedAt).Scan(&review.ID, &review.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save court review: %w", err)
+	}
+
+	if err = refreshCourtRating(ctx, tx, review.CourtID); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteReview deletes a player's review of a court
+func (r *CourtRepository) DeleteReview(ctx context.Context, courtID, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM court_reviews WHERE court_id = $1 AND user_id = $2", courtID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete court review: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("review not found")
+	}
+
+	if err = refreshCourtRating(ctx, tx, courtID); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetReviews retrieves a court's published reviews, newest first. The
+// viewer's own review is included whatever its status, so they can see it
+// is waiting for a moderator.
+func (r *CourtRepository) GetReviews(ctx context.Context, courtID, viewerID uuid.UUID, page, limit int) ([]models.CourtReview, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM court_reviews WHERE court_id = $1 AND (status = $2 OR user_id = $3)
+	`, courtID, models.ReviewStatusApproved, viewerID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count court reviews: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+courtReviewColumns+`
+		FROM court_reviews cr
+		LEFT JOIN users u ON u.id = cr.user_id
+		WHERE cr.court_id = $1 AND (cr.status = $2 OR cr.user_id = $3)
+		ORDER BY cr.updated_at DESC
+		LIMIT $4 OFFSET $5
+	`, courtID, models.ReviewStatusApproved, viewerID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query court reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews, err := scanCourtReviews(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+// GetPendingReviews retrieves reviews waiting for a moderator, oldest first
+func (r *CourtRepository) GetPendingReviews(ctx context.Context, page, limit int) ([]models.CourtReview, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM court_reviews WHERE status = $1", models.ReviewStatusPending).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count pending reviews: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+courtReviewColumns+`
+		FROM court_reviews cr
+		LEFT JOIN users u ON u.id = cr.user_id
+		WHERE cr.status = $1
+		ORDER BY cr.updated_at
+		LIMIT $2 OFFSET $3
+	`, models.ReviewStatusPending, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query pending reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews, err := scanCourtReviews(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+// ModerateReview publishes or rejects a pending review and updates the
+// court's rating
+func (r *CourtRepository) ModerateReview(ctx context.Context, reviewID, moderatorID uuid.UUID, approve bool, note string) (*models.CourtReview, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(ctx, "SELECT status FROM court_reviews WHERE id = $1 FOR UPDATE", reviewID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("review not found")
+		}
+		return nil, fmt.Errorf("failed to get court review: %w", err)
+	}
+	if status != models.ReviewStatusPending {
+		return nil, fmt.Errorf("review is not awaiting moderation")
+	}
+
+	status = models.ReviewStatusRejected
+	if approve {
+		status = models.ReviewStatusApproved
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE court_reviews SET status = $1, review_note = NULLIF($2, ''), reviewed_by = $3 WHERE id = $4
+	`, status, note, moderatorID, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate court review: %w", err)
+	}
+
+	review, err := scanCourtReview(tx.QueryRowContext(ctx, `
+		SELECT `+courtReviewColumns+`
+		FROM court_reviews cr
+		LEFT JOIN users u ON u.id = cr.user_id
+		WHERE cr.id = $1
+	`, reviewID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload court review: %w", err)
+	}
+
+	if err = refreshCourtRating(ctx, tx, review.CourtID); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return review, nil
+}
+
+// GetRating retrieves a court's rating summary, or nil if nobody has rated it
+func (r *CourtRepository) GetRating(ctx context.Context, courtID uuid.UUID) (*models.CourtRatingSummary, error) {
+	var summary models.CourtRatingSummary
+	var surface, lighting, cleanliness sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT overall_rating, rating_count, review_count, surface_rating, lighting_rating, cleanliness_rating
+		FROM court_ratings WHERE court_id = $1
+	`, courtID).Scan(&summary.Overall, &summary.RatingCount, &summary.ReviewCount, &surface, &lighting, &cleanliness)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get court rating: %w", err)
+	}
+	summary.SurfaceQuality = surface.Float64
+	summary.Lighting = lighting.Float64
+	summary.Cleanliness = cleanliness.Float64
+	return &summary, nil
+}
+
+// refreshCourtRating recomputes a court's rating summary from its published
+// reviews and the court ratings left after matches played there
+func refreshCourtRating(ctx context.Context, tx *sql.Tx, courtID uuid.UUID) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT user_id, overall, surface_quality, lighting, cleanliness, status
+		FROM court_reviews WHERE court_id = $1 AND status = $2
+	`, courtID, models.ReviewStatusApproved)
+	if err != nil {
+		return fmt.Errorf("failed to query court reviews: %w", err)
+	}
+	var reviews []models.CourtReview
+	for rows.Next() {
+		var review models.CourtReview
+		var surface, lighting, cleanliness sql.NullInt64
+		if err := rows.Scan(&review.UserID, &review.Overall, &surface, &lighting, &cleanliness, &review.Status); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan court review: %w", err)
+		}
+		review.SurfaceQuality = nullIntPtr(surface)
+		review.Lighting = nullIntPtr(lighting)
+		review.Cleanliness = nullIntPtr(cleanliness)
+		reviews = append(reviews, review)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating court reviews: %w", err)
+	}
+
+	rows, err = tx.QueryContext(ctx, `
+		SELECT pf.from_user_id, pf.court_rating
+		FROM player_feedback pf
+		JOIN player_pairings pp ON pp.id = pf.pairing_id
+		JOIN match_sessions ms ON ms.id = pp.match_session_id
+		WHERE ms.court_id = $1 AND pf.court_rating IS NOT NULL
+	`, courtID)
+	if err != nil {
+		return fmt.Errorf("failed to query match court ratings: %w", err)
+	}
+	matchRatings := map[uuid.UUID][]int{}
+	for rows.Next() {
+		var userID uuid.UUID
+		var rating int
+		if err := rows.Scan(&userID, &rating); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan match court rating: %w", err)
+		}
+		matchRatings[userID] = append(matchRatings[userID], rating)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("error iterating match court ratings: %w", err)
+	}
+
+	summary := models.AggregateCourtRatings(reviews, matchRatings)
+	if summary.RatingCount == 0 {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM court_ratings WHERE court_id = $1", courtID); err != nil {
+			return fmt.Errorf("failed to clear court rating: %w", err)
+		}
+		return nil
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO court_ratings (court_id, overall_rating, rating_count, review_count, surface_rating, lighting_rating, cleanliness_rating, rating_updated_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5::float, 0), NULLIF($6::float, 0), NULLIF($7::float, 0), NOW())
+		ON CONFLICT (court_id) DO UPDATE SET
+			overall_rating = EXCLUDED.overall_rating,
+			rating_count = EXCLUDED.rating_count,
+			review_count = EXCLUDED.review_count,
+			surface_rating = EXCLUDED.surface_rating,
+			lighting_rating = EXCLUDED.lighting_rating,
+			cleanliness_rating = EXCLUDED.cleanliness_rating,
+			rating_updated_at = EXCLUDED.rating_updated_at
+	`, courtID, summary.Overall, summary.RatingCount, summary.ReviewCount, summary.SurfaceQuality, summary.Lighting, summary.Cleanliness)
+	if err != nil {
+		return fmt.Errorf("failed to update court rating: %w", err)
+	}
+	return nil
+}
+
+func scanCourtReview(row rowScanner) (*models.CourtReview, error) {
+	var review models.CourtReview
+	var surface, lighting, cleanliness sql.NullInt64
+	err := row.Scan(
+		&review.ID, &review.CourtID, &review.UserID, &review.UserName, &review.Overall, &surface, &lighting, &cleanliness,
+		&review.Comment, &review.Status, &review.ReviewNote, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	review.SurfaceQuality = nullIntPtr(surface)
+	review.Lighting = nullIntPtr(lighting)
+	review.Cleanliness = nullIntPtr(cleanliness)
+	return &review, nil
+}
+
+func scanCourtReviews(rows *sql.Rows) ([]models.CourtReview, error) {
+	reviews := []models.CourtReview{}
+	for rows.Next() {
+		review, err := scanCourtReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan court review: %w", err)
+		}
+		reviews = append(reviews, *review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating court reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+func nullIntPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
@@ -33,6 +33,9 @@ func (r *EventRepository) Create(ctx context.Context, event *models.Event) error
 	if event.ID == uuid.Nil {
 		event.ID = uuid.New()
 	}
+	if event.Status == "" {
+		event.Status = models.EventStatusScheduled
+	}
 	event.CreatedAt = time.Now()
 	event.UpdatedAt = time.Now()
 
@@ -40,18 +43,22 @@ func (r *EventRepository) Create(ctx context.Context, event *models.Event) error
 		INSERT INTO events (
 			id, title, description, court_id, latitude, longitude, zip_code, city, state,
 			start_time, end_time, host_id, max_players, skill_level, event_type, is_recurring,
-			is_newcomer_friendly, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			is_newcomer_friendly, status, min_reliability, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 	`,
 		event.ID, event.Title, event.Description, event.CourtID,
 		event.Location.Latitude, event.Location.Longitude, event.Location.ZipCode, event.Location.City, event.Location.State,
 		event.StartTime, event.EndTime, event.HostID, event.MaxPlayers, event.SkillLevel, event.EventType, event.IsRecurring,
-		event.IsNewcomerFriendly, event.CreatedAt, event.UpdatedAt,
+		event.IsNewcomerFriendly, event.Status, event.MinReliability, event.CreatedAt, event.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert event: %w", err)
 	}
 
+	if err = replaceCoHosts(ctx, tx, event.ID, event.CoHostIDs); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -65,13 +72,13 @@ func (r *EventRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Ev
 		SELECT 
 			title, description, court_id, latitude, longitude, zip_code, city, state,
 			start_time, end_time, host_id, max_players, skill_level, event_type, is_recurring,
-			is_newcomer_friendly, created_at, updated_at
+			is_newcomer_friendly, status, COALESCE(cancellation_reason, ''), min_reliability, created_at, updated_at
 		FROM events WHERE id = $1
 	`, id).Scan(
 		&event.Title, &event.Description, &event.CourtID,
 		&event.Location.Latitude, &event.Location.Longitude, &event.Location.ZipCode, &event.Location.City, &event.Location.State,
 		&event.StartTime, &event.EndTime, &event.HostID, &event.MaxPlayers, &event.SkillLevel, &event.EventType, &event.IsRecurring,
-		&event.IsNewcomerFriendly, &event.CreatedAt, &event.UpdatedAt,
+		&event.IsNewcomerFriendly, &event.Status, &event.CancellationReason, &event.MinReliability, &event.CreatedAt, &event.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -88,14 +95,27 @@ func (r *EventRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Ev
 	}
 	event.HostName = hostName
 
-	// Get court name from courts table if court_id is provided
+	// Get court name and timezone from courts table if court_id is provided
+	timezone := courtTimezone("", event.Location.Latitude, event.Location.Longitude)
 	if event.CourtID != uuid.Nil {
-		var courtName string
-		err = r.db.QueryRowContext(ctx, "SELECT name FROM courts WHERE id = $1", event.CourtID).Scan(&courtName)
+		var courtName, override string
+		var latitude, longitude float64
+		err = r.db.QueryRowContext(ctx, "SELECT name, COALESCE(timezone, ''), latitude, longitude FROM courts WHERE id = $1", event.CourtID).
+			Scan(&courtName, &override, &latitude, &longitude)
 		if err != nil && err != sql.ErrNoRows {
 			return nil, fmt.Errorf("failed to get court name: %w", err)
 		}
 		event.CourtName = courtName
+		if err == nil {
+			timezone = courtTimezone(override, latitude, longitude)
+		}
+	}
+	event.Localize(&event.StartTime, &event.EndTime, timezone)
+
+	// Get co-hosts
+	event.CoHostIDs, err = r.getCoHostIDs(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
 	// Query event RSVPs
@@ -138,13 +158,14 @@ func (r *EventRepository) GetEvents(ctx context.Context, latitude, longitude flo
 		SELECT 
 			id, title, description, court_id, latitude, longitude, zip_code, city, state,
 			start_time, end_time, host_id, max_players, skill_level, event_type, is_recurring,
-			is_newcomer_friendly, created_at, updated_at,
-			( 6371 * acos( cos( radians($1) ) * cos( radians( latitude ) ) * cos( radians( longitude ) - radians($2) ) + sin( radians($1) ) * sin( radians( latitude ) ) ) ) AS distance
+			is_newcomer_friendly, status, created_at, updated_at,
+			` + geoDistanceSQL(1, 2) + ` AS distance
 		FROM events
 	`
 	countQuery := `SELECT COUNT(*) FROM events`
 
-	whereClauses := []string{}
+	// Cancelled events are never listed
+	whereClauses := []string{fmt.Sprintf("status != '%s'", models.EventStatusCancelled)}
 	args := []interface{}{latitude, longitude}
 	argCount := 3
 
@@ -184,27 +205,19 @@ func (r *EventRepository) GetEvents(ctx context.Context, latitude, longitude flo
 		argCount++
 	}
 
-	// Combine WHERE clauses
-	if len(whereClauses) > 0 {
-		baseQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
-		countQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
-	}
+	// Add distance filter (radius in km)
+	distanceFilter, distanceArgs := geoRadiusFilter(latitude, longitude, radius, 1, 2, argCount)
+	whereClauses = append(whereClauses, distanceFilter)
+	args = append(args, distanceArgs...)
+	argCount += len(distanceArgs)
 
-	// Add distance filter
-	distanceFilter := fmt.Sprintf("( 6371 * acos( cos( radians($1) ) * cos( radians( latitude ) ) * cos( radians( longitude ) - radians($2) ) + sin( radians($1) ) * sin( radians( latitude ) ) ) ) < $%d", argCount)
-	if len(whereClauses) == 0 {
-		baseQuery += " WHERE " + distanceFilter
-		countQuery += " WHERE " + distanceFilter
-	} else {
-		baseQuery += " AND " + distanceFilter
-		countQuery += " AND " + distanceFilter
-	}
-	args = append(args, radius) // radius in km
-	argCount++
+	// Combine WHERE clauses
+	baseQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
+	countQuery += " WHERE " + utils.JoinStrings(whereClauses, " AND ")
 
 	// Get total count for pagination
 	var totalEvents int
-	err := r.db.QueryRowContext(ctx, countQuery, args[:argCount-1]...).Scan(&totalEvents)
+	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalEvents)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count events: %w", err)
 	}
@@ -229,7 +242,7 @@ func (r *EventRepository) GetEvents(ctx context.Context, latitude, longitude flo
 			&event.ID, &event.Title, &event.Description, &event.CourtID,
 			&event.Location.Latitude, &event.Location.Longitude, &event.Location.ZipCode, &event.Location.City, &event.Location.State,
 			&event.StartTime, &event.EndTime, &event.HostID, &event.MaxPlayers, &event.SkillLevel, &event.EventType, &event.IsRecurring,
-			&event.IsNewcomerFriendly, &event.CreatedAt, &event.UpdatedAt, &distance,
+			&event.IsNewcomerFriendly, &event.Status, &event.CreatedAt, &event.UpdatedAt, &distance,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan event: %w", err)
@@ -253,15 +266,22 @@ func (r *EventRepository) GetEvents(ctx context.Context, latitude, longitude flo
 		}
 		events[i].HostName = hostName
 
-		// Get court name if court_id is provided
+		// Get court name and timezone if court_id is provided
+		timezone := courtTimezone("", event.Location.Latitude, event.Location.Longitude)
 		if event.CourtID != uuid.Nil {
-			var courtName string
-			err = r.db.QueryRowContext(ctx, "SELECT name FROM courts WHERE id = $1", event.CourtID).Scan(&courtName)
+			var courtName, override string
+			var latitude, longitude float64
+			err = r.db.QueryRowContext(ctx, "SELECT name, COALESCE(timezone, ''), latitude, longitude FROM courts WHERE id = $1", event.CourtID).
+				Scan(&courtName, &override, &latitude, &longitude)
 			if err != nil && err != sql.ErrNoRows {
 				return nil, 0, fmt.Errorf("failed to get court name: %w", err)
 			}
 			events[i].CourtName = courtName
+			if err == nil {
+				timezone = courtTimezone(override, latitude, longitude)
+			}
 		}
+		events[i].Localize(&events[i].StartTime, &events[i].EndTime, timezone)
 
 		// Get RSVP count and preview
 		rsvpRows, err := r.db.QueryContext(ctx, `
@@ -292,27 +312,42 @@ func (r *EventRepository) GetEvents(ctx context.Context, latitude, longitude flo
 	return events, totalEvents, nil
 }
 
-// CreateRSVP creates a new RSVP for an event
-func (r *EventRepository) CreateRSVP(ctx context.Context, rsvp *models.RSVP) error {
+// CreateRSVP creates a new RSVP for an event. When a confirmed player cancels,
+// waitlisted RSVPs are promoted into the freed spot and returned so the
+// caller can notify those players.
+func (r *EventRepository) CreateRSVP(ctx context.Context, rsvp *models.RSVP) ([]models.RSVP, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	// Lock the event row so concurrent RSVPs and capacity changes are serialized
+	var eventStatus string
+	err = tx.QueryRowContext(ctx, "SELECT status FROM events WHERE id = $1 FOR UPDATE", rsvp.EventID).Scan(&eventStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("event not found")
+		}
+		return nil, fmt.Errorf("failed to lock event: %w", err)
+	}
+	if eventStatus == models.EventStatusCancelled {
+		return nil, fmt.Errorf("event has been cancelled")
+	}
+
 	// Check if user has already RSVPed to this event
 	var existingID uuid.UUID
 	var existingStatus string
 	err = tx.QueryRowContext(ctx, "SELECT id, status FROM event_rsvps WHERE event_id = $1 AND user_id = $2", rsvp.EventID, rsvp.UserID).Scan(&existingID, &existingStatus)
 	if err != nil && err != sql.ErrNoRows {
-		return fmt.Errorf("failed to check existing RSVP: %w", err)
+		return nil, fmt.Errorf("failed to check existing RSVP: %w", err)
 	}
 
 	if err == nil {
 		// User has already RSVPed, update the status
 		_, err := tx.ExecContext(ctx, "UPDATE event_rsvps SET status = $1, updated_at = $2 WHERE id = $3", rsvp.Status, time.Now(), existingID)
 		if err != nil {
-			return fmt.Errorf("failed to update RSVP: %w", err)
+			return nil, fmt.Errorf("failed to update RSVP: %w", err)
 		}
 		rsvp.ID = existingID
 	} else {
@@ -336,7 +371,7 @@ func (r *EventRepository) CreateRSVP(ctx context.Context, rsvp *models.RSVP) err
 			`, rsvp.EventID).Scan(&confirmedCount, &maxPlayers)
 
 			if err != nil && err != sql.ErrNoRows {
-				return fmt.Errorf("failed to check event capacity: %w", err)
+				return nil, fmt.Errorf("failed to check event capacity: %w", err)
 			}
 
 			// If we got a row back and the event is full, add to waitlist instead
@@ -354,37 +389,22 @@ func (r *EventRepository) CreateRSVP(ctx context.Context, rsvp *models.RSVP) err
 			rsvp.ID, rsvp.EventID, rsvp.UserID, rsvp.Status, rsvp.CreatedAt, rsvp.UpdatedAt,
 		)
 		if err != nil {
-			return fmt.Errorf("failed to insert RSVP: %w", err)
+			return nil, fmt.Errorf("failed to insert RSVP: %w", err)
 		}
 	}
 
 	// If a user cancels, check if we can move someone from the waitlist
+	var promoted []models.RSVP
 	if rsvp.Status == "Cancelled" && existingStatus == "Confirmed" {
-		// Get the next person on the waitlist
-		var waitlistID uuid.UUID
-		var waitlistUserID uuid.UUID
-		err = tx.QueryRowContext(ctx, `
-			SELECT id, user_id FROM event_rsvps 
-			WHERE event_id = $1 AND status = 'Waitlisted' 
-			ORDER BY created_at ASC 
-			LIMIT 1
-		`, rsvp.EventID).Scan(&waitlistID, &waitlistUserID)
-
-		if err == nil {
-			// Move this person from waitlist to confirmed
-			_, err = tx.ExecContext(ctx, "UPDATE event_rsvps SET status = 'Confirmed', updated_at = $1 WHERE id = $2", time.Now(), waitlistID)
-			if err != nil {
-				return fmt.Errorf("failed to update waitlist RSVP: %w", err)
-			}
-
-			// In a real app, we'd send a notification to this user
+		if promoted, _, err = rebalanceRSVPs(ctx, tx, rsvp.EventID); err != nil {
+			return nil, err
 		}
 	}
 
 	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
-	return nil
+	return promoted, nil
 }
 
 // GetRSVP retrieves an RSVP by user ID and event ID
@@ -416,3 +436,246 @@ func (r *EventRepository) GetRSVP(ctx context.Context, eventID, userID uuid.UUID
 
 	return &rsvp, nil
 }
+
+// Update saves changes to an event's details and co-hosts. If the capacity
+// changed, waitlisted RSVPs are promoted or the latest confirmations demoted so
+// the confirmed list matches the new MaxPlayers. The RSVPs whose status changed
+// are returned so the caller can notify those players.
+func (r *EventRepository) Update(ctx context.Context, event *models.Event) (promoted, demoted []models.RSVP, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(ctx, "SELECT status FROM events WHERE id = $1 FOR UPDATE", event.ID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, fmt.Errorf("event not found")
+		}
+		return nil, nil, fmt.Errorf("failed to lock event: %w", err)
+	}
+	if status == models.EventStatusCancelled {
+		return nil, nil, fmt.Errorf("event has been cancelled")
+	}
+
+	event.UpdatedAt = time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE events SET
+			title = $1, description = $2, start_time = $3, end_time = $4, max_players = $5,
+			skill_level = $6, event_type = $7, is_newcomer_friendly = $8, min_reliability = $9, updated_at = $10
+		WHERE id = $11
+	`,
+		event.Title, event.Description, event.StartTime, event.EndTime, event.MaxPlayers,
+		event.SkillLevel, event.EventType, event.IsNewcomerFriendly, event.MinReliability, event.UpdatedAt, event.ID,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to update event: %w", err)
+	}
+
+	if err = replaceCoHosts(ctx, tx, event.ID, event.CoHostIDs); err != nil {
+		return nil, nil, err
+	}
+
+	promoted, demoted, err = rebalanceRSVPs(ctx, tx, event.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return promoted, demoted, nil
+}
+
+// Cancel marks an event and all of its RSVPs as cancelled. It returns the IDs
+// of the users who were confirmed or waitlisted so they can be notified.
+func (r *EventRepository) Cancel(ctx context.Context, eventID uuid.UUID, reason string) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE events SET status = $1, cancellation_reason = $2, cancelled_at = $3, updated_at = $3
+		WHERE id = $4 AND status != $1
+	`, models.EventStatusCancelled, reason, now, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel event: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("event not found or already cancelled")
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE event_rsvps SET status = 'Cancelled', updated_at = $1
+		WHERE event_id = $2 AND status IN ('Confirmed', 'Waitlisted')
+		RETURNING user_id
+	`, now, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel event RSVPs: %w", err)
+	}
+	defer rows.Close()
+
+	var attendeeIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan cancelled RSVP: %w", err)
+		}
+		attendeeIDs = append(attendeeIDs, userID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cancelled RSVPs: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return attendeeIDs, nil
+}
+
+// GetCourtConflicts returns the time ranges in the given window when the
+// court is taken by other scheduled events, every one of its courts is
+// booked, or it is closed
+func (r *EventRepository) GetCourtConflicts(ctx context.Context, courtID, excludeEventID uuid.UUID, start, end time.Time) ([]models.TimeRange, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT start_time, end_time
+		FROM events
+		WHERE court_id = $1 AND id != $2 AND status = $3
+			AND start_time < $5 AND end_time > $4
+		ORDER BY start_time
+	`, courtID, excludeEventID, models.EventStatusScheduled, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []models.TimeRange
+	for rows.Next() {
+		var conflict models.TimeRange
+		if err := rows.Scan(&conflict.Start, &conflict.End); err != nil {
+			return nil, fmt.Errorf("failed to scan court conflict: %w", err)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating court conflicts: %w", err)
+	}
+
+	units, err := getCourtUnits(ctx, r.db, courtID, false)
+	if err != nil {
+		return nil, err
+	}
+	bookings, err := queryBookings(ctx, r.db, `SELECT `+bookingColumns+bookingFrom+`
+		WHERE b.court_id = $1 AND b.status IN ('pending', 'confirmed')
+		AND b.start_time < $3 AND b.end_time > $2
+	`, courtID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query court bookings: %w", err)
+	}
+	conflicts = append(conflicts, models.BookedOutRanges(units, bookings, start, end)...)
+
+	schedule, err := getCourtSchedule(ctx, r.db, courtID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	conflicts = append(conflicts, schedule.ClosedRanges(start, end)...)
+	return models.MergeTimeRanges(conflicts), nil
+}
+
+// getCoHostIDs returns the co-hosts of an event
+func (r *EventRepository) getCoHostIDs(ctx context.Context, eventID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT user_id FROM event_cohosts WHERE event_id = $1 ORDER BY created_at", eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event co-hosts: %w", err)
+	}
+	defer rows.Close()
+
+	var coHostIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan event co-host: %w", err)
+		}
+		coHostIDs = append(coHostIDs, userID)
+	}
+	return coHostIDs, rows.Err()
+}
+
+// replaceCoHosts overwrites the co-host list of an event within a transaction
+func replaceCoHosts(ctx context.Context, tx *sql.Tx, eventID uuid.UUID, coHostIDs []uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM event_cohosts WHERE event_id = $1", eventID); err != nil {
+		return fmt.Errorf("failed to clear event co-hosts: %w", err)
+	}
+	for _, userID := range coHostIDs {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO event_cohosts (event_id, user_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, eventID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to insert event co-host: %w", err)
+		}
+	}
+	return nil
+}
+
+// rebalanceRSVPs reconciles confirmed and waitlisted RSVPs with the event's
+// current capacity within a transaction. The caller must hold a lock on the
+// event row.
+func rebalanceRSVPs(ctx context.Context, tx *sql.Tx, eventID uuid.UUID) (promoted, demoted []models.RSVP, err error) {
+	event := &models.Event{ID: eventID}
+	err = tx.QueryRowContext(ctx, "SELECT max_players FROM events WHERE id = $1", eventID).Scan(&event.MaxPlayers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get event capacity: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, user_id, status, created_at, updated_at
+		FROM event_rsvps
+		WHERE event_id = $1 AND status IN ('Confirmed', 'Waitlisted')
+		ORDER BY created_at ASC
+		FOR UPDATE
+	`, eventID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query event RSVPs: %w", err)
+	}
+	for rows.Next() {
+		var rsvp models.RSVP
+		if err := rows.Scan(&rsvp.ID, &rsvp.UserID, &rsvp.Status, &rsvp.CreatedAt, &rsvp.UpdatedAt); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("failed to scan event RSVP: %w", err)
+		}
+		rsvp.EventID = eventID
+		if rsvp.Status == "Confirmed" {
+			event.RSVPs = append(event.RSVPs, rsvp)
+		} else {
+			event.Waitlist = append(event.Waitlist, rsvp)
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating event RSVPs: %w", err)
+	}
+
+	promoted, demoted = event.Rebalance()
+
+	now := time.Now()
+	for _, changed := range [][]models.RSVP{promoted, demoted} {
+		for _, rsvp := range changed {
+			_, err = tx.ExecContext(ctx, "UPDATE event_rsvps SET status = $1, updated_at = $2 WHERE id = $3", rsvp.Status, now, rsvp.ID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to update RSVP status: %w", err)
+			}
+		}
+	}
+
+	return promoted, demoted, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/tennis-connect/utils"
+)
+
+// Radius searches share one query layer so courts, events, bulletins,
+// communities and players are all found the same way and in the same unit.
+// Every located table has an indexed geohash column (see migration 23): a
+// search first narrows rows to the geohash cells overlapping the circle's
+// bounding box, which is a handful of index range scans, and only checks the
+// exact great-circle distance for those rows. All distances are kilometres;
+// handlers convert to and from the client's unit.
+
+// geoDistanceSQL returns the expression for a row's distance in kilometres
+// from the point whose latitude and longitude are the arguments $latArg and
+// $lngArg. It uses the haversine formula, which unlike the spherical law of
+// cosines stays accurate and in range for nearby points.
+func geoDistanceSQL(latArg, lngArg int) string {
+	return fmt.Sprintf(`(2 * %g * asin(LEAST(1, sqrt(
+		power(sin(radians(latitude - $%d) / 2), 2) +
+		cos(radians($%d)) * cos(radians(latitude)) * power(sin(radians(longitude - $%d) / 2), 2)))))`,
+		utils.EarthRadiusKm, latArg, latArg, lngArg)
+}
+
+// geoRadiusFilter returns the WHERE condition matching rows within radiusKm
+// of the point at arguments $latArg and $lngArg, along with the extra
+// arguments the condition uses, numbered from argCount
+func geoRadiusFilter(latitude, longitude, radiusKm float64, latArg, lngArg, argCount int) (string, []interface{}) {
+	condition, args := geoBoxFilter(utils.NewBoundingBox(latitude, longitude, radiusKm), argCount)
+	args = append(args, radiusKm)
+	condition += fmt.Sprintf(" AND %s <= $%d", geoDistanceSQL(latArg, lngArg), argCount+len(args)-1)
+	return condition, args
+}
+
+// geoBoxFilter returns the WHERE condition matching rows inside the box,
+// along with the arguments the condition uses, numbered from argCount
+func geoBoxFilter(box utils.BoundingBox, argCount int) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	nextArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", argCount+len(args)-1)
+	}
+
+	if cells := utils.GeohashCover(box); len(cells) > 0 {
+		ranges := make([]string, 0, len(cells))
+		for _, cell := range cells {
+			ranges = append(ranges, fmt.Sprintf("(geohash >= %s AND geohash < %s)",
+				nextArg(cell), nextArg(utils.GeohashPrefixUpperBound(cell))))
+		}
+		conditions = append(conditions, "("+strings.Join(ranges, " OR ")+")")
+	}
+
+	conditions = append(conditions, fmt.Sprintf("latitude BETWEEN %s AND %s", nextArg(box.MinLat), nextArg(box.MaxLat)))
+	if box.CrossesAntimeridian() {
+		conditions = append(conditions, fmt.Sprintf("(longitude >= %s OR longitude <= %s)", nextArg(box.MinLng), nextArg(box.MaxLng)))
+	} else if box.MinLng > -180 || box.MaxLng < 180 {
+		conditions = append(conditions, fmt.Sprintf("longitude BETWEEN %s AND %s", nextArg(box.MinLng), nextArg(box.MaxLng)))
+	}
+	return strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/user/tennis-connect/database"
+)
+
+// geoBenchRows is how many courts and players the radius benchmarks search
+const geoBenchRows = 100000
+
+// seedGeoBench fills the courts and users tables with rows spread across the
+// continental US, so their geohash columns and indexes hold a realistic load
+func seedGeoBench(b *testing.B, db *database.DB) {
+	ctx := context.Background()
+	_, err := db.ExecContext(ctx, "SELECT setseed(0.42)")
+	require.NoError(b, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO courts (
+			id, name, description, latitude, longitude, zip_code, city, state,
+			image_url, court_type, is_public, contact_info, website, status
+		)
+		SELECT uuid_generate_v4(), 'Court ' || i, '', 25 + random() * 24, -124 + random() * 57, '', '', '',
+			'', 'Hard', TRUE, '', '', 'approved'
+		FROM generate_series(1, $1) AS i
+	`, geoBenchRows)
+	require.NoError(b, err, "Failed to seed courts")
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO users (id, email, password_hash, name, latitude, longitude, zip_code, city, state, skill_level)
+		SELECT uuid_generate_v4(), 'player' || i || '@example.com', 'x', 'Player ' || i,
+			25 + random() * 24, -124 + random() * 57, '', '', '', 3.5
+		FROM generate_series(1, $1) AS i
+	`, geoBenchRows)
+	require.NoError(b, err, "Failed to seed users")
+
+	_, err = db.ExecContext(ctx, "ANALYZE courts; ANALYZE users")
+	require.NoError(b, err)
+}
+
+// Radius searches through the real query layer over 100k courts or players.
+// The geohash ranges keep each search to the rows in a few index ranges, so
+// the time stays flat as the tables grow.
+func BenchmarkGetCourts100k(b *testing.B) {
+	if testing.Short() {
+		b.Skip("Skipping integration benchmark in short mode")
+	}
+	db, cleanup := setupTestDB(b)
+	defer cleanup()
+	seedGeoBench(b, db)
+
+	courtRepo := NewCourtRepository(db)
+	ctx := context.Background()
+	viewerID := uuid.New()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, err := courtRepo.GetCourts(ctx, viewerID, 37.7749, -122.4194, 25, "", nil, false, false, false, 0, "", 1, 20)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetNearbyUsers100k(b *testing.B) {
+	if testing.Short() {
+		b.Skip("Skipping integration benchmark in short mode")
+	}
+	db, cleanup := setupTestDB(b)
+	defer cleanup()
+	seedGeoBench(b, db)
+
+	userRepo := NewUserRepository(db)
+	ctx := context.Background()
+	filters := map[string]interface{}{"userID": uuid.New()}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := userRepo.GetNearbyUsers(ctx, 37.7749, -122.4194, 25, filters); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
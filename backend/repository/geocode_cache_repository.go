@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/user/tennis-connect/database"
+	"github.com/user/tennis-connect/geocoding"
+)
+
+// GeocodeCacheRepository stores geocoding lookups, implementing geocoding.Cache
+type GeocodeCacheRepository struct {
+	db *database.DB
+}
+
+// NewGeocodeCacheRepository creates a new GeocodeCacheRepository
+func NewGeocodeCacheRepository(db *database.DB) *GeocodeCacheRepository {
+	return &GeocodeCacheRepository{db: db}
+}
+
+// Get returns the cached lookup for the key, or nil if there is none
+func (r *GeocodeCacheRepository) Get(ctx context.Context, key string) (*geocoding.CacheEntry, error) {
+	var entry geocoding.CacheEntry
+	var found bool
+	var city, state, country, countryCode, postalCode, source sql.NullString
+	var latitude, longitude sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT found, latitude, longitude, city, state, country, country_code, postal_code, source, cached_at
+		FROM geocode_cache WHERE key = $1
+	`, key).Scan(&found, &latitude, &longitude, &city, &state, &country, &countryCode, &postalCode, &source, &entry.CachedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached geocode: %w", err)
+	}
+
+	if found {
+		entry.Result = &geocoding.Result{
+			Latitude:    latitude.Float64,
+			Longitude:   longitude.Float64,
+			City:        city.String,
+			State:       state.String,
+			Country:     country.String,
+			CountryCode: countryCode.String,
+			PostalCode:  postalCode.String,
+			Source:      source.String,
+		}
+	}
+	return &entry, nil
+}
+
+// Put caches a lookup, replacing any older one for the key
+func (r *GeocodeCacheRepository) Put(ctx context.Context, key string, entry geocoding.CacheEntry) error {
+	// A place that wasn't found is stored with no location
+	args := []interface{}{key, false, nil, nil, nil, nil, nil, nil, nil, nil, entry.CachedAt}
+	if result := entry.Result; result != nil {
+		args = []interface{}{key, true, result.Latitude, result.Longitude, result.City, result.State, result.Country,
+			result.CountryCode, result.PostalCode, result.Source, entry.CachedAt}
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO geocode_cache (key, found, latitude, longitude, city, state, country, country_code, postal_code, source, cached_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11)
+		ON CONFLICT (key) DO UPDATE SET
+			found = EXCLUDED.found, latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude,
+			city = EXCLUDED.city, state = EXCLUDED.state, country = EXCLUDED.country,
+			country_code = EXCLUDED.country_code, postal_code = EXCLUDED.postal_code,
+			source = EXCLUDED.source, cached_at = EXCLUDED.cached_at
+	`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to cache geocode: %w", err)
+	}
+	return nil
+}
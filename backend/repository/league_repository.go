@@ -0,0 +1,679 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/database"
+	"github.com/user/tennis-connect/models"
+)
+
+// LeagueRepository handles database operations related to leagues and ladders
+type LeagueRepository struct {
+	db *database.DB
+}
+
+// NewLeagueRepository creates a new LeagueRepository
+func NewLeagueRepository(db *database.DB) *LeagueRepository {
+	return &LeagueRepository{db: db}
+}
+
+// Create inserts a new league and its divisions
+func (r *LeagueRepository) Create(ctx context.Context, league *models.League) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	league.CreatedAt = time.Now()
+	league.UpdatedAt = time.Now()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO leagues (
+			id, name, type, community_id, event_id, owner_id, season_start, season_end,
+			points_win, points_loss, points_default, challenge_range, challenge_response_hours, challenge_play_days,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`,
+		league.ID, league.Name, league.Type, league.CommunityID, league.EventID, league.OwnerID, league.SeasonStart, league.SeasonEnd,
+		league.PointsWin, league.PointsLoss, league.PointsDefault, league.ChallengeRange, league.ChallengeResponseHours, league.ChallengePlayDays,
+		league.CreatedAt, league.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert league: %w", err)
+	}
+
+	for _, division := range league.Divisions {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO league_divisions (id, league_id, name, min_ntrp, max_ntrp)
+			VALUES ($1, $2, $3, $4, $5)
+		`, division.ID, league.ID, division.Name, division.MinNTRP, division.MaxNTRP)
+		if err != nil {
+			return fmt.Errorf("failed to insert league division: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a league with its divisions and players. Ladder players
+// are ordered by rank, others by when they joined.
+func (r *LeagueRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.League, error) {
+	league := &models.League{ID: id}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			name, type, community_id, event_id, owner_id, season_start, season_end,
+			points_win, points_loss, points_default, challenge_range, challenge_response_hours, challenge_play_days,
+			created_at, updated_at
+		FROM leagues WHERE id = $1
+	`, id).Scan(
+		&league.Name, &league.Type, &league.CommunityID, &league.EventID, &league.OwnerID, &league.SeasonStart, &league.SeasonEnd,
+		&league.PointsWin, &league.PointsLoss, &league.PointsDefault, &league.ChallengeRange, &league.ChallengeResponseHours, &league.ChallengePlayDays,
+		&league.CreatedAt, &league.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("league not found")
+		}
+		return nil, fmt.Errorf("failed to get league: %w", err)
+	}
+
+	divisionRows, err := r.db.QueryContext(ctx, `
+		SELECT id, name, min_ntrp, max_ntrp FROM league_divisions WHERE league_id = $1 ORDER BY min_ntrp
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query league divisions: %w", err)
+	}
+	league.Divisions = []models.LeagueDivision{}
+	divisionIndex := map[uuid.UUID]int{}
+	for divisionRows.Next() {
+		division := models.LeagueDivision{LeagueID: id, Players: []models.LeaguePlayer{}}
+		if err := divisionRows.Scan(&division.ID, &division.Name, &division.MinNTRP, &division.MaxNTRP); err != nil {
+			divisionRows.Close()
+			return nil, fmt.Errorf("failed to scan league division: %w", err)
+		}
+		divisionIndex[division.ID] = len(league.Divisions)
+		league.Divisions = append(league.Divisions, division)
+	}
+	divisionRows.Close()
+	if err = divisionRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating league divisions: %w", err)
+	}
+
+	playerRows, err := r.db.QueryContext(ctx, `
+		SELECT p.id, p.division_id, p.user_id, u.name, p.skill_level, p.ladder_rank, p.joined_at
+		FROM league_players p
+		JOIN users u ON p.user_id = u.id
+		WHERE p.league_id = $1
+		ORDER BY p.ladder_rank, p.joined_at
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query league players: %w", err)
+	}
+	defer playerRows.Close()
+
+	for playerRows.Next() {
+		player := models.LeaguePlayer{LeagueID: id}
+		if err := playerRows.Scan(&player.ID, &player.DivisionID, &player.UserID, &player.UserName, &player.SkillLevel, &player.LadderRank, &player.JoinedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan league player: %w", err)
+		}
+		if i, ok := divisionIndex[player.DivisionID]; ok {
+			league.Divisions[i].Players = append(league.Divisions[i].Players, player)
+		}
+	}
+	return league, playerRows.Err()
+}
+
+// AddPlayer registers a user in the league division matching their NTRP
+// level. Ladder players start at the bottom of their division.
+func (r *LeagueRepository) AddPlayer(ctx context.Context, league *models.League, userID uuid.UUID) (*models.LeaguePlayer, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the league so concurrent joins get distinct ladder ranks
+	if _, err = tx.ExecContext(ctx, "SELECT id FROM leagues WHERE id = $1 FOR UPDATE", league.ID); err != nil {
+		return nil, fmt.Errorf("failed to lock league: %w", err)
+	}
+
+	player := &models.LeaguePlayer{ID: uuid.New(), LeagueID: league.ID, UserID: userID, JoinedAt: time.Now()}
+	err = tx.QueryRowContext(ctx, "SELECT name, skill_level FROM users WHERE id = $1", userID).Scan(&player.UserName, &player.SkillLevel)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM league_players WHERE league_id = $1 AND user_id = $2)", league.ID, userID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check league membership: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("user is already in this league")
+	}
+
+	division := league.DivisionFor(player.SkillLevel)
+	if division == nil {
+		return nil, fmt.Errorf("no division in this league covers NTRP %.1f", player.SkillLevel)
+	}
+	player.DivisionID = division.ID
+
+	err = tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(ladder_rank), 0) + 1 FROM league_players WHERE division_id = $1", division.ID).Scan(&player.LadderRank)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ladder rank: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO league_players (id, league_id, division_id, user_id, skill_level, ladder_rank, joined_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, player.ID, player.LeagueID, player.DivisionID, player.UserID, player.SkillLevel, player.LadderRank, player.JoinedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert league player: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return player, nil
+}
+
+// CreateSchedule stores a generated flex schedule for a division. A division
+// can only be scheduled once.
+func (r *LeagueRepository) CreateSchedule(ctx context.Context, divisionID uuid.UUID, matches []models.LeagueMatch) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM league_matches WHERE division_id = $1 AND round > 0)", divisionID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check existing schedule: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("division already has a schedule")
+	}
+
+	for i := range matches {
+		if err = insertLeagueMatch(ctx, tx, &matches[i]); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetMatches retrieves the league's matches, optionally for one division
+func (r *LeagueRepository) GetMatches(ctx context.Context, leagueID uuid.UUID, divisionID *uuid.UUID) ([]models.LeagueMatch, error) {
+	query := `
+		SELECT id, league_id, division_id, round, player1_id, player2_id, due_date, challenge_id,
+			winner_id, COALESCE(score, ''), COALESCE(outcome, ''), status, reported_by, completed_at, created_at
+		FROM league_matches
+		WHERE league_id = $1`
+	args := []interface{}{leagueID}
+	if divisionID != nil {
+		query += " AND division_id = $2"
+		args = append(args, *divisionID)
+	}
+	query += " ORDER BY round, due_date, created_at"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query league matches: %w", err)
+	}
+	defer rows.Close()
+
+	matches := []models.LeagueMatch{}
+	for rows.Next() {
+		m, err := scanLeagueMatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, *m)
+	}
+	return matches, rows.Err()
+}
+
+// GetMatch retrieves a single league match
+func (r *LeagueRepository) GetMatch(ctx context.Context, matchID uuid.UUID) (*models.LeagueMatch, error) {
+	return getLeagueMatch(ctx, r.db, matchID, false)
+}
+
+// RecordResult stores a reported result. Results of challenge matches
+// complete the challenge and swap ladder ranks if the challenger won.
+func (r *LeagueRepository) RecordResult(ctx context.Context, matchID uuid.UUID, req models.LeagueResultRequest, reportedBy uuid.UUID) (*models.LeagueMatch, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	match, err := getLeagueMatch(ctx, tx, matchID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err = match.ValidateResult(req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	winnerID := req.WinnerID
+	match.WinnerID = &winnerID
+	match.Score = req.Score
+	match.Outcome = models.LeagueOutcomePlayed
+	if req.Default {
+		match.Outcome = models.LeagueOutcomeDefault
+		match.Score = ""
+	}
+	match.Status = models.LeagueMatchStatusCompleted
+	match.ReportedBy = &reportedBy
+	match.CompletedAt = &now
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE league_matches
+		SET winner_id = $1, score = $2, outcome = $3, status = $4, reported_by = $5, completed_at = $6
+		WHERE id = $7
+	`, match.WinnerID, match.Score, match.Outcome, match.Status, match.ReportedBy, match.CompletedAt, match.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update league match: %w", err)
+	}
+
+	if match.ChallengeID != nil {
+		_, err = tx.ExecContext(ctx, "UPDATE ladder_challenges SET status = $1 WHERE id = $2", models.ChallengeStatusCompleted, *match.ChallengeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to complete challenge: %w", err)
+		}
+		loserID := match.Player1ID
+		if loserID == winnerID {
+			loserID = match.Player2ID
+		}
+		if err = swapLadderRanks(ctx, tx, match.LeagueID, winnerID, loserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return match, nil
+}
+
+// CreateChallenge stores a new ladder challenge. Each player may only be
+// involved in one open challenge at a time.
+func (r *LeagueRepository) CreateChallenge(ctx context.Context, challenge *models.LadderChallenge) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, "SELECT id FROM leagues WHERE id = $1 FOR UPDATE", challenge.LeagueID); err != nil {
+		return fmt.Errorf("failed to lock league: %w", err)
+	}
+
+	var open bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM ladder_challenges
+			WHERE league_id = $1 AND status IN ($2, $3)
+				AND (challenger_id IN ($4, $5) OR defender_id IN ($4, $5))
+		)
+	`, challenge.LeagueID, models.ChallengeStatusPending, models.ChallengeStatusAccepted, challenge.ChallengerID, challenge.DefenderID).Scan(&open)
+	if err != nil {
+		return fmt.Errorf("failed to check open challenges: %w", err)
+	}
+	if open {
+		return fmt.Errorf("one of the players already has an open challenge")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO ladder_challenges (id, league_id, division_id, challenger_id, defender_id, status, respond_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, challenge.ID, challenge.LeagueID, challenge.DivisionID, challenge.ChallengerID, challenge.DefenderID,
+		challenge.Status, challenge.RespondBy, challenge.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert challenge: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetChallenge retrieves a ladder challenge
+func (r *LeagueRepository) GetChallenge(ctx context.Context, challengeID uuid.UUID) (*models.LadderChallenge, error) {
+	return getChallenge(ctx, r.db, challengeID, false)
+}
+
+// GetChallenges retrieves a league's challenges, newest first
+func (r *LeagueRepository) GetChallenges(ctx context.Context, leagueID uuid.UUID, openOnly bool) ([]models.LadderChallenge, error) {
+	query := `
+		SELECT id, league_id, division_id, challenger_id, defender_id, status, respond_by, match_id, created_at, responded_at
+		FROM ladder_challenges
+		WHERE league_id = $1`
+	args := []interface{}{leagueID}
+	if openOnly {
+		query += " AND status IN ($2, $3)"
+		args = append(args, models.ChallengeStatusPending, models.ChallengeStatusAccepted)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query challenges: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := []models.LadderChallenge{}
+	for rows.Next() {
+		var c models.LadderChallenge
+		if err := rows.Scan(&c.ID, &c.LeagueID, &c.DivisionID, &c.ChallengerID, &c.DefenderID, &c.Status,
+			&c.RespondBy, &c.MatchID, &c.CreatedAt, &c.RespondedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan challenge: %w", err)
+		}
+		challenges = append(challenges, c)
+	}
+	return challenges, rows.Err()
+}
+
+// RespondToChallenge accepts or declines a pending challenge. Accepting
+// schedules a match due within the league's play window; declining forfeits
+// the challenge to the challenger.
+func (r *LeagueRepository) RespondToChallenge(ctx context.Context, league *models.League, challengeID uuid.UUID, accept bool) (*models.LadderChallenge, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	challenge, err := getChallenge(ctx, tx, challengeID, true)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if challenge.Status != models.ChallengeStatusPending || challenge.IsExpired(now) {
+		return nil, fmt.Errorf("challenge is no longer pending")
+	}
+
+	if accept {
+		dueDate := now.AddDate(0, 0, league.ChallengePlayDays)
+		match := &models.LeagueMatch{
+			ID:          uuid.New(),
+			LeagueID:    challenge.LeagueID,
+			DivisionID:  challenge.DivisionID,
+			Player1ID:   challenge.ChallengerID,
+			Player2ID:   challenge.DefenderID,
+			DueDate:     &dueDate,
+			ChallengeID: &challenge.ID,
+			Status:      models.LeagueMatchStatusScheduled,
+		}
+		if err = insertLeagueMatch(ctx, tx, match); err != nil {
+			return nil, err
+		}
+		challenge.Status = models.ChallengeStatusAccepted
+		challenge.MatchID = &match.ID
+	} else {
+		if err = forfeitChallenge(ctx, tx, challenge, models.ChallengeStatusDeclined, now); err != nil {
+			return nil, err
+		}
+	}
+
+	challenge.RespondedAt = &now
+	_, err = tx.ExecContext(ctx, `
+		UPDATE ladder_challenges SET status = $1, match_id = $2, responded_at = $3 WHERE id = $4
+	`, challenge.Status, challenge.MatchID, challenge.RespondedAt, challenge.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update challenge: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return challenge, nil
+}
+
+// ExpireChallenges forfeits pending challenges whose acceptance deadline has
+// passed, awarding the challenger a default win, and lapses accepted
+// challenges not played by their due date, awarding the defender a default
+// win so they keep their place. It returns the expired challenges.
+func (r *LeagueRepository) ExpireChallenges(ctx context.Context, leagueID uuid.UUID, now time.Time) ([]models.LadderChallenge, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT lc.id FROM ladder_challenges lc
+		LEFT JOIN league_matches m ON m.id = lc.match_id
+		WHERE lc.league_id = $1 AND (
+			(lc.status = $2 AND lc.respond_by < $4) OR
+			(lc.status = $3 AND m.status = $5 AND m.due_date < $4)
+		)
+		ORDER BY lc.created_at
+		FOR UPDATE OF lc
+	`, leagueID, models.ChallengeStatusPending, models.ChallengeStatusAccepted, now, models.LeagueMatchStatusScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired challenges: %w", err)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan expired challenge: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expired challenges: %w", err)
+	}
+
+	var expired []models.LadderChallenge
+	for _, id := range ids {
+		challenge, err := getChallenge(ctx, tx, id, false)
+		if err != nil {
+			return nil, err
+		}
+		if challenge.Status == models.ChallengeStatusAccepted {
+			err = lapseChallenge(ctx, tx, challenge, now)
+		} else {
+			err = forfeitChallenge(ctx, tx, challenge, models.ChallengeStatusExpired, now)
+		}
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE ladder_challenges SET status = $1, match_id = $2 WHERE id = $3", challenge.Status, challenge.MatchID, challenge.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update challenge: %w", err)
+		}
+		expired = append(expired, *challenge)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return expired, nil
+}
+
+// GetLeaguesWithLapsedChallenges returns the ladder leagues with challenges
+// that ExpireChallenges would expire
+func (r *LeagueRepository) GetLeaguesWithLapsedChallenges(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT lc.league_id FROM ladder_challenges lc
+		LEFT JOIN league_matches m ON m.id = lc.match_id
+		WHERE (lc.status = $1 AND lc.respond_by < $3)
+			OR (lc.status = $2 AND m.status = $4 AND m.due_date < $3)
+	`, models.ChallengeStatusPending, models.ChallengeStatusAccepted, now, models.LeagueMatchStatusScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leagues with lapsed challenges: %w", err)
+	}
+	defer rows.Close()
+
+	var leagueIDs []uuid.UUID
+	for rows.Next() {
+		var leagueID uuid.UUID
+		if err := rows.Scan(&leagueID); err != nil {
+			return nil, fmt.Errorf("failed to scan league: %w", err)
+		}
+		leagueIDs = append(leagueIDs, leagueID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating leagues: %w", err)
+	}
+	return leagueIDs, nil
+}
+
+// lapseChallenge completes an accepted challenge's overdue match as a default
+// win for the defender. The defender is ranked higher, so ranks don't change.
+func lapseChallenge(ctx context.Context, tx *sql.Tx, challenge *models.LadderChallenge, now time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE league_matches SET winner_id = $1, score = '', outcome = $2, status = $3, completed_at = $4
+		WHERE id = $5
+	`, challenge.DefenderID, models.LeagueOutcomeDefault, models.LeagueMatchStatusCompleted, now, challenge.MatchID)
+	if err != nil {
+		return fmt.Errorf("failed to complete overdue challenge match: %w", err)
+	}
+	challenge.Status = models.ChallengeStatusExpired
+	return nil
+}
+
+// forfeitChallenge records a default win for the challenger and swaps ranks
+func forfeitChallenge(ctx context.Context, tx *sql.Tx, challenge *models.LadderChallenge, status string, now time.Time) error {
+	winnerID := challenge.ChallengerID
+	match := &models.LeagueMatch{
+		ID:          uuid.New(),
+		LeagueID:    challenge.LeagueID,
+		DivisionID:  challenge.DivisionID,
+		Player1ID:   challenge.ChallengerID,
+		Player2ID:   challenge.DefenderID,
+		ChallengeID: &challenge.ID,
+		WinnerID:    &winnerID,
+		Outcome:     models.LeagueOutcomeDefault,
+		Status:      models.LeagueMatchStatusCompleted,
+		CompletedAt: &now,
+	}
+	if err := insertLeagueMatch(ctx, tx, match); err != nil {
+		return err
+	}
+	challenge.Status = status
+	challenge.MatchID = &match.ID
+	return swapLadderRanks(ctx, tx, challenge.LeagueID, challenge.ChallengerID, challenge.DefenderID)
+}
+
+// swapLadderRanks applies a ladder result between two players within a transaction
+func swapLadderRanks(ctx context.Context, tx *sql.Tx, leagueID, winnerID, loserID uuid.UUID) error {
+	winner := &models.LeaguePlayer{UserID: winnerID}
+	loser := &models.LeaguePlayer{UserID: loserID}
+	for _, p := range []*models.LeaguePlayer{winner, loser} {
+		err := tx.QueryRowContext(ctx, `
+			SELECT id, ladder_rank FROM league_players WHERE league_id = $1 AND user_id = $2 FOR UPDATE
+		`, leagueID, p.UserID).Scan(&p.ID, &p.LadderRank)
+		if err != nil {
+			return fmt.Errorf("failed to get ladder rank: %w", err)
+		}
+	}
+
+	if !models.SwapLadderRanks(winner, loser) {
+		return nil
+	}
+	for _, p := range []*models.LeaguePlayer{winner, loser} {
+		if _, err := tx.ExecContext(ctx, "UPDATE league_players SET ladder_rank = $1 WHERE id = $2", p.LadderRank, p.ID); err != nil {
+			return fmt.Errorf("failed to update ladder rank: %w", err)
+		}
+	}
+	return nil
+}
+
+func insertLeagueMatch(ctx context.Context, tx *sql.Tx, m *models.LeagueMatch) error {
+	m.CreatedAt = time.Now()
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO league_matches (
+			id, league_id, division_id, round, player1_id, player2_id, due_date, challenge_id,
+			winner_id, score, outcome, status, reported_by, completed_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`,
+		m.ID, m.LeagueID, m.DivisionID, m.Round, m.Player1ID, m.Player2ID, m.DueDate, m.ChallengeID,
+		m.WinnerID, m.Score, m.Outcome, m.Status, m.ReportedBy, m.CompletedAt, m.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert league match: %w", err)
+	}
+	return nil
+}
+
+func getLeagueMatch(ctx context.Context, q queryer, matchID uuid.UUID, forUpdate bool) (*models.LeagueMatch, error) {
+	query := `
+		SELECT id, league_id, division_id, round, player1_id, player2_id, due_date, challenge_id,
+			winner_id, COALESCE(score, ''), COALESCE(outcome, ''), status, reported_by, completed_at, created_at
+		FROM league_matches WHERE id = $1`
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+	m, err := scanLeagueMatch(q.QueryRowContext(ctx, query, matchID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("match not found")
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLeagueMatch(row rowScanner) (*models.LeagueMatch, error) {
+	var m models.LeagueMatch
+	err := row.Scan(
+		&m.ID, &m.LeagueID, &m.DivisionID, &m.Round, &m.Player1ID, &m.Player2ID, &m.DueDate, &m.ChallengeID,
+		&m.WinnerID, &m.Score, &m.Outcome, &m.Status, &m.ReportedBy, &m.CompletedAt, &m.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan league match: %w", err)
+	}
+	return &m, nil
+}
+
+func getChallenge(ctx context.Context, q queryer, challengeID uuid.UUID, forUpdate bool) (*models.LadderChallenge, error) {
+	query := `
+		SELECT id, league_id, division_id, challenger_id, defender_id, status, respond_by, match_id, created_at, responded_at
+		FROM ladder_challenges WHERE id = $1`
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+	var c models.LadderChallenge
+	err := q.QueryRowContext(ctx, query, challengeID).Scan(
+		&c.ID, &c.LeagueID, &c.DivisionID, &c.ChallengerID, &c.DefenderID, &c.Status,
+		&c.RespondBy, &c.MatchID, &c.CreatedAt, &c.RespondedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("challenge not found")
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+	return &c, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/database"
+	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/utils"
+)
+
+// MapRepository clusters courts, events, bulletins and players for the map
+type MapRepository struct {
+	db *database.DB
+}
+
+// NewMapRepository creates a new MapRepository
+func NewMapRepository(db *database.DB) *MapRepository {
+	return &MapRepository{db: db}
+}
+
+// mapSource is the table and conditions a map type's markers come from
+type mapSource struct {
+	table     string
+	label     string // Column naming a single marker
+	condition string
+}
+
+// mapSources are what's shown on the map: approved courts, upcoming events,
+// open bulletins and players who have set a location
+var mapSources = map[string]mapSource{
+	models.MapTypeCourts:    {table: "courts", label: "name", condition: fmt.Sprintf("status = '%s'", models.CourtStatusApproved)},
+	models.MapTypeEvents:    {table: "events", label: "title", condition: fmt.Sprintf("status != '%s' AND end_time >= NOW()", models.EventStatusCancelled)},
+	models.MapTypeBulletins: {table: "bulletins", label: "title", condition: "is_active = TRUE AND end_time >= NOW()"},
+	models.MapTypePlayers:   {table: "users", label: "NULL", condition: "latitude != 0 AND longitude != 0"},
+}
+
+// GetMarkers clusters the given types inside the box by geohash cells of
+// the given length, returning one marker per cell. Cells are always counted
+// whole, so a cluster is the same whichever box it's fetched with; a cell
+// is returned if its marker lies inside the box. Players are counted in
+// coarser cells, and only where there are enough of them to stay anonymous.
+func (r *MapRepository) GetMarkers(ctx context.Context, box utils.BoundingBox, precision int, types []string) ([]*models.MapMarker, error) {
+	var groups []models.MapGroup
+	for _, mapType := range types {
+		source, ok := mapSources[mapType]
+		if !ok {
+			return nil, fmt.Errorf("unknown map type %s", mapType)
+		}
+
+		typePrecision := models.MapPrecision(mapType, precision)
+		filter, filterArgs := geoBoxFilter(box.SnapToGeohashCells(typePrecision), 3)
+		args := append([]interface{}{typePrecision, minMapCount(mapType)}, filterArgs...)
+
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT substr(geohash, 1, $1) AS cell, COUNT(*), AVG(latitude), AVG(longitude),
+				MIN(latitude), MIN(longitude), MAX(latitude), MAX(longitude),
+				MIN(id::text), MIN(`+source.label+`)
+			FROM `+source.table+`
+			WHERE `+source.condition+` AND `+filter+`
+			GROUP BY cell
+			HAVING COUNT(*) >= $2
+		`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s for the map: %w", mapType, err)
+		}
+
+		for rows.Next() {
+			group := models.MapGroup{Type: mapType}
+			var id string
+			var label sql.NullString
+			err := rows.Scan(&group.Cell, &group.Count, &group.Latitude, &group.Longitude,
+				&group.Bounds.MinLat, &group.Bounds.MinLng, &group.Bounds.MaxLat, &group.Bounds.MaxLng,
+				&id, &label)
+			if err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s for the map: %w", mapType, err)
+			}
+
+			// Players are never placed where they are
+			if mapType == models.MapTypePlayers {
+				cell, err := utils.GeohashBounds(group.Cell)
+				if err != nil {
+					rows.Close()
+					return nil, err
+				}
+				group.Latitude, group.Longitude = cell.Center()
+				group.Bounds = models.MapBounds{}
+			} else {
+				group.ID, _ = uuid.Parse(id)
+				group.Label = label.String
+			}
+			groups = append(groups, group)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error iterating %s for the map: %w", mapType, err)
+		}
+	}
+
+	markers := []*models.MapMarker{}
+	for _, marker := range models.ClusterMapGroups(groups) {
+		if box.Contains(marker.Latitude, marker.Longitude) {
+			markers = append(markers, marker)
+		}
+	}
+	return markers, nil
+}
+
+// minMapCount is how many of a type a cell needs to be shown
+func minMapCount(mapType string) int {
+	if mapType == models.MapTypePlayers {
+		return models.MinPlayerMapCount
+	}
+	return 1
+}
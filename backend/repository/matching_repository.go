@@ -218,9 +218,39 @@ func (r *MatchingRepository) TriggerMatching(ctx context.Context, sessionID uuid
 		users = append(users, user)
 	}
 
+	// Score availability from imported calendars, leaving out players who
+	// are busy for the whole session
+	criteria := models.DefaultMatchingCriteria()
+	criteria.Slot = models.TimeRange{Start: session.StartTime, End: session.EndTime}
+	userIDs := make([]uuid.UUID, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+	criteria.BusyTimes, err = getBusyTimes(ctx, r.db, userIDs, criteria.Slot)
+	if err != nil {
+		return err
+	}
+
+	// Score locality from who plays at the session's court
+	criteria.CourtID = session.CourtID
+	criteria.CourtFavoriters, err = getCourtFavoriters(ctx, r.db, session.CourtID, userIDs)
+	if err != nil {
+		return err
+	}
+	available := users[:0]
+	for _, user := range users {
+		if busy, ok := criteria.BusyTimes[user.ID]; ok && models.FreeFraction(busy, criteria.Slot) == 0 {
+			continue
+		}
+		available = append(available, user)
+	}
+	users = available
+	if len(users) < 2 {
+		return fmt.Errorf("not enough available players for matching")
+	}
+
 	// Generate pairings based on game type
 	var pairings []models.PlayerPairing
-	criteria := models.DefaultMatchingCriteria()
 
 	if session.GameType == "Singles" {
 		pairings = r.generateSinglesPairings(users, session, criteria)
@@ -341,14 +371,21 @@ func (r *MatchingRepository) CreatePlayerPairing(ctx context.Context, pairing *m
 	return nil
 }
 
-// SubmitFeedback submits feedback for a completed match
+// SubmitFeedback submits feedback for a completed match. Court ratings count
+// towards the rating of the court the match was played on.
 func (r *MatchingRepository) SubmitFeedback(ctx context.Context, feedback *models.PlayerFeedback) error {
 	if feedback.ID == uuid.Nil {
 		feedback.ID = uuid.New()
 	}
 	feedback.CreatedAt = time.Now()
 
-	_, err := r.db.ExecContext(ctx, `
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO player_feedback (
 			id, pairing_id, from_user_id, to_user_id, rating, comments,
 			court_rating, court_comments, match_quality, created_at
@@ -369,10 +406,29 @@ func (r *MatchingRepository) SubmitFeedback(ctx context.Context, feedback *model
 		return fmt.Errorf("failed to submit feedback: %w", err)
 	}
 
+	var courtID uuid.NullUUID
+	err = tx.QueryRowContext(ctx, `
+		SELECT ms.court_id FROM player_pairings pp
+		JOIN match_sessions ms ON ms.id = pp.match_session_id
+		WHERE pp.id = $1
+	`, feedback.PairingID).Scan(&courtID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get match court: %w", err)
+	}
+	if courtID.Valid {
+		if err = refreshCourtRating(ctx, tx, courtID.UUID); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
-// GetAvailableMatchSessions gets available match sessions for a user
+// GetAvailableMatchSessions gets available match sessions for a user, leaving
+// out sessions their imported calendar shows them busy for
 func (r *MatchingRepository) GetAvailableMatchSessions(ctx context.Context, userID uuid.UUID, courtID *uuid.UUID, gameType string) ([]*models.MatchSession, error) {
 	query := `
 		SELECT 
@@ -386,6 +442,10 @@ func (r *MatchingRepository) GetAvailableMatchSessions(ctx context.Context, user
 		AND ms.id NOT IN (
 			SELECT match_session_id FROM match_players WHERE user_id = $2
 		)
+		AND NOT EXISTS (
+			SELECT 1 FROM user_busy_times ub
+			WHERE ub.user_id = $2 AND ub.start_time < ms.end_time AND ub.end_time > ms.start_time
+		)
 	`
 	args := []interface{}{time.Now(), userID}
 	argCount := 3
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/database"
+	"github.com/user/tennis-connect/models"
+)
+
+// MixerRepository handles database operations related to event mixers
+type MixerRepository struct {
+	db *database.DB
+}
+
+// NewMixerRepository creates a new MixerRepository
+func NewMixerRepository(db *database.DB) *MixerRepository {
+	return &MixerRepository{db: db}
+}
+
+// Save creates or replaces the mixer for an event
+func (r *MixerRepository) Save(ctx context.Context, mixer *models.Mixer) error {
+	players, err := json.Marshal(mixer.Players)
+	if err != nil {
+		return fmt.Errorf("failed to encode mixer players: %w", err)
+	}
+	schedule, err := json.Marshal(mixer.Schedule)
+	if err != nil {
+		return fmt.Errorf("failed to encode mixer schedule: %w", err)
+	}
+
+	mixer.UpdatedAt = time.Now()
+	if mixer.CreatedAt.IsZero() {
+		mixer.CreatedAt = mixer.UpdatedAt
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO event_mixers (event_id, courts, rounds, mixed_doubles, players, schedule, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (event_id) DO UPDATE SET
+			courts = EXCLUDED.courts,
+			rounds = EXCLUDED.rounds,
+			mixed_doubles = EXCLUDED.mixed_doubles,
+			players = EXCLUDED.players,
+			schedule = EXCLUDED.schedule,
+			updated_at = EXCLUDED.updated_at
+	`, mixer.EventID, mixer.Courts, mixer.Rounds, mixer.MixedDoubles, players, schedule, mixer.CreatedAt, mixer.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save mixer: %w", err)
+	}
+	return nil
+}
+
+// GetByEventID retrieves the mixer for an event
+func (r *MixerRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) (*models.Mixer, error) {
+	mixer := &models.Mixer{EventID: eventID}
+	var players, schedule []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT courts, rounds, mixed_doubles, players, schedule, created_at, updated_at
+		FROM event_mixers WHERE event_id = $1
+	`, eventID).Scan(&mixer.Courts, &mixer.Rounds, &mixer.MixedDoubles, &players, &schedule, &mixer.CreatedAt, &mixer.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("mixer not found")
+		}
+		return nil, fmt.Errorf("failed to get mixer: %w", err)
+	}
+
+	if err = json.Unmarshal(players, &mixer.Players); err != nil {
+		return nil, fmt.Errorf("failed to decode mixer players: %w", err)
+	}
+	if err = json.Unmarshal(schedule, &mixer.Schedule); err != nil {
+		return nil, fmt.Errorf("failed to decode mixer schedule: %w", err)
+	}
+	return mixer, nil
+}
+
+// GetEventPlayerIDs returns the players who can take part in an event's
+// mixer: its confirmed RSVPs in the order they signed up, then anyone else
+// who checked in, such as late arrivals
+func (r *MixerRepository) GetEventPlayerIDs(ctx context.Context, eventID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id FROM (
+			SELECT user_id, 0 AS source, created_at AS joined_at
+			FROM event_rsvps WHERE event_id = $1 AND status = 'Confirmed'
+			UNION ALL
+			SELECT user_id, 1, checked_in_at
+			FROM attendance WHERE subject_type = $2 AND subject_id = $1 AND status = $3
+		) players
+		GROUP BY user_id
+		ORDER BY MIN(source), MIN(joined_at)
+	`, eventID, models.AttendanceSubjectEvent, models.AttendanceStatusCheckedIn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event players: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan event player: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event players: %w", err)
+	}
+	return userIDs, nil
+}
+
+// GetPlayers loads the name, NTRP level and gender of each user
+func (r *MixerRepository) GetPlayers(ctx context.Context, userIDs []uuid.UUID) ([]models.MixerPlayer, error) {
+	players := make([]models.MixerPlayer, 0, len(userIDs))
+	for _, userID := range userIDs {
+		player := models.MixerPlayer{UserID: userID}
+		err := r.db.QueryRowContext(ctx, `
+			SELECT name, skill_level, COALESCE(gender, '') FROM users WHERE id = $1
+		`, userID).Scan(&player.UserName, &player.SkillLevel, &player.Gender)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get player: %w", err)
+		}
+		players = append(players, player)
+	}
+	return players, nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/database"
+	"github.com/user/tennis-connect/models"
+)
+
+// NotificationRepository handles database operations related to notifications
+type NotificationRepository struct {
+	db *database.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository
+func NewNotificationRepository(db *database.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create inserts a new notification
+func (r *NotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	if notification.ID == uuid.Nil {
+		notification.ID = uuid.New()
+	}
+	notification.CreatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO notifications (id, user_id, type, title, message, reference_id, is_read, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		notification.ID, notification.UserID, notification.Type, notification.Title,
+		notification.Message, notification.ReferenceID, notification.IsRead, notification.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+// NotifyUsers sends the same notification to each of the given users
+func (r *NotificationRepository) NotifyUsers(ctx context.Context, userIDs []uuid.UUID, notificationType, title, message string, referenceID *uuid.UUID) error {
+	for _, userID := range userIDs {
+		notification := &models.Notification{
+			UserID:      userID,
+			Type:        notificationType,
+			Title:       title,
+			Message:     message,
+			ReferenceID: referenceID,
+		}
+		if err := r.Create(ctx, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetByUserID retrieves notifications for a user, newest first
+func (r *NotificationRepository) GetByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]*models.Notification, int, error) {
+	whereClause := "WHERE user_id = $1"
+	if unreadOnly {
+		whereClause += " AND is_read = FALSE"
+	}
+
+	var total int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM notifications "+whereClause, userID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, type, title, message, reference_id, is_read, created_at
+		FROM notifications `+whereClause+`
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []*models.Notification{}
+	for rows.Next() {
+		notification := &models.Notification{}
+		var message *string
+		err := rows.Scan(
+			&notification.ID, &notification.UserID, &notification.Type, &notification.Title,
+			&message, &notification.ReferenceID, &notification.IsRead, &notification.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if message != nil {
+			notification.Message = *message
+		}
+		notifications = append(notifications, notification)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating notification rows: %w", err)
+	}
+
+	return notifications, total, nil
+}
+
+// MarkRead marks a notification as read for its owner
+func (r *NotificationRepository) MarkRead(ctx context.Context, notificationID, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE notifications SET is_read = TRUE
+		WHERE id = $1 AND user_id = $2
+	`, notificationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("notification not found")
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/user/tennis-connect/database"
+	"github.com/user/tennis-connect/models"
+)
+
+// TournamentRepository handles database operations related to tournaments
+type TournamentRepository struct {
+	db *database.DB
+}
+
+// NewTournamentRepository creates a new TournamentRepository
+func NewTournamentRepository(db *database.DB) *TournamentRepository {
+	return &TournamentRepository{db: db}
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Create inserts a tournament together with its entrants and generated matches
+func (r *TournamentRepository) Create(ctx context.Context, tournament *models.Tournament) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM tournaments WHERE event_id = $1)", tournament.EventID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check existing tournament: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("tournament already exists for this event")
+	}
+
+	if tournament.ID == uuid.Nil {
+		tournament.ID = uuid.New()
+	}
+	tournament.CreatedAt = time.Now()
+	tournament.UpdatedAt = time.Now()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tournaments (
+			id, event_id, format, seeding_method, status, match_duration, court_count, champion_id, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		tournament.ID, tournament.EventID, tournament.Format, tournament.SeedingMethod, tournament.Status,
+		tournament.MatchDuration, tournament.CourtCount, tournament.ChampionID, tournament.CreatedAt, tournament.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert tournament: %w", err)
+	}
+
+	for i := range tournament.Entrants {
+		entrant := &tournament.Entrants[i]
+		entrant.ID = uuid.New()
+		entrant.TournamentID = tournament.ID
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO tournament_entrants (id, tournament_id, user_id, seed, rating)
+			VALUES ($1, $2, $3, $4, $5)
+		`, entrant.ID, entrant.TournamentID, entrant.UserID, entrant.Seed, entrant.Rating)
+		if err != nil {
+			return fmt.Errorf("failed to insert tournament entrant: %w", err)
+		}
+	}
+
+	for i := range tournament.Matches {
+		m := &tournament.Matches[i]
+		m.TournamentID = tournament.ID
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO tournament_matches (
+				id, tournament_id, bracket, round, position, player1_id, player2_id, player1_bye, player2_bye,
+				winner_id, score, status, scheduled_at, court_number, next_match_id, next_match_slot,
+				loser_next_match_id, loser_next_match_slot, completed_at, sequence
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		`,
+			m.ID, m.TournamentID, m.Bracket, m.Round, m.Position, m.Player1ID, m.Player2ID, m.Player1Bye, m.Player2Bye,
+			m.WinnerID, m.Score, m.Status, m.ScheduledAt, m.CourtNumber, m.NextMatchID, m.NextMatchSlot,
+			m.LoserNextMatchID, m.LoserNextMatchSlot, m.CompletedAt, i,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert tournament match: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a tournament with its entrants and matches
+func (r *TournamentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Tournament, error) {
+	return getTournament(ctx, r.db, "id", id, false)
+}
+
+// GetByEventID retrieves the tournament played as part of an event
+func (r *TournamentRepository) GetByEventID(ctx context.Context, eventID uuid.UUID) (*models.Tournament, error) {
+	return getTournament(ctx, r.db, "event_id", eventID, false)
+}
+
+// RecordResult records a match result and saves the resulting progression.
+// The tournament row is locked so concurrent reports cannot overwrite each other.
+func (r *TournamentRepository) RecordResult(ctx context.Context, tournamentID, matchID, winnerID uuid.UUID, score string) (*models.Tournament, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tournament, err := getTournament(ctx, tx, "id", tournamentID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err = tournament.RecordResult(matchID, winnerID, score); err != nil {
+		return nil, err
+	}
+	if err = saveProgress(ctx, tx, tournament); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return tournament, nil
+}
+
+// GetSkillLevels returns the NTRP skill level of each user
+func (r *TournamentRepository) GetSkillLevels(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]float64, error) {
+	levels := make(map[uuid.UUID]float64, len(userIDs))
+	for _, userID := range userIDs {
+		var level float64
+		err := r.db.QueryRowContext(ctx, "SELECT COALESCE(skill_level, 0) FROM users WHERE id = $1", userID).Scan(&level)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to get skill level: %w", err)
+		}
+		levels[userID] = level
+	}
+	return levels, nil
+}
+
+// getTournament loads a tournament by the given column, optionally locking the row
+func getTournament(ctx context.Context, q queryer, column string, value uuid.UUID, forUpdate bool) (*models.Tournament, error) {
+	query := `
+		SELECT id, event_id, format, seeding_method, status, match_duration, court_count, champion_id, created_at, updated_at
+		FROM tournaments WHERE ` + column + ` = $1`
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+
+	tournament := &models.Tournament{}
+	err := q.QueryRowContext(ctx, query, value).Scan(
+		&tournament.ID, &tournament.EventID, &tournament.Format, &tournament.SeedingMethod, &tournament.Status,
+		&tournament.MatchDuration, &tournament.CourtCount, &tournament.ChampionID, &tournament.CreatedAt, &tournament.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("tournament not found")
+		}
+		return nil, fmt.Errorf("failed to get tournament: %w", err)
+	}
+
+	entrantRows, err := q.QueryContext(ctx, `
+		SELECT e.id, e.user_id, u.name, e.seed, e.rating
+		FROM tournament_entrants e
+		JOIN users u ON e.user_id = u.id
+		WHERE e.tournament_id = $1
+		ORDER BY e.seed
+	`, tournament.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tournament entrants: %w", err)
+	}
+	tournament.Entrants = []models.TournamentEntrant{}
+	for entrantRows.Next() {
+		entrant := models.TournamentEntrant{TournamentID: tournament.ID}
+		if err := entrantRows.Scan(&entrant.ID, &entrant.UserID, &entrant.UserName, &entrant.Seed, &entrant.Rating); err != nil {
+			entrantRows.Close()
+			return nil, fmt.Errorf("failed to scan tournament entrant: %w", err)
+		}
+		tournament.Entrants = append(tournament.Entrants, entrant)
+	}
+	entrantRows.Close()
+	if err = entrantRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tournament entrants: %w", err)
+	}
+
+	matchRows, err := q.QueryContext(ctx, `
+		SELECT id, bracket, round, position, player1_id, player2_id, player1_bye, player2_bye,
+			winner_id, COALESCE(score, ''), status, scheduled_at, COALESCE(court_number, 0),
+			next_match_id, COALESCE(next_match_slot, 0), loser_next_match_id, COALESCE(loser_next_match_slot, 0), completed_at
+		FROM tournament_matches
+		WHERE tournament_id = $1
+		ORDER BY sequence
+	`, tournament.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tournament matches: %w", err)
+	}
+	defer matchRows.Close()
+
+	tournament.Matches = []models.TournamentMatch{}
+	for matchRows.Next() {
+		m := models.TournamentMatch{TournamentID: tournament.ID}
+		if err := matchRows.Scan(
+			&m.ID, &m.Bracket, &m.Round, &m.Position, &m.Player1ID, &m.Player2ID, &m.Player1Bye, &m.Player2Bye,
+			&m.WinnerID, &m.Score, &m.Status, &m.ScheduledAt, &m.CourtNumber,
+			&m.NextMatchID, &m.NextMatchSlot, &m.LoserNextMatchID, &m.LoserNextMatchSlot, &m.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan tournament match: %w", err)
+		}
+		tournament.Matches = append(tournament.Matches, m)
+	}
+	if err = matchRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tournament matches: %w", err)
+	}
+
+	return tournament, nil
+}
+
+// saveProgress writes the tournament status and every match's mutable fields
+func saveProgress(ctx context.Context, tx *sql.Tx, tournament *models.Tournament) error {
+	tournament.UpdatedAt = time.Now()
+	_, err := tx.ExecContext(ctx, `
+		UPDATE tournaments SET status = $1, champion_id = $2, updated_at = $3 WHERE id = $4
+	`, tournament.Status, tournament.ChampionID, tournament.UpdatedAt, tournament.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update tournament: %w", err)
+	}
+
+	for _, m := range tournament.Matches {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE tournament_matches SET
+				player1_id = $1, player2_id = $2, player1_bye = $3, player2_bye = $4,
+				winner_id = $5, score = $6, status = $7, completed_at = $8
+			WHERE id = $9
+		`, m.Player1ID, m.Player2ID, m.Player1Bye, m.Player2Bye, m.WinnerID, m.Score, m.Status, m.CompletedAt, m.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update tournament match: %w", err)
+		}
+	}
+	return nil
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/user/tennis-connect/database"
 	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -121,19 +123,24 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	user := &models.User{ID: id}
+	var homeCourtID uuid.NullUUID
+	var homeCourtName, homeCourtCity sql.NullString
 
 	// Query user's basic information
 	err := r.db.QueryRowContext(ctx, `
-		SELECT email, name, profile_picture, 
-			   latitude, longitude, zip_code, city, state,
-			   skill_level, bio, is_verified, is_new_to_area, gender,
-			   created_at, updated_at
-		FROM users
-		WHERE id = $1
+		SELECT u.email, u.name, u.profile_picture, 
+			   u.latitude, u.longitude, u.zip_code, u.city, u.state,
+			   u.skill_level, u.bio, u.is_verified, u.is_new_to_area, u.gender,
+			   u.home_court_id, c.name, c.city,
+			   u.created_at, u.updated_at
+		FROM users u
+		LEFT JOIN courts c ON c.id = u.home_court_id
+		WHERE u.id = $1
 	`, id).Scan(
 		&user.Email, &user.Name, &user.ProfilePicture,
 		&user.Location.Latitude, &user.Location.Longitude, &user.Location.ZipCode, &user.Location.City, &user.Location.State,
 		&user.SkillLevel, &user.Bio, &user.IsVerified, &user.IsNewToArea, &user.Gender,
+		&homeCourtID, &homeCourtName, &homeCourtCity,
 		&user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
@@ -142,6 +149,10 @@ func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Use
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	if homeCourtID.Valid {
+		user.HomeCourtID = &homeCourtID.UUID
+		user.HomeCourt = &models.HomeCourt{ID: homeCourtID.UUID, Name: homeCourtName.String, City: homeCourtCity.String}
+	}
 
 	// Initialize empty slices to avoid nil pointer errors
 	user.GameStyles = make([]string, 0)
@@ -303,88 +314,40 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	return nil
 }
 
-// GetNearbyUsers finds users near the given location
-func (r *UserRepository) GetNearbyUsers(ctx context.Context, latitude, longitude float64, radius float64, filters map[string]interface{}) ([]*models.User, error) {
-	// Base query using Haversine formula for accurate distance calculation
-	// This calculates the great-circle distance between two points on Earth
-	query := `
-		SELECT id, 
-			   (3959 * acos(cos(radians($1)) * cos(radians(latitude)) * 
-			   cos(radians(longitude) - radians($2)) + sin(radians($1)) * 
-			   sin(radians(latitude)))) AS distance_miles
-		FROM users
-		WHERE id != $3 -- Exclude the requesting user
-		AND latitude IS NOT NULL 
-		AND longitude IS NOT NULL
-		AND latitude != 0 
-		AND longitude != 0
-	`
-
-	args := []interface{}{latitude, longitude, filters["userID"]}
-	argCount := 4 // Start counting from 4 (we've used 3 args already)
-
-	// Apply filters
-	if skillLevel, ok := filters["skillLevel"].(float32); ok {
-		query += fmt.Sprintf(" AND ABS(skill_level - $%d) <= 0.5", argCount)
-		args = append(args, skillLevel)
-		argCount++
-	}
-
-	if gender, ok := filters["gender"].(string); ok && gender != "" {
-		query += fmt.Sprintf(" AND gender = $%d", argCount)
-		args = append(args, gender)
-		argCount++
-	}
+// Nearby player searches return up to nearbyUsersLimit players in range. If
+// nobody is in range, the radius grows by fallbackRadiusGrowth until it takes
+// in fallbackUsersLimit players, and the nearest of them are returned instead.
+const (
+	nearbyUsersLimit     = 50
+	fallbackUsersLimit   = 20
+	fallbackRadiusGrowth = 4
+)
 
-	if isNewcomer, ok := filters["isNewcomer"].(bool); ok && isNewcomer {
-		query += " AND is_new_to_area = TRUE"
-	}
+// maxSearchRadiusKm is half the Earth's circumference, which reaches every point
+var maxSearchRadiusKm = math.Pi * utils.EarthRadiusKm
 
-	// Order by distance and apply limit
-	query += " ORDER BY distance_miles ASC LIMIT 50"
+// userDistance is a user ID with the user's distance in kilometres from the search point
+type userDistance struct {
+	ID       uuid.UUID
+	Distance float64
+}
 
-	// Execute query
-	rows, err := r.db.QueryContext(ctx, query, args...)
+// GetNearbyUsers finds users within radius kilometres of the given location,
+// nearest first. Users' Distance is set in kilometres.
+func (r *UserRepository) GetNearbyUsers(ctx context.Context, latitude, longitude float64, radius float64, filters map[string]interface{}) ([]*models.User, error) {
+	selectedUsers, err := r.findUsersWithin(ctx, latitude, longitude, radius, nearbyUsersLimit, filters)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query nearby users: %w", err)
-	}
-	defer rows.Close()
-
-	// Process results
-	type userWithDistance struct {
-		ID       uuid.UUID
-		Distance float64
-	}
-
-	var usersInRange []userWithDistance
-	var allUsers []userWithDistance
-
-	for rows.Next() {
-		var userID uuid.UUID
-		var distanceMiles float64
-		if err := rows.Scan(&userID, &distanceMiles); err != nil {
-			return nil, fmt.Errorf("failed to scan user row: %w", err)
-		}
-
-		userDist := userWithDistance{ID: userID, Distance: distanceMiles}
-		allUsers = append(allUsers, userDist)
-
-		// Check if within radius
-		if distanceMiles <= radius {
-			usersInRange = append(usersInRange, userDist)
-		}
+		return nil, err
 	}
 
-	// Determine which users to return
-	var selectedUsers []userWithDistance
-	if len(usersInRange) > 0 {
-		selectedUsers = usersInRange
-	} else if len(allUsers) > 0 {
-		// If no users found within radius, return all users (up to 20) as fallback
-		if len(allUsers) > 20 {
-			selectedUsers = allUsers[:20]
-		} else {
-			selectedUsers = allUsers
+	// If no users found within radius, widen the search and return the nearest as fallback
+	if len(selectedUsers) == 0 {
+		for searchRadius := math.Max(radius, 1); len(selectedUsers) < fallbackUsersLimit && searchRadius < maxSearchRadiusKm; {
+			searchRadius = math.Min(searchRadius*fallbackRadiusGrowth, maxSearchRadiusKm)
+			selectedUsers, err = r.findUsersWithin(ctx, latitude, longitude, searchRadius, fallbackUsersLimit, filters)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -443,6 +406,68 @@ func (r *UserRepository) GetNearbyUsers(ctx context.Context, latitude, longitude
 	return users, nil
 }
 
+// findUsersWithin returns the IDs of up to limit users within radiusKm of
+// the given location, nearest first, applying the query-level filters
+func (r *UserRepository) findUsersWithin(ctx context.Context, latitude, longitude, radiusKm float64, limit int, filters map[string]interface{}) ([]userDistance, error) {
+	query := `
+		SELECT id, ` + geoDistanceSQL(1, 2) + ` AS distance_km
+		FROM users
+		WHERE id != $3 -- Exclude the requesting user
+		AND latitude IS NOT NULL 
+		AND longitude IS NOT NULL
+		AND latitude != 0 
+		AND longitude != 0
+	`
+
+	args := []interface{}{latitude, longitude, filters["userID"]}
+	argCount := 4 // Start counting from 4 (we've used 3 args already)
+
+	// Apply filters
+	if skillLevel, ok := filters["skillLevel"].(float32); ok {
+		query += fmt.Sprintf(" AND ABS(skill_level - $%d) <= 0.5", argCount)
+		args = append(args, skillLevel)
+		argCount++
+	}
+
+	if gender, ok := filters["gender"].(string); ok && gender != "" {
+		query += fmt.Sprintf(" AND gender = $%d", argCount)
+		args = append(args, gender)
+		argCount++
+	}
+
+	if isNewcomer, ok := filters["isNewcomer"].(bool); ok && isNewcomer {
+		query += " AND is_new_to_area = TRUE"
+	}
+
+	distanceFilter, distanceArgs := geoRadiusFilter(latitude, longitude, radiusKm, 1, 2, argCount)
+	query += " AND " + distanceFilter
+	args = append(args, distanceArgs...)
+	argCount += len(distanceArgs)
+
+	// Order by distance and apply limit
+	query += fmt.Sprintf(" ORDER BY distance_km ASC LIMIT $%d", argCount)
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nearby users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []userDistance
+	for rows.Next() {
+		var user userDistance
+		if err := rows.Scan(&user.ID, &user.Distance); err != nil {
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read nearby users: %w", err)
+	}
+	return users, nil
+}
+
 // VerifyPassword checks if the provided password matches the stored hash
 func (r *UserRepository) VerifyPassword(ctx context.Context, email, password string) (bool, *models.User, error) {
 	var (
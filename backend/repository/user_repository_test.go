@@ -12,10 +12,11 @@ import (
 	"github.com/user/tennis-connect/config"
 	"github.com/user/tennis-connect/database"
 	"github.com/user/tennis-connect/models"
+	"github.com/user/tennis-connect/utils"
 )
 
 // verifyTestDatabase ensures we're connected to the test database
-func verifyTestDatabase(t *testing.T, db *database.DB) {
+func verifyTestDatabase(t testing.TB, db *database.DB) {
 	var dbName string
 	err := db.QueryRow("SELECT current_database()").Scan(&dbName)
 	require.NoError(t, err, "Failed to get current database name")
@@ -28,7 +29,7 @@ func verifyTestDatabase(t *testing.T, db *database.DB) {
 }
 
 // setupTestDB sets up a test database connection
-func setupTestDB(t *testing.T) (*database.DB, func()) {
+func setupTestDB(t testing.TB) (*database.DB, func()) {
 	// Set test environment variable BEFORE loading config
 	originalEnv := os.Getenv("APP_ENV")
 	os.Setenv("APP_ENV", "test")
@@ -71,7 +72,7 @@ func setupTestDB(t *testing.T) (*database.DB, func()) {
 }
 
 // clearTestData clears all test data from the database
-func clearTestData(t *testing.T, db *database.DB) {
+func clearTestData(t testing.TB, db *database.DB) {
 	// Use a transaction to ensure all cleanup happens atomically
 	tx, err := db.Begin()
 	if err != nil {
@@ -410,13 +411,13 @@ func TestUserRepository_GetNearbyUsers(t *testing.T) {
 	filters := map[string]interface{}{
 		"userID": mainUser.ID,
 	}
-	users, err := repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, 10, filters)
+	users, err := repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, utils.Miles.ToKm(10), filters)
 	assert.NoError(t, err, "GetNearbyUsers should not return an error")
 	assert.Equal(t, 2, len(users), "Should find 2 nearby users within 10 miles")
 
 	// Test - filter by skill level (should find users within 0.5 range)
 	filters["skillLevel"] = float32(4.0)
-	users, err = repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, 10, filters)
+	users, err = repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, utils.Miles.ToKm(10), filters)
 	assert.NoError(t, err, "GetNearbyUsers with skill filter should not return an error")
 	assert.Equal(t, 2, len(users), "Should find 2 nearby users within skill level range (3.5-4.5)")
 	// Check that we found users within the skill range
@@ -433,7 +434,7 @@ func TestUserRepository_GetNearbyUsers(t *testing.T) {
 		"userID": mainUser.ID,
 		"gender": "Female",
 	}
-	users, err = repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, 10, filters)
+	users, err = repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, utils.Miles.ToKm(10), filters)
 	assert.NoError(t, err, "GetNearbyUsers with gender filter should not return an error")
 	assert.Equal(t, 1, len(users), "Should find 1 nearby female user")
 	assert.Equal(t, "Nearby User 1", users[0].Name, "Should find the female user")
@@ -443,7 +444,7 @@ func TestUserRepository_GetNearbyUsers(t *testing.T) {
 		"userID":     mainUser.ID,
 		"isNewcomer": true,
 	}
-	users, err = repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, 10, filters)
+	users, err = repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, utils.Miles.ToKm(10), filters)
 	assert.NoError(t, err, "GetNearbyUsers with newcomer filter should not return an error")
 	assert.Equal(t, 1, len(users), "Should find 1 nearby newcomer user")
 	assert.Equal(t, "Nearby User 1", users[0].Name, "Should find the newcomer user")
@@ -588,20 +589,20 @@ func TestUserRepository_GetNearbyUsers_FallbackBehavior(t *testing.T) {
 	filters := map[string]interface{}{
 		"userID": mainUser.ID,
 	}
-	users, err := repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, 1.0, filters)
+	users, err := repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, utils.Miles.ToKm(1.0), filters)
 	assert.NoError(t, err, "GetNearbyUsers should not return an error")
 	assert.Equal(t, 3, len(users), "Should return all 3 far users as fallback when none in range")
 
 	// Verify all returned users have distance information
 	for _, user := range users {
-		assert.Greater(t, user.Distance, 1.0, "All users should be outside the 1-mile radius")
+		assert.Greater(t, utils.Miles.FromKm(user.Distance), 1.0, "All users should be outside the 1-mile radius")
 		assert.NotEmpty(t, user.Name, "User should have a name")
 		assert.NotNil(t, user.GameStyles, "GameStyles should be initialized")
 		assert.NotNil(t, user.PreferredTimes, "PreferredTimes should be initialized")
 	}
 
 	// Test 2: Search with very large radius - should return users normally (not fallback)
-	users, err = repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, 25000.0, filters)
+	users, err = repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, utils.Miles.ToKm(25000.0), filters)
 	assert.NoError(t, err, "GetNearbyUsers should not return an error")
 	assert.Equal(t, 3, len(users), "Should return all 3 users within large radius")
 
@@ -627,7 +628,7 @@ func TestUserRepository_GetNearbyUsers_FallbackBehavior(t *testing.T) {
 	}
 
 	// Test fallback with limit
-	users, err = repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, 0.1, filters)
+	users, err = repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, utils.Miles.ToKm(0.1), filters)
 	assert.NoError(t, err, "GetNearbyUsers should not return an error")
 	assert.Equal(t, 20, len(users), "Should return exactly 20 users as fallback limit")
 }
@@ -691,13 +692,13 @@ func TestUserRepository_GetNearbyUsers_DistanceCalculation(t *testing.T) {
 	filters := map[string]interface{}{
 		"userID": mainUser.ID,
 	}
-	users, err := repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, 5.0, filters)
+	users, err := repo.GetNearbyUsers(ctx, mainUser.Location.Latitude, mainUser.Location.Longitude, utils.Miles.ToKm(5.0), filters)
 	assert.NoError(t, err, "GetNearbyUsers should not return an error")
 	assert.Equal(t, 1, len(users), "Should find 1 nearby user")
 
 	if len(users) > 0 {
 		assert.Greater(t, users[0].Distance, 0.0, "Distance should be greater than 0")
-		assert.Less(t, users[0].Distance, 2.0, "Distance should be less than 2 miles for this test case")
+		assert.Less(t, utils.Miles.FromKm(users[0].Distance), 2.0, "Distance should be less than 2 miles for this test case")
 		assert.Equal(t, "Near User", users[0].Name, "Should find the correct user")
 	}
 }
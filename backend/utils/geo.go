@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// EarthRadiusKm is the mean radius of the Earth used for all distance calculations
+const EarthRadiusKm = 6371.0
+
+const kmPerMile = 1.609344
+
+// DistanceUnit is the unit a client sends radii in and gets distances back in
+type DistanceUnit string
+
+// Supported distance units. Kilometres are the default everywhere; the
+// database and repositories always work in kilometres.
+const (
+	Kilometers DistanceUnit = "km"
+	Miles      DistanceUnit = "mi"
+)
+
+// ParseDistanceUnit parses the units query parameter, defaulting to kilometres
+func ParseDistanceUnit(value string) (DistanceUnit, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "km", "kilometers", "kilometres":
+		return Kilometers, nil
+	case "mi", "miles":
+		return Miles, nil
+	default:
+		return "", fmt.Errorf("unknown distance unit %s", value)
+	}
+}
+
+// ToKm converts a distance in this unit to kilometres
+func (u DistanceUnit) ToKm(distance float64) float64 {
+	if u == Miles {
+		return distance * kmPerMile
+	}
+	return distance
+}
+
+// FromKm converts a distance in kilometres to this unit
+func (u DistanceUnit) FromKm(km float64) float64 {
+	if u == Miles {
+		return km / kmPerMile
+	}
+	return km
+}
+
+// DistanceKm returns the great-circle distance between two points in kilometres
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLng := (lng2 - lng1) * math.Pi / 180
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return EarthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// BoundingBox is the smallest latitude/longitude box containing a circle.
+// A box crossing the antimeridian has MinLng greater than MaxLng.
+type BoundingBox struct {
+	MinLat float64
+	MaxLat float64
+	MinLng float64
+	MaxLng float64
+}
+
+// NewBoundingBox returns the box containing every point within radiusKm of
+// the given point. Circles reaching a pole span every longitude.
+func NewBoundingBox(latitude, longitude, radiusKm float64) BoundingBox {
+	angular := radiusKm / EarthRadiusKm
+	latDelta := angular * 180 / math.Pi
+	box := BoundingBox{
+		MinLat: latitude - latDelta,
+		MaxLat: latitude + latDelta,
+		MinLng: -180,
+		MaxLng: 180,
+	}
+	if box.MinLat <= -90 || box.MaxLat >= 90 || angular >= math.Pi/2 {
+		box.MinLat = math.Max(box.MinLat, -90)
+		box.MaxLat = math.Min(box.MaxLat, 90)
+		return box
+	}
+
+	lngDelta := math.Asin(math.Sin(angular)/math.Cos(latitude*math.Pi/180)) * 180 / math.Pi
+	if lngDelta >= 180 {
+		return box
+	}
+	box.MinLng = longitude - lngDelta
+	box.MaxLng = longitude + lngDelta
+	if box.MinLng < -180 {
+		box.MinLng += 360
+	}
+	if box.MaxLng > 180 {
+		box.MaxLng -= 360
+	}
+	return box
+}
+
+// CrossesAntimeridian reports whether the box wraps from 180 to -180 longitude
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return b.MinLng > b.MaxLng
+}
+
+// Contains reports whether the point lies inside the box
+func (b BoundingBox) Contains(latitude, longitude float64) bool {
+	if latitude < b.MinLat || latitude > b.MaxLat {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return longitude >= b.MinLng || longitude <= b.MaxLng
+	}
+	return longitude >= b.MinLng && longitude <= b.MaxLng
+}
+
+// GeohashPrecision is the length of the geohash stored for every located row
+// (about 5m x 5m cells)
+const GeohashPrecision = 9
+
+// MaxGeohashCells caps how many cells a radius search looks up. Larger areas
+// are covered with shorter, coarser prefixes.
+const MaxGeohashCells = 16
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// EncodeGeohash returns the geohash of a point with the given number of characters
+func EncodeGeohash(latitude, longitude float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+	hash := make([]byte, 0, precision)
+	bits, bitCount, even := 0, 0, true
+
+	for len(hash) < precision {
+		if even {
+			mid := (minLng + maxLng) / 2
+			if longitude >= mid {
+				bits = bits*2 + 1
+				minLng = mid
+			} else {
+				bits *= 2
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if latitude >= mid {
+				bits = bits*2 + 1
+				minLat = mid
+			} else {
+				bits *= 2
+				maxLat = mid
+			}
+		}
+		even = !even
+		if bitCount++; bitCount == 5 {
+			hash = append(hash, geohashAlphabet[bits])
+			bits, bitCount = 0, 0
+		}
+	}
+	return string(hash)
+}
+
+// geohashCellSize returns the height and width in degrees of a geohash cell
+func geohashCellSize(precision int) (float64, float64) {
+	lngBits := (5*precision + 1) / 2
+	latBits := 5 * precision / 2
+	return 180 / math.Pow(2, float64(latBits)), 360 / math.Pow(2, float64(lngBits))
+}
+
+// GeohashCover returns the geohash prefixes of the cells overlapping the box,
+// using the longest prefix that needs at most MaxGeohashCells cells. Every
+// point in the box has a geohash starting with one of the prefixes. It
+// returns nil when the box is so large that every row would match anyway.
+func GeohashCover(box BoundingBox) []string {
+	lngRanges := [][2]float64{{box.MinLng, box.MaxLng}}
+	if box.CrossesAntimeridian() {
+		lngRanges = [][2]float64{{box.MinLng, 180}, {-180, box.MaxLng}}
+	}
+
+	for precision := GeohashPrecision; precision >= 1; precision-- {
+		height, width := geohashCellSize(precision)
+		rows := cellSpan(box.MinLat, box.MaxLat, -90, height)
+		columns := 0
+		for _, lngRange := range lngRanges {
+			columns += cellSpan(lngRange[0], lngRange[1], -180, width)
+		}
+		if rows*columns > MaxGeohashCells {
+			continue
+		}
+		if precision == 1 && rows*columns == len(geohashAlphabet) {
+			return nil
+		}
+
+		seen := map[string]bool{}
+		cells := make([]string, 0, rows*columns)
+		for row := 0; row < rows; row++ {
+			lat := cellCenter(box.MinLat, -90, height, row)
+			for _, lngRange := range lngRanges {
+				for column := 0; column < cellSpan(lngRange[0], lngRange[1], -180, width); column++ {
+					cell := EncodeGeohash(lat, cellCenter(lngRange[0], -180, width, column), precision)
+					if !seen[cell] {
+						seen[cell] = true
+						cells = append(cells, cell)
+					}
+				}
+			}
+		}
+		sort.Strings(cells)
+		return cells
+	}
+	return nil
+}
+
+// cellSpan counts the grid cells of the given size, anchored at origin, that
+// overlap the range from min to max
+func cellSpan(min, max, origin, size float64) int {
+	first := math.Floor((min - origin) / size)
+	last := math.Floor((max - origin) / size)
+	// The top edge of the grid belongs to the last cell
+	if limit := math.Round(-2*origin/size) - 1; last > limit {
+		last = limit
+	}
+	return int(last-first) + 1
+}
+
+// cellCenter returns the centre of the nth cell overlapping a range starting at min
+func cellCenter(min, origin, size float64, n int) float64 {
+	first := math.Floor((min - origin) / size)
+	return origin + (first+float64(n)+0.5)*size
+}
+
+// GeohashPrefixUpperBound returns the smallest string sorting after every
+// geohash starting with prefix, so prefix search can use a range scan
+func GeohashPrefixUpperBound(prefix string) string {
+	return prefix + "~"
+}
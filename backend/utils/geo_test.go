@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDistanceUnit(t *testing.T) {
+	for value, expected := range map[string]DistanceUnit{"": Kilometers, "km": Kilometers, "MI": Miles, "miles": Miles} {
+		unit, err := ParseDistanceUnit(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, unit)
+	}
+	_, err := ParseDistanceUnit("furlongs")
+	assert.EqualError(t, err, "unknown distance unit furlongs")
+
+	assert.InDelta(t, 16.09, Miles.ToKm(10), 0.01)
+	assert.InDelta(t, 10, Miles.FromKm(Miles.ToKm(10)), 1e-9)
+	assert.Equal(t, 10.0, Kilometers.ToKm(10))
+}
+
+func TestDistanceKm(t *testing.T) {
+	assert.InDelta(t, 0, DistanceKm(37.7694, -122.4862, 37.7694, -122.4862), 1e-9)
+	// San Francisco to Los Angeles
+	assert.InDelta(t, 559, DistanceKm(37.7749, -122.4194, 34.0522, -118.2437), 5)
+}
+
+func TestEncodeGeohash(t *testing.T) {
+	assert.Equal(t, "u4pruydqq", EncodeGeohash(57.64911, 10.40744, 9))
+	assert.Equal(t, "9q8yy", EncodeGeohash(37.7749, -122.4194, 5))
+	assert.Equal(t, "s", EncodeGeohash(0, 0, 1))
+}
+
+func TestNewBoundingBox(t *testing.T) {
+	box := NewBoundingBox(37.7749, -122.4194, 10)
+	assert.InDelta(t, 37.685, box.MinLat, 0.001)
+	assert.InDelta(t, 37.865, box.MaxLat, 0.001)
+	assert.InDelta(t, -122.533, box.MinLng, 0.001)
+	assert.InDelta(t, -122.306, box.MaxLng, 0.001)
+	assert.False(t, box.CrossesAntimeridian())
+
+	// Fiji straddles the antimeridian
+	box = NewBoundingBox(-17.7, 179.9, 50)
+	assert.True(t, box.CrossesAntimeridian())
+	assert.True(t, box.Contains(-17.7, -179.9))
+	assert.False(t, box.Contains(-17.7, 0))
+
+	// Circles over a pole take in every longitude
+	box = NewBoundingBox(89.9, 0, 50)
+	assert.Equal(t, 90.0, box.MaxLat)
+	assert.Equal(t, -180.0, box.MinLng)
+	assert.Equal(t, 180.0, box.MaxLng)
+}
+
+func TestGeohashCover(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	centers := [][2]float64{{37.7749, -122.4194}, {-17.7, 179.9}, {89.5, 45}, {0, 0}, {-33.8688, 151.2093}}
+	for _, center := range centers {
+		for _, radiusKm := range []float64{0.5, 10, 80, 500} {
+			box := NewBoundingBox(center[0], center[1], radiusKm)
+			cells := GeohashCover(box)
+			require.NotEmpty(t, cells)
+			assert.LessOrEqual(t, len(cells), MaxGeohashCells)
+
+			// Every point in range must fall in one of the cells
+			for i := 0; i < 200; i++ {
+				lat, lng := destination(center[0], center[1], rng.Float64()*360, rng.Float64()*radiusKm)
+				require.True(t, box.Contains(lat, lng))
+				hash := EncodeGeohash(lat, lng, GeohashPrecision)
+				assert.True(t, hasAnyPrefix(hash, cells), "%s not covered around %v within %vkm", hash, center, radiusKm)
+			}
+		}
+	}
+
+	// The whole world doesn't need a geohash lookup
+	assert.Nil(t, GeohashCover(NewBoundingBox(0, 0, 25000)))
+}
+
+// destination returns the point distanceKm from the start along the bearing
+func destination(lat, lng, bearing, distanceKm float64) (float64, float64) {
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+	angular := distanceKm / EarthRadiusKm
+	lat1, lng1, theta := toRadians(lat), toRadians(lng), toRadians(bearing)
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(angular) + math.Cos(lat1)*math.Sin(angular)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(angular)*math.Cos(lat1), math.Cos(angular)-math.Sin(lat1)*math.Sin(lat2))
+	lng2 = math.Mod(lng2*180/math.Pi+540, 360) - 180
+	return lat2 * 180 / math.Pi, lng2
+}
+
+func hasAnyPrefix(hash string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(hash, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// geoPoint and geoIndex stand in for a located table and its geohash index:
+// points sorted by geohash, searched by prefix range like the database does
+type geoPoint struct {
+	lat, lng float64
+	hash     string
+}
+
+type geoIndex []geoPoint
+
+func newGeoIndex(count int, seed int64) geoIndex {
+	rng := rand.New(rand.NewSource(seed))
+	index := make(geoIndex, count)
+	for i := range index {
+		// Spread points across the continental US
+		lat := 25 + rng.Float64()*24
+		lng := -124 + rng.Float64()*57
+		index[i] = geoPoint{lat: lat, lng: lng, hash: EncodeGeohash(lat, lng, GeohashPrecision)}
+	}
+	sort.Slice(index, func(i, j int) bool { return index[i].hash < index[j].hash })
+	return index
+}
+
+func (index geoIndex) searchIndexed(lat, lng, radiusKm float64) int {
+	box := NewBoundingBox(lat, lng, radiusKm)
+	found := 0
+	for _, cell := range GeohashCover(box) {
+		upper := GeohashPrefixUpperBound(cell)
+		start := sort.Search(len(index), func(i int) bool { return index[i].hash >= cell })
+		for i := start; i < len(index) && index[i].hash < upper; i++ {
+			if box.Contains(index[i].lat, index[i].lng) && DistanceKm(lat, lng, index[i].lat, index[i].lng) <= radiusKm {
+				found++
+			}
+		}
+	}
+	return found
+}
+
+func (index geoIndex) searchFullScan(lat, lng, radiusKm float64) int {
+	found := 0
+	for _, point := range index {
+		if DistanceKm(lat, lng, point.lat, point.lng) <= radiusKm {
+			found++
+		}
+	}
+	return found
+}
+
+func TestGeoIndexMatchesFullScan(t *testing.T) {
+	index := newGeoIndex(20000, 1)
+	for _, radiusKm := range []float64{1, 10, 50, 250} {
+		assert.Equal(t, index.searchFullScan(37.7749, -122.4194, radiusKm), index.searchIndexed(37.7749, -122.4194, radiusKm))
+		assert.Equal(t, index.searchFullScan(40.7128, -74.0060, radiusKm), index.searchIndexed(40.7128, -74.0060, radiusKm))
+	}
+}
+
+// Radius searches over an in-memory model of 100k courts or players. The
+// indexed search only reads the rows in a few geohash ranges, so it stays
+// flat as tables grow while the full scan grows with every row. The
+// repository benchmarks run the same searches against the database.
+func BenchmarkRadiusSearchIndexed100k(b *testing.B) {
+	index := newGeoIndex(100000, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.searchIndexed(37.7749, -122.4194, 25)
+	}
+}
+
+func BenchmarkRadiusSearchFullScan100k(b *testing.B) {
+	index := newGeoIndex(100000, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		index.searchFullScan(37.7749, -122.4194, 25)
+	}
+}
@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICalEvent is one VEVENT in an iCalendar document
+type ICalEvent struct {
+	UID          string
+	Sequence     int
+	Summary      string
+	Description  string
+	Location     string
+	Status       string // CONFIRMED, TENTATIVE, CANCELLED
+	Start        time.Time
+	End          time.Time
+	Timezone     string // IANA name; start and end are written in this zone
+	Created      time.Time
+	LastModified time.Time
+}
+
+const (
+	icalDateTime      = "20060102T150405"
+	icalLineMaxOctets = 75
+)
+
+// RenderICalendar writes events as an iCalendar (RFC 5545) document. Times are
+// written in each event's own timezone with a VTIMEZONE describing it, so
+// calendar apps show them at the court's local time wherever the player is.
+func RenderICalendar(name string, events []ICalEvent, now time.Time) string {
+	var lines []string
+	lines = append(lines,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Tennis Connect//Calendar//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:"+escapeICalText(name),
+		"X-PUBLISHED-TTL:PT1H",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H",
+	)
+
+	// One VTIMEZONE per zone, covering every event that uses it
+	zones := make(map[string][2]time.Time)
+	var zoneNames []string
+	for _, event := range events {
+		loc := LoadTimezone(event.Timezone)
+		if loc == time.UTC {
+			continue
+		}
+		span, ok := zones[loc.String()]
+		if !ok {
+			span = [2]time.Time{event.Start, event.End}
+			zoneNames = append(zoneNames, loc.String())
+		}
+		if event.Start.Before(span[0]) {
+			span[0] = event.Start
+		}
+		if event.End.After(span[1]) {
+			span[1] = event.End
+		}
+		zones[loc.String()] = span
+	}
+	sort.Strings(zoneNames)
+	for _, zone := range zoneNames {
+		lines = append(lines, vtimezone(LoadTimezone(zone), zones[zone][0], zones[zone][1])...)
+	}
+
+	stamp := formatICalUTC(now)
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.UID,
+			"DTSTAMP:"+stamp,
+			fmt.Sprintf("SEQUENCE:%d", event.Sequence),
+			formatICalTime("DTSTART", event.Start, event.Timezone),
+			formatICalTime("DTEND", event.End, event.Timezone),
+			"SUMMARY:"+escapeICalText(event.Summary),
+		)
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+escapeICalText(event.Description))
+		}
+		if event.Location != "" {
+			lines = append(lines, "LOCATION:"+escapeICalText(event.Location))
+		}
+		if event.Status != "" {
+			lines = append(lines, "STATUS:"+event.Status)
+		}
+		if !event.Created.IsZero() {
+			lines = append(lines, "CREATED:"+formatICalUTC(event.Created))
+		}
+		if !event.LastModified.IsZero() {
+			lines = append(lines, "LAST-MODIFIED:"+formatICalUTC(event.LastModified))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldICalLine(line))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// vtimezone describes loc's UTC offsets from the start of from's year until
+// to, one STANDARD or DAYLIGHT component per change. Listing the actual
+// changes rather than recurrence rules keeps past and future rule changes right.
+func vtimezone(loc *time.Location, from, to time.Time) []string {
+	from = time.Date(from.In(loc).Year(), time.January, 1, 0, 0, 0, 0, loc)
+	name, offset := from.Zone()
+	lines := []string{"BEGIN:VTIMEZONE", "TZID:" + loc.String()}
+	lines = append(lines, tzComponent(from.IsDST(), from, name, offset, offset)...)
+
+	for t := from; ; {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || end.After(to) {
+			break
+		}
+		next := end.In(loc)
+		nextName, nextOffset := next.Zone()
+		// DTSTART is the wall-clock time the change happens at, before it happens
+		lines = append(lines, tzComponent(next.IsDST(), end.UTC().Add(time.Duration(offset)*time.Second), nextName, offset, nextOffset)...)
+		offset, t = nextOffset, next
+	}
+	return append(lines, "END:VTIMEZONE")
+}
+
+func tzComponent(dst bool, start time.Time, name string, offsetFrom, offsetTo int) []string {
+	kind := "STANDARD"
+	if dst {
+		kind = "DAYLIGHT"
+	}
+	return []string{
+		"BEGIN:" + kind,
+		"DTSTART:" + start.Format(icalDateTime),
+		"TZOFFSETFROM:" + formatUTCOffset(offsetFrom),
+		"TZOFFSETTO:" + formatUTCOffset(offsetTo),
+		"TZNAME:" + escapeICalText(name),
+		"END:" + kind,
+	}
+}
+
+// formatUTCOffset formats seconds east of UTC as +HHMM, or +HHMMSS for the
+// odd historical offset that needs it
+func formatUTCOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	formatted := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+	if seconds%60 != 0 {
+		formatted += fmt.Sprintf("%02d", seconds%60)
+	}
+	return formatted
+}
+
+// formatICalTime writes a DTSTART or DTEND property in the named timezone, or
+// in UTC when there is none
+func formatICalTime(property string, t time.Time, timezone string) string {
+	loc := LoadTimezone(timezone)
+	if loc == time.UTC {
+		return property + ":" + formatICalUTC(t)
+	}
+	return fmt.Sprintf("%s;TZID=%s:%s", property, loc.String(), t.In(loc).Format(icalDateTime))
+}
+
+func formatICalUTC(t time.Time) string {
+	return t.UTC().Format(icalDateTime) + "Z"
+}
+
+// escapeICalText escapes a TEXT value's backslashes, separators and newlines
+func escapeICalText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(text)
+}
+
+// foldICalLine splits a line longer than 75 octets into continuation lines
+// starting with a space, without breaking a UTF-8 character
+func foldICalLine(line string) string {
+	if len(line) <= icalLineMaxOctets {
+		return line
+	}
+	var b strings.Builder
+	limit := icalLineMaxOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines lose an octet to the leading space
+		limit = icalLineMaxOctets - 1
+	}
+	b.WriteString(line)
+	return b.String()
+}
@@ -0,0 +1,668 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ICalPeriod is a span of busy time read from an iCalendar file
+type ICalPeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+const (
+	// maxRecurrences caps how many occurrences one recurring event can expand to
+	maxRecurrences = 5000
+	// maxRecurrencePeriods caps how many days, weeks, months or years a rule is
+	// followed for, about 270 years of a daily event
+	maxRecurrencePeriods = 100000
+)
+
+// icalProperty is one content line: NAME;PARAM=value:VALUE
+type icalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icalEvent collects the scheduling properties of a VEVENT. Titles,
+// descriptions and attendees are never read.
+type icalEvent struct {
+	UID          string
+	Start        *icalProperty
+	End          *icalProperty
+	Duration     string
+	RRule        string
+	RDates       []*icalProperty
+	ExDates      []*icalProperty
+	RecurrenceID *icalProperty
+	Transparent  bool
+	Cancelled    bool
+}
+
+// ParseICalBusy reads the busy time between from and to out of an iCalendar
+// file: events (expanding recurrences) and VFREEBUSY busy periods. Free,
+// transparent and cancelled events are skipped. Times without a zone, and
+// zones we don't recognise, are read in loc.
+func ParseICalBusy(r io.Reader, loc *time.Location, from, to time.Time) ([]ICalPeriod, error) {
+	props, err := readICalProperties(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*icalEvent
+	var periods []ICalPeriod
+	var current *icalEvent
+	inFreeBusy, sawCalendar := false, false
+	for _, prop := range props {
+		switch {
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VCALENDAR"):
+			sawCalendar = true
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VEVENT"):
+			current = &icalEvent{}
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VEVENT"):
+			if current != nil {
+				events = append(events, current)
+			}
+			current = nil
+		case prop.Name == "BEGIN" && strings.EqualFold(prop.Value, "VFREEBUSY"):
+			inFreeBusy = true
+		case prop.Name == "END" && strings.EqualFold(prop.Value, "VFREEBUSY"):
+			inFreeBusy = false
+		case current != nil:
+			current.set(prop)
+		case inFreeBusy && prop.Name == "FREEBUSY":
+			busy, err := parseFreeBusy(prop, loc)
+			if err != nil {
+				return nil, err
+			}
+			periods = append(periods, busy...)
+		}
+	}
+	if !sawCalendar {
+		return nil, fmt.Errorf("not an iCalendar file")
+	}
+
+	// Occurrences moved or cancelled individually are listed as their own
+	// events with a RECURRENCE-ID, and replace that occurrence of the series
+	overridden := make(map[string]map[int64]bool)
+	for _, event := range events {
+		if event.RecurrenceID == nil {
+			continue
+		}
+		at, _, err := parseICalTime(event.RecurrenceID, loc)
+		if err != nil {
+			return nil, err
+		}
+		if overridden[event.UID] == nil {
+			overridden[event.UID] = make(map[int64]bool)
+		}
+		overridden[event.UID][at.Unix()] = true
+	}
+
+	for _, event := range events {
+		busy, err := event.expand(loc, from, to, overridden[event.UID])
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, busy...)
+	}
+
+	// Keep only the part of each period inside the window
+	var kept []ICalPeriod
+	for _, period := range periods {
+		if !period.End.After(from) || !period.Start.Before(to) {
+			continue
+		}
+		if period.Start.Before(from) {
+			period.Start = from
+		}
+		if period.End.After(to) {
+			period.End = to
+		}
+		kept = append(kept, period)
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Start.Before(kept[j].Start) })
+	return kept, nil
+}
+
+func (e *icalEvent) set(prop *icalProperty) {
+	switch prop.Name {
+	case "UID":
+		e.UID = prop.Value
+	case "DTSTART":
+		e.Start = prop
+	case "DTEND":
+		e.End = prop
+	case "DURATION":
+		e.Duration = prop.Value
+	case "RRULE":
+		e.RRule = prop.Value
+	case "RDATE":
+		e.RDates = append(e.RDates, prop)
+	case "EXDATE":
+		e.ExDates = append(e.ExDates, prop)
+	case "RECURRENCE-ID":
+		e.RecurrenceID = prop
+	case "TRANSP":
+		e.Transparent = strings.EqualFold(prop.Value, "TRANSPARENT")
+	case "STATUS":
+		e.Cancelled = strings.EqualFold(prop.Value, "CANCELLED")
+	}
+}
+
+// expand returns the busy periods of an event, one per occurrence that could
+// overlap from to until
+func (e *icalEvent) expand(loc *time.Location, from, until time.Time, skip map[int64]bool) ([]ICalPeriod, error) {
+	if e.Transparent || e.Cancelled || e.Start == nil {
+		return nil, nil
+	}
+	start, allDay, err := parseICalTime(e.Start, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// An occurrence lasts until DTEND, for DURATION, or all day for dates.
+	// All-day events are counted in days so they keep to midnight across DST.
+	var length time.Duration
+	days := 0
+	switch {
+	case e.End != nil:
+		last, _, err := parseICalTime(e.End, loc)
+		if err != nil {
+			return nil, err
+		}
+		length = last.Sub(start)
+		if allDay {
+			days = int(length.Hours()/24 + 0.5)
+		}
+	case e.Duration != "":
+		if length, err = parseICalDuration(e.Duration); err != nil {
+			return nil, err
+		}
+	case allDay:
+		length, days = 24*time.Hour, 1
+	}
+	end := func(occurrence time.Time) time.Time {
+		if days > 0 {
+			return occurrence.AddDate(0, 0, days)
+		}
+		return occurrence.Add(length)
+	}
+
+	starts := []time.Time{start}
+	if e.RRule != "" && e.RecurrenceID == nil {
+		// Allow an extra hour for an occurrence stretched by a DST change
+		starts, err = expandRRule(e.RRule, start, from.Add(-length-time.Hour), until)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, prop := range e.RDates {
+		extra, err := parseICalTimes(prop, loc)
+		if err != nil {
+			return nil, err
+		}
+		starts = append(starts, extra...)
+	}
+	excluded := make(map[int64]bool)
+	for _, prop := range e.ExDates {
+		times, err := parseICalTimes(prop, loc)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range times {
+			excluded[t.Unix()] = true
+		}
+	}
+
+	var periods []ICalPeriod
+	for _, occurrence := range starts {
+		if excluded[occurrence.Unix()] || (e.RecurrenceID == nil && skip[occurrence.Unix()]) {
+			continue
+		}
+		if finish := end(occurrence); finish.After(occurrence) {
+			periods = append(periods, ICalPeriod{Start: occurrence, End: finish})
+		}
+	}
+	return periods, nil
+}
+
+// expandRRule lists the starts of a recurring event between after and until,
+// or until the rule ends if that is sooner. DTSTART is always included. It
+// covers the rules calendar apps write: DAILY, WEEKLY, MONTHLY and YEARLY
+// with INTERVAL, COUNT, UNTIL, BYDAY and BYMONTHDAY.
+func expandRRule(rule string, start, after, until time.Time) ([]time.Time, error) {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rule, ";") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			parts[strings.ToUpper(key)] = strings.ToUpper(value)
+		}
+	}
+
+	interval := 1
+	if value, ok := parts["INTERVAL"]; ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid RRULE interval: %s", value)
+		}
+		interval = n
+	}
+	count := 0
+	if value, ok := parts["COUNT"]; ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid RRULE count: %s", value)
+		}
+		count = n
+	}
+	if value, ok := parts["UNTIL"]; ok {
+		last, isDate, err := parseICalTime(&icalProperty{Value: value}, start.Location())
+		if err != nil {
+			return nil, err
+		}
+		// UNTIL is inclusive, and a date includes the whole day
+		if isDate {
+			last = last.AddDate(0, 0, 1)
+		} else {
+			last = last.Add(time.Second)
+		}
+		if last.Before(until) {
+			until = last
+		}
+	}
+
+	var byDay []icalWeekday
+	if value, ok := parts["BYDAY"]; ok {
+		for _, day := range strings.Split(value, ",") {
+			weekday, err := parseICalWeekday(day)
+			if err != nil {
+				return nil, err
+			}
+			byDay = append(byDay, weekday)
+		}
+	}
+	var byMonthDay []int
+	if value, ok := parts["BYMONTHDAY"]; ok {
+		for _, day := range strings.Split(value, ",") {
+			n, err := strconv.Atoi(day)
+			if err != nil || n == 0 || n < -31 || n > 31 {
+				return nil, fmt.Errorf("invalid RRULE month day: %s", day)
+			}
+			byMonthDay = append(byMonthDay, n)
+		}
+	}
+
+	// Occurrences keep DTSTART's wall-clock time across DST changes
+	local := start
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, local.Hour(), local.Minute(), local.Second(), 0, local.Location())
+	}
+
+	// candidates lists the occurrences in the period'th day, week, month or year
+	var candidates func(period int) []time.Time
+	switch parts["FREQ"] {
+	case "DAILY":
+		candidates = func(period int) []time.Time {
+			day := at(local.Year(), local.Month(), local.Day()+period*interval)
+			if len(byDay) > 0 && !matchesWeekday(day, byDay) {
+				return nil
+			}
+			return []time.Time{day}
+		}
+	case "WEEKLY":
+		days := byDay
+		if len(days) == 0 {
+			days = []icalWeekday{{Day: local.Weekday()}}
+		}
+		// Weeks start on Monday
+		offset := (int(local.Weekday()) + 6) % 7
+		candidates = func(period int) []time.Time {
+			var week []time.Time
+			for _, day := range days {
+				n := (int(day.Day)+6)%7 - offset
+				week = append(week, at(local.Year(), local.Month(), local.Day()+period*interval*7+n))
+			}
+			return week
+		}
+	case "MONTHLY":
+		candidates = func(period int) []time.Time {
+			first := time.Date(local.Year(), local.Month()+time.Month(period*interval), 1, 0, 0, 0, 0, local.Location())
+			switch {
+			case len(byMonthDay) > 0:
+				return monthDays(first, byMonthDay, at)
+			case len(byDay) > 0:
+				return monthWeekdays(first, byDay, at)
+			}
+			return monthDays(first, []int{local.Day()}, at)
+		}
+	case "YEARLY":
+		candidates = func(period int) []time.Time {
+			year := local.Year() + period*interval
+			if local.Month() == time.February && local.Day() == 29 && at(year, time.February, 29).Month() != time.February {
+				return nil
+			}
+			return []time.Time{at(year, local.Month(), local.Day())}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported RRULE frequency: %s", parts["FREQ"])
+	}
+
+	// DTSTART is always the first occurrence. COUNT includes occurrences
+	// before after, so every one is counted even though only later ones are kept.
+	starts := []time.Time{start}
+	seen := 1
+	for period := 0; period < maxRecurrencePeriods && len(starts) < maxRecurrences; period++ {
+		occurrences := candidates(period)
+		sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].Before(occurrences[j]) })
+		if period > 0 && len(occurrences) > 0 && !occurrences[0].Before(until) {
+			break
+		}
+		for _, occurrence := range occurrences {
+			if !occurrence.After(start) {
+				continue
+			}
+			if !occurrence.Before(until) || (count > 0 && seen >= count) {
+				return starts, nil
+			}
+			seen++
+			if !occurrence.Before(after) {
+				starts = append(starts, occurrence)
+			}
+		}
+	}
+	return starts, nil
+}
+
+// icalWeekday is a BYDAY value such as MO, or 2TU for the second Tuesday
+type icalWeekday struct {
+	Day     time.Weekday
+	Ordinal int // 0 for every such day in the period; negative counts from the end
+}
+
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseICalWeekday(value string) (icalWeekday, error) {
+	if len(value) < 2 {
+		return icalWeekday{}, fmt.Errorf("invalid RRULE day: %s", value)
+	}
+	day, ok := icalWeekdays[value[len(value)-2:]]
+	if !ok {
+		return icalWeekday{}, fmt.Errorf("invalid RRULE day: %s", value)
+	}
+	weekday := icalWeekday{Day: day}
+	if prefix := value[:len(value)-2]; prefix != "" {
+		n, err := strconv.Atoi(prefix)
+		if err != nil || n == 0 {
+			return icalWeekday{}, fmt.Errorf("invalid RRULE day: %s", value)
+		}
+		weekday.Ordinal = n
+	}
+	return weekday, nil
+}
+
+func matchesWeekday(t time.Time, days []icalWeekday) bool {
+	for _, day := range days {
+		if t.Weekday() == day.Day {
+			return true
+		}
+	}
+	return false
+}
+
+// monthDays returns the given days of first's month; negative days count
+// back from the end and days the month doesn't have are skipped
+func monthDays(first time.Time, days []int, at func(int, time.Month, int) time.Time) []time.Time {
+	length := first.AddDate(0, 1, -1).Day()
+	var occurrences []time.Time
+	for _, day := range days {
+		if day < 0 {
+			day = length + day + 1
+		}
+		if day >= 1 && day <= length {
+			occurrences = append(occurrences, at(first.Year(), first.Month(), day))
+		}
+	}
+	return occurrences
+}
+
+// monthWeekdays returns the matching weekdays of first's month, such as
+// every Monday or the last Friday
+func monthWeekdays(first time.Time, days []icalWeekday, at func(int, time.Month, int) time.Time) []time.Time {
+	length := first.AddDate(0, 1, -1).Day()
+	var occurrences []time.Time
+	for _, day := range days {
+		var matching []int
+		for d := 1; d <= length; d++ {
+			if time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, time.UTC).Weekday() == day.Day {
+				matching = append(matching, d)
+			}
+		}
+		switch {
+		case day.Ordinal == 0:
+			for _, d := range matching {
+				occurrences = append(occurrences, at(first.Year(), first.Month(), d))
+			}
+		case day.Ordinal > 0 && day.Ordinal <= len(matching):
+			occurrences = append(occurrences, at(first.Year(), first.Month(), matching[day.Ordinal-1]))
+		case day.Ordinal < 0 && -day.Ordinal <= len(matching):
+			occurrences = append(occurrences, at(first.Year(), first.Month(), matching[len(matching)+day.Ordinal]))
+		}
+	}
+	return occurrences
+}
+
+// parseFreeBusy reads the busy periods of a FREEBUSY property
+func parseFreeBusy(prop *icalProperty, loc *time.Location) ([]ICalPeriod, error) {
+	if fbType := strings.ToUpper(prop.Params["FBTYPE"]); fbType == "FREE" {
+		return nil, nil
+	}
+	var periods []ICalPeriod
+	for _, value := range strings.Split(prop.Value, ",") {
+		startValue, endValue, ok := strings.Cut(value, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid FREEBUSY period: %s", value)
+		}
+		start, _, err := parseICalTime(&icalProperty{Value: startValue}, loc)
+		if err != nil {
+			return nil, err
+		}
+		var end time.Time
+		if strings.HasPrefix(endValue, "P") || strings.HasPrefix(endValue, "+P") {
+			length, err := parseICalDuration(endValue)
+			if err != nil {
+				return nil, err
+			}
+			end = start.Add(length)
+		} else if end, _, err = parseICalTime(&icalProperty{Value: endValue}, loc); err != nil {
+			return nil, err
+		}
+		if end.After(start) {
+			periods = append(periods, ICalPeriod{Start: start, End: end})
+		}
+	}
+	return periods, nil
+}
+
+// parseICalTimes reads a property that may list several comma-separated times
+func parseICalTimes(prop *icalProperty, loc *time.Location) ([]time.Time, error) {
+	var times []time.Time
+	for _, value := range strings.Split(prop.Value, ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		t, _, err := parseICalTime(&icalProperty{Value: value, Params: prop.Params}, loc)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+// parseICalTime reads a DATE or DATE-TIME value, reporting whether it was a date
+func parseICalTime(prop *icalProperty, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.Value)
+	if tzid := prop.Params["TZID"]; tzid != "" {
+		loc = loadICalTimezone(tzid, loc)
+	}
+
+	switch {
+	case len(value) == 8:
+		t, err := time.ParseInLocation("20060102", value, loc)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid iCalendar date: %s", value)
+		}
+		return t, true, nil
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse(icalDateTime+"Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid iCalendar time: %s", value)
+		}
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation(icalDateTime, value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid iCalendar time: %s", value)
+	}
+	return t, false, nil
+}
+
+// loadICalTimezone loads a TZID. Some apps prefix the IANA name with a path,
+// such as /mozilla.org/20050126_1/America/New_York; names we still can't
+// place, like Windows zone names, fall back to loc.
+func loadICalTimezone(tzid string, loc *time.Location) *time.Location {
+	tzid = strings.Trim(tzid, `"`)
+	for name := tzid; name != ""; {
+		if zone, err := time.LoadLocation(name); err == nil {
+			return zone
+		}
+		_, rest, ok := strings.Cut(name, "/")
+		if !ok {
+			break
+		}
+		name = rest
+	}
+	return loc
+}
+
+// parseICalDuration reads a DURATION value such as PT1H30M or P1W
+func parseICalDuration(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid iCalendar duration: %s", value)
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign, value = -1, value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return 0, invalid
+	}
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for _, ch := range value[1:] {
+		switch {
+		case ch >= '0' && ch <= '9':
+			number += string(ch)
+			continue
+		case ch == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, invalid
+		}
+		number = ""
+		switch {
+		case ch == 'W' && !inTime:
+			total += time.Duration(n) * 7 * 24 * time.Hour
+		case ch == 'D' && !inTime:
+			total += time.Duration(n) * 24 * time.Hour
+		case ch == 'H' && inTime:
+			total += time.Duration(n) * time.Hour
+		case ch == 'M' && inTime:
+			total += time.Duration(n) * time.Minute
+		case ch == 'S' && inTime:
+			total += time.Duration(n) * time.Second
+		default:
+			return 0, invalid
+		}
+	}
+	if number != "" {
+		return 0, invalid
+	}
+	return sign * total, nil
+}
+
+// readICalProperties unfolds and splits an iCalendar file into properties
+func readICalProperties(r io.Reader) ([]*icalProperty, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+
+	props := make([]*icalProperty, 0, len(lines))
+	for _, line := range lines {
+		if prop := parseICalLine(line); prop != nil {
+			props = append(props, prop)
+		}
+	}
+	return props, nil
+}
+
+// parseICalLine splits NAME;PARAM=value;PARAM="quoted:value":VALUE, skipping
+// lines that aren't properties
+func parseICalLine(line string) *icalProperty {
+	// The value starts at the first colon outside a quoted parameter
+	inQuotes, colon := false, -1
+	for i, ch := range line {
+		if ch == '"' {
+			inQuotes = !inQuotes
+		} else if ch == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return nil
+	}
+
+	head := strings.Split(line[:colon], ";")
+	prop := &icalProperty{
+		Name:   strings.ToUpper(strings.TrimSpace(head[0])),
+		Params: make(map[string]string),
+		Value:  line[colon+1:],
+	}
+	for _, param := range head[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			prop.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prop
+}
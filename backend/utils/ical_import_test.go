@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestCalendar(t *testing.T, body string, from, to time.Time) []ICalPeriod {
+	t.Helper()
+	ics := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.ReplaceAll(strings.TrimSpace(body), "\n", "\r\n") + "\r\nEND:VCALENDAR\r\n"
+	periods, err := ParseICalBusy(strings.NewReader(ics), time.UTC, from, to)
+	require.NoError(t, err)
+	return periods
+}
+
+func TestParseICalBusy(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, ny)
+	to := from.AddDate(0, 0, 14)
+
+	t.Run("single events in their own zone", func(t *testing.T) {
+		periods := parseTestCalendar(t, `
+BEGIN:VEVENT
+UID:1
+SUMMARY:Dentist
+DTSTART;TZID=America/New_York:20260303T090000
+DTEND;TZID=America/New_York:20260303T100000
+END:VEVENT
+BEGIN:VEVENT
+UID:2
+DTSTART:20260304T150000Z
+DURATION:PT30M
+END:VEVENT`, from, to)
+		require.Len(t, periods, 2)
+		assert.True(t, periods[0].Start.Equal(time.Date(2026, time.March, 3, 9, 0, 0, 0, ny)))
+		assert.True(t, periods[0].End.Equal(time.Date(2026, time.March, 3, 10, 0, 0, 0, ny)))
+		assert.Equal(t, 30*time.Minute, periods[1].End.Sub(periods[1].Start))
+	})
+
+	t.Run("free, cancelled and out of range events are skipped", func(t *testing.T) {
+		periods := parseTestCalendar(t, `
+BEGIN:VEVENT
+UID:1
+DTSTART:20260303T090000Z
+DTEND:20260303T100000Z
+TRANSP:TRANSPARENT
+END:VEVENT
+BEGIN:VEVENT
+UID:2
+DTSTART:20260303T090000Z
+DTEND:20260303T100000Z
+STATUS:CANCELLED
+END:VEVENT
+BEGIN:VEVENT
+UID:3
+DTSTART:20260501T090000Z
+DTEND:20260501T100000Z
+END:VEVENT`, from, to)
+		assert.Empty(t, periods)
+	})
+
+	t.Run("weekly recurrence keeps local time across DST", func(t *testing.T) {
+		// Mondays and Wednesdays at 6pm; March 8 2026 is the DST change
+		periods := parseTestCalendar(t, `
+BEGIN:VEVENT
+UID:1
+DTSTART;TZID=America/New_York:20260202T180000
+DTEND;TZID=America/New_York:20260202T190000
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE
+EXDATE;TZID=America/New_York:20260304T180000
+END:VEVENT`, from, to)
+		var starts []string
+		for _, p := range periods {
+			starts = append(starts, p.Start.In(ny).Format("Mon Jan 2 15:04"))
+		}
+		assert.Equal(t, []string{"Mon Mar 2 18:00", "Mon Mar 9 18:00", "Wed Mar 11 18:00"}, starts)
+	})
+
+	t.Run("moved occurrence replaces the original", func(t *testing.T) {
+		periods := parseTestCalendar(t, `
+BEGIN:VEVENT
+UID:series
+DTSTART:20260302T120000Z
+DTEND:20260302T130000Z
+RRULE:FREQ=DAILY;COUNT=3
+END:VEVENT
+BEGIN:VEVENT
+UID:series
+RECURRENCE-ID:20260303T120000Z
+DTSTART:20260303T160000Z
+DTEND:20260303T170000Z
+END:VEVENT`, from, to)
+		var starts []string
+		for _, p := range periods {
+			starts = append(starts, p.Start.UTC().Format("Jan 2 15:04"))
+		}
+		assert.Equal(t, []string{"Mar 2 12:00", "Mar 3 16:00", "Mar 4 12:00"}, starts)
+	})
+
+	t.Run("long running series reach the window", func(t *testing.T) {
+		periods := parseTestCalendar(t, `
+BEGIN:VEVENT
+UID:1
+DTSTART:20000101T070000Z
+DTEND:20000101T080000Z
+RRULE:FREQ=DAILY
+END:VEVENT`, from, to)
+		assert.Len(t, periods, 14)
+	})
+
+	t.Run("monthly by weekday and all-day events", func(t *testing.T) {
+		periods := parseTestCalendar(t, `
+BEGIN:VEVENT
+UID:1
+DTSTART;VALUE=DATE:20260113
+RRULE:FREQ=MONTHLY;BYDAY=2TU;UNTIL=20261231
+END:VEVENT`, from, to)
+		require.Len(t, periods, 1)
+		assert.Equal(t, time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC), periods[0].Start)
+		assert.Equal(t, 24*time.Hour, periods[0].End.Sub(periods[0].Start))
+	})
+
+	t.Run("free/busy periods", func(t *testing.T) {
+		periods := parseTestCalendar(t, `
+BEGIN:VFREEBUSY
+FREEBUSY;FBTYPE=BUSY:20260305T140000Z/20260305T150000Z,20260306T140000Z/PT2H
+FREEBUSY;FBTYPE=FREE:20260307T140000Z/20260307T150000Z
+END:VFREEBUSY`, from, to)
+		require.Len(t, periods, 2)
+		assert.Equal(t, 2*time.Hour, periods[1].End.Sub(periods[1].Start))
+	})
+
+	t.Run("rejects files that aren't calendars", func(t *testing.T) {
+		_, err := ParseICalBusy(strings.NewReader("hello"), time.UTC, from, to)
+		assert.EqualError(t, err, "not an iCalendar file")
+	})
+}
+
+func TestParseICalDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT1H30M": 90 * time.Minute,
+		"P1W":     7 * 24 * time.Hour,
+		"P1DT2H":  26 * time.Hour,
+		"-PT15M":  -15 * time.Minute,
+	}
+	for value, expected := range tests {
+		got, err := parseICalDuration(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, got, value)
+	}
+
+	_, err := parseICalDuration("P1H")
+	assert.Error(t, err)
+}
+
+func TestLoadICalTimezone(t *testing.T) {
+	assert.Equal(t, "America/New_York", loadICalTimezone("/mozilla.org/20050126_1/America/New_York", time.UTC).String())
+	assert.Equal(t, time.UTC, loadICalTimezone("Eastern Standard Time", time.UTC))
+}
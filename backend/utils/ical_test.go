@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderICalendar(t *testing.T) {
+	ny, _ := time.LoadLocation("America/New_York")
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	event := ICalEvent{
+		UID:          "booking-1@tennis-connect",
+		Sequence:     3,
+		Summary:      "Tennis at Court 1, Central Park",
+		Description:  "Doubles\nBring balls; we have none",
+		Status:       "CANCELLED",
+		Start:        time.Date(2026, time.March, 20, 17, 0, 0, 0, ny),
+		End:          time.Date(2026, time.March, 20, 18, 0, 0, 0, ny),
+		Timezone:     "America/New_York",
+		Created:      now,
+		LastModified: now.Add(3 * time.Second),
+	}
+
+	ics := RenderICalendar("My tennis", []ICalEvent{event}, now)
+	lines := strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n")
+
+	assert.Equal(t, "BEGIN:VCALENDAR", lines[0])
+	assert.Equal(t, "END:VCALENDAR", lines[len(lines)-1])
+	assert.NotContains(t, strings.ReplaceAll(ics, "\r\n", ""), "\n", "every line should end in CRLF")
+	assert.Contains(t, lines, "UID:booking-1@tennis-connect")
+	assert.Contains(t, lines, "SEQUENCE:3")
+	assert.Contains(t, lines, "STATUS:CANCELLED")
+	assert.Contains(t, lines, "DTSTAMP:20260301T120000Z")
+	assert.Contains(t, lines, "DTSTART;TZID=America/New_York:20260320T170000")
+	assert.Contains(t, lines, "DTEND;TZID=America/New_York:20260320T180000")
+	assert.Contains(t, lines, `SUMMARY:Tennis at Court 1\, Central Park`)
+	assert.Contains(t, lines, `DESCRIPTION:Doubles\nBring balls\; we have none`)
+	assert.Contains(t, lines, "LAST-MODIFIED:20260301T120003Z")
+
+	t.Run("timezone covers the event", func(t *testing.T) {
+		assert.Contains(t, lines, "TZID:America/New_York")
+		// Starts in EST, then the March 8 change to EDT at 2am local
+		assert.Contains(t, ics, "BEGIN:STANDARD\r\nDTSTART:20260101T000000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0500\r\nTZNAME:EST\r\nEND:STANDARD")
+		assert.Contains(t, ics, "BEGIN:DAYLIGHT\r\nDTSTART:20260308T020000\r\nTZOFFSETFROM:-0500\r\nTZOFFSETTO:-0400\r\nTZNAME:EDT\r\nEND:DAYLIGHT")
+		// The November change back is after the event
+		assert.NotContains(t, ics, "DTSTART:20261101")
+	})
+
+	t.Run("UTC events need no timezone", func(t *testing.T) {
+		event.Timezone = ""
+		ics := RenderICalendar("My tennis", []ICalEvent{event}, now)
+		assert.NotContains(t, ics, "VTIMEZONE")
+		assert.Contains(t, ics, "DTSTART:20260320T210000Z\r\n")
+	})
+
+	t.Run("fixed offset zones", func(t *testing.T) {
+		event.Timezone = "Etc/GMT-8"
+		ics := RenderICalendar("My tennis", []ICalEvent{event}, now)
+		assert.Equal(t, 1, strings.Count(ics, "BEGIN:STANDARD"))
+		assert.Contains(t, ics, "TZOFFSETTO:+0800")
+		assert.Contains(t, ics, "DTSTART;TZID=Etc/GMT-8:20260321T050000")
+	})
+}
+
+func TestFoldICalLine(t *testing.T) {
+	assert.Equal(t, "SUMMARY:short", foldICalLine("SUMMARY:short"))
+
+	line := "DESCRIPTION:" + strings.Repeat("網球", 40)
+	folded := foldICalLine(line)
+	parts := strings.Split(folded, "\r\n")
+	assert.Greater(t, len(parts), 1)
+	for i, part := range parts {
+		assert.LessOrEqual(t, len(part), 75)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(part, " "))
+		}
+		assert.True(t, strings.ToValidUTF8(part, "?") == part, "folding split a character")
+	}
+
+	unfolded := strings.ReplaceAll(folded, "\r\n ", "")
+	assert.Equal(t, line, unfolded)
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	assert.Equal(t, "+0000", formatUTCOffset(0))
+	assert.Equal(t, "+0530", formatUTCOffset(5*3600+30*60))
+	assert.Equal(t, "-0800", formatUTCOffset(-8*3600))
+	assert.Equal(t, "-001715", formatUTCOffset(-(17*60 + 15)))
+}
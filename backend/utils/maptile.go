@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+)
+
+// MaxMapZoom is the deepest web map zoom level served
+const MaxMapZoom = 22
+
+// MapClusterPixels is roughly how far apart, in screen pixels at 256 pixels
+// per tile, map markers are clustered
+const MapClusterPixels = 64
+
+// MaxMapCells caps how many grid cells a single marker request covers. A
+// screen at the requested zoom needs a few hundred.
+const MaxMapCells = 4096
+
+// TileBounds returns the area covered by web map tile x, y at a zoom level,
+// using the Web Mercator tiling used by Mapbox, Leaflet and Google Maps
+func TileBounds(zoom, x, y int) (BoundingBox, error) {
+	if zoom < 0 || zoom > MaxMapZoom {
+		return BoundingBox{}, fmt.Errorf("zoom must be between 0 and %d", MaxMapZoom)
+	}
+	tiles := 1 << zoom
+	if x < 0 || x >= tiles || y < 0 || y >= tiles {
+		return BoundingBox{}, fmt.Errorf("tile %d/%d/%d does not exist", zoom, x, y)
+	}
+	return BoundingBox{
+		MinLat: tileLatitude(y+1, tiles),
+		MaxLat: tileLatitude(y, tiles),
+		MinLng: float64(x)/float64(tiles)*360 - 180,
+		MaxLng: float64(x+1)/float64(tiles)*360 - 180,
+	}, nil
+}
+
+// tileLatitude returns the latitude of the top edge of tile row y
+func tileLatitude(y, tiles int) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/float64(tiles)))) * 180 / math.Pi
+}
+
+// TilePoint returns where a point falls in tile x, y at a zoom level, in
+// tile coordinates running from 0 to extent left to right and top to bottom
+func TilePoint(zoom, x, y int, latitude, longitude float64, extent int) (int, int) {
+	tiles := float64(int(1) << zoom)
+	sinLat := math.Sin(latitude * math.Pi / 180)
+	worldX := (longitude + 180) / 360
+	worldY := 0.5 - math.Log((1+sinLat)/(1-sinLat))/(4*math.Pi)
+	return int(math.Round((worldX*tiles - float64(x)) * float64(extent))),
+		int(math.Round((worldY*tiles - float64(y)) * float64(extent)))
+}
+
+// GeohashPrecisionForZoom returns the geohash length whose cells are closest
+// in width to MapClusterPixels at a zoom level, so clustering by geohash
+// prefix gives clusters about that far apart on screen
+func GeohashPrecisionForZoom(zoom int) int {
+	target := 360 / math.Pow(2, float64(zoom)) * MapClusterPixels / 256
+	best, bestDiff := 1, math.Inf(1)
+	for precision := 1; precision <= GeohashPrecision; precision++ {
+		_, width := geohashCellSize(precision)
+		if diff := math.Abs(math.Log(width / target)); diff < bestDiff {
+			best, bestDiff = precision, diff
+		}
+	}
+	return best
+}
+
+// GeohashBounds returns the cell a geohash stands for
+func GeohashBounds(hash string) (BoundingBox, error) {
+	box := BoundingBox{MinLat: -90, MaxLat: 90, MinLng: -180, MaxLng: 180}
+	even := true
+	for i := 0; i < len(hash); i++ {
+		bits := -1
+		for j := 0; j < len(geohashAlphabet); j++ {
+			if geohashAlphabet[j] == hash[i] {
+				bits = j
+				break
+			}
+		}
+		if bits < 0 {
+			return BoundingBox{}, fmt.Errorf("invalid geohash %q", hash)
+		}
+		for mask := 16; mask > 0; mask >>= 1 {
+			if even {
+				mid := (box.MinLng + box.MaxLng) / 2
+				if bits&mask != 0 {
+					box.MinLng = mid
+				} else {
+					box.MaxLng = mid
+				}
+			} else {
+				mid := (box.MinLat + box.MaxLat) / 2
+				if bits&mask != 0 {
+					box.MinLat = mid
+				} else {
+					box.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return box, nil
+}
+
+// Center returns the middle of the box
+func (b BoundingBox) Center() (float64, float64) {
+	longitude := (b.MinLng + b.MaxLng) / 2
+	if b.CrossesAntimeridian() {
+		if longitude += 180; longitude > 180 {
+			longitude -= 360
+		}
+	}
+	return (b.MinLat + b.MaxLat) / 2, longitude
+}
+
+// SnapToGeohashCells grows the box outwards to the edges of the geohash
+// cells of the given length it overlaps, so every cell touching the box is
+// wholly inside it
+func (b BoundingBox) SnapToGeohashCells(precision int) BoundingBox {
+	height, width := geohashCellSize(precision)
+	snapped := BoundingBox{
+		MinLat: math.Max(-90, -90+math.Floor((b.MinLat+90)/height)*height),
+		MaxLat: math.Min(90, -90+math.Ceil((b.MaxLat+90)/height)*height),
+		MinLng: math.Max(-180, -180+math.Floor((b.MinLng+180)/width)*width),
+		MaxLng: math.Min(180, -180+math.Ceil((b.MaxLng+180)/width)*width),
+	}
+	if snapped.MaxLat == snapped.MinLat {
+		snapped.MaxLat = math.Min(90, snapped.MinLat+height)
+	}
+	if snapped.MaxLng == snapped.MinLng {
+		snapped.MaxLng = math.Min(180, snapped.MinLng+width)
+	}
+	return snapped
+}
+
+// GeohashCellCount returns how many geohash cells of the given length
+// overlap the box
+func GeohashCellCount(box BoundingBox, precision int) int {
+	height, width := geohashCellSize(precision)
+	lngRanges := [][2]float64{{box.MinLng, box.MaxLng}}
+	if box.CrossesAntimeridian() {
+		lngRanges = [][2]float64{{box.MinLng, 180}, {-180, box.MaxLng}}
+	}
+	columns := 0
+	for _, lngRange := range lngRanges {
+		columns += cellSpan(lngRange[0], lngRange[1], -180, width)
+	}
+	return cellSpan(box.MinLat, box.MaxLat, -90, height) * columns
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestTileBounds(t *testing.T) {
+	world, err := TileBounds(0, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, -180.0, world.MinLng)
+	assert.Equal(t, 180.0, world.MaxLng)
+	assert.InDelta(t, 85.0511, world.MaxLat, 1e-4)
+	assert.InDelta(t, -85.0511, world.MinLat, 1e-4)
+
+	// The zoom 12 tile holding San Francisco's Golden Gate Park
+	tile, err := TileBounds(12, 654, 1583)
+	require.NoError(t, err)
+	assert.True(t, tile.Contains(37.7705, -122.4588))
+	assert.InDelta(t, 360.0/4096, tile.MaxLng-tile.MinLng, 1e-12)
+
+	_, err = TileBounds(2, 4, 0)
+	assert.EqualError(t, err, "tile 2/4/0 does not exist")
+	_, err = TileBounds(MaxMapZoom+1, 0, 0)
+	assert.Error(t, err)
+}
+
+func TestTilePoint(t *testing.T) {
+	tile, err := TileBounds(12, 654, 1583)
+	require.NoError(t, err)
+
+	x, y := TilePoint(12, 654, 1583, tile.MaxLat, tile.MinLng, VectorTileExtent)
+	assert.Equal(t, 0, x)
+	assert.Equal(t, 0, y)
+	x, y = TilePoint(12, 654, 1583, tile.MinLat, tile.MaxLng, VectorTileExtent)
+	assert.Equal(t, VectorTileExtent, x)
+	assert.Equal(t, VectorTileExtent, y)
+
+	// North is up, so y grows southwards
+	_, north := TilePoint(12, 654, 1583, 37.772, -122.4588, VectorTileExtent)
+	_, south := TilePoint(12, 654, 1583, 37.769, -122.4588, VectorTileExtent)
+	assert.Less(t, north, south)
+}
+
+func TestGeohashPrecisionForZoom(t *testing.T) {
+	assert.Equal(t, 1, GeohashPrecisionForZoom(0))
+	assert.Equal(t, GeohashPrecision, GeohashPrecisionForZoom(MaxMapZoom))
+
+	previous := 0
+	for zoom := 0; zoom <= MaxMapZoom; zoom++ {
+		precision := GeohashPrecisionForZoom(zoom)
+		assert.GreaterOrEqual(t, precision, previous, "zoom %d", zoom)
+		previous = precision
+	}
+
+	// Cells are within a factor of two of MapClusterPixels across
+	for _, zoom := range []int{4, 8, 12, 16} {
+		_, width := geohashCellSize(GeohashPrecisionForZoom(zoom))
+		target := 360 / math.Pow(2, float64(zoom)) * MapClusterPixels / 256
+		assert.LessOrEqual(t, math.Abs(math.Log2(width/target)), 1.0, "zoom %d", zoom)
+	}
+}
+
+func TestGeohashBounds(t *testing.T) {
+	cell, err := GeohashBounds("9q8yy")
+	require.NoError(t, err)
+	assert.True(t, cell.Contains(37.7749, -122.4194))
+	height, width := geohashCellSize(5)
+	assert.InDelta(t, height, cell.MaxLat-cell.MinLat, 1e-12)
+	assert.InDelta(t, width, cell.MaxLng-cell.MinLng, 1e-12)
+
+	latitude, longitude := cell.Center()
+	assert.Equal(t, "9q8yy", EncodeGeohash(latitude, longitude, 5))
+
+	_, err = GeohashBounds("9q8ya")
+	assert.EqualError(t, err, `invalid geohash "9q8ya"`)
+}
+
+func TestSnapToGeohashCells(t *testing.T) {
+	box := NewBoundingBox(37.7749, -122.4194, 1)
+	snapped := box.SnapToGeohashCells(5)
+	assert.LessOrEqual(t, snapped.MinLat, box.MinLat)
+	assert.GreaterOrEqual(t, snapped.MaxLat, box.MaxLat)
+	assert.LessOrEqual(t, snapped.MinLng, box.MinLng)
+	assert.GreaterOrEqual(t, snapped.MaxLng, box.MaxLng)
+
+	// A box inside one cell snaps to that cell
+	cell, err := GeohashBounds("9q8yy")
+	require.NoError(t, err)
+	inside := BoundingBox{MinLat: cell.MinLat + 0.001, MaxLat: cell.MaxLat - 0.001, MinLng: cell.MinLng + 0.001, MaxLng: cell.MaxLng - 0.001}
+	assert.Equal(t, cell, inside.SnapToGeohashCells(5))
+
+	fiji := NewBoundingBox(-17.7, 179.9, 50).SnapToGeohashCells(3)
+	assert.True(t, fiji.CrossesAntimeridian())
+}
+
+func TestGeohashCellCount(t *testing.T) {
+	world := BoundingBox{MinLat: -90, MaxLat: 90, MinLng: -180, MaxLng: 180}
+	assert.Equal(t, 32, GeohashCellCount(world, 1))
+	assert.Equal(t, 1024, GeohashCellCount(world, 2))
+
+	cell, err := GeohashBounds("9q8yy")
+	require.NoError(t, err)
+	latitude, longitude := cell.Center()
+	assert.Equal(t, 1, GeohashCellCount(BoundingBox{MinLat: latitude, MaxLat: latitude, MinLng: longitude, MaxLng: longitude}, 5))
+}
+
+func TestEncodeVectorTile(t *testing.T) {
+	tile, err := EncodeVectorTile([]VectorTileLayer{{
+		Name: "markers",
+		Features: []VectorTilePoint{
+			{X: 100, Y: 200, Properties: map[string]interface{}{"count": 3, "cluster": true}},
+			{X: -5, Y: 4100, Properties: map[string]interface{}{"count": 3, "label": "Dolores Park", "rating": 4.5}},
+		},
+	}})
+	require.NoError(t, err)
+
+	layers := decodeFields(t, tile)
+	require.Len(t, layers[3], 1)
+	layer := decodeFields(t, layers[3][0])
+	assert.Equal(t, "markers", string(layer[1][0]))
+	assert.Equal(t, []string{"cluster", "count", "label", "rating"}, stringsOf(layer[3]))
+	assert.Len(t, layer[4], 4, "equal values are shared")
+	require.Len(t, layer[2], 2)
+
+	feature := decodeFields(t, layer[2][1])
+	geometry := packedVarints(t, feature[4][0])
+	assert.Equal(t, []uint64{9, protowire.EncodeZigZag(-5), protowire.EncodeZigZag(4100)}, geometry)
+	// Pairs of key and value indexes, sharing the first feature's count
+	tags := packedVarints(t, feature[2][0])
+	assert.Equal(t, []uint64{1, 1, 2, 2, 3, 3}, tags)
+
+	_, err = EncodeVectorTile([]VectorTileLayer{{Name: "markers", Features: []VectorTilePoint{
+		{Properties: map[string]interface{}{"tags": []string{"a"}}},
+	}}})
+	assert.EqualError(t, err, "invalid property tags in layer markers: unsupported type []string")
+}
+
+// decodeFields splits a protobuf message into its length-delimited fields
+func decodeFields(t *testing.T, data []byte) map[protowire.Number][][]byte {
+	t.Helper()
+	fields := map[protowire.Number][][]byte{}
+	for len(data) > 0 {
+		number, wireType, n := protowire.ConsumeTag(data)
+		require.GreaterOrEqual(t, n, 0)
+		data = data[n:]
+		n = protowire.ConsumeFieldValue(number, wireType, data)
+		require.GreaterOrEqual(t, n, 0)
+		if wireType == protowire.BytesType {
+			value, _ := protowire.ConsumeBytes(data)
+			fields[number] = append(fields[number], value)
+		}
+		data = data[n:]
+	}
+	return fields
+}
+
+func packedVarints(t *testing.T, data []byte) []uint64 {
+	t.Helper()
+	var values []uint64
+	for len(data) > 0 {
+		value, n := protowire.ConsumeVarint(data)
+		require.GreaterOrEqual(t, n, 0)
+		values = append(values, value)
+		data = data[n:]
+	}
+	return values
+}
+
+func stringsOf(values [][]byte) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = string(value)
+	}
+	return result
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// VectorTileExtent is the number of tile coordinates across a vector tile
+const VectorTileExtent = 4096
+
+// VectorTileContentType is the media type of an encoded vector tile
+const VectorTileContentType = "application/vnd.mapbox-vector-tile"
+
+// VectorTileLayer is a named layer of point features in a vector tile
+type VectorTileLayer struct {
+	Name     string
+	Features []VectorTilePoint
+}
+
+// VectorTilePoint is a point feature at X, Y in tile coordinates (see
+// TilePoint). Properties may be strings, bools, ints or float64s.
+type VectorTilePoint struct {
+	ID         uint64
+	X, Y       int
+	Properties map[string]interface{}
+}
+
+// Field numbers and values from the Mapbox Vector Tile 2.1 protobuf schema
+const (
+	mvtTileLayers = 3
+
+	mvtLayerVersion  = 15
+	mvtLayerName     = 1
+	mvtLayerFeatures = 2
+	mvtLayerKeys     = 3
+	mvtLayerValues   = 4
+	mvtLayerExtent   = 5
+
+	mvtFeatureID       = 1
+	mvtFeatureTags     = 2
+	mvtFeatureType     = 3
+	mvtFeatureGeometry = 4
+	mvtGeometryPoint   = 1
+	mvtCommandMoveTo   = 1
+
+	mvtValueString = 1
+	mvtValueDouble = 3
+	mvtValueSint   = 6
+	mvtValueBool   = 7
+)
+
+// EncodeVectorTile encodes layers of points as a Mapbox Vector Tile
+func EncodeVectorTile(layers []VectorTileLayer) ([]byte, error) {
+	var tile []byte
+	for _, layer := range layers {
+		encoded, err := encodeVectorTileLayer(layer)
+		if err != nil {
+			return nil, err
+		}
+		tile = protowire.AppendTag(tile, mvtTileLayers, protowire.BytesType)
+		tile = protowire.AppendBytes(tile, encoded)
+	}
+	return tile, nil
+}
+
+func encodeVectorTileLayer(layer VectorTileLayer) ([]byte, error) {
+	// Keys and values are shared by the layer's features, which refer to
+	// them by index
+	keys := map[string]uint64{}
+	var keyList []string
+	values := map[interface{}]uint64{}
+	var valueList [][]byte
+
+	var features [][]byte
+	for _, point := range layer.Features {
+		names := make([]string, 0, len(point.Properties))
+		for name := range point.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var tags []byte
+		for _, name := range names {
+			value, err := normalizeVectorTileValue(point.Properties[name])
+			if err != nil {
+				return nil, fmt.Errorf("invalid property %s in layer %s: %w", name, layer.Name, err)
+			}
+			key, ok := keys[name]
+			if !ok {
+				key = uint64(len(keyList))
+				keys[name] = key
+				keyList = append(keyList, name)
+			}
+			index, ok := values[value]
+			if !ok {
+				index = uint64(len(valueList))
+				values[value] = index
+				valueList = append(valueList, encodeVectorTileValue(value))
+			}
+			tags = protowire.AppendVarint(tags, key)
+			tags = protowire.AppendVarint(tags, index)
+		}
+
+		var geometry []byte
+		geometry = protowire.AppendVarint(geometry, mvtCommandMoveTo|1<<3)
+		geometry = protowire.AppendVarint(geometry, protowire.EncodeZigZag(int64(point.X)))
+		geometry = protowire.AppendVarint(geometry, protowire.EncodeZigZag(int64(point.Y)))
+
+		var feature []byte
+		if point.ID != 0 {
+			feature = protowire.AppendTag(feature, mvtFeatureID, protowire.VarintType)
+			feature = protowire.AppendVarint(feature, point.ID)
+		}
+		if len(tags) > 0 {
+			feature = protowire.AppendTag(feature, mvtFeatureTags, protowire.BytesType)
+			feature = protowire.AppendBytes(feature, tags)
+		}
+		feature = protowire.AppendTag(feature, mvtFeatureType, protowire.VarintType)
+		feature = protowire.AppendVarint(feature, mvtGeometryPoint)
+		feature = protowire.AppendTag(feature, mvtFeatureGeometry, protowire.BytesType)
+		feature = protowire.AppendBytes(feature, geometry)
+		features = append(features, feature)
+	}
+
+	var encoded []byte
+	encoded = protowire.AppendTag(encoded, mvtLayerVersion, protowire.VarintType)
+	encoded = protowire.AppendVarint(encoded, 2)
+	encoded = protowire.AppendTag(encoded, mvtLayerName, protowire.BytesType)
+	encoded = protowire.AppendString(encoded, layer.Name)
+	for _, feature := range features {
+		encoded = protowire.AppendTag(encoded, mvtLayerFeatures, protowire.BytesType)
+		encoded = protowire.AppendBytes(encoded, feature)
+	}
+	for _, key := range keyList {
+		encoded = protowire.AppendTag(encoded, mvtLayerKeys, protowire.BytesType)
+		encoded = protowire.AppendString(encoded, key)
+	}
+	for _, value := range valueList {
+		encoded = protowire.AppendTag(encoded, mvtLayerValues, protowire.BytesType)
+		encoded = protowire.AppendBytes(encoded, value)
+	}
+	encoded = protowire.AppendTag(encoded, mvtLayerExtent, protowire.VarintType)
+	encoded = protowire.AppendVarint(encoded, VectorTileExtent)
+	return encoded, nil
+}
+
+// normalizeVectorTileValue converts a property to one of the types a tile
+// value can hold, so equal values share an index
+func normalizeVectorTileValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string, bool, int64, float64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+func encodeVectorTileValue(value interface{}) []byte {
+	var encoded []byte
+	switch v := value.(type) {
+	case string:
+		encoded = protowire.AppendTag(encoded, mvtValueString, protowire.BytesType)
+		encoded = protowire.AppendString(encoded, v)
+	case float64:
+		encoded = protowire.AppendTag(encoded, mvtValueDouble, protowire.Fixed64Type)
+		encoded = protowire.AppendFixed64(encoded, math.Float64bits(v))
+	case int64:
+		encoded = protowire.AppendTag(encoded, mvtValueSint, protowire.VarintType)
+		encoded = protowire.AppendVarint(encoded, protowire.EncodeZigZag(v))
+	case bool:
+		encoded = protowire.AppendTag(encoded, mvtValueBool, protowire.VarintType)
+		encoded = protowire.AppendVarint(encoded, protowire.EncodeBool(v))
+	}
+	return encoded
+}
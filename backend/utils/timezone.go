@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"time"
+	_ "time/tzdata" // Embed the timezone database so lookups work on hosts without one
+)
+
+// timezoneReference is a city whose IANA timezone stands for the area around it
+type timezoneReference struct {
+	Latitude  float64
+	Longitude float64
+	Timezone  string
+}
+
+// timezoneReferences covers the cities we serve plus the major cities of each
+// timezone. A coordinate takes the timezone of the nearest reference.
+var timezoneReferences = []timezoneReference{
+	// Asia and Oceania
+	{25.0330, 121.5654, "Asia/Taipei"},
+	{22.7583, 121.1444, "Asia/Taipei"},
+	{35.6762, 139.6503, "Asia/Tokyo"},
+	{37.5665, 126.9780, "Asia/Seoul"},
+	{31.2304, 121.4737, "Asia/Shanghai"},
+	{39.9042, 116.4074, "Asia/Shanghai"},
+	{22.3193, 114.1694, "Asia/Hong_Kong"},
+	{14.5995, 120.9842, "Asia/Manila"},
+	{1.3521, 103.8198, "Asia/Singapore"},
+	{13.7563, 100.5018, "Asia/Bangkok"},
+	{-6.2088, 106.8456, "Asia/Jakarta"},
+	{28.6139, 77.2090, "Asia/Kolkata"},
+	{19.0760, 72.8777, "Asia/Kolkata"},
+	{25.2048, 55.2708, "Asia/Dubai"},
+	{-36.8485, 174.7633, "Pacific/Auckland"},
+	{-45.0312, 168.6626, "Pacific/Auckland"},
+	{-33.8688, 151.2093, "Australia/Sydney"},
+	{-37.8136, 144.9631, "Australia/Melbourne"},
+	{-27.4698, 153.0251, "Australia/Brisbane"},
+	{-34.9285, 138.6007, "Australia/Adelaide"},
+	{-31.9505, 115.8605, "Australia/Perth"},
+	// Europe and Africa
+	{48.8566, 2.3522, "Europe/Paris"},
+	{50.1109, 8.6821, "Europe/Berlin"},
+	{52.5200, 13.4050, "Europe/Berlin"},
+	{51.5074, -0.1278, "Europe/London"},
+	{53.3498, -6.2603, "Europe/Dublin"},
+	{40.4168, -3.7038, "Europe/Madrid"},
+	{38.7223, -9.1393, "Europe/Lisbon"},
+	{41.9028, 12.4964, "Europe/Rome"},
+	{52.3676, 4.9041, "Europe/Amsterdam"},
+	{47.3769, 8.5417, "Europe/Zurich"},
+	{48.2082, 16.3738, "Europe/Vienna"},
+	{59.3293, 18.0686, "Europe/Stockholm"},
+	{37.9838, 23.7275, "Europe/Athens"},
+	{41.0082, 28.9784, "Europe/Istanbul"},
+	{55.7558, 37.6173, "Europe/Moscow"},
+	{30.0444, 31.2357, "Africa/Cairo"},
+	{6.5244, 3.3792, "Africa/Lagos"},
+	{-1.2921, 36.8219, "Africa/Nairobi"},
+	{-26.2041, 28.0473, "Africa/Johannesburg"},
+	// The Americas
+	{40.7128, -74.0060, "America/New_York"},
+	{42.3601, -71.0589, "America/New_York"},
+	{25.7617, -80.1918, "America/New_York"},
+	{43.6532, -79.3832, "America/Toronto"},
+	{41.8781, -87.6298, "America/Chicago"},
+	{29.7604, -95.3698, "America/Chicago"},
+	{39.7392, -104.9903, "America/Denver"},
+	{33.4484, -112.0740, "America/Phoenix"},
+	{34.0522, -118.2437, "America/Los_Angeles"},
+	{37.7749, -122.4194, "America/Los_Angeles"},
+	{47.6062, -122.3321, "America/Los_Angeles"},
+	{49.2827, -123.1207, "America/Vancouver"},
+	{61.2181, -149.9003, "America/Anchorage"},
+	{21.3069, -157.8583, "Pacific/Honolulu"},
+	{19.4326, -99.1332, "America/Mexico_City"},
+	{4.7110, -74.0721, "America/Bogota"},
+	{-12.0464, -77.0428, "America/Lima"},
+	{-33.4489, -70.6693, "America/Santiago"},
+	{-23.5505, -46.6333, "America/Sao_Paulo"},
+	{-34.6037, -58.3816, "America/Argentina/Buenos_Aires"},
+}
+
+// maxTimezoneReferenceKm is how far a coordinate can be from the nearest
+// reference city before falling back to a fixed offset from its longitude
+const maxTimezoneReferenceKm = 1000
+
+// TimezoneForCoordinates returns the IANA timezone for a location without
+// calling any external service. Locations far from every reference city get
+// a fixed-offset Etc/GMT zone based on longitude.
+func TimezoneForCoordinates(latitude, longitude float64) string {
+	best, bestKm := "", math.MaxFloat64
+	for _, ref := range timezoneReferences {
+		if km := DistanceKm(latitude, longitude, ref.Latitude, ref.Longitude); km < bestKm {
+			best, bestKm = ref.Timezone, km
+		}
+	}
+	if bestKm <= maxTimezoneReferenceKm {
+		return best
+	}
+
+	// Etc/GMT zones have inverted signs: Etc/GMT-8 is UTC+8
+	offset := int(math.Round(longitude / 15))
+	switch {
+	case offset == 0:
+		return "UTC"
+	case offset > 0:
+		return fmt.Sprintf("Etc/GMT-%d", offset)
+	default:
+		return fmt.Sprintf("Etc/GMT+%d", -offset)
+	}
+}
+
+// LoadTimezone loads a timezone by IANA name, falling back to UTC if it is unknown
+func LoadTimezone(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimezoneForCoordinates(t *testing.T) {
+	tests := []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		expected  string
+	}{
+		{"Taipei", 25.0478, 121.5319, "Asia/Taipei"},
+		{"Kaohsiung", 22.6273, 120.3014, "Asia/Taipei"},
+		{"Paris", 48.8584, 2.2945, "Europe/Paris"},
+		{"Auckland", -36.8509, 174.7645, "Pacific/Auckland"},
+		{"San Francisco", 37.7694, -122.4862, "America/Los_Angeles"},
+		{"Mid-Pacific", 0, -150, "Etc/GMT+10"},
+		{"Indian Ocean", -30, 75, "Etc/GMT-5"},
+		{"Gulf of Guinea", -10, 0, "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone := TimezoneForCoordinates(tt.latitude, tt.longitude)
+			assert.Equal(t, tt.expected, zone)
+			_, err := time.LoadLocation(zone)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLoadTimezone(t *testing.T) {
+	assert.Equal(t, "Asia/Taipei", LoadTimezone("Asia/Taipei").String())
+	assert.Equal(t, time.UTC, LoadTimezone("Pacific/Nowhere"))
+	assert.Equal(t, time.UTC, LoadTimezone(""))
+}
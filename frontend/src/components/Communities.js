@@ -124,6 +124,7 @@ const Communities = () => {
 
     if (filters.radius) {
       queryParams.append('radius', filters.radius);
+      queryParams.append('units', 'mi');
     }
 
     queryParams.append('latitude', location.latitude);
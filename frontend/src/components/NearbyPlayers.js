@@ -118,7 +118,7 @@ const NearbyPlayers = () => {
       
       console.log(`📍 Trying expanded search with ${maxReasonableRadius} mile radius...`);
       
-      const response = await fetch(`${apiUrl}/users/nearby?radius=${maxReasonableRadius}`, {
+      const response = await fetch(`${apiUrl}/users/nearby?radius=${maxReasonableRadius}&units=mi`, {
         method: 'GET',
         headers: {
           'Authorization': `Bearer ${token}`,
@@ -197,6 +197,7 @@ const NearbyPlayers = () => {
       // Build query parameters
       const params = new URLSearchParams({
         radius: filters.radius.toString(),
+        units: 'mi',
         ...(filters.skillLevel && { skill_level: filters.skillLevel }),
         ...(filters.gender && { gender: filters.gender }),
         ...(filters.isNewcomer && { is_newcomer: 'true' }),
@@ -105,6 +105,7 @@ const PlayBulletin = () => {
         queryParams.append('longitude', position.coords.longitude);
         if (searchRadius < 999) {
           queryParams.append('radius', searchRadius.toString());
+          queryParams.append('units', 'mi');
         }
         // If searchRadius is 999 (All Distances), don't send radius parameter
       } catch (error) {
@@ -114,6 +115,7 @@ const PlayBulletin = () => {
         queryParams.append('longitude', '-122.4194');
         if (searchRadius < 999) {
           queryParams.append('radius', searchRadius.toString());
+          queryParams.append('units', 'mi');
         }
         // If searchRadius is 999 (All Distances), don't send radius parameter
       }
@@ -270,6 +272,7 @@ const PlayBulletin = () => {
            queryParams.append('longitude', position.coords.longitude);
            if (expandedRadius < 999) {
              queryParams.append('radius', expandedRadius);
+             queryParams.append('units', 'mi');
            }
          } catch (error) {
            console.warn("Couldn't get location, using default San Francisco location");
@@ -277,6 +280,7 @@ const PlayBulletin = () => {
            queryParams.append('longitude', '-122.4194');
            if (expandedRadius < 999) {
              queryParams.append('radius', expandedRadius);
+             queryParams.append('units', 'mi');
            }
          }
         
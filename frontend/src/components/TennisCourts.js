@@ -38,7 +38,8 @@ const TennisCourts = () => {
       let queryParams = new URLSearchParams({
         latitude: latitude,
         longitude: longitude,
-        radius: '25' // 25 miles radius
+        radius: '25', // 25 miles radius
+        units: 'mi'
       });
 
       if (filters.courtType) {